@@ -0,0 +1,465 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// generate returns the Go source for the schema files, read with readFile.
+func generate(pkg string, files []string, readFile func(string) ([]byte, error)) ([]byte, error) {
+	p := newParser()
+	type root struct {
+		typ    *avroType
+		schema string
+	}
+	var roots []root
+	for _, file := range files {
+		data, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+		t, err := p.parseFile(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		roots = append(roots, root{typ: t, schema: strings.TrimSpace(string(data))})
+	}
+
+	g := &generator{done: make(map[string]bool)}
+	for _, r := range p.records {
+		g.record(r)
+	}
+	for _, r := range roots {
+		if strings.Contains(r.schema, "`") {
+			return nil, fmt.Errorf("schema %s contains a backquote", r.typ.record.fullName)
+		}
+		g.root(r.typ.record, r.schema)
+	}
+	for _, r := range p.records {
+		if err := g.recordCodec(r); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	sources := make([]string, len(files))
+	for i, f := range files {
+		sources[i] = filepath.Base(f)
+	}
+	fmt.Fprintf(&out, "// Code generated by avrogen from %s. DO NOT EDIT.\n\n", strings.Join(sources, ", "))
+	fmt.Fprintf(&out, "package %s\n\nimport (\n\t\"fmt\"\n", pkg)
+	if g.usesTime {
+		out.WriteString("\t\"time\"\n")
+	}
+	out.WriteString("\n\t\"github.com/linkedin/goavro/v2\"\n)\n")
+	out.Write(g.types.Bytes())
+	out.Write(g.funcs.Bytes())
+	out.WriteString(mustCodecFunc)
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, out.Bytes())
+	}
+	return src, nil
+}
+
+type generator struct {
+	types    bytes.Buffer
+	funcs    bytes.Buffer
+	done     map[string]bool
+	usesTime bool
+}
+
+func (g *generator) record(r *record) {
+	fmt.Fprintf(&g.types, "\n// %s is the Avro record %s.\n", r.name, r.fullName)
+	if r.doc != "" {
+		g.types.WriteString("//\n")
+		writeComment(&g.types, "", r.doc)
+	}
+	fmt.Fprintf(&g.types, "type %s struct {\n", r.name)
+	for _, f := range r.fields {
+		if f.doc != "" {
+			writeComment(&g.types, "\t", f.doc)
+		}
+		fmt.Fprintf(&g.types, "\t%s %s `avro:%q`\n", f.goName, f.typ.goType(), f.name)
+	}
+	g.types.WriteString("}\n")
+}
+
+// root writes the schema and the exported methods of a top-level record.
+func (g *generator) root(r *record, schema string) {
+	codec := strings.ToLower(r.name[:1]) + r.name[1:] + "Codec"
+	fmt.Fprintf(&g.types, `
+// %[1]sSchema is the Avro schema of %[1]s.
+const %[1]sSchema = %[2]s
+
+var %[3]s = mustCodec(%[1]sSchema)
+
+// AvroSchema returns %[1]sSchema.
+func (r *%[1]s) AvroSchema() string {
+	return %[1]sSchema
+}
+
+// AvroNative returns r in the native form of goavro, e.g. to log it.
+func (r *%[1]s) AvroNative() map[string]interface{} {
+	return encode%[1]s(*r)
+}
+
+// MarshalAvro encodes r in the Avro binary encoding of %[1]sSchema.
+func (r *%[1]s) MarshalAvro() ([]byte, error) {
+	return r.EncodeAvro(%[3]s)
+}
+
+// UnmarshalAvro decodes data written with %[1]sSchema.
+func (r *%[1]s) UnmarshalAvro(data []byte) error {
+	return r.DecodeAvro(%[3]s, data)
+}
+
+// EncodeAvro encodes r with codec, which must be %[1]sSchema or a version
+// of it whose fields r has.
+func (r *%[1]s) EncodeAvro(codec *goavro.Codec) ([]byte, error) {
+	data, err := codec.BinaryFromNative(nil, r.AvroNative())
+	if err != nil {
+		return nil, fmt.Errorf("encode %[1]s: %%w", err)
+	}
+	return data, nil
+}
+
+// DecodeAvro decodes data written with the schema of writer, which may be
+// another version of %[1]sSchema. Fields the writer does not have take their
+// defaults.
+func (r *%[1]s) DecodeAvro(writer *goavro.Codec, data []byte) error {
+	native, _, err := writer.NativeFromBinary(data)
+	if err != nil {
+		return fmt.Errorf("decode %[1]s: %%w", err)
+	}
+	decoded, err := decode%[1]s(native)
+	if err != nil {
+		return fmt.Errorf("decode %[1]s: %%w", err)
+	}
+	*r = decoded
+	return nil
+}
+`, r.name, "`"+schema+"`", codec)
+}
+
+// recordCodec writes the functions converting r to and from goavro's native
+// form, and those of the types of its fields.
+func (g *generator) recordCodec(r *record) error {
+	var enc, dec bytes.Buffer
+	fmt.Fprintf(&enc, "\nfunc encode%s(r %s) map[string]interface{} {\n\treturn map[string]interface{}{\n", r.name, r.name)
+	fmt.Fprintf(&dec, `
+func decode%[1]s(v interface{}) (%[1]s, error) {
+	var r %[1]s
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return r, fmt.Errorf("want record %[2]s, got %%T", v)
+	}
+	var err error
+`, r.name, r.fullName)
+
+	for _, f := range r.fields {
+		if err := g.typeCodec(f.typ); err != nil {
+			return fmt.Errorf("%s.%s: %w", r.fullName, f.name, err)
+		}
+		fmt.Fprintf(&enc, "\t\t%q: %s,\n", f.name, encodeExpr(f.typ, "r."+f.goName))
+
+		fmt.Fprintf(&dec, "\tif v, ok := m[%q]; ok {\n", f.name)
+		fmt.Fprintf(&dec, "\t\tif r.%s, err = decode%s(v); err != nil {\n", f.goName, f.typ.id())
+		fmt.Fprintf(&dec, "\t\t\treturn r, fmt.Errorf(\"%s: %%w\", err)\n\t\t}\n", f.name)
+		def, err := defaultValue(f)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", r.fullName, f.name, err)
+		}
+		switch {
+		case f.def == nil:
+			fmt.Fprintf(&dec, "\t} else {\n\t\treturn r, fmt.Errorf(\"%s: missing\")\n\t}\n", f.name)
+		case def != "":
+			fmt.Fprintf(&dec, "\t} else {\n\t\tr.%s = %s\n\t}\n", f.goName, def)
+		default:
+			dec.WriteString("\t}\n")
+		}
+	}
+	enc.WriteString("\t}\n}\n")
+	dec.WriteString("\treturn r, nil\n}\n")
+	g.funcs.Write(enc.Bytes())
+	g.funcs.Write(dec.Bytes())
+	return nil
+}
+
+// encodeExpr converts the Go value expr of type t to goavro's native form.
+func encodeExpr(t *avroType, expr string) string {
+	switch t.kind {
+	case "record", "array", "nullable":
+		return "encode" + t.id() + "(" + expr + ")"
+	default:
+		return expr
+	}
+}
+
+// typeCodec writes the encode and decode functions of t, other than those
+// of records, unless they were written already.
+func (g *generator) typeCodec(t *avroType) error {
+	id := t.id()
+	if t.kind == "record" || g.done[id] {
+		return nil
+	}
+	g.done[id] = true
+
+	switch t.kind {
+	case "array":
+		if err := g.typeCodec(t.elem); err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.funcs, `
+func encode%[1]s(a %[2]s) []interface{} {
+	out := make([]interface{}, len(a))
+	for i, x := range a {
+		out[i] = %[3]s
+	}
+	return out
+}
+
+func decode%[1]s(v interface{}) (%[2]s, error) {
+	a, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("want array, got %%T", v)
+	}
+	out := make(%[2]s, len(a))
+	for i, x := range a {
+		var err error
+		if out[i], err = decode%[4]s(x); err != nil {
+			return nil, fmt.Errorf("[%%d]: %%w", i, err)
+		}
+	}
+	return out, nil
+}
+`, id, t.goType(), encodeExpr(t.elem, "x"), t.elem.id())
+
+	case "nullable":
+		if err := g.typeCodec(t.elem); err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.funcs, `
+func encode%[1]s(x %[2]s) interface{} {
+	if x == nil {
+		return nil
+	}
+	return goavro.Union(%[3]q, %[4]s)
+}
+
+func decode%[1]s(v interface{}) (%[2]s, error) {
+	if v == nil {
+		return nil, nil
+	}
+	// goavro decodes a union member as a map from its name to its value;
+	// a writer that did not use a union sends the value itself.
+	if m, ok := v.(map[string]interface{}); ok && len(m) == 1 {
+		if member, ok := m[%[3]q]; ok {
+			v = member
+		}
+	}
+	x, err := decode%[5]s(v)
+	if err != nil {
+		return nil, err
+	}
+	return &x, nil
+}
+`, id, t.goType(), t.elem.unionName(), encodeExpr(t.elem, "*x"), t.elem.id())
+
+	case timestampMillis:
+		g.usesTime = true
+		g.funcs.WriteString(decodeTimestampMillisFunc)
+
+	default:
+		src, ok := primitiveDecoders[t.kind]
+		if !ok {
+			return fmt.Errorf("unsupported type %s", t.kind)
+		}
+		g.funcs.WriteString(src)
+	}
+	return nil
+}
+
+// defaultValue is the Go expression of the default of f, or "" when the
+// default is the zero value.
+func defaultValue(f *field) (string, error) {
+	if f.def == nil {
+		return "", nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(f.def, &v); err != nil {
+		return "", err
+	}
+	switch f.typ.kind {
+	case "nullable":
+		if v != nil {
+			return "", fmt.Errorf("only null defaults are supported for unions")
+		}
+		return "", nil
+	case "array":
+		if a, ok := v.([]interface{}); !ok || len(a) > 0 {
+			return "", fmt.Errorf("only empty array defaults are supported")
+		}
+		return "", nil
+	case "string":
+		s, ok := v.(string)
+		if !ok {
+			return "", fmt.Errorf("default %s is not a string", f.def)
+		}
+		if s == "" {
+			return "", nil
+		}
+		return strconv.Quote(s), nil
+	case "int", "long", "float", "double":
+		n, ok := v.(float64)
+		if !ok {
+			return "", fmt.Errorf("default %s is not a number", f.def)
+		}
+		if n == 0 {
+			return "", nil
+		}
+		return string(f.def), nil
+	case "boolean":
+		b, ok := v.(bool)
+		if !ok {
+			return "", fmt.Errorf("default %s is not a boolean", f.def)
+		}
+		return strconv.FormatBool(b), nil
+	case timestampMillis:
+		if _, ok := v.(float64); !ok {
+			return "", fmt.Errorf("default %s is not a number", f.def)
+		}
+		return "time.UnixMilli(" + string(f.def) + ").UTC()", nil
+	}
+	return "", fmt.Errorf("defaults of type %s are not supported", f.typ.kind)
+}
+
+func writeComment(b *bytes.Buffer, indent, text string) {
+	const width = 76
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > width {
+			fmt.Fprintf(b, "%s// %s\n", indent, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		fmt.Fprintf(b, "%s// %s\n", indent, line)
+	}
+}
+
+const decodeTimestampMillisFunc = `
+func decodeTimestampMillis(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t.UTC(), nil
+	case int64:
+		return time.UnixMilli(t).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("want timestamp-millis, got %T", v)
+}
+`
+
+// primitiveDecoders accept the types goavro decodes each primitive to, and
+// those the primitive can be promoted from.
+var primitiveDecoders = map[string]string{
+	"string": `
+func decodeString(v interface{}) (string, error) {
+	switch s := v.(type) {
+	case string:
+		return s, nil
+	case []byte:
+		return string(s), nil
+	}
+	return "", fmt.Errorf("want string, got %T", v)
+}
+`,
+	"bytes": `
+func decodeBytes(v interface{}) ([]byte, error) {
+	switch b := v.(type) {
+	case []byte:
+		return b, nil
+	case string:
+		return []byte(b), nil
+	}
+	return nil, fmt.Errorf("want bytes, got %T", v)
+}
+`,
+	"int": `
+func decodeInt(v interface{}) (int32, error) {
+	n, ok := v.(int32)
+	if !ok {
+		return 0, fmt.Errorf("want int, got %T", v)
+	}
+	return n, nil
+}
+`,
+	"long": `
+func decodeLong(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case int32:
+		return int64(n), nil
+	}
+	return 0, fmt.Errorf("want long, got %T", v)
+}
+`,
+	"float": `
+func decodeFloat(v interface{}) (float32, error) {
+	switch n := v.(type) {
+	case float32:
+		return n, nil
+	case int32:
+		return float32(n), nil
+	case int64:
+		return float32(n), nil
+	}
+	return 0, fmt.Errorf("want float, got %T", v)
+}
+`,
+	"double": `
+func decodeDouble(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	}
+	return 0, fmt.Errorf("want double, got %T", v)
+}
+`,
+	"boolean": `
+func decodeBoolean(v interface{}) (bool, error) {
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("want boolean, got %T", v)
+	}
+	return b, nil
+}
+`,
+}
+
+const mustCodecFunc = `
+func mustCodec(schema string) *goavro.Codec {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		panic(err)
+	}
+	return codec
+}
+`
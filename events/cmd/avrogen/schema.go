@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// primitives maps the supported Avro primitives to their Go types.
+var primitives = map[string]string{
+	"string":  "string",
+	"bytes":   "[]byte",
+	"int":     "int32",
+	"long":    "int64",
+	"float":   "float32",
+	"double":  "float64",
+	"boolean": "bool",
+}
+
+const timestampMillis = "timestamp-millis"
+
+// avroType is a parsed schema. kind is a primitive, timestampMillis,
+// "record", "array" or "nullable".
+type avroType struct {
+	kind   string
+	record *record
+	// elem is the item type of an array or the non-null type of a nullable.
+	elem *avroType
+}
+
+type record struct {
+	name     string
+	fullName string
+	doc      string
+	fields   []*field
+}
+
+type field struct {
+	name   string
+	goName string
+	doc    string
+	typ    *avroType
+	// def is the JSON default, nil when the field has none.
+	def json.RawMessage
+}
+
+// id names the type in the generated encode and decode functions.
+func (t *avroType) id() string {
+	switch t.kind {
+	case "record":
+		return t.record.name
+	case "array":
+		return t.elem.id() + "Array"
+	case "nullable":
+		return "Nullable" + t.elem.id()
+	case timestampMillis:
+		return "TimestampMillis"
+	default:
+		return strings.ToUpper(t.kind[:1]) + t.kind[1:]
+	}
+}
+
+func (t *avroType) goType() string {
+	switch t.kind {
+	case "record":
+		return t.record.name
+	case "array":
+		return "[]" + t.elem.goType()
+	case "nullable":
+		return "*" + t.elem.goType()
+	case timestampMillis:
+		return "time.Time"
+	default:
+		return primitives[t.kind]
+	}
+}
+
+// unionName is the name goavro gives the type as a union member.
+func (t *avroType) unionName() string {
+	switch t.kind {
+	case "record":
+		return t.record.fullName
+	case timestampMillis:
+		return "long." + timestampMillis
+	default:
+		return t.kind
+	}
+}
+
+// parser resolves named types while parsing the schemas of one package.
+type parser struct {
+	named   map[string]*avroType
+	records []*record
+}
+
+func newParser() *parser {
+	return &parser{named: make(map[string]*avroType)}
+}
+
+func (p *parser) parseFile(data []byte) (*avroType, error) {
+	var schema interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	t, err := p.parse(schema, "")
+	if err != nil {
+		return nil, err
+	}
+	if t.kind != "record" {
+		return nil, fmt.Errorf("top-level type must be a record, got %s", t.kind)
+	}
+	return t, nil
+}
+
+func (p *parser) parse(schema interface{}, namespace string) (*avroType, error) {
+	switch s := schema.(type) {
+	case string:
+		if _, ok := primitives[s]; ok {
+			return &avroType{kind: s}, nil
+		}
+		if t, ok := p.named[s]; ok {
+			return t, nil
+		}
+		if t, ok := p.named[namespace+"."+s]; ok {
+			return t, nil
+		}
+		return nil, fmt.Errorf("unknown type %q", s)
+
+	case []interface{}:
+		if len(s) != 2 || s[0] != "null" {
+			return nil, fmt.Errorf("unsupported union %v: only [\"null\", type] is supported", s)
+		}
+		elem, err := p.parse(s[1], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &avroType{kind: "nullable", elem: elem}, nil
+
+	case map[string]interface{}:
+		if s["logicalType"] == timestampMillis && s["type"] == "long" {
+			return &avroType{kind: timestampMillis}, nil
+		}
+		switch s["type"] {
+		case "record":
+			return p.parseRecord(s, namespace)
+		case "array":
+			elem, err := p.parse(s["items"], namespace)
+			if err != nil {
+				return nil, err
+			}
+			return &avroType{kind: "array", elem: elem}, nil
+		default:
+			return p.parse(s["type"], namespace)
+		}
+	}
+	return nil, fmt.Errorf("unsupported schema %v", schema)
+}
+
+func (p *parser) parseRecord(s map[string]interface{}, namespace string) (*avroType, error) {
+	name, _ := s["name"].(string)
+	if name == "" {
+		return nil, fmt.Errorf("record without a name")
+	}
+	if ns, ok := s["namespace"].(string); ok {
+		namespace = ns
+	}
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		namespace, name = name[:i], name[i+1:]
+	}
+	r := &record{name: name, fullName: name}
+	if namespace != "" {
+		r.fullName = namespace + "." + name
+	}
+	r.doc, _ = s["doc"].(string)
+	t := &avroType{kind: "record", record: r}
+	if _, ok := p.named[r.fullName]; ok {
+		return nil, fmt.Errorf("record %s is defined twice", r.fullName)
+	}
+	p.named[r.fullName] = t
+	p.records = append(p.records, r)
+
+	fields, _ := s["fields"].([]interface{})
+	for _, f := range fields {
+		fm, ok := f.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("record %s: invalid field %v", r.fullName, f)
+		}
+		fieldName, _ := fm["name"].(string)
+		typ, err := p.parse(fm["type"], namespace)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", r.fullName, fieldName, err)
+		}
+		fd := &field{name: fieldName, goName: goName(fieldName), typ: typ}
+		fd.doc, _ = fm["doc"].(string)
+		if def, ok := fm["default"]; ok {
+			if fd.def, err = json.Marshal(def); err != nil {
+				return nil, err
+			}
+		}
+		r.fields = append(r.fields, fd)
+	}
+	return t, nil
+}
+
+// initialisms are written in upper case in Go names.
+var initialisms = map[string]bool{"id": true, "url": true, "http": true, "api": true, "uuid": true}
+
+// goName turns a snake_case Avro name into an exported Go name, e.g.
+// order_id into OrderID.
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		if initialisms[strings.ToLower(part)] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		r := []rune(part)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,19 @@
+module github.com/jakkapat-chongsuwat/go-microservice/events
+
+go 1.22.7
+
+require (
+	github.com/linkedin/goavro/v2 v2.13.1
+	github.com/riferrei/srclient v0.7.1
+	github.com/stretchr/testify v1.10.0
+	google.golang.org/protobuf v1.36.5
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/golang/snappy v0.0.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/santhosh-tekuri/jsonschema/v5 v5.0.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,19 @@
+// Package orderevents holds the Avro schemas of the events on the order
+// topic and the Go types generated from them. order-service publishes these
+// events and notification-service consumes them.
+//
+// Edit the .avsc files and run go generate to update the types. Schema
+// changes must stay BACKWARD compatible with the versions already
+// registered: add fields with defaults and do not change or remove the
+// fields without one.
+package orderevents
+
+import "embed"
+
+//go:generate go run ../cmd/avrogen -package orderevents -o order_event.avro.go order_event.avsc
+
+// Schemas holds the .avsc files, for the schema registry and for tools that
+// need the schemas as files.
+//
+//go:embed *.avsc
+var Schemas embed.FS
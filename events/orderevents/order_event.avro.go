@@ -0,0 +1,532 @@
+// Code generated by avrogen from order_event.avsc. DO NOT EDIT.
+
+package orderevents
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+// OrderEvent is the Avro record com.example.order.OrderEvent.
+//
+// Envelope of the events on the order topic. Fields up to timestamp are
+// version 1 and stay for its consumers; fields added since have defaults, so
+// each version reads the events of the one before.
+type OrderEvent struct {
+	OrderID       string    `avro:"order_id"`
+	EventType     string    `avro:"event_type"`
+	Message       string    `avro:"message"`
+	Timestamp     time.Time `avro:"timestamp"`
+	EventID       string    `avro:"event_id"`
+	SchemaVersion int32     `avro:"schema_version"`
+	CorrelationID *string   `avro:"correlation_id"`
+	Source        string    `avro:"source"`
+	Order         *Order    `avro:"order"`
+}
+
+// Order is the Avro record com.example.order.Order.
+type Order struct {
+	OrderID       string      `avro:"order_id"`
+	UserID        string      `avro:"user_id"`
+	Status        string      `avro:"status"`
+	Items         []OrderItem `avro:"items"`
+	TotalQuantity int32       `avro:"total_quantity"`
+	Total         *Money      `avro:"total"`
+	CreatedAt     time.Time   `avro:"created_at"`
+}
+
+// OrderItem is the Avro record com.example.order.OrderItem.
+type OrderItem struct {
+	ProductID string `avro:"product_id"`
+	Quantity  int32  `avro:"quantity"`
+	UnitPrice *Money `avro:"unit_price"`
+}
+
+// Money is the Avro record com.example.order.Money.
+//
+// An amount in the minor unit of an ISO 4217 currency.
+type Money struct {
+	AmountMinor  int64  `avro:"amount_minor"`
+	CurrencyCode string `avro:"currency_code"`
+}
+
+// OrderEventSchema is the Avro schema of OrderEvent.
+const OrderEventSchema = `{
+  "type": "record",
+  "name": "OrderEvent",
+  "namespace": "com.example.order",
+  "doc": "Envelope of the events on the order topic. Fields up to timestamp are version 1 and stay for its consumers; fields added since have defaults, so each version reads the events of the one before.",
+  "fields": [
+    { "name": "order_id", "type": "string" },
+    { "name": "event_type", "type": "string" },
+    { "name": "message", "type": "string", "default": "" },
+    {
+      "name": "timestamp",
+      "type": { "type": "long", "logicalType": "timestamp-millis" }
+    },
+    { "name": "event_id", "type": "string", "default": "" },
+    { "name": "schema_version", "type": "int", "default": 1 },
+    { "name": "correlation_id", "type": ["null", "string"], "default": null },
+    { "name": "source", "type": "string", "default": "" },
+    {
+      "name": "order",
+      "type": [
+        "null",
+        {
+          "type": "record",
+          "name": "Order",
+          "fields": [
+            { "name": "order_id", "type": "string" },
+            { "name": "user_id", "type": "string" },
+            { "name": "status", "type": "string" },
+            {
+              "name": "items",
+              "type": {
+                "type": "array",
+                "items": {
+                  "type": "record",
+                  "name": "OrderItem",
+                  "fields": [
+                    { "name": "product_id", "type": "string" },
+                    { "name": "quantity", "type": "int" },
+                    {
+                      "name": "unit_price",
+                      "type": [
+                        "null",
+                        {
+                          "type": "record",
+                          "name": "Money",
+                          "doc": "An amount in the minor unit of an ISO 4217 currency.",
+                          "fields": [
+                            { "name": "amount_minor", "type": "long" },
+                            { "name": "currency_code", "type": "string" }
+                          ]
+                        }
+                      ],
+                      "default": null
+                    }
+                  ]
+                }
+              }
+            },
+            { "name": "total_quantity", "type": "int" },
+            { "name": "total", "type": ["null", "Money"], "default": null },
+            {
+              "name": "created_at",
+              "type": { "type": "long", "logicalType": "timestamp-millis" }
+            }
+          ]
+        }
+      ],
+      "default": null
+    }
+  ]
+}`
+
+var orderEventCodec = mustCodec(OrderEventSchema)
+
+// AvroSchema returns OrderEventSchema.
+func (r *OrderEvent) AvroSchema() string {
+	return OrderEventSchema
+}
+
+// AvroNative returns r in the native form of goavro, e.g. to log it.
+func (r *OrderEvent) AvroNative() map[string]interface{} {
+	return encodeOrderEvent(*r)
+}
+
+// MarshalAvro encodes r in the Avro binary encoding of OrderEventSchema.
+func (r *OrderEvent) MarshalAvro() ([]byte, error) {
+	return r.EncodeAvro(orderEventCodec)
+}
+
+// UnmarshalAvro decodes data written with OrderEventSchema.
+func (r *OrderEvent) UnmarshalAvro(data []byte) error {
+	return r.DecodeAvro(orderEventCodec, data)
+}
+
+// EncodeAvro encodes r with codec, which must be OrderEventSchema or a version
+// of it whose fields r has.
+func (r *OrderEvent) EncodeAvro(codec *goavro.Codec) ([]byte, error) {
+	data, err := codec.BinaryFromNative(nil, r.AvroNative())
+	if err != nil {
+		return nil, fmt.Errorf("encode OrderEvent: %w", err)
+	}
+	return data, nil
+}
+
+// DecodeAvro decodes data written with the schema of writer, which may be
+// another version of OrderEventSchema. Fields the writer does not have take their
+// defaults.
+func (r *OrderEvent) DecodeAvro(writer *goavro.Codec, data []byte) error {
+	native, _, err := writer.NativeFromBinary(data)
+	if err != nil {
+		return fmt.Errorf("decode OrderEvent: %w", err)
+	}
+	decoded, err := decodeOrderEvent(native)
+	if err != nil {
+		return fmt.Errorf("decode OrderEvent: %w", err)
+	}
+	*r = decoded
+	return nil
+}
+
+func decodeString(v interface{}) (string, error) {
+	switch s := v.(type) {
+	case string:
+		return s, nil
+	case []byte:
+		return string(s), nil
+	}
+	return "", fmt.Errorf("want string, got %T", v)
+}
+
+func decodeTimestampMillis(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t.UTC(), nil
+	case int64:
+		return time.UnixMilli(t).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("want timestamp-millis, got %T", v)
+}
+
+func decodeInt(v interface{}) (int32, error) {
+	n, ok := v.(int32)
+	if !ok {
+		return 0, fmt.Errorf("want int, got %T", v)
+	}
+	return n, nil
+}
+
+func encodeNullableString(x *string) interface{} {
+	if x == nil {
+		return nil
+	}
+	return goavro.Union("string", *x)
+}
+
+func decodeNullableString(v interface{}) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	// goavro decodes a union member as a map from its name to its value;
+	// a writer that did not use a union sends the value itself.
+	if m, ok := v.(map[string]interface{}); ok && len(m) == 1 {
+		if member, ok := m["string"]; ok {
+			v = member
+		}
+	}
+	x, err := decodeString(v)
+	if err != nil {
+		return nil, err
+	}
+	return &x, nil
+}
+
+func encodeNullableOrder(x *Order) interface{} {
+	if x == nil {
+		return nil
+	}
+	return goavro.Union("com.example.order.Order", encodeOrder(*x))
+}
+
+func decodeNullableOrder(v interface{}) (*Order, error) {
+	if v == nil {
+		return nil, nil
+	}
+	// goavro decodes a union member as a map from its name to its value;
+	// a writer that did not use a union sends the value itself.
+	if m, ok := v.(map[string]interface{}); ok && len(m) == 1 {
+		if member, ok := m["com.example.order.Order"]; ok {
+			v = member
+		}
+	}
+	x, err := decodeOrder(v)
+	if err != nil {
+		return nil, err
+	}
+	return &x, nil
+}
+
+func encodeOrderEvent(r OrderEvent) map[string]interface{} {
+	return map[string]interface{}{
+		"order_id":       r.OrderID,
+		"event_type":     r.EventType,
+		"message":        r.Message,
+		"timestamp":      r.Timestamp,
+		"event_id":       r.EventID,
+		"schema_version": r.SchemaVersion,
+		"correlation_id": encodeNullableString(r.CorrelationID),
+		"source":         r.Source,
+		"order":          encodeNullableOrder(r.Order),
+	}
+}
+
+func decodeOrderEvent(v interface{}) (OrderEvent, error) {
+	var r OrderEvent
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return r, fmt.Errorf("want record com.example.order.OrderEvent, got %T", v)
+	}
+	var err error
+	if v, ok := m["order_id"]; ok {
+		if r.OrderID, err = decodeString(v); err != nil {
+			return r, fmt.Errorf("order_id: %w", err)
+		}
+	} else {
+		return r, fmt.Errorf("order_id: missing")
+	}
+	if v, ok := m["event_type"]; ok {
+		if r.EventType, err = decodeString(v); err != nil {
+			return r, fmt.Errorf("event_type: %w", err)
+		}
+	} else {
+		return r, fmt.Errorf("event_type: missing")
+	}
+	if v, ok := m["message"]; ok {
+		if r.Message, err = decodeString(v); err != nil {
+			return r, fmt.Errorf("message: %w", err)
+		}
+	}
+	if v, ok := m["timestamp"]; ok {
+		if r.Timestamp, err = decodeTimestampMillis(v); err != nil {
+			return r, fmt.Errorf("timestamp: %w", err)
+		}
+	} else {
+		return r, fmt.Errorf("timestamp: missing")
+	}
+	if v, ok := m["event_id"]; ok {
+		if r.EventID, err = decodeString(v); err != nil {
+			return r, fmt.Errorf("event_id: %w", err)
+		}
+	}
+	if v, ok := m["schema_version"]; ok {
+		if r.SchemaVersion, err = decodeInt(v); err != nil {
+			return r, fmt.Errorf("schema_version: %w", err)
+		}
+	} else {
+		r.SchemaVersion = 1
+	}
+	if v, ok := m["correlation_id"]; ok {
+		if r.CorrelationID, err = decodeNullableString(v); err != nil {
+			return r, fmt.Errorf("correlation_id: %w", err)
+		}
+	}
+	if v, ok := m["source"]; ok {
+		if r.Source, err = decodeString(v); err != nil {
+			return r, fmt.Errorf("source: %w", err)
+		}
+	}
+	if v, ok := m["order"]; ok {
+		if r.Order, err = decodeNullableOrder(v); err != nil {
+			return r, fmt.Errorf("order: %w", err)
+		}
+	}
+	return r, nil
+}
+
+func encodeOrderItemArray(a []OrderItem) []interface{} {
+	out := make([]interface{}, len(a))
+	for i, x := range a {
+		out[i] = encodeOrderItem(x)
+	}
+	return out
+}
+
+func decodeOrderItemArray(v interface{}) ([]OrderItem, error) {
+	a, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("want array, got %T", v)
+	}
+	out := make([]OrderItem, len(a))
+	for i, x := range a {
+		var err error
+		if out[i], err = decodeOrderItem(x); err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	return out, nil
+}
+
+func encodeNullableMoney(x *Money) interface{} {
+	if x == nil {
+		return nil
+	}
+	return goavro.Union("com.example.order.Money", encodeMoney(*x))
+}
+
+func decodeNullableMoney(v interface{}) (*Money, error) {
+	if v == nil {
+		return nil, nil
+	}
+	// goavro decodes a union member as a map from its name to its value;
+	// a writer that did not use a union sends the value itself.
+	if m, ok := v.(map[string]interface{}); ok && len(m) == 1 {
+		if member, ok := m["com.example.order.Money"]; ok {
+			v = member
+		}
+	}
+	x, err := decodeMoney(v)
+	if err != nil {
+		return nil, err
+	}
+	return &x, nil
+}
+
+func encodeOrder(r Order) map[string]interface{} {
+	return map[string]interface{}{
+		"order_id":       r.OrderID,
+		"user_id":        r.UserID,
+		"status":         r.Status,
+		"items":          encodeOrderItemArray(r.Items),
+		"total_quantity": r.TotalQuantity,
+		"total":          encodeNullableMoney(r.Total),
+		"created_at":     r.CreatedAt,
+	}
+}
+
+func decodeOrder(v interface{}) (Order, error) {
+	var r Order
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return r, fmt.Errorf("want record com.example.order.Order, got %T", v)
+	}
+	var err error
+	if v, ok := m["order_id"]; ok {
+		if r.OrderID, err = decodeString(v); err != nil {
+			return r, fmt.Errorf("order_id: %w", err)
+		}
+	} else {
+		return r, fmt.Errorf("order_id: missing")
+	}
+	if v, ok := m["user_id"]; ok {
+		if r.UserID, err = decodeString(v); err != nil {
+			return r, fmt.Errorf("user_id: %w", err)
+		}
+	} else {
+		return r, fmt.Errorf("user_id: missing")
+	}
+	if v, ok := m["status"]; ok {
+		if r.Status, err = decodeString(v); err != nil {
+			return r, fmt.Errorf("status: %w", err)
+		}
+	} else {
+		return r, fmt.Errorf("status: missing")
+	}
+	if v, ok := m["items"]; ok {
+		if r.Items, err = decodeOrderItemArray(v); err != nil {
+			return r, fmt.Errorf("items: %w", err)
+		}
+	} else {
+		return r, fmt.Errorf("items: missing")
+	}
+	if v, ok := m["total_quantity"]; ok {
+		if r.TotalQuantity, err = decodeInt(v); err != nil {
+			return r, fmt.Errorf("total_quantity: %w", err)
+		}
+	} else {
+		return r, fmt.Errorf("total_quantity: missing")
+	}
+	if v, ok := m["total"]; ok {
+		if r.Total, err = decodeNullableMoney(v); err != nil {
+			return r, fmt.Errorf("total: %w", err)
+		}
+	}
+	if v, ok := m["created_at"]; ok {
+		if r.CreatedAt, err = decodeTimestampMillis(v); err != nil {
+			return r, fmt.Errorf("created_at: %w", err)
+		}
+	} else {
+		return r, fmt.Errorf("created_at: missing")
+	}
+	return r, nil
+}
+
+func encodeOrderItem(r OrderItem) map[string]interface{} {
+	return map[string]interface{}{
+		"product_id": r.ProductID,
+		"quantity":   r.Quantity,
+		"unit_price": encodeNullableMoney(r.UnitPrice),
+	}
+}
+
+func decodeOrderItem(v interface{}) (OrderItem, error) {
+	var r OrderItem
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return r, fmt.Errorf("want record com.example.order.OrderItem, got %T", v)
+	}
+	var err error
+	if v, ok := m["product_id"]; ok {
+		if r.ProductID, err = decodeString(v); err != nil {
+			return r, fmt.Errorf("product_id: %w", err)
+		}
+	} else {
+		return r, fmt.Errorf("product_id: missing")
+	}
+	if v, ok := m["quantity"]; ok {
+		if r.Quantity, err = decodeInt(v); err != nil {
+			return r, fmt.Errorf("quantity: %w", err)
+		}
+	} else {
+		return r, fmt.Errorf("quantity: missing")
+	}
+	if v, ok := m["unit_price"]; ok {
+		if r.UnitPrice, err = decodeNullableMoney(v); err != nil {
+			return r, fmt.Errorf("unit_price: %w", err)
+		}
+	}
+	return r, nil
+}
+
+func decodeLong(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case int32:
+		return int64(n), nil
+	}
+	return 0, fmt.Errorf("want long, got %T", v)
+}
+
+func encodeMoney(r Money) map[string]interface{} {
+	return map[string]interface{}{
+		"amount_minor":  r.AmountMinor,
+		"currency_code": r.CurrencyCode,
+	}
+}
+
+func decodeMoney(v interface{}) (Money, error) {
+	var r Money
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return r, fmt.Errorf("want record com.example.order.Money, got %T", v)
+	}
+	var err error
+	if v, ok := m["amount_minor"]; ok {
+		if r.AmountMinor, err = decodeLong(v); err != nil {
+			return r, fmt.Errorf("amount_minor: %w", err)
+		}
+	} else {
+		return r, fmt.Errorf("amount_minor: missing")
+	}
+	if v, ok := m["currency_code"]; ok {
+		if r.CurrencyCode, err = decodeString(v); err != nil {
+			return r, fmt.Errorf("currency_code: %w", err)
+		}
+	} else {
+		return r, fmt.Errorf("currency_code: missing")
+	}
+	return r, nil
+}
+
+func mustCodec(schema string) *goavro.Codec {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		panic(err)
+	}
+	return codec
+}
@@ -0,0 +1,145 @@
+package orderevents
+
+import (
+	"testing"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderEventV1 is the first version of OrderEventSchema, still registered
+// for the order topic.
+const orderEventV1 = `{
+  "type": "record",
+  "name": "OrderEvent",
+  "namespace": "com.example.order",
+  "fields": [
+    { "name": "order_id", "type": "string" },
+    { "name": "event_type", "type": "string" },
+    { "name": "message", "type": "string", "default": "" },
+    { "name": "timestamp", "type": { "type": "long", "logicalType": "timestamp-millis" } }
+  ]
+}`
+
+func ptr[T any](v T) *T { return &v }
+
+func TestOrderEvent_RoundTrip(t *testing.T) {
+	at := time.UnixMilli(1_700_000_000_000).UTC()
+	usd := func(amount int64) *Money { return &Money{AmountMinor: amount, CurrencyCode: "USD"} }
+
+	for name, event := range map[string]OrderEvent{
+		"full": {
+			OrderID:       "order-1",
+			EventType:     "CREATED",
+			Message:       "Order created for items: product-1, product-2",
+			Timestamp:     at,
+			EventID:       "event-1",
+			SchemaVersion: 2,
+			CorrelationID: ptr("req-1"),
+			Source:        "order-service",
+			Order: &Order{
+				OrderID: "order-1",
+				UserID:  "user-1",
+				Status:  "CREATED",
+				Items: []OrderItem{
+					{ProductID: "product-1", Quantity: 2, UnitPrice: usd(1999)},
+					{ProductID: "product-2", Quantity: 1},
+				},
+				TotalQuantity: 3,
+				Total:         usd(4498),
+				CreatedAt:     at.Add(-time.Second),
+			},
+		},
+		"minimal": {
+			OrderID:   "order-1",
+			EventType: "CREATED",
+			Timestamp: at,
+		},
+		"no items": {
+			OrderID:   "order-1",
+			EventType: "CREATED",
+			Timestamp: at,
+			Order:     &Order{OrderID: "order-1", UserID: "user-1", Items: []OrderItem{}, CreatedAt: at},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := event.MarshalAvro()
+			require.NoError(t, err)
+
+			var decoded OrderEvent
+			require.NoError(t, decoded.UnmarshalAvro(data))
+			assert.Equal(t, event, decoded)
+		})
+	}
+}
+
+func TestOrderEvent_DecodesVersionOne(t *testing.T) {
+	v1, err := goavro.NewCodec(orderEventV1)
+	require.NoError(t, err)
+	data, err := v1.BinaryFromNative(nil, map[string]interface{}{
+		"order_id":   "order-1",
+		"event_type": "CREATED",
+		"message":    "Order created",
+		"timestamp":  int64(1_700_000_000_000),
+	})
+	require.NoError(t, err)
+
+	var event OrderEvent
+	require.NoError(t, event.DecodeAvro(v1, data))
+
+	assert.Equal(t, OrderEvent{
+		OrderID:       "order-1",
+		EventType:     "CREATED",
+		Message:       "Order created",
+		Timestamp:     time.UnixMilli(1_700_000_000_000).UTC(),
+		SchemaVersion: 1,
+	}, event)
+}
+
+func TestOrderEvent_EncodesForVersionOneReaders(t *testing.T) {
+	v1, err := goavro.NewCodec(orderEventV1)
+	require.NoError(t, err)
+	event := OrderEvent{
+		OrderID:   "order-1",
+		EventType: "CREATED",
+		Timestamp: time.UnixMilli(1_700_000_000_000).UTC(),
+		Order:     &Order{OrderID: "order-1", UserID: "user-1"},
+	}
+
+	data, err := event.EncodeAvro(v1)
+	require.NoError(t, err)
+	var decoded OrderEvent
+	require.NoError(t, decoded.DecodeAvro(v1, data))
+
+	assert.Equal(t, "order-1", decoded.OrderID)
+	assert.Nil(t, decoded.Order, "version 1 has no order")
+}
+
+func TestOrderEvent_DecodeErrors(t *testing.T) {
+	var event OrderEvent
+	assert.ErrorContains(t, event.UnmarshalAvro([]byte{0xff}), "decode OrderEvent")
+
+	partial, err := goavro.NewCodec(`{"type": "record", "name": "OrderEvent", "fields": [{"name": "order_id", "type": "string"}]}`)
+	require.NoError(t, err)
+	data, err := partial.BinaryFromNative(nil, map[string]interface{}{"order_id": "order-1"})
+	require.NoError(t, err)
+	assert.ErrorContains(t, event.DecodeAvro(partial, data), "event_type: missing")
+}
+
+func TestSchemas(t *testing.T) {
+	data, err := Schemas.ReadFile("order_event.avsc")
+	require.NoError(t, err)
+	assert.JSONEq(t, OrderEventSchema, string(data))
+}
+
+func TestOrderEvent_AvroNative(t *testing.T) {
+	event := OrderEvent{OrderID: "order-1", CorrelationID: ptr("req-1"), Order: &Order{UserID: "user-1"}}
+
+	native := event.AvroNative()
+
+	assert.Equal(t, "order-1", native["order_id"])
+	assert.Equal(t, goavro.Union("string", "req-1"), native["correlation_id"])
+	assert.Equal(t, "user-1", native["order"].(map[string]interface{})["com.example.order.Order"].(map[string]interface{})["user_id"])
+}
@@ -0,0 +1,46 @@
+package serde
+
+import (
+	"fmt"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/riferrei/srclient"
+)
+
+// AvroEncoder is a value the Avro serializer accepts, such as the types
+// generated by avrogen.
+type AvroEncoder interface {
+	EncodeAvro(codec *goavro.Codec) ([]byte, error)
+}
+
+// AvroDecoder is a value Avro messages decode into.
+type AvroDecoder interface {
+	DecodeAvro(writer *goavro.Codec, data []byte) error
+}
+
+type avroSerializer struct {
+	schema string
+	codec  *goavro.Codec
+}
+
+// NewAvroSerializer encodes AvroEncoder values with codec, compiled from
+// schema.
+func NewAvroSerializer(schema string, codec *goavro.Codec) Serializer {
+	return &avroSerializer{schema: schema, codec: codec}
+}
+
+func (s *avroSerializer) Format() Format                  { return Avro }
+func (s *avroSerializer) SchemaType() srclient.SchemaType { return srclient.Avro }
+func (s *avroSerializer) Schema() string                  { return s.schema }
+
+func (s *avroSerializer) Serialize(schemaID int, value any) ([]byte, error) {
+	v, ok := value.(AvroEncoder)
+	if !ok {
+		return nil, fmt.Errorf("avro: cannot serialize %T", value)
+	}
+	payload, err := v.EncodeAvro(s.codec)
+	if err != nil {
+		return nil, fmt.Errorf("avro: %w", err)
+	}
+	return append(appendHeader(make([]byte, 0, headerSize+len(payload)), schemaID), payload...), nil
+}
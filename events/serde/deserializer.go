@@ -0,0 +1,123 @@
+package serde
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/riferrei/srclient"
+	"google.golang.org/protobuf/proto"
+)
+
+// Registry looks up registered schemas by ID. *srclient.SchemaRegistryClient
+// implements it.
+type Registry interface {
+	GetSchema(schemaID int) (*srclient.Schema, error)
+}
+
+// Deserializer reads messages of any format, detected from the type of the
+// schema they were written with.
+type Deserializer struct {
+	registry Registry
+
+	mu     sync.Mutex
+	codecs map[int]*goavro.Codec
+}
+
+func NewDeserializer(registry Registry) *Deserializer {
+	return &Deserializer{registry: registry, codecs: make(map[int]*goavro.Codec)}
+}
+
+// Message is a message split into its wire format parts.
+type Message struct {
+	SchemaID int
+	Format   Format
+	Schema   *srclient.Schema
+	// Indexes is the path of the message type in the .proto file of a
+	// protobuf message.
+	Indexes []int
+	Payload []byte
+}
+
+// Parse reads the header of data and looks up the schema it names.
+func (d *Deserializer) Parse(data []byte) (*Message, error) {
+	if len(data) < headerSize {
+		return nil, fmt.Errorf("data too short")
+	}
+	if data[0] != 0 {
+		return nil, fmt.Errorf("unknown magic byte: %v", data[0])
+	}
+	msg := &Message{SchemaID: int(binary.BigEndian.Uint32(data[1:headerSize]))}
+	schema, err := d.registry.GetSchema(msg.SchemaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema for id %d: %w", msg.SchemaID, err)
+	}
+	msg.Schema = schema
+
+	schemaType := srclient.Avro
+	if t := schema.SchemaType(); t != nil && *t != "" {
+		schemaType = *t
+	}
+	format, ok := schemaFormats[schemaType]
+	if !ok {
+		return nil, fmt.Errorf("schema %d has unsupported type %s", msg.SchemaID, schemaType)
+	}
+	msg.Format = format
+
+	msg.Payload = data[headerSize:]
+	if format == Protobuf {
+		if msg.Indexes, msg.Payload, err = readMessageIndexes(msg.Payload); err != nil {
+			return nil, err
+		}
+	}
+	return msg, nil
+}
+
+// DecodeAvro decodes an Avro message into into, resolving the schema it
+// was written with to that of into.
+func (d *Deserializer) DecodeAvro(msg *Message, into AvroDecoder) error {
+	if msg.Format != Avro {
+		return fmt.Errorf("cannot decode a %s message as avro", msg.Format)
+	}
+	codec, err := d.codec(msg)
+	if err != nil {
+		return err
+	}
+	return into.DecodeAvro(codec, msg.Payload)
+}
+
+func (d *Deserializer) codec(msg *Message) (*goavro.Codec, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if codec, ok := d.codecs[msg.SchemaID]; ok {
+		return codec, nil
+	}
+	codec, err := goavro.NewCodec(msg.Schema.Schema())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create codec: %w", err)
+	}
+	d.codecs[msg.SchemaID] = codec
+	return codec, nil
+}
+
+var errWrongMessage = errors.New("protobuf: message indexes do not match")
+
+// DecodeProtobuf decodes a protobuf message into into. The writer's .proto
+// file is not parsed; the message must be at the same place in it as into
+// is in its own file.
+func DecodeProtobuf(msg *Message, into proto.Message) error {
+	if msg.Format != Protobuf {
+		return fmt.Errorf("cannot decode a %s message as protobuf", msg.Format)
+	}
+	want := messageIndexes(into.ProtoReflect().Descriptor())
+	if !slices.Equal(msg.Indexes, want) {
+		return fmt.Errorf("%w: got %v, want %v for %s", errWrongMessage, msg.Indexes, want, into.ProtoReflect().Descriptor().FullName())
+	}
+	if err := proto.Unmarshal(msg.Payload, into); err != nil {
+		return fmt.Errorf("protobuf: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package serde
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/events/orderevents"
+	"github.com/linkedin/goavro/v2"
+	"github.com/riferrei/srclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// fakeRegistry serves schemas by ID. The mock client of srclient compiles
+// every schema as Avro and cannot hold protobuf schemas.
+type fakeRegistry map[int]*srclient.Schema
+
+func (r fakeRegistry) GetSchema(schemaID int) (*srclient.Schema, error) {
+	schema, ok := r[schemaID]
+	if !ok {
+		return nil, fmt.Errorf("schema %d not found", schemaID)
+	}
+	return schema, nil
+}
+
+func (r fakeRegistry) add(t *testing.T, id int, schema string, schemaType srclient.SchemaType) {
+	t.Helper()
+	s, err := srclient.NewSchema(id, schema, schemaType, 1, nil, nil, nil)
+	require.NoError(t, err)
+	r[id] = s
+}
+
+func TestDeserializer_Avro(t *testing.T) {
+	registry := fakeRegistry{}
+	registry.add(t, 1, orderevents.OrderEventSchema, srclient.Avro)
+	codec, err := goavro.NewCodec(orderevents.OrderEventSchema)
+	require.NoError(t, err)
+	s := NewAvroSerializer(orderevents.OrderEventSchema, codec)
+	event := &orderevents.OrderEvent{
+		OrderID: "order-1", EventType: "CREATED", SchemaVersion: 2,
+		Timestamp: time.UnixMilli(1_700_000_000_000).UTC(),
+	}
+
+	data, err := s.Serialize(1, event)
+	require.NoError(t, err)
+	d := NewDeserializer(registry)
+	msg, err := d.Parse(data)
+	require.NoError(t, err)
+	assert.Equal(t, Avro, msg.Format)
+	assert.Equal(t, 1, msg.SchemaID)
+
+	var got orderevents.OrderEvent
+	require.NoError(t, d.DecodeAvro(msg, &got))
+	assert.Equal(t, *event, got)
+	assert.ErrorContains(t, DecodeProtobuf(msg, &structpb.Struct{}), "cannot decode a avro message as protobuf")
+}
+
+// Schemas registered before the registry supported other types have no type.
+func TestDeserializer_UntypedSchemaIsAvro(t *testing.T) {
+	registry := fakeRegistry{}
+	registry.add(t, 1, orderevents.OrderEventSchema, "")
+
+	msg, err := NewDeserializer(registry).Parse([]byte{0, 0, 0, 0, 1})
+	require.NoError(t, err)
+	assert.Equal(t, Avro, msg.Format)
+}
+
+func TestDeserializer_Protobuf(t *testing.T) {
+	registry := fakeRegistry{}
+	registry.add(t, 3, "syntax = \"proto3\";", srclient.Protobuf)
+	s := NewProtobufSerializer("syntax = \"proto3\";", (&structpb.ListValue{}).ProtoReflect().Descriptor())
+	value, err := structpb.NewList([]any{"a", 1.5})
+	require.NoError(t, err)
+
+	data, err := s.Serialize(3, value)
+	require.NoError(t, err)
+	d := NewDeserializer(registry)
+	msg, err := d.Parse(data)
+	require.NoError(t, err)
+	assert.Equal(t, Protobuf, msg.Format)
+	assert.Equal(t, []int{2}, msg.Indexes)
+
+	var got structpb.ListValue
+	require.NoError(t, DecodeProtobuf(msg, &got))
+	assert.True(t, proto.Equal(value, &got))
+	assert.ErrorIs(t, DecodeProtobuf(msg, &structpb.Struct{}), errWrongMessage)
+	assert.ErrorContains(t, d.DecodeAvro(msg, &orderevents.OrderEvent{}), "cannot decode a protobuf message as avro")
+}
+
+func TestDeserializer_Errors(t *testing.T) {
+	registry := fakeRegistry{}
+	registry.add(t, 1, "{}", srclient.Json)
+	registry.add(t, 2, "syntax = \"proto3\";", srclient.Protobuf)
+	d := NewDeserializer(registry)
+
+	_, err := d.Parse([]byte{0, 0})
+	assert.ErrorContains(t, err, "too short")
+	_, err = d.Parse([]byte{1, 0, 0, 0, 1})
+	assert.ErrorContains(t, err, "magic byte")
+	_, err = d.Parse([]byte{0, 0, 0, 0, 9})
+	assert.ErrorContains(t, err, "schema for id 9")
+	_, err = d.Parse([]byte{0, 0, 0, 0, 1})
+	assert.ErrorContains(t, err, "unsupported type JSON")
+	_, err = d.Parse([]byte{0, 0, 0, 0, 2})
+	assert.ErrorIs(t, err, errBadIndexes)
+}
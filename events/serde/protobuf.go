@@ -0,0 +1,103 @@
+package serde
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/riferrei/srclient"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type protobufSerializer struct {
+	schema  string
+	message protoreflect.MessageDescriptor
+	indexes []byte
+}
+
+// NewProtobufSerializer encodes messages of the type message describes.
+// schema is the source of the .proto file that defines it; it must import
+// nothing but the well-known types, which the registry resolves itself.
+func NewProtobufSerializer(schema string, message protoreflect.MessageDescriptor) Serializer {
+	return &protobufSerializer{
+		schema:  schema,
+		message: message,
+		indexes: appendMessageIndexes(nil, messageIndexes(message)),
+	}
+}
+
+func (s *protobufSerializer) Format() Format                  { return Protobuf }
+func (s *protobufSerializer) SchemaType() srclient.SchemaType { return srclient.Protobuf }
+func (s *protobufSerializer) Schema() string                  { return s.schema }
+
+func (s *protobufSerializer) Serialize(schemaID int, value any) ([]byte, error) {
+	m, ok := value.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("protobuf: cannot serialize %T", value)
+	}
+	if name := m.ProtoReflect().Descriptor().FullName(); name != s.message.FullName() {
+		return nil, fmt.Errorf("protobuf: serializer is for %s, got %s", s.message.FullName(), name)
+	}
+	buf := appendHeader(make([]byte, 0, headerSize+len(s.indexes)+proto.Size(m)), schemaID)
+	buf = append(buf, s.indexes...)
+	buf, err := proto.MarshalOptions{}.MarshalAppend(buf, m)
+	if err != nil {
+		return nil, fmt.Errorf("protobuf: %w", err)
+	}
+	return buf, nil
+}
+
+// messageIndexes is the path of message within its file: the index of the
+// top-level message, then that of each nested message down to it.
+func messageIndexes(message protoreflect.MessageDescriptor) []int {
+	var indexes []int
+	var d protoreflect.Descriptor = message
+	for {
+		indexes = append([]int{d.Index()}, indexes...)
+		parent, ok := d.Parent().(protoreflect.MessageDescriptor)
+		if !ok {
+			return indexes
+		}
+		d = parent
+	}
+}
+
+// appendMessageIndexes writes the count of indexes and the indexes as
+// zig-zag varints. The common case, the first message of the file, is
+// written as a single 0.
+func appendMessageIndexes(buf []byte, indexes []int) []byte {
+	if len(indexes) == 1 && indexes[0] == 0 {
+		return append(buf, 0)
+	}
+	buf = binary.AppendVarint(buf, int64(len(indexes)))
+	for _, i := range indexes {
+		buf = binary.AppendVarint(buf, int64(i))
+	}
+	return buf
+}
+
+var errBadIndexes = errors.New("protobuf: invalid message indexes")
+
+// readMessageIndexes reads what appendMessageIndexes wrote and returns the
+// rest of data.
+func readMessageIndexes(data []byte) ([]int, []byte, error) {
+	count, n := binary.Varint(data)
+	if n <= 0 || count < 0 || count > int64(len(data)) {
+		return nil, nil, errBadIndexes
+	}
+	data = data[n:]
+	if count == 0 {
+		return []int{0}, data, nil
+	}
+	indexes := make([]int, count)
+	for i := range indexes {
+		index, n := binary.Varint(data)
+		if n <= 0 || index < 0 {
+			return nil, nil, errBadIndexes
+		}
+		indexes[i] = int(index)
+		data = data[n:]
+	}
+	return indexes, data, nil
+}
@@ -0,0 +1,60 @@
+package serde
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestMessageIndexes(t *testing.T) {
+	for _, tc := range []struct {
+		message protoreflect.MessageDescriptor
+		indexes []int
+		encoded []byte
+	}{
+		{(&structpb.Struct{}).ProtoReflect().Descriptor(), []int{0}, []byte{0}},
+		{(&structpb.ListValue{}).ProtoReflect().Descriptor(), []int{2}, []byte{2, 4}},
+		{(&descriptorpb.DescriptorProto_ExtensionRange{}).ProtoReflect().Descriptor(), []int{2, 0}, []byte{4, 4, 0}},
+	} {
+		t.Run(string(tc.message.Name()), func(t *testing.T) {
+			indexes := messageIndexes(tc.message)
+			assert.Equal(t, tc.indexes, indexes)
+			encoded := appendMessageIndexes(nil, indexes)
+			assert.Equal(t, tc.encoded, encoded)
+
+			read, rest, err := readMessageIndexes(append(encoded, 0xff))
+			require.NoError(t, err)
+			assert.Equal(t, tc.indexes, read)
+			assert.Equal(t, []byte{0xff}, rest)
+		})
+	}
+}
+
+func TestReadMessageIndexes_Invalid(t *testing.T) {
+	for _, data := range [][]byte{nil, {1}, {0x80}, {4, 3}, {2, 1}} {
+		_, _, err := readMessageIndexes(data)
+		assert.ErrorIs(t, err, errBadIndexes, "%v", data)
+	}
+}
+
+func TestProtobufSerializer(t *testing.T) {
+	s := NewProtobufSerializer("syntax = \"proto3\";", (&structpb.ListValue{}).ProtoReflect().Descriptor())
+	assert.Equal(t, Protobuf, s.Format())
+	assert.Equal(t, "PROTOBUF", string(s.SchemaType()))
+
+	value, err := structpb.NewList([]any{"a"})
+	require.NoError(t, err)
+	data, err := s.Serialize(7, value)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0, 7, 2, 4}, data[:7])
+
+	_, err = s.Serialize(7, timestamppb.Now())
+	assert.ErrorContains(t, err, "serializer is for google.protobuf.ListValue, got google.protobuf.Timestamp")
+	_, err = s.Serialize(7, "a")
+	assert.ErrorContains(t, err, "cannot serialize string")
+}
@@ -0,0 +1,80 @@
+// Package serde serializes events in Confluent's wire format, so that any
+// client of the schema registry can read them:
+//
+//	[magic byte (0)] + [4-byte schema ID] + [payload]
+//
+// Protobuf payloads are preceded by the message indexes, the path of the
+// message type within the registered .proto file.
+//
+// The format of a topic is chosen per topic in configuration. A subject
+// holds schemas of one type only, so switching the format of a topic
+// means publishing under a new subject or to a new topic.
+package serde
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/riferrei/srclient"
+)
+
+// Format is the serialization format of a topic.
+type Format string
+
+const (
+	Avro     Format = "avro"
+	Protobuf Format = "protobuf"
+)
+
+// ParseFormat parses "avro" or "protobuf".
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case Avro, Protobuf:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown format %q, want %s or %s", s, Avro, Protobuf)
+}
+
+// ParseTopicFormats parses "topic=format" entries, e.g. "order-events=protobuf".
+func ParseTopicFormats(entries []string) (map[string]Format, error) {
+	formats := make(map[string]Format, len(entries))
+	for _, entry := range entries {
+		topic, value, ok := strings.Cut(entry, "=")
+		topic = strings.TrimSpace(topic)
+		if !ok || topic == "" {
+			return nil, fmt.Errorf("invalid topic format %q, want topic=format", entry)
+		}
+		format, err := ParseFormat(value)
+		if err != nil {
+			return nil, fmt.Errorf("topic %s: %w", topic, err)
+		}
+		formats[topic] = format
+	}
+	return formats, nil
+}
+
+// schemaFormats maps the schema types of the registry to formats. The
+// registry leaves the type out for Avro schemas.
+var schemaFormats = map[srclient.SchemaType]Format{
+	srclient.Avro:     Avro,
+	srclient.Protobuf: Protobuf,
+}
+
+// Serializer encodes the values of a topic.
+type Serializer interface {
+	Format() Format
+	// SchemaType and Schema are what to register for the topic's subject.
+	SchemaType() srclient.SchemaType
+	Schema() string
+	// Serialize encodes value in the wire format, under the ID the registry
+	// gave Schema.
+	Serialize(schemaID int, value any) ([]byte, error)
+}
+
+const headerSize = 5
+
+func appendHeader(buf []byte, schemaID int) []byte {
+	buf = append(buf, 0)
+	return binary.BigEndian.AppendUint32(buf, uint32(schemaID))
+}
@@ -0,0 +1,29 @@
+package serde
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTopicFormats(t *testing.T) {
+	formats, err := ParseTopicFormats([]string{"order-events=protobuf", " audit = Avro "})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Format{"order-events": Protobuf, "audit": Avro}, formats)
+
+	formats, err = ParseTopicFormats(nil)
+	require.NoError(t, err)
+	assert.Empty(t, formats)
+}
+
+func TestParseTopicFormats_Invalid(t *testing.T) {
+	for entry, want := range map[string]string{
+		"order-events":      "want topic=format",
+		"=protobuf":         "want topic=format",
+		"order-events=json": `topic order-events: unknown format "json"`,
+	} {
+		_, err := ParseTopicFormats([]string{entry})
+		assert.ErrorContains(t, err, want, entry)
+	}
+}
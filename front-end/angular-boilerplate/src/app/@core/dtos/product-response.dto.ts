@@ -1,10 +1,15 @@
 import { z } from 'zod';
 import { BaseResponseSchema } from './base.dto';
 
+export const MoneySchema = z.object({
+  amount: z.number().int(),
+  currency: z.string().length(3),
+});
+
 export const ProductSchema = z.object({
   id: z.string(),
   name: z.string(),
-  price: z.number(),
+  price: MoneySchema,
   quantity: z.number(),
 });
 
@@ -1,7 +1,13 @@
+export interface Money {
+  // amount is in the currency's minor unit, e.g. cents.
+  amount: number;
+  currency: string;
+}
+
 export class ProductEntity {
   id: string;
   name: string;
-  price: number;
+  price: Money;
   quantity: number;
 
   get inStock(): boolean {
@@ -12,6 +18,12 @@ export class ProductEntity {
     return this.name;
   }
 
+  get displayPrice(): string {
+    const format = new Intl.NumberFormat(undefined, { style: 'currency', currency: this.price.currency });
+    const digits = format.resolvedOptions().maximumFractionDigits ?? 2;
+    return format.format(this.price.amount / 10 ** digits);
+  }
+
   constructor(partial: Partial<ProductEntity>) {
     Object.assign(this, partial);
   }
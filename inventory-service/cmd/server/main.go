@@ -1,41 +1,100 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	fiber_http "inventory-service/internal/adapters/fiber"
 	inventoryGrpc "inventory-service/internal/adapters/grpc"
 	"inventory-service/internal/adapters/repository"
+	"inventory-service/internal/clients"
+	"inventory-service/internal/config"
 	"inventory-service/internal/usecases"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/joho/godotenv"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/apikey"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/apikey/fiberapikey"
+	platformconfig "github.com/jakkapat-chongsuwat/go-microservice/platform/config"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health/fiberhealth"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health/grpchealth"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lifecycle"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging/fiberlog"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging/grpclog"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics/fibermetrics"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics/gormmetrics"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics/grpcmetrics"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry/fibertrace"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry/grpctrace"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/inventory_service"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/user_service"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 func main() {
-	loadEnv()
+	configFile := flag.String("config", "", "path to a YAML config file")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration, secrets redacted, and exit")
+	flag.Parse()
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *printConfig {
+		if err := platformconfig.Print(os.Stdout, cfg); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
 	logger := createLogger()
 	defer logger.Sync()
+	logger.Info("Configuration loaded", zap.Any("config", platformconfig.Values(cfg)))
+
+	runner := lifecycle.NewRunner(logger, cfg.ShutdownTimeout)
+	runner.OnStop("tracing", setupTracing(logger))
 
-	inventoryRepo := buildRepository(logger)
+	checker := health.NewChecker(0)
+	inventoryRepo := buildRepository(cfg, logger, checker, runner)
 	inventoryUseCase := usecases.NewInventoryUsecase(inventoryRepo, logger)
 
-	go startGRPC(logger, inventoryUseCase)
-	startHTTP(logger, inventoryUseCase)
+	healthServer := grpchealth.NewServer()
+	runner.Go("health sync", func(ctx context.Context) error {
+		grpchealth.Sync(ctx, healthServer, checker, 0, inventory_service.InventoryService_ServiceDesc.ServiceName)
+		return nil
+	})
+	runner.Add("grpc", newGRPCServer(cfg.GRPCPort, logger, inventoryUseCase, healthServer))
+	runner.Add("http", newHTTPServer(cfg.HTTPPort, logger, inventoryUseCase, checker, buildAPIKeyAuth(cfg.APIKeys, logger, runner)))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := runner.Run(ctx); err != nil {
+		logger.Error("Inventory service stopped with errors", zap.Error(err))
+		logger.Sync()
+		os.Exit(1)
+	}
+	logger.Info("Inventory service stopped")
 }
 
-func loadEnv() {
-	if err := godotenv.Load(".env"); err != nil {
-		log.Println("Warning: no .env file found, proceeding...")
+func setupTracing(logger *zap.Logger) func(context.Context) error {
+	shutdown, err := telemetry.Setup(context.Background(), telemetry.ConfigFromEnv("inventory-service"))
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", zap.Error(err))
 	}
+	return shutdown
 }
 
 func createLogger() *zap.Logger {
@@ -46,46 +105,44 @@ func createLogger() *zap.Logger {
 	return logger
 }
 
-func buildRepository(logger *zap.Logger) usecases.InventoryRepository {
-	repoType := getEnv("REPO_TYPE", "gorm")
-	switch repoType {
+func buildRepository(cfg *config.Config, logger *zap.Logger, checker *health.Checker, runner *lifecycle.Runner) usecases.InventoryRepository {
+	switch cfg.RepoType {
 	case "gorm":
-		return buildGormRepo(logger)
+		return buildGormRepo(cfg.Database, logger, checker, runner)
 	default:
-		return buildGormRepo(logger)
+		return buildGormRepo(cfg.Database, logger, checker, runner)
 	}
 }
 
-func buildGormRepo(logger *zap.Logger) usecases.InventoryRepository {
-	dbDriver := getEnv("DB_DRIVER", "postgres")
-	db, err := connectGorm(dbDriver, logger)
+func buildGormRepo(dbConfig platformconfig.Database, logger *zap.Logger, checker *health.Checker, runner *lifecycle.Runner) usecases.InventoryRepository {
+	db, err := connectGorm(dbConfig, logger)
 	if err != nil {
 		logger.Fatal("Failed to connect GORM DB", zap.Error(err))
 	}
+	if err := db.Use(gormmetrics.Plugin{}); err != nil {
+		logger.Fatal("Failed to install GORM metrics", zap.Error(err))
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		logger.Fatal("Failed to get database handle", zap.Error(err))
+	}
+	checker.Register("database", health.Ping(sqlDB))
+	runner.OnStop("database", lifecycle.Close(sqlDB))
 	return repository.NewGormInventoryRepo(db, logger)
 }
 
-func connectGorm(driver string, logger *zap.Logger) (*gorm.DB, error) {
-	switch driver {
+func connectGorm(dbConfig platformconfig.Database, logger *zap.Logger) (*gorm.DB, error) {
+	switch dbConfig.Driver {
 	case "mysql":
-		return connectMySQL(logger)
+		return connectMySQL(dbConfig, logger)
 	default:
-		return connectPostgres(logger)
+		return connectPostgres(dbConfig, logger)
 	}
 }
 
-func connectPostgres(logger *zap.Logger) (*gorm.DB, error) {
-	host := getEnv("DB_HOST", "localhost")
-	port := getEnv("DB_PORT", "5432")
-	user := getEnv("DB_USER", "devuser")
-	pass := getEnv("DB_PASS", "devpass")
-	dbname := getEnv("DB_NAME", "inventory_service")
-	dbschema := getEnv("DB_SCHEMA", "public")
-	sslmode := getEnv("DB_SSLMODE", "disable")
-
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s search_path=%s",
-		host, port, user, pass, dbname, sslmode, dbschema)
-	logger.Info("Connecting to Postgres", zap.String("dsn", dsn))
+func connectPostgres(dbConfig platformconfig.Database, logger *zap.Logger) (*gorm.DB, error) {
+	dsn := dbConfig.DSN()
+	logger.Info("Connecting to Postgres", zap.Stringer("database", dbConfig))
 
 	var db *gorm.DB
 	var err error
@@ -100,42 +157,57 @@ func connectPostgres(logger *zap.Logger) (*gorm.DB, error) {
 	return nil, fmt.Errorf("failed to connect to Postgres after multiple attempts: %w", err)
 }
 
-func connectMySQL(logger *zap.Logger) (*gorm.DB, error) {
-	host := getEnv("DB_HOST", "localhost")
-	port := getEnv("DB_PORT", "3306")
-	user := getEnv("DB_USER", "root")
-	pass := getEnv("DB_PASS", "")
-	dbname := getEnv("DB_NAME", "inventory_service")
-
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", user, pass, host, port, dbname)
-	logger.Info("Connecting to MySQL", zap.String("dsn", dsn))
+func connectMySQL(dbConfig platformconfig.Database, logger *zap.Logger) (*gorm.DB, error) {
+	dsn := dbConfig.DSN()
+	logger.Info("Connecting to MySQL", zap.Stringer("database", dbConfig))
 
 	return gorm.Open(mysql.Open(dsn), &gorm.Config{})
 }
 
-func startGRPC(logger *zap.Logger, uc usecases.InventoryUseCase) {
-	port := getEnv("GRPC_PORT", "30051")
-	if err := inventoryGrpc.StartGRPCServer(port, uc, logger); err != nil {
-		logger.Fatal("failed to start gRPC server", zap.Error(err))
+func newGRPCServer(port int, logger *zap.Logger, uc usecases.InventoryUseCase, healthServer grpc_health_v1.HealthServer) lifecycle.Component {
+	opts := append(grpcmetrics.ServerOptions(), grpctrace.ServerOption())
+	opts = append(opts, grpclog.ServerOptions(logger)...)
+	srv := inventoryGrpc.NewGRPCServer(uc, logger, healthServer, opts...)
+	logger.Info("Starting gRPC server", zap.Int("port", port))
+	return lifecycle.GRPC(srv, fmt.Sprintf(":%d", port))
+}
+
+// buildAPIKeyAuth returns the middleware that checks partner API keys on
+// /api, or nil when keys are not verified.
+func buildAPIKeyAuth(cfg config.APIKeys, logger *zap.Logger, runner *lifecycle.Runner) fiber.Handler {
+	if !cfg.Enabled() {
+		logger.Info("API keys are not verified, USER_SERVICE_ADDRESS is not set")
+		return nil
 	}
+	conn, err := grpc.NewClient(cfg.UserServiceAddress,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpctrace.DialOption(),
+		grpcmetrics.DialOption(),
+		grpclog.DialOption(),
+	)
+	if err != nil {
+		logger.Fatal("Invalid gRPC target", zap.String("address", cfg.UserServiceAddress), zap.Error(err))
+	}
+	runner.OnStop("user-service connection", lifecycle.Close(conn))
+
+	verifier := clients.NewAPIKeyVerifier(user_service.NewUserServiceClient(conn), cfg.VerifyTimeout)
+	return fiberapikey.Middleware(apikey.NewCache(verifier, cfg.CacheTTL), logger, fiberapikey.Options{
+		Required: cfg.Required,
+		Scope:    fiberapikey.MethodScopes(apikey.ScopeInventoryRead, apikey.ScopeInventoryWrite),
+	})
 }
 
-func startHTTP(logger *zap.Logger, uc usecases.InventoryUseCase) {
+func newHTTPServer(port int, logger *zap.Logger, uc usecases.InventoryUseCase, checker *health.Checker, apiKeyAuth fiber.Handler) lifecycle.Component {
 	app := fiber.New()
+	app.Use(fibertrace.Middleware(), fiberlog.Middleware(logger), fibermetrics.Middleware())
+	app.Get("/metrics", fibermetrics.Handler())
+	fiberhealth.Register(app, checker)
+	if apiKeyAuth != nil {
+		app.Use("/api", apiKeyAuth)
+	}
 	handler := fiber_http.NewInventoryHTTPHandler(uc, logger)
 	fiber_http.RegisterInventoryRoutes(app, handler)
 
-	port := getEnv("HTTP_PORT", "30052")
-	logger.Info("Starting Inventory HTTP server", zap.String("port", port))
-	if err := app.Listen(":" + port); err != nil {
-		logger.Fatal("failed to start HTTP server", zap.Error(err))
-	}
-}
-
-func getEnv(key, fallback string) string {
-	val := os.Getenv(key)
-	if val == "" {
-		return fallback
-	}
-	return val
+	logger.Info("Starting Inventory HTTP server", zap.Int("port", port))
+	return lifecycle.Fiber(app, fmt.Sprintf(":%d", port))
 }
@@ -9,21 +9,56 @@ table "public" "products" {
     default = sql("gen_random_uuid()")
   }
 
+  column "sku" {
+    type = varchar(64)
+    null = false
+  }
+
   column "name" {
     type = varchar(255)
     null = false
   }
 
+  column "description" {
+    type    = text
+    null    = false
+    default = ""
+  }
+
+  column "category" {
+    type    = varchar(255)
+    null    = false
+    default = ""
+  }
+
+  column "attributes" {
+    type    = jsonb
+    null    = false
+    default = sql("'{}'::jsonb")
+  }
+
+  column "status" {
+    type    = varchar(16)
+    null    = false
+    default = "ACTIVE"
+  }
+
   column "quantity" {
     type = int
     null = false
   }
 
-  column "price" {
-    type = numeric
+  column "price_amount" {
+    type = bigint
     null = false
   }
 
+  column "price_currency" {
+    type    = varchar(3)
+    null    = false
+    default = "USD"
+  }
+
   column "created_at" {
     type    = timestamp
     null    = false
@@ -39,6 +74,98 @@ table "public" "products" {
   primary_key {
     columns = [column.id]
   }
+
+  check "products_price_amount_check" {
+    expr = "(price_amount >= 0)"
+  }
+
+  index "products_sku_key" {
+    unique  = true
+    columns = [column.sku]
+  }
+
+  index "products_category_idx" {
+    on {
+      column = column.category
+      ops    = varchar_pattern_ops
+    }
+  }
+
+  index "products_status_idx" {
+    columns = [column.status]
+  }
+
+  index "products_search_idx" {
+    type = GIN
+    on {
+      expr = "(setweight(to_tsvector('simple', name), 'A') || setweight(to_tsvector('simple', sku), 'A') || setweight(to_tsvector('simple', description), 'B'))"
+    }
+  }
+}
+
+table "public" "product_prices" {
+  schema = schema.public
+
+  column "id" {
+    type    = varchar(255)
+    null    = false
+    default = sql("gen_random_uuid()")
+  }
+
+  column "product_id" {
+    type = varchar(255)
+    null = false
+  }
+
+  column "price_amount" {
+    type = bigint
+    null = false
+  }
+
+  column "price_currency" {
+    type = varchar(3)
+    null = false
+  }
+
+  column "effective_from" {
+    type = timestamptz
+    null = false
+  }
+
+  column "effective_to" {
+    type = timestamptz
+    null = true
+  }
+
+  column "created_at" {
+    type    = timestamp
+    null    = false
+    default = sql("CURRENT_TIMESTAMP")
+  }
+
+  primary_key {
+    columns = [column.id]
+  }
+
+  foreign_key "product_prices_product_id_fkey" {
+    columns     = [column.product_id]
+    ref_columns = [table.public.products.column.id]
+    on_update   = NO_ACTION
+    on_delete   = CASCADE
+  }
+
+  check "product_prices_price_amount_check" {
+    expr = "(price_amount >= 0)"
+  }
+
+  check "product_prices_effective_range_check" {
+    expr = "((effective_to IS NULL) OR (effective_to > effective_from))"
+  }
+
+  index "product_prices_product_id_effective_from_key" {
+    unique  = true
+    columns = [column.product_id, column.effective_from]
+  }
 }
 
 function "set_updated_at" {
@@ -1,8 +1,20 @@
 package columns
 
 const (
-	ColumnID       = "id"
-	ColumnName     = "name"
-	ColumnQuantity = "quantity"
-	ColumnPrice    = "price"
+	ColumnID            = "id"
+	ColumnSKU           = "sku"
+	ColumnName          = "name"
+	ColumnDescription   = "description"
+	ColumnCategory      = "category"
+	ColumnAttributes    = "attributes"
+	ColumnStatus        = "status"
+	ColumnQuantity      = "quantity"
+	ColumnPriceAmount   = "price_amount"
+	ColumnPriceCurrency = "price_currency"
+)
+
+const (
+	ColumnProductID     = "product_id"
+	ColumnEffectiveFrom = "effective_from"
+	ColumnEffectiveTo   = "effective_to"
 )
@@ -1,11 +1,15 @@
 package fiber_http
 
 import (
+	"errors"
 	"inventory-service/internal/adapters/mappers"
 	"inventory-service/internal/adapters/models"
+	"inventory-service/internal/domain"
 	"inventory-service/internal/usecases"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"go.uber.org/zap"
 )
 
@@ -21,23 +25,31 @@ func NewInventoryHTTPHandler(inventoryUC usecases.InventoryUseCase, logger *zap.
 	}
 }
 
+// log returns the request-scoped logger of c.
+func (h *InventoryHTTPHandler) log(c *fiber.Ctx) *zap.Logger {
+	return logging.FromContext(c.UserContext(), h.logger)
+}
+
 func (h *InventoryHTTPHandler) CreateProduct(c *fiber.Ctx) error {
-	h.logger.Info("CreateProduct endpoint called")
 	var req models.CreateProductRequest
 	if err := c.BodyParser(&req); err != nil {
-		h.logger.Error("failed to parse request", zap.Error(err))
+		h.log(c).Error("failed to parse request", zap.Error(err))
 		return c.Status(fiber.StatusBadRequest).
 			JSON(fiber.Map{"error": "Invalid request payload"})
 	}
-	if req.Name == "" || req.Quantity < 0 || req.Price < 0 {
+	if req.Name == "" || req.Quantity < 0 {
 		return c.Status(fiber.StatusBadRequest).
 			JSON(fiber.Map{"error": "Invalid product data"})
 	}
 
-	product := mappers.MapCreateProductRequestToProduct(req)
-	created, err := h.inventoryUseCase.CreateProduct(c.Context(), product)
+	product, err := mappers.MapCreateProductRequestToProduct(req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": err.Error()})
+	}
+	created, err := h.inventoryUseCase.CreateProduct(c.UserContext(), product)
 	if err != nil {
-		h.logger.Error("failed to create product", zap.Error(err))
+		h.log(c).Error("failed to create product", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).
 			JSON(fiber.Map{"error": "failed to create product"})
 	}
@@ -52,9 +64,39 @@ func (h *InventoryHTTPHandler) GetProduct(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).
 			JSON(fiber.Map{"error": "Product ID is required"})
 	}
-	product, err := h.inventoryUseCase.GetProduct(c.Context(), id)
+	var (
+		product *domain.Product
+		err     error
+	)
+	if raw := c.Query("asOf"); raw != "" {
+		asOf, parseErr := time.Parse(time.RFC3339, raw)
+		if parseErr != nil {
+			return c.Status(fiber.StatusBadRequest).
+				JSON(fiber.Map{"error": "asOf must be an RFC 3339 timestamp"})
+		}
+		product, err = h.inventoryUseCase.GetProductAsOf(c.UserContext(), id, asOf)
+	} else {
+		product, err = h.inventoryUseCase.GetProduct(c.UserContext(), id)
+	}
+	if err != nil {
+		h.log(c).Error("failed to get product", zap.String("id", id), zap.Error(err))
+		return c.Status(fiber.StatusNotFound).
+			JSON(fiber.Map{"error": "Product not found"})
+	}
+
+	responseDto := mappers.MapProductToProductResponse(product)
+	return c.JSON(responseDto)
+}
+
+func (h *InventoryHTTPHandler) GetProductBySKU(c *fiber.Ctx) error {
+	sku := c.Params("sku")
+	if sku == "" {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "Product SKU is required"})
+	}
+	product, err := h.inventoryUseCase.GetProductBySKU(c.UserContext(), sku)
 	if err != nil {
-		h.logger.Error("failed to get product", zap.String("id", id), zap.Error(err))
+		h.log(c).Error("failed to get product by SKU", zap.String("sku", sku), zap.Error(err))
 		return c.Status(fiber.StatusNotFound).
 			JSON(fiber.Map{"error": "Product not found"})
 	}
@@ -71,21 +113,24 @@ func (h *InventoryHTTPHandler) UpdateProductMetadata(c *fiber.Ctx) error {
 	}
 	var req models.UpdateProductMetadataRequest
 	if err := c.BodyParser(&req); err != nil {
-		h.logger.Error("failed to parse request", zap.Error(err))
+		h.log(c).Error("failed to parse request", zap.Error(err))
 		return c.Status(fiber.StatusBadRequest).
 			JSON(fiber.Map{"error": "Invalid request payload"})
 	}
-	product, err := h.inventoryUseCase.GetProduct(c.Context(), id)
+	product, err := h.inventoryUseCase.GetProduct(c.UserContext(), id)
 	if err != nil {
-		h.logger.Error("failed to get product", zap.String("id", id), zap.Error(err))
+		h.log(c).Error("failed to get product", zap.String("id", id), zap.Error(err))
 		return c.Status(fiber.StatusNotFound).
 			JSON(fiber.Map{"error": "Product not found"})
 	}
 
-	mappers.MapUpdateProductMetadataRequestToProduct(req, product)
-	updated, err := h.inventoryUseCase.UpdateProductMetadata(c.Context(), product)
+	if err := mappers.MapUpdateProductMetadataRequestToProduct(req, product); err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": err.Error()})
+	}
+	updated, err := h.inventoryUseCase.UpdateProductMetadata(c.UserContext(), product)
 	if err != nil {
-		h.logger.Error("failed to update product metadata", zap.String("id", id), zap.Error(err))
+		h.log(c).Error("failed to update product metadata", zap.String("id", id), zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).
 			JSON(fiber.Map{"error": "failed to update product metadata"})
 	}
@@ -102,13 +147,13 @@ func (h *InventoryHTTPHandler) UpdateProductStockQuantity(c *fiber.Ctx) error {
 	}
 	var req models.UpdateProductStockQuantityRequest
 	if err := c.BodyParser(&req); err != nil {
-		h.logger.Error("failed to parse request", zap.Error(err))
+		h.log(c).Error("failed to parse request", zap.Error(err))
 		return c.Status(fiber.StatusBadRequest).
 			JSON(fiber.Map{"error": "Invalid request payload"})
 	}
-	updated, err := h.inventoryUseCase.UpdateProductStockQuantity(c.Context(), id, req.QuantityChange)
+	updated, err := h.inventoryUseCase.UpdateProductStockQuantity(c.UserContext(), id, req.QuantityChange)
 	if err != nil {
-		h.logger.Error("failed to update product stock quantity", zap.String("id", id), zap.Error(err))
+		h.log(c).Error("failed to update product stock quantity", zap.String("id", id), zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).
 			JSON(fiber.Map{"error": "failed to update product stock quantity"})
 	}
@@ -118,9 +163,18 @@ func (h *InventoryHTTPHandler) UpdateProductStockQuantity(c *fiber.Ctx) error {
 }
 
 func (h *InventoryHTTPHandler) ListProducts(c *fiber.Ctx) error {
-	products, err := h.inventoryUseCase.ListProducts(c.Context())
+	filter := domain.ProductFilter{Category: c.Query("category")}
+	if raw := c.Query("status"); raw != "" {
+		status, err := domain.ParseProductStatus(raw)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).
+				JSON(fiber.Map{"error": err.Error()})
+		}
+		filter.Status = status
+	}
+	products, err := h.inventoryUseCase.ListProducts(c.UserContext(), filter)
 	if err != nil {
-		h.logger.Error("failed to list products", zap.Error(err))
+		h.log(c).Error("failed to list products", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).
 			JSON(fiber.Map{"error": "failed to list products"})
 	}
@@ -136,11 +190,41 @@ func (h *InventoryHTTPHandler) ListProducts(c *fiber.Ctx) error {
 	return c.JSON(res)
 }
 
+func (h *InventoryHTTPHandler) SearchProducts(c *fiber.Ctx) error {
+	q := c.Query("q")
+	results, err := h.inventoryUseCase.SearchProducts(c.UserContext(), q, c.QueryInt("limit"))
+	if errors.Is(err, domain.ErrEmptySearchQuery) {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "Query parameter q is required"})
+	}
+	if err != nil {
+		h.log(c).Error("failed to search products", zap.String("q", q), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(fiber.Map{"error": "failed to search products"})
+	}
+	dtos := make([]models.ProductSearchResultResponse, 0, len(results))
+	for _, result := range results {
+		dtos = append(dtos, mappers.MapSearchResultToResponse(result))
+	}
+
+	var res = models.NewResponse(dtos, &models.Meta{
+		Total: len(dtos),
+	})
+
+	return c.JSON(res)
+}
+
 func RegisterInventoryRoutes(app *fiber.App, handler *InventoryHTTPHandler) {
 	api := app.Group("/api")
 	api.Post("/products", handler.CreateProduct)
 	api.Get("/products", handler.ListProducts)
+	api.Post("/products\\:import", handler.ImportProducts)
+	api.Get("/products\\:export", handler.ExportProducts)
+	api.Get("/products/search", handler.SearchProducts)
+	api.Get("/products/by-sku/:sku", handler.GetProductBySKU)
 	api.Get("/products/:id", handler.GetProduct)
 	api.Put("/products/:id/metadata", handler.UpdateProductMetadata)
 	api.Patch("/products/:id/quantity", handler.UpdateProductStockQuantity)
+	api.Post("/products/:id/prices", handler.ScheduleProductPrice)
+	api.Get("/products/:id/prices", handler.ListProductPrices)
 }
@@ -5,9 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"inventory-service/internal/adapters/models"
 	"inventory-service/internal/domain"
@@ -22,9 +24,16 @@ import (
 type FakeInventoryUseCase struct {
 	CreateProductFunc              func(ctx context.Context, product *domain.Product) (*domain.Product, error)
 	GetProductFunc                 func(ctx context.Context, productID string) (*domain.Product, error)
+	GetProductAsOfFunc             func(ctx context.Context, productID string, asOf time.Time) (*domain.Product, error)
+	GetProductBySKUFunc            func(ctx context.Context, sku string) (*domain.Product, error)
 	UpdateProductMetadataFunc      func(ctx context.Context, product *domain.Product) (*domain.Product, error)
 	UpdateProductStockQuantityFunc func(ctx context.Context, productID string, quantityChange int) (*domain.Product, error)
-	ListProductsFunc               func(ctx context.Context) ([]*domain.Product, error)
+	ListProductsFunc               func(ctx context.Context, filter domain.ProductFilter) ([]*domain.Product, error)
+	SearchProductsFunc             func(ctx context.Context, text string, limit int) ([]*domain.ProductSearchResult, error)
+	ImportProductsFunc             func(ctx context.Context, r io.Reader, opts domain.ImportOptions) (*domain.ImportResult, error)
+	ExportProductsFunc             func(ctx context.Context, w io.Writer, format domain.ProductFormat) error
+	ScheduleProductPriceFunc       func(ctx context.Context, productID string, price domain.Money, effectiveFrom time.Time) (*domain.ProductPrice, error)
+	ListProductPricesFunc          func(ctx context.Context, productID string) ([]*domain.ProductPrice, error)
 }
 
 var _ usecases.InventoryUseCase = (*FakeInventoryUseCase)(nil)
@@ -37,6 +46,22 @@ func (f *FakeInventoryUseCase) GetProduct(ctx context.Context, productID string)
 	return f.GetProductFunc(ctx, productID)
 }
 
+func (f *FakeInventoryUseCase) GetProductAsOf(ctx context.Context, productID string, asOf time.Time) (*domain.Product, error) {
+	return f.GetProductAsOfFunc(ctx, productID, asOf)
+}
+
+func (f *FakeInventoryUseCase) ScheduleProductPrice(ctx context.Context, productID string, price domain.Money, effectiveFrom time.Time) (*domain.ProductPrice, error) {
+	return f.ScheduleProductPriceFunc(ctx, productID, price, effectiveFrom)
+}
+
+func (f *FakeInventoryUseCase) ListProductPrices(ctx context.Context, productID string) ([]*domain.ProductPrice, error) {
+	return f.ListProductPricesFunc(ctx, productID)
+}
+
+func (f *FakeInventoryUseCase) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	return f.GetProductBySKUFunc(ctx, sku)
+}
+
 func (f *FakeInventoryUseCase) UpdateProductMetadata(ctx context.Context, product *domain.Product) (*domain.Product, error) {
 	return f.UpdateProductMetadataFunc(ctx, product)
 }
@@ -45,8 +70,20 @@ func (f *FakeInventoryUseCase) UpdateProductStockQuantity(ctx context.Context, p
 	return f.UpdateProductStockQuantityFunc(ctx, productID, quantityChange)
 }
 
-func (f *FakeInventoryUseCase) ListProducts(ctx context.Context) ([]*domain.Product, error) {
-	return f.ListProductsFunc(ctx)
+func (f *FakeInventoryUseCase) ListProducts(ctx context.Context, filter domain.ProductFilter) ([]*domain.Product, error) {
+	return f.ListProductsFunc(ctx, filter)
+}
+
+func (f *FakeInventoryUseCase) SearchProducts(ctx context.Context, text string, limit int) ([]*domain.ProductSearchResult, error) {
+	return f.SearchProductsFunc(ctx, text, limit)
+}
+
+func (f *FakeInventoryUseCase) ImportProducts(ctx context.Context, r io.Reader, opts domain.ImportOptions) (*domain.ImportResult, error) {
+	return f.ImportProductsFunc(ctx, r, opts)
+}
+
+func (f *FakeInventoryUseCase) ExportProducts(ctx context.Context, w io.Writer, format domain.ProductFormat) error {
+	return f.ExportProductsFunc(ctx, w, format)
 }
 
 func TestCreateProduct_Success(t *testing.T) {
@@ -64,7 +101,7 @@ func TestCreateProduct_Success(t *testing.T) {
 	reqPayload := models.CreateProductRequest{
 		Name:     "Widget",
 		Quantity: 100,
-		Price:    9.99,
+		Price:    models.MoneyDTO{Amount: 999, Currency: "USD"},
 	}
 	body, err := json.Marshal(reqPayload)
 	assert.NoError(t, err)
@@ -102,6 +139,20 @@ func TestCreateProduct_InvalidPayload(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
+func TestCreateProduct_NegativePrice(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	app := fiber.New()
+	handler := NewInventoryHTTPHandler(&FakeInventoryUseCase{}, logger)
+	RegisterInventoryRoutes(app, handler)
+
+	body := []byte(`{"name":"Widget","quantity":1,"price":{"amount":-100,"currency":"USD"}}`)
+	req := httptest.NewRequest("POST", "/api/products", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
 func TestGetProduct_Success(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	fakeUC := &FakeInventoryUseCase{
@@ -110,7 +161,7 @@ func TestGetProduct_Success(t *testing.T) {
 				ID:       productID,
 				Name:     "Widget",
 				Quantity: 100,
-				Price:    9.99,
+				Price:    domain.Money{Amount: 999, Currency: "USD"},
 			}, nil
 		},
 	}
@@ -130,6 +181,39 @@ func TestGetProduct_Success(t *testing.T) {
 	assert.Equal(t, "Widget", productResp.Name)
 }
 
+func TestGetProductBySKU_Success(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	fakeUC := &FakeInventoryUseCase{
+		GetProductBySKUFunc: func(ctx context.Context, sku string) (*domain.Product, error) {
+			return &domain.Product{
+				ID:         "prod123",
+				SKU:        sku,
+				Name:       "Widget",
+				Category:   "hardware/widgets",
+				Attributes: domain.Attributes{"color": domain.StringAttribute("red"), "weight": domain.NumberAttribute(1.5)},
+				Status:     domain.ProductStatusActive,
+			}, nil
+		},
+	}
+	app := fiber.New()
+	handler := NewInventoryHTTPHandler(fakeUC, logger)
+	RegisterInventoryRoutes(app, handler)
+
+	req := httptest.NewRequest("GET", "/api/products/by-sku/WID-001", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var productResp models.ProductResponse
+	err = json.NewDecoder(resp.Body).Decode(&productResp)
+	assert.NoError(t, err)
+	assert.Equal(t, "prod123", productResp.ID)
+	assert.Equal(t, "WID-001", productResp.SKU)
+	assert.Equal(t, "hardware/widgets", productResp.Category)
+	assert.Equal(t, "ACTIVE", productResp.Status)
+	assert.Equal(t, map[string]interface{}{"color": "red", "weight": 1.5}, productResp.Attributes)
+}
+
 func TestGetProduct_NotFound(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	fakeUC := &FakeInventoryUseCase{
@@ -155,7 +239,7 @@ func TestUpdateProductMetadata_Success(t *testing.T) {
 				ID:       productID,
 				Name:     "Widget",
 				Quantity: 100,
-				Price:    9.99,
+				Price:    domain.Money{Amount: 999, Currency: "USD"},
 			}, nil
 		},
 		UpdateProductMetadataFunc: func(ctx context.Context, product *domain.Product) (*domain.Product, error) {
@@ -168,7 +252,7 @@ func TestUpdateProductMetadata_Success(t *testing.T) {
 
 	reqPayload := models.UpdateProductMetadataRequest{
 		Name:  "Updated Widget",
-		Price: 12.99,
+		Price: models.MoneyDTO{Amount: 1299, Currency: "USD"},
 	}
 	body, err := json.Marshal(reqPayload)
 	assert.NoError(t, err)
@@ -187,6 +271,25 @@ func TestUpdateProductMetadata_Success(t *testing.T) {
 	assert.Equal(t, reqPayload.Price, updatedResp.Price)
 }
 
+func TestUpdateProductMetadata_InvalidAttributes(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	fakeUC := &FakeInventoryUseCase{
+		GetProductFunc: func(ctx context.Context, productID string) (*domain.Product, error) {
+			return &domain.Product{ID: productID, Name: "Widget"}, nil
+		},
+	}
+	app := fiber.New()
+	handler := NewInventoryHTTPHandler(fakeUC, logger)
+	RegisterInventoryRoutes(app, handler)
+
+	body := []byte(`{"name":"Widget","price":1,"attributes":{"dims":{"w":1}}}`)
+	req := httptest.NewRequest("PUT", "/api/products/prod123/metadata", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
 func TestUpdateProductMetadata_Failure(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	fakeUC := &FakeInventoryUseCase{
@@ -195,7 +298,7 @@ func TestUpdateProductMetadata_Failure(t *testing.T) {
 				ID:       productID,
 				Name:     "Widget",
 				Quantity: 100,
-				Price:    9.99,
+				Price:    domain.Money{Amount: 999, Currency: "USD"},
 			}, nil
 		},
 		UpdateProductMetadataFunc: func(ctx context.Context, product *domain.Product) (*domain.Product, error) {
@@ -208,7 +311,7 @@ func TestUpdateProductMetadata_Failure(t *testing.T) {
 
 	reqPayload := models.UpdateProductMetadataRequest{
 		Name:  "Updated Widget",
-		Price: 12.99,
+		Price: models.MoneyDTO{Amount: 1299, Currency: "USD"},
 	}
 	body, err := json.Marshal(reqPayload)
 	assert.NoError(t, err)
@@ -228,7 +331,7 @@ func TestUpdateProductStockQuantity_Success(t *testing.T) {
 				ID:       productID,
 				Name:     "Widget",
 				Quantity: 90,
-				Price:    9.99,
+				Price:    domain.Money{Amount: 999, Currency: "USD"},
 			}, nil
 		},
 	}
@@ -282,10 +385,10 @@ func TestUpdateProductStockQuantity_Failure(t *testing.T) {
 func TestListProducts_Success(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	fakeUC := &FakeInventoryUseCase{
-		ListProductsFunc: func(ctx context.Context) ([]*domain.Product, error) {
+		ListProductsFunc: func(ctx context.Context, filter domain.ProductFilter) ([]*domain.Product, error) {
 			return []*domain.Product{
-				{ID: "prod1", Name: "Widget", Quantity: 100, Price: 9.99},
-				{ID: "prod2", Name: "Gadget", Quantity: 50, Price: 19.99},
+				{ID: "prod1", Name: "Widget", Quantity: 100, Price: domain.Money{Amount: 999, Currency: "USD"}},
+				{ID: "prod2", Name: "Gadget", Quantity: 50, Price: domain.Money{Amount: 1999, Currency: "USD"}},
 			}, nil
 		},
 	}
@@ -298,18 +401,51 @@ func TestListProducts_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	var productsResp []models.ProductResponse
+	var productsResp models.Response[[]models.ProductResponse]
 	err = json.NewDecoder(resp.Body).Decode(&productsResp)
 	assert.NoError(t, err)
-	assert.Len(t, productsResp, 2)
-	assert.Equal(t, "prod1", productsResp[0].ID)
-	assert.Equal(t, "prod2", productsResp[1].ID)
+	assert.Len(t, productsResp.Data, 2)
+	assert.Equal(t, "prod1", productsResp.Data[0].ID)
+	assert.Equal(t, "prod2", productsResp.Data[1].ID)
+	assert.Equal(t, 2, productsResp.Meta.Total)
+}
+
+func TestListProducts_CategoryAndStatusFilter(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	var gotFilter domain.ProductFilter
+	fakeUC := &FakeInventoryUseCase{
+		ListProductsFunc: func(ctx context.Context, filter domain.ProductFilter) ([]*domain.Product, error) {
+			gotFilter = filter
+			return []*domain.Product{}, nil
+		},
+	}
+	app := fiber.New()
+	handler := NewInventoryHTTPHandler(fakeUC, logger)
+	RegisterInventoryRoutes(app, handler)
+
+	req := httptest.NewRequest("GET", "/api/products?category=electronics/audio&status=archived", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, domain.ProductFilter{Category: "electronics/audio", Status: domain.ProductStatusArchived}, gotFilter)
+}
+
+func TestListProducts_InvalidStatus(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	app := fiber.New()
+	handler := NewInventoryHTTPHandler(&FakeInventoryUseCase{}, logger)
+	RegisterInventoryRoutes(app, handler)
+
+	req := httptest.NewRequest("GET", "/api/products?status=deleted", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 func TestListProducts_Failure(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	fakeUC := &FakeInventoryUseCase{
-		ListProductsFunc: func(ctx context.Context) ([]*domain.Product, error) {
+		ListProductsFunc: func(ctx context.Context, filter domain.ProductFilter) ([]*domain.Product, error) {
 			return nil, errors.New("failed to list products")
 		},
 	}
@@ -322,3 +458,234 @@ func TestListProducts_Failure(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 }
+
+func TestSearchProducts_Success(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	var gotText string
+	var gotLimit int
+	fakeUC := &FakeInventoryUseCase{
+		SearchProductsFunc: func(ctx context.Context, text string, limit int) ([]*domain.ProductSearchResult, error) {
+			gotText, gotLimit = text, limit
+			return []*domain.ProductSearchResult{
+				{Product: &domain.Product{ID: "prod1", Name: "Widget"}, Rank: 0.6, Snippet: "<b>Widget</b>"},
+			}, nil
+		},
+	}
+	app := fiber.New()
+	handler := NewInventoryHTTPHandler(fakeUC, logger)
+	RegisterInventoryRoutes(app, handler)
+
+	req := httptest.NewRequest("GET", "/api/products/search?q=wid&limit=5", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "wid", gotText)
+	assert.Equal(t, 5, gotLimit)
+
+	var searchResp models.Response[[]models.ProductSearchResultResponse]
+	err = json.NewDecoder(resp.Body).Decode(&searchResp)
+	assert.NoError(t, err)
+	assert.Len(t, searchResp.Data, 1)
+	assert.Equal(t, "prod1", searchResp.Data[0].Product.ID)
+	assert.Equal(t, 0.6, searchResp.Data[0].Rank)
+	assert.Equal(t, "<b>Widget</b>", searchResp.Data[0].Snippet)
+}
+
+func TestSearchProducts_EmptyQuery(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	fakeUC := &FakeInventoryUseCase{
+		SearchProductsFunc: func(ctx context.Context, text string, limit int) ([]*domain.ProductSearchResult, error) {
+			return nil, domain.ErrEmptySearchQuery
+		},
+	}
+	app := fiber.New()
+	handler := NewInventoryHTTPHandler(fakeUC, logger)
+	RegisterInventoryRoutes(app, handler)
+
+	req := httptest.NewRequest("GET", "/api/products/search", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestImportProducts_Success(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	var gotBody string
+	var gotOpts domain.ImportOptions
+	fakeUC := &FakeInventoryUseCase{
+		ImportProductsFunc: func(ctx context.Context, r io.Reader, opts domain.ImportOptions) (*domain.ImportResult, error) {
+			body, _ := io.ReadAll(r)
+			gotBody = string(body)
+			gotOpts = opts
+			return &domain.ImportResult{
+				Created: 1,
+				Failed:  1,
+				DryRun:  opts.DryRun,
+				Errors:  []domain.ImportRowError{{Row: 2, SKU: "BAD", Message: "name is required"}},
+			}, nil
+		},
+	}
+	app := fiber.New()
+	handler := NewInventoryHTTPHandler(fakeUC, logger)
+	RegisterInventoryRoutes(app, handler)
+
+	body := "sku,name,quantity,price\nWID-001,Widget,1,9.99\nBAD,,1,1\n"
+	req := httptest.NewRequest("POST", "/api/products:import?dryRun=true", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "text/csv")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, body, gotBody)
+	assert.Equal(t, domain.ProductFormatCSV, gotOpts.Format)
+	assert.True(t, gotOpts.DryRun)
+
+	var importResp models.ImportProductsResponse
+	err = json.NewDecoder(resp.Body).Decode(&importResp)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, importResp.Created)
+	assert.Equal(t, 1, importResp.Failed)
+	assert.True(t, importResp.DryRun)
+	assert.Equal(t, "BAD", importResp.Errors[0].SKU)
+}
+
+func TestImportProducts_UnsupportedFormat(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	app := fiber.New()
+	handler := NewInventoryHTTPHandler(&FakeInventoryUseCase{}, logger)
+	RegisterInventoryRoutes(app, handler)
+
+	req := httptest.NewRequest("POST", "/api/products:import?format=xml", bytes.NewReader([]byte("<products/>")))
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestExportProducts_NDJSON(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	fakeUC := &FakeInventoryUseCase{
+		ExportProductsFunc: func(ctx context.Context, w io.Writer, format domain.ProductFormat) error {
+			assert.Equal(t, domain.ProductFormatNDJSON, format)
+			_, err := io.WriteString(w, `{"sku":"WID-001","name":"Widget","quantity":1,"price":9.99}`+"\n")
+			return err
+		},
+	}
+	app := fiber.New()
+	handler := NewInventoryHTTPHandler(fakeUC, logger)
+	RegisterInventoryRoutes(app, handler)
+
+	req := httptest.NewRequest("GET", "/api/products:export?format=ndjson", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"sku":"WID-001","name":"Widget","quantity":1,"price":9.99}`+"\n", string(body))
+}
+
+func TestGetProduct_AsOf(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	fakeUC := &FakeInventoryUseCase{
+		GetProductAsOfFunc: func(ctx context.Context, productID string, asOf time.Time) (*domain.Product, error) {
+			assert.Equal(t, time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC), asOf.UTC())
+			return &domain.Product{ID: productID, Price: domain.Money{Amount: 899, Currency: "USD"}}, nil
+		},
+	}
+	app := fiber.New()
+	handler := NewInventoryHTTPHandler(fakeUC, logger)
+	RegisterInventoryRoutes(app, handler)
+
+	req := httptest.NewRequest("GET", "/api/products/prod123?asOf=2026-03-01T12:00:00Z", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var productResp models.ProductResponse
+	err = json.NewDecoder(resp.Body).Decode(&productResp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(899), productResp.Price.Amount)
+
+	req = httptest.NewRequest("GET", "/api/products/prod123?asOf=yesterday", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestScheduleProductPrice_Success(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	from := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	fakeUC := &FakeInventoryUseCase{
+		ScheduleProductPriceFunc: func(ctx context.Context, productID string, price domain.Money, effectiveFrom time.Time) (*domain.ProductPrice, error) {
+			assert.Equal(t, domain.Money{Amount: 1299, Currency: "USD"}, price)
+			return &domain.ProductPrice{ID: "price1", ProductID: productID, Price: price, EffectiveFrom: effectiveFrom}, nil
+		},
+	}
+	app := fiber.New()
+	handler := NewInventoryHTTPHandler(fakeUC, logger)
+	RegisterInventoryRoutes(app, handler)
+
+	body, _ := json.Marshal(models.ScheduleProductPriceRequest{
+		Price:         models.MoneyDTO{Amount: 1299, Currency: "USD"},
+		EffectiveFrom: &from,
+	})
+	req := httptest.NewRequest("POST", "/api/products/prod123/prices", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var priceResp models.ProductPriceResponse
+	err = json.NewDecoder(resp.Body).Decode(&priceResp)
+	assert.NoError(t, err)
+	assert.Equal(t, "prod123", priceResp.ProductID)
+	assert.True(t, from.Equal(priceResp.EffectiveFrom))
+	assert.Nil(t, priceResp.EffectiveTo)
+}
+
+func TestScheduleProductPrice_InPast(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	fakeUC := &FakeInventoryUseCase{
+		ScheduleProductPriceFunc: func(ctx context.Context, productID string, price domain.Money, effectiveFrom time.Time) (*domain.ProductPrice, error) {
+			return nil, domain.ErrPriceInPast
+		},
+	}
+	app := fiber.New()
+	handler := NewInventoryHTTPHandler(fakeUC, logger)
+	RegisterInventoryRoutes(app, handler)
+
+	req := httptest.NewRequest("POST", "/api/products/prod123/prices",
+		bytes.NewReader([]byte(`{"price":{"amount":1299,"currency":"USD"},"effectiveFrom":"2020-01-01T00:00:00Z"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestListProductPrices_Success(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	from := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	fakeUC := &FakeInventoryUseCase{
+		ListProductPricesFunc: func(ctx context.Context, productID string) ([]*domain.ProductPrice, error) {
+			return []*domain.ProductPrice{
+				{ID: "p1", ProductID: productID, Price: domain.Money{Amount: 999, Currency: "USD"}, EffectiveFrom: from, EffectiveTo: &to},
+				{ID: "p2", ProductID: productID, Price: domain.Money{Amount: 1299, Currency: "USD"}, EffectiveFrom: to},
+			}, nil
+		},
+	}
+	app := fiber.New()
+	handler := NewInventoryHTTPHandler(fakeUC, logger)
+	RegisterInventoryRoutes(app, handler)
+
+	req := httptest.NewRequest("GET", "/api/products/prod123/prices", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var res models.Response[[]models.ProductPriceResponse]
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Len(t, res.Data, 2)
+	assert.Equal(t, int64(1299), res.Data[1].Price.Amount)
+}
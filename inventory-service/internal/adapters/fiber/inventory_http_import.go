@@ -0,0 +1,86 @@
+package fiber_http
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"inventory-service/internal/adapters/mappers"
+	"inventory-service/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+var productFormatContentTypes = map[domain.ProductFormat]string{
+	domain.ProductFormatCSV:    "text/csv; charset=utf-8",
+	domain.ProductFormatNDJSON: "application/x-ndjson",
+}
+
+func (h *InventoryHTTPHandler) ImportProducts(c *fiber.Ctx) error {
+	format, err := requestProductFormat(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": err.Error()})
+	}
+
+	opts := domain.ImportOptions{
+		Format: format,
+		DryRun: c.QueryBool("dryRun"),
+	}
+	result, err := h.inventoryUseCase.ImportProducts(c.UserContext(), bytes.NewReader(c.Body()), opts)
+	if err != nil {
+		h.log(c).Error("failed to import products", zap.Error(err))
+		if errors.Is(err, domain.ErrMalformedImport) || errors.Is(err, domain.ErrUnsupportedFormat) {
+			return c.Status(fiber.StatusBadRequest).
+				JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(fiber.Map{"error": "failed to import products"})
+	}
+
+	return c.JSON(mappers.MapImportResultToResponse(result))
+}
+
+func (h *InventoryHTTPHandler) ExportProducts(c *fiber.Ctx) error {
+	format, err := domain.ParseProductFormat(c.Query("format", string(domain.ProductFormatCSV)))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.Set(fiber.HeaderContentType, productFormatContentTypes[format])
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="products.%s"`, format))
+
+	// The writer runs after the handler has returned, when c may already be
+	// reused, so it only uses what it captures here.
+	ctx := c.UserContext()
+	logger := h.log(c)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.inventoryUseCase.ExportProducts(ctx, w, format); err != nil {
+			logger.Error("failed to export products", zap.Error(err))
+		}
+	})
+	return nil
+}
+
+// requestProductFormat takes the format from the "format" query parameter
+// and falls back to the request Content-Type.
+func requestProductFormat(c *fiber.Ctx) (domain.ProductFormat, error) {
+	if format := c.Query("format"); format != "" {
+		return domain.ParseProductFormat(format)
+	}
+	contentType := strings.ToLower(string(c.Request().Header.ContentType()))
+	switch {
+	case strings.HasPrefix(contentType, "text/csv"):
+		return domain.ProductFormatCSV, nil
+	case strings.HasPrefix(contentType, "application/x-ndjson"),
+		strings.HasPrefix(contentType, "application/ndjson"),
+		strings.HasPrefix(contentType, "application/jsonl"):
+		return domain.ProductFormatNDJSON, nil
+	default:
+		return "", fmt.Errorf("%w: set ?format=csv|ndjson or a matching Content-Type", domain.ErrUnsupportedFormat)
+	}
+}
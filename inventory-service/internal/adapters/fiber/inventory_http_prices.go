@@ -0,0 +1,64 @@
+package fiber_http
+
+import (
+	"errors"
+	"time"
+
+	"inventory-service/internal/adapters/mappers"
+	"inventory-service/internal/adapters/models"
+	"inventory-service/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+func (h *InventoryHTTPHandler) ScheduleProductPrice(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req models.ScheduleProductPriceRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.log(c).Error("failed to parse request", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "Invalid request payload"})
+	}
+	price, err := mappers.MapMoneyDTOToMoney(req.Price)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": err.Error()})
+	}
+	var effectiveFrom time.Time
+	if req.EffectiveFrom != nil {
+		effectiveFrom = *req.EffectiveFrom
+	}
+
+	scheduled, err := h.inventoryUseCase.ScheduleProductPrice(c.UserContext(), id, price, effectiveFrom)
+	if err != nil {
+		h.log(c).Error("failed to schedule product price", zap.String("id", id), zap.Error(err))
+		if errors.Is(err, domain.ErrPriceInPast) || errors.Is(err, domain.ErrCurrencyMismatch) {
+			return c.Status(fiber.StatusBadRequest).
+				JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(fiber.Map{"error": "failed to schedule product price"})
+	}
+	return c.Status(fiber.StatusCreated).JSON(mappers.MapProductPriceToResponse(scheduled))
+}
+
+func (h *InventoryHTTPHandler) ListProductPrices(c *fiber.Ctx) error {
+	id := c.Params("id")
+	prices, err := h.inventoryUseCase.ListProductPrices(c.UserContext(), id)
+	if err != nil {
+		h.log(c).Error("failed to list product prices", zap.String("id", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(fiber.Map{"error": "failed to list product prices"})
+	}
+	dtos := make([]models.ProductPriceResponse, 0, len(prices))
+	for _, price := range prices {
+		dtos = append(dtos, mappers.MapProductPriceToResponse(price))
+	}
+
+	var res = models.NewResponse(dtos, &models.Meta{
+		Total: len(dtos),
+	})
+
+	return c.JSON(res)
+}
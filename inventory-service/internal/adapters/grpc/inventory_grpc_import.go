@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"inventory-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/inventory_service"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ImportProducts streams the uploaded chunks straight into the use case
+// through a pipe, so the file is never held in memory as a whole.
+func (s *InventoryGRPCServer) ImportProducts(stream inventory_service.InventoryService_ImportProductsServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "import stream is empty")
+	}
+	if err != nil {
+		return err
+	}
+
+	format, err := protoFormatToDomain(first.GetFormat())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		if _, err := pw.Write(first.GetChunk()); err != nil {
+			return
+		}
+		for {
+			req, err := stream.Recv()
+			if err == io.EOF {
+				pw.Close()
+				return
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if _, err := pw.Write(req.GetChunk()); err != nil {
+				return
+			}
+		}
+	}()
+
+	result, err := s.inventoryUseCase.ImportProducts(stream.Context(), pr, domain.ImportOptions{
+		Format: format,
+		DryRun: first.GetDryRun(),
+	})
+	pr.Close()
+	if err != nil {
+		s.log(stream.Context()).Error("Failed to import products", zap.Error(err))
+		if errors.Is(err, domain.ErrMalformedImport) || errors.Is(err, domain.ErrUnsupportedFormat) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		return err
+	}
+
+	resp := &inventory_service.ImportProductsResponse{
+		Created: int32(result.Created),
+		Updated: int32(result.Updated),
+		Failed:  int32(result.Failed),
+		DryRun:  result.DryRun,
+	}
+	for _, rowErr := range result.Errors {
+		resp.Errors = append(resp.Errors, &inventory_service.ImportRowError{
+			Row:     int32(rowErr.Row),
+			Sku:     rowErr.SKU,
+			Message: rowErr.Message,
+		})
+	}
+	return stream.SendAndClose(resp)
+}
+
+func protoFormatToDomain(format inventory_service.ProductFormat) (domain.ProductFormat, error) {
+	switch format {
+	case inventory_service.ProductFormat_PRODUCT_FORMAT_CSV:
+		return domain.ProductFormatCSV, nil
+	case inventory_service.ProductFormat_PRODUCT_FORMAT_NDJSON:
+		return domain.ProductFormatNDJSON, nil
+	default:
+		return "", fmt.Errorf("%w: %s", domain.ErrUnsupportedFormat, format)
+	}
+}
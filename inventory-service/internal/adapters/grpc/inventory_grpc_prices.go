@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"inventory-service/internal/adapters/mappers"
+	"inventory-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/inventory_service"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *InventoryGRPCServer) ScheduleProductPrice(ctx context.Context, req *inventory_service.ScheduleProductPriceRequest) (*inventory_service.ScheduleProductPriceResponse, error) {
+	price, err := mappers.MapProtoToMoney(req.GetPrice())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	var effectiveFrom time.Time
+	if req.GetEffectiveFrom() != nil {
+		if err := req.GetEffectiveFrom().CheckValid(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		effectiveFrom = req.GetEffectiveFrom().AsTime()
+	}
+	scheduled, err := s.inventoryUseCase.ScheduleProductPrice(ctx, req.GetProductId(), price, effectiveFrom)
+	if errors.Is(err, domain.ErrPriceInPast) || errors.Is(err, domain.ErrCurrencyMismatch) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		s.log(ctx).Error("Failed to schedule product price", zap.String("productId", req.GetProductId()), zap.Error(err))
+		return nil, err
+	}
+	return &inventory_service.ScheduleProductPriceResponse{
+		Price: mappers.MapProductPriceToProto(scheduled),
+	}, nil
+}
+
+func (s *InventoryGRPCServer) ListProductPrices(ctx context.Context, req *inventory_service.ListProductPricesRequest) (*inventory_service.ListProductPricesResponse, error) {
+	prices, err := s.inventoryUseCase.ListProductPrices(ctx, req.GetProductId())
+	if err != nil {
+		s.log(ctx).Error("Failed to list product prices", zap.String("productId", req.GetProductId()), zap.Error(err))
+		return nil, err
+	}
+	return &inventory_service.ListProductPricesResponse{
+		Prices: mappers.MapProductPricesToProto(prices),
+	}, nil
+}
@@ -2,11 +2,16 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"inventory-service/internal/adapters/mappers"
 	"inventory-service/internal/domain"
 	"inventory-service/internal/usecases"
 
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"github.com/jakkapat-chongsuwat/go-microservice/proto/inventory_service"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type InventoryGRPCServer struct {
@@ -22,102 +27,152 @@ func NewInventoryGRPCServer(inventoryUC usecases.InventoryUseCase, logger *zap.L
 	}
 }
 
+// log returns the request-scoped logger of ctx.
+func (s *InventoryGRPCServer) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, s.logger)
+}
+
 func (s *InventoryGRPCServer) CreateProduct(ctx context.Context, req *inventory_service.CreateProductRequest) (*inventory_service.CreateProductResponse, error) {
-	s.logger.Info("Received CreateProduct request", zap.String("name", req.GetName()))
-	product := domain.NewProduct(req.GetName(), int(req.GetQuantity()), req.GetPrice())
+	attrs, err := mappers.MapProtoToAttributes(req.GetAttributes())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	price, err := mappers.MapProtoToMoney(req.GetPrice())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	product := domain.NewProduct(req.GetName(), int(req.GetQuantity()), price)
+	if req.GetSku() != "" {
+		product.SKU = req.GetSku()
+	}
+	product.Description = req.GetDescription()
+	product.Category = req.GetCategory()
+	product.Attributes = attrs
 	created, err := s.inventoryUseCase.CreateProduct(ctx, product)
 	if err != nil {
-		s.logger.Error("Failed to create product", zap.Error(err))
+		s.log(ctx).Error("Failed to create product", zap.Error(err))
 		return nil, err
 	}
 
 	return &inventory_service.CreateProductResponse{
-		Product: &inventory_service.Product{
-			Id:       created.ID,
-			Name:     created.Name,
-			Quantity: int32(created.Quantity),
-			Price:    created.Price,
-		},
+		Product: mappers.MapProductToProto(created),
 	}, nil
 }
 
 func (s *InventoryGRPCServer) GetProduct(ctx context.Context, req *inventory_service.GetProductRequest) (*inventory_service.GetProductResponse, error) {
-	s.logger.Info("Received GetProduct request", zap.String("id", req.GetId()))
-	product, err := s.inventoryUseCase.GetProduct(ctx, req.GetId())
+	var (
+		product *domain.Product
+		err     error
+	)
+	if req.GetAsOf() != nil {
+		if err := req.GetAsOf().CheckValid(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		product, err = s.inventoryUseCase.GetProductAsOf(ctx, req.GetId(), req.GetAsOf().AsTime())
+	} else {
+		product, err = s.inventoryUseCase.GetProduct(ctx, req.GetId())
+	}
 	if err != nil {
-		s.logger.Error("Failed to get product", zap.String("id", req.GetId()), zap.Error(err))
+		s.log(ctx).Error("Failed to get product", zap.String("id", req.GetId()), zap.Error(err))
 		return nil, err
 	}
 
 	return &inventory_service.GetProductResponse{
-		Product: &inventory_service.Product{
-			Id:       product.ID,
-			Name:     product.Name,
-			Quantity: int32(product.Quantity),
-			Price:    product.Price,
-		},
+		Product: mappers.MapProductToProto(product),
+	}, nil
+}
+
+func (s *InventoryGRPCServer) GetProductBySKU(ctx context.Context, req *inventory_service.GetProductBySKURequest) (*inventory_service.GetProductBySKUResponse, error) {
+	if req.GetSku() == "" {
+		return nil, status.Error(codes.InvalidArgument, "sku is required")
+	}
+	product, err := s.inventoryUseCase.GetProductBySKU(ctx, req.GetSku())
+	if err != nil {
+		s.log(ctx).Error("Failed to get product by SKU", zap.String("sku", req.GetSku()), zap.Error(err))
+		return nil, err
+	}
+
+	return &inventory_service.GetProductBySKUResponse{
+		Product: mappers.MapProductToProto(product),
 	}, nil
 }
 
 func (s *InventoryGRPCServer) UpdateProductMetadata(ctx context.Context, req *inventory_service.UpdateProductMetadataRequest) (*inventory_service.UpdateProductMetadataResponse, error) {
-	s.logger.Info("Received UpdateProductMetadata request", zap.String("id", req.GetId()))
+	attrs, err := mappers.MapProtoToAttributes(req.GetAttributes())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	productStatus, err := mappers.MapProtoToProductStatus(req.GetStatus())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	price, err := mappers.MapProtoToMoney(req.GetPrice())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	product, err := s.inventoryUseCase.GetProduct(ctx, req.GetId())
 	if err != nil {
-		s.logger.Error("Failed to get product", zap.String("id", req.GetId()), zap.Error(err))
+		s.log(ctx).Error("Failed to get product", zap.String("id", req.GetId()), zap.Error(err))
 		return nil, err
 	}
+	product.SKU = req.GetSku()
 	product.Name = req.GetName()
-	product.Price = req.GetPrice()
+	product.Description = req.GetDescription()
+	product.Category = req.GetCategory()
+	product.Attributes = attrs
+	product.Status = productStatus
+	product.Price = price
 	updated, err := s.inventoryUseCase.UpdateProductMetadata(ctx, product)
 	if err != nil {
-		s.logger.Error("Failed to update product metadata", zap.String("id", req.GetId()), zap.Error(err))
+		s.log(ctx).Error("Failed to update product metadata", zap.String("id", req.GetId()), zap.Error(err))
 		return nil, err
 	}
 	return &inventory_service.UpdateProductMetadataResponse{
-		Product: &inventory_service.Product{
-			Id:       updated.ID,
-			Name:     updated.Name,
-			Quantity: int32(updated.Quantity),
-			Price:    updated.Price,
-		},
+		Product: mappers.MapProductToProto(updated),
 	}, nil
 }
 
 func (s *InventoryGRPCServer) UpdateProductStockQuantity(ctx context.Context, req *inventory_service.UpdateProductStockQuantityRequest) (*inventory_service.UpdateProductStockQuantityResponse, error) {
-	s.logger.Info("Received AdjustInventory request", zap.String("id", req.GetId()))
 	updated, err := s.inventoryUseCase.UpdateProductStockQuantity(ctx, req.GetId(), int(req.GetQuantityChange()))
 	if err != nil {
-		s.logger.Error("Failed to adjust inventory", zap.String("id", req.GetId()), zap.Error(err))
+		s.log(ctx).Error("Failed to adjust inventory", zap.String("id", req.GetId()), zap.Error(err))
 		return nil, err
 	}
 	return &inventory_service.UpdateProductStockQuantityResponse{
-		Product: &inventory_service.Product{
-			Id:       updated.ID,
-			Name:     updated.Name,
-			Quantity: int32(updated.Quantity),
-			Price:    updated.Price,
-		},
+		Product: mappers.MapProductToProto(updated),
 	}, nil
 }
 
 func (s *InventoryGRPCServer) ListProducts(ctx context.Context, req *inventory_service.ListProductsRequest) (*inventory_service.ListProductsResponse, error) {
-	s.logger.Info("Received ListProducts request")
-	products, err := s.inventoryUseCase.ListProducts(ctx)
+	productStatus, err := mappers.MapProtoToProductStatus(req.GetStatus())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	products, err := s.inventoryUseCase.ListProducts(ctx, domain.ProductFilter{
+		Category: req.GetCategory(),
+		Status:   productStatus,
+	})
 	if err != nil {
-		s.logger.Error("Failed to list products", zap.Error(err))
+		s.log(ctx).Error("Failed to list products", zap.Error(err))
 		return nil, err
 	}
 
-	var prodResponses []*inventory_service.Product
-	for _, product := range products {
-		prodResponses = append(prodResponses, &inventory_service.Product{
-			Id:       product.ID,
-			Name:     product.Name,
-			Quantity: int32(product.Quantity),
-			Price:    product.Price,
-		})
-	}
 	return &inventory_service.ListProductsResponse{
-		Products: prodResponses,
+		Products: mappers.MapProductsToProto(products),
+	}, nil
+}
+
+func (s *InventoryGRPCServer) SearchProducts(ctx context.Context, req *inventory_service.SearchProductsRequest) (*inventory_service.SearchProductsResponse, error) {
+	results, err := s.inventoryUseCase.SearchProducts(ctx, req.GetQuery(), int(req.GetLimit()))
+	if errors.Is(err, domain.ErrEmptySearchQuery) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		s.log(ctx).Error("Failed to search products", zap.Error(err))
+		return nil, err
+	}
+
+	return &inventory_service.SearchProductsResponse{
+		Results: mappers.MapSearchResultsToProto(results),
 	}, nil
 }
@@ -3,7 +3,9 @@ package grpc
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
+	"time"
 
 	"inventory-service/internal/domain"
 
@@ -11,6 +13,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type MockInventoryUseCase struct {
@@ -33,6 +39,38 @@ func (m *MockInventoryUseCase) GetProduct(ctx context.Context, productId string)
 	return nil, args.Error(1)
 }
 
+func (m *MockInventoryUseCase) GetProductAsOf(ctx context.Context, productId string, asOf time.Time) (*domain.Product, error) {
+	args := m.Called(ctx, productId, asOf)
+	if prod, ok := args.Get(0).(*domain.Product); ok {
+		return prod, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockInventoryUseCase) ScheduleProductPrice(ctx context.Context, productID string, price domain.Money, effectiveFrom time.Time) (*domain.ProductPrice, error) {
+	args := m.Called(ctx, productID, price, effectiveFrom)
+	if p, ok := args.Get(0).(*domain.ProductPrice); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockInventoryUseCase) ListProductPrices(ctx context.Context, productID string) ([]*domain.ProductPrice, error) {
+	args := m.Called(ctx, productID)
+	if p, ok := args.Get(0).([]*domain.ProductPrice); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockInventoryUseCase) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	args := m.Called(ctx, sku)
+	if prod, ok := args.Get(0).(*domain.Product); ok {
+		return prod, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockInventoryUseCase) UpdateProductMetadata(ctx context.Context, product *domain.Product) (*domain.Product, error) {
 	args := m.Called(ctx, product)
 	if prod, ok := args.Get(0).(*domain.Product); ok {
@@ -49,14 +87,40 @@ func (m *MockInventoryUseCase) UpdateProductStockQuantity(ctx context.Context, p
 	return nil, args.Error(1)
 }
 
-func (m *MockInventoryUseCase) ListProducts(ctx context.Context) ([]*domain.Product, error) {
-	args := m.Called(ctx)
+func (m *MockInventoryUseCase) ListProducts(ctx context.Context, filter domain.ProductFilter) ([]*domain.Product, error) {
+	args := m.Called(ctx, filter)
 	if prods, ok := args.Get(0).([]*domain.Product); ok {
 		return prods, args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
+func (m *MockInventoryUseCase) SearchProducts(ctx context.Context, text string, limit int) ([]*domain.ProductSearchResult, error) {
+	args := m.Called(ctx, text, limit)
+	if res, ok := args.Get(0).([]*domain.ProductSearchResult); ok {
+		return res, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockInventoryUseCase) ImportProducts(ctx context.Context, r io.Reader, opts domain.ImportOptions) (*domain.ImportResult, error) {
+	body, _ := io.ReadAll(r)
+	args := m.Called(ctx, string(body), opts)
+	if res, ok := args.Get(0).(*domain.ImportResult); ok {
+		return res, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockInventoryUseCase) ExportProducts(ctx context.Context, w io.Writer, format domain.ProductFormat) error {
+	args := m.Called(ctx, w, format)
+	return args.Error(0)
+}
+
+func usd(amount int64) domain.Money {
+	return domain.Money{Amount: amount, Currency: "USD"}
+}
+
 func TestInventoryGRPCServer_CreateProduct(t *testing.T) {
 	ctx := context.Background()
 	logger := zap.NewNop()
@@ -66,18 +130,18 @@ func TestInventoryGRPCServer_CreateProduct(t *testing.T) {
 	req := &inventory_service.CreateProductRequest{
 		Name:     "Test Product",
 		Quantity: 100,
-		Price:    9.99,
+		Price:    &inventory_service.Money{AmountMinor: 999, CurrencyCode: "USD"},
 	}
 
 	mockUC.On("CreateProduct", ctx, mock.MatchedBy(func(p *domain.Product) bool {
 		return p.Name == req.GetName() &&
 			p.Quantity == int(req.GetQuantity()) &&
-			p.Price == req.GetPrice()
+			p.Price == usd(req.GetPrice().GetAmountMinor())
 	})).Return(&domain.Product{
 		ID:       "123",
 		Name:     req.GetName(),
 		Quantity: int(req.GetQuantity()),
-		Price:    req.GetPrice(),
+		Price:    usd(req.GetPrice().GetAmountMinor()),
 	}, nil)
 
 	resp, err := server.CreateProduct(ctx, req)
@@ -86,7 +150,7 @@ func TestInventoryGRPCServer_CreateProduct(t *testing.T) {
 	assert.Equal(t, "123", resp.Product.Id)
 	assert.Equal(t, req.GetName(), resp.Product.Name)
 	assert.Equal(t, req.GetQuantity(), int32(resp.Product.Quantity))
-	assert.Equal(t, req.GetPrice(), resp.Product.Price)
+	assert.Equal(t, req.GetPrice().GetAmountMinor(), resp.Product.Price.GetAmountMinor())
 
 	mockUC.AssertExpectations(t)
 }
@@ -100,7 +164,7 @@ func TestInventoryGRPCServer_CreateProduct_Error(t *testing.T) {
 	req := &inventory_service.CreateProductRequest{
 		Name:     "Test Product",
 		Quantity: 100,
-		Price:    9.99,
+		Price:    &inventory_service.Money{AmountMinor: 999, CurrencyCode: "USD"},
 	}
 
 	expectedErr := errors.New("create error")
@@ -113,6 +177,14 @@ func TestInventoryGRPCServer_CreateProduct_Error(t *testing.T) {
 	mockUC.AssertExpectations(t)
 }
 
+func TestInventoryGRPCServer_CreateProduct_MissingPrice(t *testing.T) {
+	server := NewInventoryGRPCServer(new(MockInventoryUseCase), zap.NewNop())
+
+	resp, err := server.CreateProduct(context.Background(), &inventory_service.CreateProductRequest{Name: "Test Product"})
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 func TestInventoryGRPCServer_GetProduct(t *testing.T) {
 	ctx := context.Background()
 	logger := zap.NewNop()
@@ -126,7 +198,7 @@ func TestInventoryGRPCServer_GetProduct(t *testing.T) {
 		ID:       "123",
 		Name:     "Test Product",
 		Quantity: 50,
-		Price:    19.99,
+		Price:    usd(1999),
 	}
 	mockUC.On("GetProduct", ctx, req.GetId()).Return(expectedProduct, nil)
 
@@ -136,11 +208,68 @@ func TestInventoryGRPCServer_GetProduct(t *testing.T) {
 	assert.Equal(t, expectedProduct.ID, resp.Product.Id)
 	assert.Equal(t, expectedProduct.Name, resp.Product.Name)
 	assert.Equal(t, int32(expectedProduct.Quantity), resp.Product.Quantity)
-	assert.Equal(t, expectedProduct.Price, resp.Product.Price)
+	assert.Equal(t, expectedProduct.Price.Amount, resp.Product.Price.GetAmountMinor())
+
+	mockUC.AssertExpectations(t)
+}
+
+func TestInventoryGRPCServer_GetProduct_AsOf(t *testing.T) {
+	ctx := context.Background()
+	mockUC := new(MockInventoryUseCase)
+	server := NewInventoryGRPCServer(mockUC, zap.NewNop())
+
+	asOf := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	mockUC.On("GetProductAsOf", ctx, "123", asOf).
+		Return(&domain.Product{ID: "123", Price: usd(899)}, nil)
+
+	resp, err := server.GetProduct(ctx, &inventory_service.GetProductRequest{Id: "123", AsOf: timestamppb.New(asOf)})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(899), resp.Product.Price.GetAmountMinor())
+	mockUC.AssertExpectations(t)
+	mockUC.AssertNotCalled(t, "GetProduct", mock.Anything, mock.Anything)
+}
+
+func TestInventoryGRPCServer_GetProductBySKU(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	mockUC := new(MockInventoryUseCase)
+	server := NewInventoryGRPCServer(mockUC, logger)
+
+	req := &inventory_service.GetProductBySKURequest{Sku: "WID-001"}
+	mockUC.On("GetProductBySKU", ctx, "WID-001").Return(&domain.Product{
+		ID:       "123",
+		SKU:      "WID-001",
+		Name:     "Widget",
+		Category: "hardware/widgets",
+		Attributes: domain.Attributes{
+			"color":   domain.StringAttribute("red"),
+			"weight":  domain.NumberAttribute(1.5),
+			"fragile": domain.BoolAttribute(true),
+		},
+		Status: domain.ProductStatusArchived,
+	}, nil)
+
+	resp, err := server.GetProductBySKU(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, "123", resp.Product.Id)
+	assert.Equal(t, "WID-001", resp.Product.Sku)
+	assert.Equal(t, "hardware/widgets", resp.Product.Category)
+	assert.Equal(t, inventory_service.ProductStatus_PRODUCT_STATUS_ARCHIVED, resp.Product.Status)
+	assert.Equal(t, "red", resp.Product.Attributes["color"].GetStringValue())
+	assert.Equal(t, 1.5, resp.Product.Attributes["weight"].GetNumberValue())
+	assert.True(t, resp.Product.Attributes["fragile"].GetBoolValue())
 
 	mockUC.AssertExpectations(t)
 }
 
+func TestInventoryGRPCServer_GetProductBySKU_Empty(t *testing.T) {
+	server := NewInventoryGRPCServer(new(MockInventoryUseCase), zap.NewNop())
+
+	resp, err := server.GetProductBySKU(context.Background(), &inventory_service.GetProductBySKURequest{})
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 func TestInventoryGRPCServer_GetProduct_Error(t *testing.T) {
 	ctx := context.Background()
 	logger := zap.NewNop()
@@ -169,13 +298,13 @@ func TestInventoryGRPCServer_UpdateProductMetadata(t *testing.T) {
 	req := &inventory_service.UpdateProductMetadataRequest{
 		Id:    "123",
 		Name:  "Updated Name",
-		Price: 29.99,
+		Price: &inventory_service.Money{AmountMinor: 2999, CurrencyCode: "USD"},
 	}
 	existingProduct := &domain.Product{
 		ID:       "123",
 		Name:     "Old Name",
 		Quantity: 50,
-		Price:    19.99,
+		Price:    usd(1999),
 	}
 	mockUC.On("GetProduct", ctx, req.GetId()).Return(existingProduct, nil)
 
@@ -183,12 +312,12 @@ func TestInventoryGRPCServer_UpdateProductMetadata(t *testing.T) {
 		ID:       "123",
 		Name:     req.GetName(),
 		Quantity: existingProduct.Quantity,
-		Price:    req.GetPrice(),
+		Price:    usd(req.GetPrice().GetAmountMinor()),
 	}
 	mockUC.On("UpdateProductMetadata", ctx, mock.MatchedBy(func(p *domain.Product) bool {
 		return p.ID == "123" &&
 			p.Name == req.GetName() &&
-			p.Price == req.GetPrice() &&
+			p.Price == usd(req.GetPrice().GetAmountMinor()) &&
 			p.Quantity == existingProduct.Quantity
 	})).Return(updatedProduct, nil)
 
@@ -198,11 +327,55 @@ func TestInventoryGRPCServer_UpdateProductMetadata(t *testing.T) {
 	assert.Equal(t, updatedProduct.ID, resp.Product.Id)
 	assert.Equal(t, updatedProduct.Name, resp.Product.Name)
 	assert.Equal(t, int32(updatedProduct.Quantity), resp.Product.Quantity)
-	assert.Equal(t, updatedProduct.Price, resp.Product.Price)
+	assert.Equal(t, updatedProduct.Price.Amount, resp.Product.Price.GetAmountMinor())
 
 	mockUC.AssertExpectations(t)
 }
 
+func TestInventoryGRPCServer_UpdateProductMetadata_CatalogFields(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	mockUC := new(MockInventoryUseCase)
+	server := NewInventoryGRPCServer(mockUC, logger)
+
+	req := &inventory_service.UpdateProductMetadataRequest{
+		Id:          "123",
+		Name:        "Widget",
+		Price:       &inventory_service.Money{AmountMinor: 999, CurrencyCode: "USD"},
+		Description: "A small widget",
+		Category:    "hardware/widgets",
+		Attributes: map[string]*inventory_service.AttributeValue{
+			"color": {Kind: &inventory_service.AttributeValue_StringValue{StringValue: "red"}},
+		},
+		Status: inventory_service.ProductStatus_PRODUCT_STATUS_ARCHIVED,
+	}
+	mockUC.On("GetProduct", ctx, req.GetId()).Return(&domain.Product{ID: "123", SKU: "WID-001"}, nil)
+	mockUC.On("UpdateProductMetadata", ctx, mock.MatchedBy(func(p *domain.Product) bool {
+		return p.Description == "A small widget" &&
+			p.Category == "hardware/widgets" &&
+			p.Status == domain.ProductStatusArchived &&
+			p.Attributes["color"] == domain.StringAttribute("red")
+	})).Return(&domain.Product{ID: "123", SKU: "WID-001", Status: domain.ProductStatusArchived}, nil)
+
+	resp, err := server.UpdateProductMetadata(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, inventory_service.ProductStatus_PRODUCT_STATUS_ARCHIVED, resp.Product.Status)
+
+	mockUC.AssertExpectations(t)
+}
+
+func TestInventoryGRPCServer_UpdateProductMetadata_EmptyAttribute(t *testing.T) {
+	server := NewInventoryGRPCServer(new(MockInventoryUseCase), zap.NewNop())
+
+	req := &inventory_service.UpdateProductMetadataRequest{
+		Id:         "123",
+		Attributes: map[string]*inventory_service.AttributeValue{"color": {}},
+	}
+	resp, err := server.UpdateProductMetadata(context.Background(), req)
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 func TestInventoryGRPCServer_UpdateProductMetadata_GetError(t *testing.T) {
 	ctx := context.Background()
 	logger := zap.NewNop()
@@ -212,7 +385,7 @@ func TestInventoryGRPCServer_UpdateProductMetadata_GetError(t *testing.T) {
 	req := &inventory_service.UpdateProductMetadataRequest{
 		Id:    "123",
 		Name:  "Updated Name",
-		Price: 29.99,
+		Price: &inventory_service.Money{AmountMinor: 2999, CurrencyCode: "USD"},
 	}
 	expectedErr := errors.New("get error")
 	mockUC.On("GetProduct", ctx, req.GetId()).Return((*domain.Product)(nil), expectedErr)
@@ -238,7 +411,7 @@ func TestInventoryGRPCServer_UpdateProductStockQuantity(t *testing.T) {
 		ID:       "123",
 		Name:     "Test Product",
 		Quantity: 60,
-		Price:    19.99,
+		Price:    usd(1999),
 	}
 	mockUC.On("UpdateProductStockQuantity", ctx, req.GetId(), int(req.GetQuantityChange())).Return(updatedProduct, nil)
 
@@ -248,7 +421,7 @@ func TestInventoryGRPCServer_UpdateProductStockQuantity(t *testing.T) {
 	assert.Equal(t, updatedProduct.ID, resp.Product.Id)
 	assert.Equal(t, updatedProduct.Name, resp.Product.Name)
 	assert.Equal(t, int32(updatedProduct.Quantity), resp.Product.Quantity)
-	assert.Equal(t, updatedProduct.Price, resp.Product.Price)
+	assert.Equal(t, updatedProduct.Price.Amount, resp.Product.Price.GetAmountMinor())
 
 	mockUC.AssertExpectations(t)
 }
@@ -281,10 +454,10 @@ func TestInventoryGRPCServer_ListProducts(t *testing.T) {
 
 	req := &inventory_service.ListProductsRequest{}
 	products := []*domain.Product{
-		{ID: "1", Name: "Prod1", Quantity: 10, Price: 9.99},
-		{ID: "2", Name: "Prod2", Quantity: 20, Price: 19.99},
+		{ID: "1", Name: "Prod1", Quantity: 10, Price: usd(999)},
+		{ID: "2", Name: "Prod2", Quantity: 20, Price: usd(1999)},
 	}
-	mockUC.On("ListProducts", ctx).Return(products, nil)
+	mockUC.On("ListProducts", ctx, domain.ProductFilter{}).Return(products, nil)
 
 	resp, err := server.ListProducts(ctx, req)
 	assert.NoError(t, err)
@@ -294,8 +467,31 @@ func TestInventoryGRPCServer_ListProducts(t *testing.T) {
 		assert.Equal(t, products[i].ID, prod.Id)
 		assert.Equal(t, products[i].Name, prod.Name)
 		assert.Equal(t, int32(products[i].Quantity), prod.Quantity)
-		assert.Equal(t, products[i].Price, prod.Price)
+		assert.Equal(t, products[i].Price.Amount, prod.Price.GetAmountMinor())
+		assert.Equal(t, products[i].Price.Currency, prod.Price.GetCurrencyCode())
+	}
+
+	mockUC.AssertExpectations(t)
+}
+
+func TestInventoryGRPCServer_ListProducts_Filter(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	mockUC := new(MockInventoryUseCase)
+	server := NewInventoryGRPCServer(mockUC, logger)
+
+	req := &inventory_service.ListProductsRequest{
+		Category: "electronics",
+		Status:   inventory_service.ProductStatus_PRODUCT_STATUS_ACTIVE,
 	}
+	mockUC.On("ListProducts", ctx, domain.ProductFilter{
+		Category: "electronics",
+		Status:   domain.ProductStatusActive,
+	}).Return([]*domain.Product{}, nil)
+
+	resp, err := server.ListProducts(ctx, req)
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Products)
 
 	mockUC.AssertExpectations(t)
 }
@@ -308,7 +504,7 @@ func TestInventoryGRPCServer_ListProducts_Error(t *testing.T) {
 
 	req := &inventory_service.ListProductsRequest{}
 	expectedErr := errors.New("list error")
-	mockUC.On("ListProducts", ctx).Return(([]*domain.Product)(nil), expectedErr)
+	mockUC.On("ListProducts", ctx, domain.ProductFilter{}).Return(([]*domain.Product)(nil), expectedErr)
 
 	resp, err := server.ListProducts(ctx, req)
 	assert.Error(t, err)
@@ -316,3 +512,146 @@ func TestInventoryGRPCServer_ListProducts_Error(t *testing.T) {
 
 	mockUC.AssertExpectations(t)
 }
+
+func TestInventoryGRPCServer_SearchProducts(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	mockUC := new(MockInventoryUseCase)
+	server := NewInventoryGRPCServer(mockUC, logger)
+
+	mockUC.On("SearchProducts", ctx, "wid", 5).Return([]*domain.ProductSearchResult{
+		{Product: &domain.Product{ID: "1", Name: "Widget"}, Rank: 0.6, Snippet: "<b>Widget</b>"},
+	}, nil)
+
+	resp, err := server.SearchProducts(ctx, &inventory_service.SearchProductsRequest{Query: "wid", Limit: 5})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Results, 1)
+	assert.Equal(t, "1", resp.Results[0].Product.Id)
+	assert.Equal(t, 0.6, resp.Results[0].Rank)
+	assert.Equal(t, "<b>Widget</b>", resp.Results[0].Snippet)
+
+	mockUC.AssertExpectations(t)
+}
+
+func TestInventoryGRPCServer_SearchProducts_EmptyQuery(t *testing.T) {
+	ctx := context.Background()
+	mockUC := new(MockInventoryUseCase)
+	server := NewInventoryGRPCServer(mockUC, zap.NewNop())
+
+	mockUC.On("SearchProducts", ctx, "", 0).Return(nil, domain.ErrEmptySearchQuery)
+
+	resp, err := server.SearchProducts(ctx, &inventory_service.SearchProductsRequest{})
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+type fakeImportStream struct {
+	grpc.ServerStream
+	requests []*inventory_service.ImportProductsRequest
+	response *inventory_service.ImportProductsResponse
+}
+
+func (f *fakeImportStream) Context() context.Context {
+	return context.Background()
+}
+
+func (f *fakeImportStream) Recv() (*inventory_service.ImportProductsRequest, error) {
+	if len(f.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := f.requests[0]
+	f.requests = f.requests[1:]
+	return req, nil
+}
+
+func (f *fakeImportStream) SendAndClose(resp *inventory_service.ImportProductsResponse) error {
+	f.response = resp
+	return nil
+}
+
+func TestInventoryGRPCServer_ImportProducts(t *testing.T) {
+	logger := zap.NewNop()
+	mockUC := new(MockInventoryUseCase)
+	server := NewInventoryGRPCServer(mockUC, logger)
+
+	stream := &fakeImportStream{requests: []*inventory_service.ImportProductsRequest{
+		{Format: inventory_service.ProductFormat_PRODUCT_FORMAT_NDJSON, DryRun: true, Chunk: []byte(`{"sku":"A","name":"Al`)},
+		{Chunk: []byte(`pha","quantity":1,"price":1}` + "\n")},
+	}}
+	mockUC.On("ImportProducts", mock.Anything, `{"sku":"A","name":"Alpha","quantity":1,"price":1}`+"\n",
+		domain.ImportOptions{Format: domain.ProductFormatNDJSON, DryRun: true}).
+		Return(&domain.ImportResult{Created: 1, DryRun: true}, nil)
+
+	err := server.ImportProducts(stream)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), stream.response.Created)
+	assert.True(t, stream.response.DryRun)
+	mockUC.AssertExpectations(t)
+}
+
+func TestInventoryGRPCServer_ImportProducts_UnspecifiedFormat(t *testing.T) {
+	server := NewInventoryGRPCServer(new(MockInventoryUseCase), zap.NewNop())
+	stream := &fakeImportStream{requests: []*inventory_service.ImportProductsRequest{
+		{Chunk: []byte("sku,name,quantity,price\n")},
+	}}
+
+	err := server.ImportProducts(stream)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestInventoryGRPCServer_ScheduleProductPrice(t *testing.T) {
+	ctx := context.Background()
+	mockUC := new(MockInventoryUseCase)
+	server := NewInventoryGRPCServer(mockUC, zap.NewNop())
+
+	from := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	mockUC.On("ScheduleProductPrice", ctx, "123", usd(1299), from).
+		Return(&domain.ProductPrice{ID: "p1", ProductID: "123", Price: usd(1299), EffectiveFrom: from}, nil)
+
+	resp, err := server.ScheduleProductPrice(ctx, &inventory_service.ScheduleProductPriceRequest{
+		ProductId:     "123",
+		Price:         &inventory_service.Money{AmountMinor: 1299, CurrencyCode: "USD"},
+		EffectiveFrom: timestamppb.New(from),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "p1", resp.Price.Id)
+	assert.Equal(t, from, resp.Price.EffectiveFrom.AsTime())
+	assert.Nil(t, resp.Price.EffectiveTo)
+	mockUC.AssertExpectations(t)
+}
+
+func TestInventoryGRPCServer_ScheduleProductPrice_Invalid(t *testing.T) {
+	ctx := context.Background()
+	mockUC := new(MockInventoryUseCase)
+	server := NewInventoryGRPCServer(mockUC, zap.NewNop())
+
+	_, err := server.ScheduleProductPrice(ctx, &inventory_service.ScheduleProductPriceRequest{ProductId: "123"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mockUC.On("ScheduleProductPrice", ctx, "123", mock.Anything, mock.Anything).
+		Return((*domain.ProductPrice)(nil), domain.ErrCurrencyMismatch)
+	_, err = server.ScheduleProductPrice(ctx, &inventory_service.ScheduleProductPriceRequest{
+		ProductId: "123",
+		Price:     &inventory_service.Money{AmountMinor: 1299, CurrencyCode: "EUR"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestInventoryGRPCServer_ListProductPrices(t *testing.T) {
+	ctx := context.Background()
+	mockUC := new(MockInventoryUseCase)
+	server := NewInventoryGRPCServer(mockUC, zap.NewNop())
+
+	from := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	mockUC.On("ListProductPrices", ctx, "123").Return([]*domain.ProductPrice{
+		{ID: "p1", ProductID: "123", Price: usd(999), EffectiveFrom: from, EffectiveTo: &to},
+		{ID: "p2", ProductID: "123", Price: usd(1299), EffectiveFrom: to},
+	}, nil)
+
+	resp, err := server.ListProductPrices(ctx, &inventory_service.ListProductPricesRequest{ProductId: "123"})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Prices, 2)
+	assert.Equal(t, to, resp.Prices[0].EffectiveTo.AsTime())
+	assert.Equal(t, int64(1299), resp.Prices[1].Price.GetAmountMinor())
+}
@@ -1,29 +1,26 @@
 package grpc
 
 import (
-	"fmt"
 	"inventory-service/internal/usecases"
-	"net"
 
 	"github.com/jakkapat-chongsuwat/go-microservice/proto/inventory_service"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
-func StartGRPCServer(port string, useCase usecases.InventoryUseCase, logger *zap.Logger) error {
-	lis, err := net.Listen("tcp", ":"+port)
-	if err != nil {
-		return fmt.Errorf("failed to listen on port %s: %w", port, err)
-	}
-
-	grpcServer := grpc.NewServer()
+// NewGRPCServer registers the InventoryService and the health service on a
+// new gRPC server. The caller serves it and stops it.
+func NewGRPCServer(useCase usecases.InventoryUseCase, logger *zap.Logger, healthServer grpc_health_v1.HealthServer, opts ...grpc.ServerOption) *grpc.Server {
+	grpcServer := grpc.NewServer(opts...)
 
 	inventory_service.RegisterInventoryServiceServer(grpcServer, NewInventoryGRPCServer(useCase, logger))
 
+	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
+
 	// for testing purpose
 	reflection.Register(grpcServer)
 
-	logger.Info("Starting InventoryService gRPC server", zap.String("port", port))
-	return grpcServer.Serve(lis)
+	return grpcServer
 }
@@ -0,0 +1,141 @@
+package mappers
+
+import (
+	"fmt"
+
+	"inventory-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/inventory_service"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func MapProductToProto(product *domain.Product) *inventory_service.Product {
+	return &inventory_service.Product{
+		Id:          product.ID,
+		Sku:         product.SKU,
+		Name:        product.Name,
+		Description: product.Description,
+		Category:    product.Category,
+		Attributes:  MapAttributesToProto(product.Attributes),
+		Status:      MapProductStatusToProto(product.Status),
+		Quantity:    int32(product.Quantity),
+		Price:       MapMoneyToProto(product.Price),
+	}
+}
+
+func MapProductsToProto(products []*domain.Product) []*inventory_service.Product {
+	res := make([]*inventory_service.Product, 0, len(products))
+	for _, product := range products {
+		res = append(res, MapProductToProto(product))
+	}
+	return res
+}
+
+func MapSearchResultsToProto(results []*domain.ProductSearchResult) []*inventory_service.ProductSearchResult {
+	res := make([]*inventory_service.ProductSearchResult, 0, len(results))
+	for _, r := range results {
+		res = append(res, &inventory_service.ProductSearchResult{
+			Product: MapProductToProto(r.Product),
+			Rank:    r.Rank,
+			Snippet: r.Snippet,
+		})
+	}
+	return res
+}
+
+func MapAttributesToProto(attrs domain.Attributes) map[string]*inventory_service.AttributeValue {
+	res := make(map[string]*inventory_service.AttributeValue, len(attrs))
+	for key, val := range attrs {
+		switch val.Type {
+		case domain.AttributeTypeNumber:
+			res[key] = &inventory_service.AttributeValue{Kind: &inventory_service.AttributeValue_NumberValue{NumberValue: val.Number}}
+		case domain.AttributeTypeBool:
+			res[key] = &inventory_service.AttributeValue{Kind: &inventory_service.AttributeValue_BoolValue{BoolValue: val.Bool}}
+		default:
+			res[key] = &inventory_service.AttributeValue{Kind: &inventory_service.AttributeValue_StringValue{StringValue: val.String}}
+		}
+	}
+	return res
+}
+
+func MapProtoToAttributes(attrs map[string]*inventory_service.AttributeValue) (domain.Attributes, error) {
+	res := make(domain.Attributes, len(attrs))
+	for key, val := range attrs {
+		if key == "" {
+			return nil, fmt.Errorf("%w: empty key", domain.ErrInvalidAttribute)
+		}
+		switch kind := val.GetKind().(type) {
+		case *inventory_service.AttributeValue_StringValue:
+			res[key] = domain.StringAttribute(kind.StringValue)
+		case *inventory_service.AttributeValue_NumberValue:
+			res[key] = domain.NumberAttribute(kind.NumberValue)
+		case *inventory_service.AttributeValue_BoolValue:
+			res[key] = domain.BoolAttribute(kind.BoolValue)
+		default:
+			return nil, fmt.Errorf("attribute %q: %w: missing value", key, domain.ErrInvalidAttribute)
+		}
+	}
+	return res, nil
+}
+
+func MapMoneyToProto(m domain.Money) *inventory_service.Money {
+	return &inventory_service.Money{
+		AmountMinor:  m.Amount,
+		CurrencyCode: m.Currency,
+	}
+}
+
+// MapProtoToMoney validates the amount; a missing price is an error.
+func MapProtoToMoney(m *inventory_service.Money) (domain.Money, error) {
+	if m == nil {
+		return domain.Money{}, fmt.Errorf("%w: price is required", domain.ErrInvalidAmount)
+	}
+	return newMoney(m.GetAmountMinor(), m.GetCurrencyCode())
+}
+
+func MapProductPriceToProto(price *domain.ProductPrice) *inventory_service.ProductPrice {
+	res := &inventory_service.ProductPrice{
+		Id:            price.ID,
+		ProductId:     price.ProductID,
+		Price:         MapMoneyToProto(price.Price),
+		EffectiveFrom: timestamppb.New(price.EffectiveFrom),
+	}
+	if price.EffectiveTo != nil {
+		res.EffectiveTo = timestamppb.New(*price.EffectiveTo)
+	}
+	return res
+}
+
+func MapProductPricesToProto(prices []*domain.ProductPrice) []*inventory_service.ProductPrice {
+	res := make([]*inventory_service.ProductPrice, 0, len(prices))
+	for _, price := range prices {
+		res = append(res, MapProductPriceToProto(price))
+	}
+	return res
+}
+
+func MapProductStatusToProto(status domain.ProductStatus) inventory_service.ProductStatus {
+	switch status {
+	case domain.ProductStatusActive:
+		return inventory_service.ProductStatus_PRODUCT_STATUS_ACTIVE
+	case domain.ProductStatusArchived:
+		return inventory_service.ProductStatus_PRODUCT_STATUS_ARCHIVED
+	default:
+		return inventory_service.ProductStatus_PRODUCT_STATUS_UNSPECIFIED
+	}
+}
+
+// MapProtoToProductStatus maps PRODUCT_STATUS_UNSPECIFIED to the empty
+// status, which callers treat as "not set".
+func MapProtoToProductStatus(status inventory_service.ProductStatus) (domain.ProductStatus, error) {
+	switch status {
+	case inventory_service.ProductStatus_PRODUCT_STATUS_UNSPECIFIED:
+		return "", nil
+	case inventory_service.ProductStatus_PRODUCT_STATUS_ACTIVE:
+		return domain.ProductStatusActive, nil
+	case inventory_service.ProductStatus_PRODUCT_STATUS_ARCHIVED:
+		return domain.ProductStatusArchived, nil
+	default:
+		return "", fmt.Errorf("%w: %v", domain.ErrInvalidProductStatus, status)
+	}
+}
@@ -5,24 +5,121 @@ import (
 	"inventory-service/internal/domain"
 )
 
-func MapCreateProductRequestToProduct(dto models.CreateProductRequest) *domain.Product {
-	return domain.NewProduct(dto.Name, dto.Quantity, dto.Price)
+func MapCreateProductRequestToProduct(dto models.CreateProductRequest) (*domain.Product, error) {
+	attrs, err := domain.AttributesFromMap(dto.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	price, err := MapMoneyDTOToMoney(dto.Price)
+	if err != nil {
+		return nil, err
+	}
+	product := domain.NewProduct(dto.Name, dto.Quantity, price)
+	if dto.SKU != "" {
+		product.SKU = dto.SKU
+	}
+	product.Description = dto.Description
+	product.Category = dto.Category
+	product.Attributes = attrs
+	return product, nil
 }
 
 func MapProductToProductResponse(product *domain.Product) models.ProductResponse {
 	return models.ProductResponse{
-		ID:       product.ID,
-		Name:     product.Name,
-		Quantity: product.Quantity,
-		Price:    product.Price,
+		ID:          product.ID,
+		SKU:         product.SKU,
+		Name:        product.Name,
+		Description: product.Description,
+		Category:    product.Category,
+		Attributes:  product.Attributes.ToMap(),
+		Status:      string(product.Status),
+		Quantity:    product.Quantity,
+		Price:       MapMoneyToMoneyDTO(product.Price),
 	}
 }
 
-func MapUpdateProductMetadataRequestToProduct(dto models.UpdateProductMetadataRequest, product *domain.Product) {
+// MapUpdateProductMetadataRequestToProduct replaces the product metadata
+// with the request. An empty SKU or status keeps the current value.
+func MapUpdateProductMetadataRequestToProduct(dto models.UpdateProductMetadataRequest, product *domain.Product) error {
+	attrs, err := domain.AttributesFromMap(dto.Attributes)
+	if err != nil {
+		return err
+	}
+	price, err := MapMoneyDTOToMoney(dto.Price)
+	if err != nil {
+		return err
+	}
+	var status domain.ProductStatus
+	if dto.Status != "" {
+		if status, err = domain.ParseProductStatus(dto.Status); err != nil {
+			return err
+		}
+	}
+	product.SKU = dto.SKU
 	product.Name = dto.Name
-	product.Price = dto.Price
+	product.Description = dto.Description
+	product.Category = dto.Category
+	product.Attributes = attrs
+	product.Status = status
+	product.Price = price
+	return nil
+}
+
+func MapMoneyDTOToMoney(dto models.MoneyDTO) (domain.Money, error) {
+	return newMoney(dto.Amount, dto.Currency)
+}
+
+func MapMoneyToMoneyDTO(m domain.Money) models.MoneyDTO {
+	return models.MoneyDTO{
+		Amount:   m.Amount,
+		Currency: m.Currency,
+	}
+}
+
+// newMoney treats an empty currency as domain.DefaultCurrency.
+func newMoney(amount int64, currency string) (domain.Money, error) {
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+	return domain.NewMoney(amount, currency)
 }
 
 func MapUpdateProductStockQuantityRequestToProduct(dto models.UpdateProductStockQuantityRequest, product *domain.Product) {
 	product.Quantity += dto.QuantityChange
 }
+
+func MapSearchResultToResponse(result *domain.ProductSearchResult) models.ProductSearchResultResponse {
+	return models.ProductSearchResultResponse{
+		Product: MapProductToProductResponse(result.Product),
+		Rank:    result.Rank,
+		Snippet: result.Snippet,
+	}
+}
+
+func MapProductPriceToResponse(price *domain.ProductPrice) models.ProductPriceResponse {
+	return models.ProductPriceResponse{
+		ID:            price.ID,
+		ProductID:     price.ProductID,
+		Price:         MapMoneyToMoneyDTO(price.Price),
+		EffectiveFrom: price.EffectiveFrom,
+		EffectiveTo:   price.EffectiveTo,
+	}
+}
+
+func MapImportResultToResponse(result *domain.ImportResult) models.ImportProductsResponse {
+	errs := make([]models.ImportRowErrorResponse, 0, len(result.Errors))
+	for _, e := range result.Errors {
+		errs = append(errs, models.ImportRowErrorResponse{
+			Row:     e.Row,
+			SKU:     e.SKU,
+			Message: e.Message,
+		})
+	}
+	return models.ImportProductsResponse{
+		Created: result.Created,
+		Updated: result.Updated,
+		Failed:  result.Failed,
+		DryRun:  result.DryRun,
+		Errors:  errs,
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"inventory-service/internal/domain"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,13 +9,19 @@ import (
 )
 
 type GormDBProduct struct {
-	ID        string         `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
-	Name      string         `gorm:"column:name"`
-	Quantity  int            `gorm:"column:quantity"`
-	Price     float64        `gorm:"column:price"`
-	CreatedAt time.Time      `gorm:"column:created_at"`
-	UpdatedAt time.Time      `gorm:"column:updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
+	ID            string            `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	SKU           string            `gorm:"column:sku;uniqueIndex:products_sku_key"`
+	Name          string            `gorm:"column:name"`
+	Description   string            `gorm:"column:description"`
+	Category      string            `gorm:"column:category;index:products_category_idx"`
+	Attributes    domain.Attributes `gorm:"column:attributes;type:jsonb"`
+	Status        string            `gorm:"column:status;index:products_status_idx"`
+	Quantity      int               `gorm:"column:quantity"`
+	PriceAmount   int64             `gorm:"column:price_amount"`
+	PriceCurrency string            `gorm:"column:price_currency"`
+	CreatedAt     time.Time         `gorm:"column:created_at"`
+	UpdatedAt     time.Time         `gorm:"column:updated_at"`
+	DeletedAt     gorm.DeletedAt    `gorm:"column:deleted_at;index"`
 }
 
 func (GormDBProduct) TableName() string {
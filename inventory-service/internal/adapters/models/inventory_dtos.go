@@ -1,14 +1,31 @@
 package models
 
+import "time"
+
+// MoneyDTO is an amount in the currency's minor unit, e.g. cents.
+type MoneyDTO struct {
+	Amount   int64  `json:"amount" example:"999"`
+	Currency string `json:"currency" example:"USD"`
+}
+
 type CreateProductRequest struct {
-	Name     string  `json:"name" example:"Widget"`
-	Quantity int     `json:"quantity" example:"100"`
-	Price    float64 `json:"price" example:"9.99"`
+	SKU         string                 `json:"sku" example:"WID-001"`
+	Name        string                 `json:"name" example:"Widget"`
+	Description string                 `json:"description" example:"A small widget"`
+	Category    string                 `json:"category" example:"hardware/widgets"`
+	Attributes  map[string]interface{} `json:"attributes"`
+	Quantity    int                    `json:"quantity" example:"100"`
+	Price       MoneyDTO               `json:"price"`
 }
 
 type UpdateProductMetadataRequest struct {
-	Name  string  `json:"name" example:"Updated Widget"`
-	Price float64 `json:"price" example:"12.99"`
+	SKU         string                 `json:"sku" example:"WID-001"`
+	Name        string                 `json:"name" example:"Updated Widget"`
+	Description string                 `json:"description" example:"A small widget"`
+	Category    string                 `json:"category" example:"hardware/widgets"`
+	Attributes  map[string]interface{} `json:"attributes"`
+	Status      string                 `json:"status" example:"ACTIVE"`
+	Price       MoneyDTO               `json:"price"`
 }
 
 type UpdateProductStockQuantityRequest struct {
@@ -16,8 +33,49 @@ type UpdateProductStockQuantityRequest struct {
 }
 
 type ProductResponse struct {
-	ID       string  `json:"id" example:"a1b2c3d4"`
-	Name     string  `json:"name" example:"Widget"`
-	Quantity int     `json:"quantity" example:"100"`
-	Price    float64 `json:"price" example:"9.99"`
+	ID          string                 `json:"id" example:"a1b2c3d4"`
+	SKU         string                 `json:"sku" example:"WID-001"`
+	Name        string                 `json:"name" example:"Widget"`
+	Description string                 `json:"description" example:"A small widget"`
+	Category    string                 `json:"category" example:"hardware/widgets"`
+	Attributes  map[string]interface{} `json:"attributes"`
+	Status      string                 `json:"status" example:"ACTIVE"`
+	Quantity    int                    `json:"quantity" example:"100"`
+	Price       MoneyDTO               `json:"price"`
+}
+
+type ProductSearchResultResponse struct {
+	Product ProductResponse `json:"product"`
+	Rank    float64         `json:"rank" example:"0.6079"`
+	// Snippet is HTML: the product text, escaped, with the matched words
+	// wrapped in <b></b>.
+	Snippet string `json:"snippet" example:"<b>Widget</b> for the kitchen &amp; garage"`
+}
+
+// ScheduleProductPriceRequest takes effect now when EffectiveFrom is omitted.
+type ScheduleProductPriceRequest struct {
+	Price         MoneyDTO   `json:"price"`
+	EffectiveFrom *time.Time `json:"effectiveFrom,omitempty" example:"2026-11-01T00:00:00Z"`
+}
+
+type ProductPriceResponse struct {
+	ID            string     `json:"id" example:"e5f6a7b8"`
+	ProductID     string     `json:"productId" example:"a1b2c3d4"`
+	Price         MoneyDTO   `json:"price"`
+	EffectiveFrom time.Time  `json:"effectiveFrom" example:"2026-11-01T00:00:00Z"`
+	EffectiveTo   *time.Time `json:"effectiveTo,omitempty" example:"2026-12-01T00:00:00Z"`
+}
+
+type ImportRowErrorResponse struct {
+	Row     int    `json:"row" example:"3"`
+	SKU     string `json:"sku,omitempty" example:"WID-001"`
+	Message string `json:"message" example:"price must not be negative"`
+}
+
+type ImportProductsResponse struct {
+	Created int                      `json:"created" example:"10"`
+	Updated int                      `json:"updated" example:"5"`
+	Failed  int                      `json:"failed" example:"1"`
+	DryRun  bool                     `json:"dryRun" example:"false"`
+	Errors  []ImportRowErrorResponse `json:"errors"`
 }
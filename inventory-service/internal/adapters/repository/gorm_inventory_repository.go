@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"inventory-service/internal/adapters/columns"
 	"inventory-service/internal/domain"
 	"inventory-service/internal/usecases"
+	"strings"
 
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -23,9 +26,20 @@ func NewGormInventoryRepo(db *gorm.DB, logger *zap.Logger) *GormInventoryReposit
 	}
 }
 
+// log returns the request-scoped logger of ctx.
+func (r *GormInventoryRepository) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, r.logger)
+}
+
 func (r *GormInventoryRepository) CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
-	if err := r.db.WithContext(ctx).Create(product).Error; err != nil {
-		r.logger.Error("failed to create product", zap.Error(err))
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return err
+		}
+		return recordPriceChange(tx, product)
+	})
+	if err != nil {
+		r.log(ctx).Error("failed to create product", zap.Error(err))
 		return nil, err
 	}
 	return product, nil
@@ -34,25 +48,102 @@ func (r *GormInventoryRepository) CreateProduct(ctx context.Context, product *do
 func (r *GormInventoryRepository) GetProduct(ctx context.Context, productId string) (*domain.Product, error) {
 	var product domain.Product
 	if err := r.db.WithContext(ctx).First(&product, "id = ?", productId).Error; err != nil {
-		r.logger.Error("failed to get product", zap.String("productId", productId), zap.Error(err))
+		r.log(ctx).Error("failed to get product", zap.String("productId", productId), zap.Error(err))
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *GormInventoryRepository) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	var product domain.Product
+	if err := r.db.WithContext(ctx).First(&product, columns.ColumnSKU+" = ?", sku).Error; err != nil {
+		r.log(ctx).Error("failed to get product by SKU", zap.String("sku", sku), zap.Error(err))
 		return nil, err
 	}
 	return &product, nil
 }
 
 func (r *GormInventoryRepository) UpdateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
-	if err := r.db.WithContext(ctx).Save(product).Error; err != nil {
-		r.logger.Error("failed to update product", zap.String("productId", product.ID), zap.Error(err))
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(product).Error; err != nil {
+			return err
+		}
+		return recordPriceChange(tx, product)
+	})
+	if err != nil {
+		r.log(ctx).Error("failed to update product", zap.String("productId", product.ID), zap.Error(err))
 		return nil, err
 	}
 	return product, nil
 }
 
-func (r *GormInventoryRepository) ListProducts(ctx context.Context) ([]*domain.Product, error) {
+func (r *GormInventoryRepository) ListProducts(ctx context.Context, filter domain.ProductFilter) ([]*domain.Product, error) {
 	var products []*domain.Product
-	if err := r.db.WithContext(ctx).Find(&products).Error; err != nil {
-		r.logger.Error("failed to list products", zap.Error(err))
+	query := r.db.WithContext(ctx)
+	if filter.Category != "" {
+		query = query.Where(columns.ColumnCategory+" = ? OR "+columns.ColumnCategory+" LIKE ?",
+			filter.Category, escapeLike(filter.Category)+domain.CategorySeparator+"%")
+	}
+	if filter.Status != "" {
+		query = query.Where(columns.ColumnStatus+" = ?", filter.Status)
+	}
+	if err := query.Find(&products).Error; err != nil {
+		r.log(ctx).Error("failed to list products", zap.Error(err))
 		return nil, err
 	}
 	return products, nil
 }
+
+func (r *GormInventoryRepository) GetProductsBySKUs(ctx context.Context, skus []string) ([]*domain.Product, error) {
+	var products []*domain.Product
+	if len(skus) == 0 {
+		return products, nil
+	}
+	if err := r.db.WithContext(ctx).Where(columns.ColumnSKU+" IN ?", skus).Find(&products).Error; err != nil {
+		r.log(ctx).Error("failed to get products by SKU", zap.Int("count", len(skus)), zap.Error(err))
+		return nil, err
+	}
+	return products, nil
+}
+
+// SaveProducts inserts or updates the given products in one transaction, so
+// either all of them, and their price history, are persisted or none are.
+func (r *GormInventoryRepository) SaveProducts(ctx context.Context, products []*domain.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&products).Error; err != nil {
+			return err
+		}
+		for _, p := range products {
+			if err := recordPriceChange(tx, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.log(ctx).Error("failed to save products", zap.Int("count", len(products)), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *GormInventoryRepository) ListProductsInBatches(ctx context.Context, batchSize int, fn func([]*domain.Product) error) error {
+	var batch []*domain.Product
+	err := r.db.WithContext(ctx).FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+	if err != nil {
+		r.log(ctx).Error("failed to list products in batches", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
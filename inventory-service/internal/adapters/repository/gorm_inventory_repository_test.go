@@ -18,6 +18,10 @@ import (
 	"gorm.io/gorm"
 )
 
+type fixedClock struct{ now time.Time }
+
+func (c fixedClock) Now() time.Time { return c.now }
+
 func TestGormInventoryRepository(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	ctx := context.Background()
@@ -52,13 +56,13 @@ func TestGormInventoryRepository(t *testing.T) {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	require.NoError(t, err)
 
-	err = db.AutoMigrate(&domain.Product{})
+	err = db.AutoMigrate(&domain.Product{}, &domain.ProductPrice{})
 	require.NoError(t, err)
 
 	repo := NewGormInventoryRepo(db, logger)
 
 	t.Run("CreateAndGetProduct_Success", func(t *testing.T) {
-		product := domain.NewProduct("Test Product", 50, 19.99)
+		product := domain.NewProduct("Test Product", 50, domain.Money{Amount: 1999, Currency: "USD"})
 		created, err := repo.CreateProduct(ctx, product)
 		require.NoError(t, err)
 		require.NotEmpty(t, created.ID)
@@ -70,7 +74,7 @@ func TestGormInventoryRepository(t *testing.T) {
 	})
 
 	t.Run("UpdateProduct_Success", func(t *testing.T) {
-		product := domain.NewProduct("Update Test", 100, 29.99)
+		product := domain.NewProduct("Update Test", 100, domain.Money{Amount: 2999, Currency: "USD"})
 		created, err := repo.CreateProduct(ctx, product)
 		require.NoError(t, err)
 
@@ -82,18 +86,167 @@ func TestGormInventoryRepository(t *testing.T) {
 	})
 
 	t.Run("ListProducts_Success", func(t *testing.T) {
-		p1 := domain.NewProduct("List Product 1", 10, 9.99)
-		p2 := domain.NewProduct("List Product 2", 20, 14.99)
+		p1 := domain.NewProduct("List Product 1", 10, domain.Money{Amount: 999, Currency: "USD"})
+		p2 := domain.NewProduct("List Product 2", 20, domain.Money{Amount: 1499, Currency: "USD"})
 		_, err := repo.CreateProduct(ctx, p1)
 		require.NoError(t, err)
 		_, err = repo.CreateProduct(ctx, p2)
 		require.NoError(t, err)
 
-		products, err := repo.ListProducts(ctx)
+		products, err := repo.ListProducts(ctx, domain.ProductFilter{})
 		require.NoError(t, err)
 		require.GreaterOrEqual(t, len(products), 2)
 	})
 
+	t.Run("ListProducts_CategoryFilter", func(t *testing.T) {
+		audio := domain.NewProduct("Headphones", 1, domain.Money{Amount: 9900, Currency: "USD"})
+		audio.Category = "electronics/audio"
+		audiophile := domain.NewProduct("Wildcard", 1, domain.Money{Amount: 9900, Currency: "USD"})
+		audiophile.Category = "electronics/audio_hifi"
+		archived := domain.NewProduct("Old Radio", 1, domain.Money{Amount: 1000, Currency: "USD"})
+		archived.Category = "electronics"
+		archived.Status = domain.ProductStatusArchived
+		for _, p := range []*domain.Product{audio, audiophile, archived} {
+			_, err := repo.CreateProduct(ctx, p)
+			require.NoError(t, err)
+		}
+
+		products, err := repo.ListProducts(ctx, domain.ProductFilter{Category: "electronics"})
+		require.NoError(t, err)
+		require.Len(t, products, 3)
+
+		products, err = repo.ListProducts(ctx, domain.ProductFilter{Category: "electronics/audio"})
+		require.NoError(t, err)
+		require.Len(t, products, 1)
+		require.Equal(t, audio.ID, products[0].ID)
+
+		products, err = repo.ListProducts(ctx, domain.ProductFilter{Category: "electronics", Status: domain.ProductStatusArchived})
+		require.NoError(t, err)
+		require.Len(t, products, 1)
+		require.Equal(t, archived.ID, products[0].ID)
+	})
+
+	t.Run("GetProductBySKU", func(t *testing.T) {
+		p := domain.NewProduct("SKU Product", 1, domain.Money{Amount: 100, Currency: "USD"})
+		p.SKU = "SKU-LOOKUP-1"
+		p.Attributes = domain.Attributes{"color": domain.StringAttribute("red")}
+		_, err := repo.CreateProduct(ctx, p)
+		require.NoError(t, err)
+
+		fetched, err := repo.GetProductBySKU(ctx, "SKU-LOOKUP-1")
+		require.NoError(t, err)
+		require.Equal(t, p.ID, fetched.ID)
+		require.Equal(t, domain.StringAttribute("red"), fetched.Attributes["color"])
+	})
+
+	t.Run("SearchProducts", func(t *testing.T) {
+		widget := domain.NewProduct("Searchable Sprocket", 1, domain.Money{Amount: 100, Currency: "USD"})
+		widget.Description = "Fits every bicycle"
+		other := domain.NewProduct("Bicycle Bell", 1, domain.Money{Amount: 100, Currency: "USD"})
+		other.Description = "Loud sprocket-free bell"
+		for _, p := range []*domain.Product{widget, other} {
+			_, err := repo.CreateProduct(ctx, p)
+			require.NoError(t, err)
+		}
+
+		query, err := domain.NewProductSearchQuery("sprock", 0)
+		require.NoError(t, err)
+		results, err := repo.SearchProducts(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.Equal(t, widget.ID, results[0].Product.ID)
+		require.Greater(t, results[0].Rank, results[1].Rank)
+		require.Contains(t, results[0].Snippet, domain.HighlightStart+"Sprocket"+domain.HighlightStop)
+
+		query, err = domain.NewProductSearchQuery("sprocket bell", 0)
+		require.NoError(t, err)
+		results, err = repo.SearchProducts(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, other.ID, results[0].Product.ID)
+
+		tagged := domain.NewProduct("Tagged Gizmo", 1, domain.Money{Amount: 100, Currency: "USD"})
+		tagged.Description = `<img src=x onerror="alert(1)"> gizmo & co`
+		_, err = repo.CreateProduct(ctx, tagged)
+		require.NoError(t, err)
+		query, err = domain.NewProductSearchQuery("gizmo", 0)
+		require.NoError(t, err)
+		results, err = repo.SearchProducts(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.NotContains(t, results[0].Snippet, "<img", "the product text is escaped")
+		require.Contains(t, results[0].Snippet, domain.HighlightStart+"Gizmo"+domain.HighlightStop)
+	})
+
+	t.Run("PriceHistory", func(t *testing.T) {
+		product := domain.NewProduct("Kettle", 5, domain.Money{Amount: 2500, Currency: "USD"})
+		_, err := repo.CreateProduct(ctx, product)
+		require.NoError(t, err)
+		created := product.UpdatedAt
+
+		next := created.Add(48 * time.Hour)
+		later := created.Add(96 * time.Hour)
+		require.NoError(t, repo.SchedulePrice(ctx, domain.NewProductPrice(product.ID, domain.Money{Amount: 2000, Currency: "USD"}, later)))
+		require.NoError(t, repo.SchedulePrice(ctx, domain.NewProductPrice(product.ID, domain.Money{Amount: 2200, Currency: "USD"}, next)))
+
+		prices, err := repo.ListProductPrices(ctx, product.ID)
+		require.NoError(t, err)
+		require.Len(t, prices, 3)
+		require.WithinDuration(t, next, *prices[0].EffectiveTo, time.Millisecond)
+		require.WithinDuration(t, later, *prices[1].EffectiveTo, time.Millisecond)
+		require.Nil(t, prices[2].EffectiveTo)
+
+		for asOf, want := range map[time.Time]int64{
+			created.Add(time.Hour): 2500,
+			next.Add(time.Hour):    2200,
+			later.Add(time.Hour):   2000,
+		} {
+			resolved, err := repo.GetEffectivePrices(ctx, []string{product.ID}, asOf)
+			require.NoError(t, err)
+			require.Equal(t, want, resolved[product.ID].Amount, asOf)
+		}
+
+		product.Quantity = 4
+		_, err = repo.UpdateProduct(ctx, product)
+		require.NoError(t, err)
+		prices, err = repo.ListProductPrices(ctx, product.ID)
+		require.NoError(t, err)
+		require.Len(t, prices, 3, "an unchanged price adds no history")
+	})
+
+	t.Run("PriceHistory_NonUTCClock", func(t *testing.T) {
+		// The clock and the database session are in different zones,
+		// neither of them UTC; the history must still hold instants.
+		zoned, err := gorm.Open(postgres.Open(dsn+" TimeZone=America/New_York"), &gorm.Config{})
+		require.NoError(t, err)
+		zonedRepo := NewGormInventoryRepo(zoned, logger)
+		bangkok := time.FixedZone("ICT", 7*60*60)
+		defer func(c domain.ClockInterface) { domain.Clock = c }(domain.Clock)
+		start := time.Now().In(bangkok).Truncate(time.Second)
+		domain.Clock = fixedClock{now: start}
+
+		product := domain.NewProduct("Teapot", 5, domain.Money{Amount: 1500, Currency: "USD"})
+		_, err = zonedRepo.CreateProduct(ctx, product)
+		require.NoError(t, err)
+		change := start.Add(time.Hour)
+		require.NoError(t, zonedRepo.SchedulePrice(ctx, domain.NewProductPrice(product.ID, domain.Money{Amount: 1200, Currency: "USD"}, change)))
+
+		prices, err := zonedRepo.ListProductPrices(ctx, product.ID)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.WithinDuration(t, start, prices[0].EffectiveFrom, time.Millisecond)
+		require.WithinDuration(t, change, *prices[0].EffectiveTo, time.Millisecond)
+
+		for asOf, want := range map[time.Time]int64{
+			start.Add(30 * time.Minute):  1500,
+			change.Add(30 * time.Minute): 1200,
+		} {
+			resolved, err := zonedRepo.GetEffectivePrices(ctx, []string{product.ID}, asOf)
+			require.NoError(t, err)
+			require.Equal(t, want, resolved[product.ID].Amount, asOf)
+		}
+	})
+
 	t.Run("GetProduct_NotFound", func(t *testing.T) {
 		nonExistingID := uuid.NewString()
 		fetched, err := repo.GetProduct(ctx, nonExistingID)
@@ -105,7 +258,7 @@ func TestGormInventoryRepository(t *testing.T) {
 		err = db.Exec("DELETE FROM products").Error
 		require.NoError(t, err)
 
-		products, err := repo.ListProducts(ctx)
+		products, err := repo.ListProducts(ctx, domain.ProductFilter{})
 		require.NoError(t, err)
 		require.Len(t, products, 0)
 	})
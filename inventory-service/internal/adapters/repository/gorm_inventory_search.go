@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"inventory-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// productSearchVector must stay identical to the expression behind the
+// products_search_idx GIN index, otherwise Postgres cannot use the index.
+// The 'simple' configuration does no stemming, which keeps prefix matches
+// predictable.
+const productSearchVector = `(setweight(to_tsvector('simple', name), 'A') || ` +
+	`setweight(to_tsvector('simple', sku), 'A') || ` +
+	`setweight(to_tsvector('simple', description), 'B'))`
+
+const searchProductsSQL = `SELECT products.*, ` +
+	`ts_rank(` + productSearchVector + `, query) AS rank, ` +
+	`ts_headline('simple', name || ' ' || description, query, ?) AS snippet ` +
+	`FROM products, to_tsquery('simple', ?) AS query ` +
+	`WHERE ` + productSearchVector + ` @@ query ` +
+	`ORDER BY rank DESC, name ` +
+	`LIMIT ?`
+
+// headlineOptions mark matches with domain.MatchStart and MatchStop rather
+// than HTML tags, because ts_headline does not escape the text around them.
+var headlineOptions = fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxWords=20, MinWords=5, MaxFragments=2`,
+	domain.MatchStart, domain.MatchStop)
+
+type productSearchRow struct {
+	domain.Product
+	Rank    float64
+	Snippet string
+}
+
+func (r *GormInventoryRepository) SearchProducts(ctx context.Context, query domain.ProductSearchQuery) ([]*domain.ProductSearchResult, error) {
+	var rows []productSearchRow
+	err := r.db.WithContext(ctx).
+		Raw(searchProductsSQL, headlineOptions, toPrefixTSQuery(query.Terms), query.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		r.log(ctx).Error("failed to search products", zap.Strings("terms", query.Terms), zap.Error(err))
+		return nil, err
+	}
+
+	results := make([]*domain.ProductSearchResult, 0, len(rows))
+	for i := range rows {
+		results = append(results, &domain.ProductSearchResult{
+			Product: &rows[i].Product,
+			Rank:    rows[i].Rank,
+			Snippet: domain.NewSearchSnippet(rows[i].Snippet),
+		})
+	}
+	return results, nil
+}
+
+// toPrefixTSQuery turns terms into "term1:* & term2:*". Terms come from
+// domain.TokenizeSearchText and only contain letters and digits, so they
+// need no tsquery escaping.
+func toPrefixTSQuery(terms []string) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		parts = append(parts, term+":*")
+	}
+	return strings.Join(parts, " & ")
+}
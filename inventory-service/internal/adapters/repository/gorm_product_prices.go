@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"inventory-service/internal/adapters/columns"
+	"inventory-service/internal/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SchedulePrice inserts a price history entry. The entry it starts inside is
+// closed at its EffectiveFrom and the new entry runs until the next later
+// one; an entry starting at the same instant is replaced.
+func (r *GormInventoryRepository) SchedulePrice(ctx context.Context, price *domain.ProductPrice) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return schedulePrice(tx, price)
+	})
+	if err != nil {
+		r.log(ctx).Error("failed to schedule price", zap.String("productId", price.ProductID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *GormInventoryRepository) ListProductPrices(ctx context.Context, productID string) ([]*domain.ProductPrice, error) {
+	var prices []*domain.ProductPrice
+	err := r.db.WithContext(ctx).
+		Where(columns.ColumnProductID+" = ?", productID).
+		Order(columns.ColumnEffectiveFrom).
+		Find(&prices).Error
+	if err != nil {
+		r.log(ctx).Error("failed to list product prices", zap.String("productId", productID), zap.Error(err))
+		return nil, err
+	}
+	return prices, nil
+}
+
+// GetEffectivePrices returns the price in effect at asOf for each product
+// that has one. Products without history are left out of the map.
+func (r *GormInventoryRepository) GetEffectivePrices(ctx context.Context, productIDs []string, asOf time.Time) (map[string]domain.Money, error) {
+	res := make(map[string]domain.Money, len(productIDs))
+	if len(productIDs) == 0 {
+		return res, nil
+	}
+	var prices []*domain.ProductPrice
+	err := effectiveAt(r.db.WithContext(ctx), asOf).
+		Where(columns.ColumnProductID+" IN ?", productIDs).
+		Order(columns.ColumnEffectiveFrom).
+		Find(&prices).Error
+	if err != nil {
+		r.log(ctx).Error("failed to get effective prices", zap.Int("count", len(productIDs)), zap.Error(err))
+		return nil, err
+	}
+	for _, p := range prices {
+		res[p.ProductID] = p.Price
+	}
+	return res, nil
+}
+
+func effectiveAt(db *gorm.DB, t time.Time) *gorm.DB {
+	t = t.UTC()
+	return db.Where(columns.ColumnEffectiveFrom+" <= ? AND ("+columns.ColumnEffectiveTo+" IS NULL OR "+columns.ColumnEffectiveTo+" > ?)", t, t)
+}
+
+// recordPriceChange adds a history entry from the product's UpdatedAt when
+// its price differs from the one in effect at that time. UpdatedAt is in
+// the clock's zone, so it is moved to UTC like every stored instant.
+func recordPriceChange(tx *gorm.DB, product *domain.Product) error {
+	at := product.UpdatedAt
+	if at.IsZero() {
+		at = domain.Clock.Now()
+	}
+	at = at.UTC()
+	var current domain.ProductPrice
+	err := effectiveAt(tx, at).
+		Where(columns.ColumnProductID+" = ?", product.ID).
+		Order(columns.ColumnEffectiveFrom + " DESC").
+		Take(&current).Error
+	switch {
+	case err == nil && current.Price == product.Price:
+		return nil
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		return err
+	}
+	return schedulePrice(tx, domain.NewProductPrice(product.ID, product.Price, at))
+}
+
+func schedulePrice(tx *gorm.DB, price *domain.ProductPrice) error {
+	// Lock the product so concurrent schedules cannot interleave their
+	// range updates.
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select(columns.ColumnID).
+		Take(&domain.Product{}, columns.ColumnID+" = ?", price.ProductID).Error
+	if err != nil {
+		return err
+	}
+
+	byProduct := tx.Model(&domain.ProductPrice{}).
+		Where(columns.ColumnProductID+" = ?", price.ProductID).
+		Session(&gorm.Session{})
+
+	err = byProduct.
+		Where(columns.ColumnEffectiveFrom+" = ?", price.EffectiveFrom).
+		Delete(&domain.ProductPrice{}).Error
+	if err != nil {
+		return err
+	}
+
+	var next domain.ProductPrice
+	err = byProduct.
+		Where(columns.ColumnEffectiveFrom+" > ?", price.EffectiveFrom).
+		Order(columns.ColumnEffectiveFrom).
+		Take(&next).Error
+	switch {
+	case err == nil:
+		price.EffectiveTo = &next.EffectiveFrom
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return err
+	}
+
+	err = effectiveAt(byProduct, price.EffectiveFrom).
+		Update(columns.ColumnEffectiveTo, price.EffectiveFrom).Error
+	if err != nil {
+		return err
+	}
+	return tx.Create(price).Error
+}
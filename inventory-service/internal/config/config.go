@@ -0,0 +1,74 @@
+// Package config is the typed configuration of inventory-service.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	platformconfig "github.com/jakkapat-chongsuwat/go-microservice/platform/config"
+)
+
+type Config struct {
+	GRPCPort        int           `env:"GRPC_PORT" default:"30051" yaml:"grpc_port"`
+	HTTPPort        int           `env:"HTTP_PORT" default:"30052" yaml:"http_port"`
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" default:"25s" yaml:"shutdown_timeout"`
+	RepoType        string        `env:"REPO_TYPE" default:"gorm" yaml:"repo_type" validate:"oneof=gorm"`
+
+	APIKeys  APIKeys                 `yaml:"api_keys"`
+	Database platformconfig.Database `yaml:"database"`
+}
+
+// APIKeys configures the partner API keys accepted by the HTTP API. Keys are
+// verified with user-service; without its address they are not checked.
+type APIKeys struct {
+	UserServiceAddress string `env:"USER_SERVICE_ADDRESS" yaml:"user_service_address"`
+	// Required rejects requests without a key instead of serving them
+	// anonymously.
+	Required      bool          `env:"API_KEY_REQUIRED" yaml:"required"`
+	CacheTTL      time.Duration `env:"API_KEY_CACHE_TTL" default:"30s" yaml:"cache_ttl"`
+	VerifyTimeout time.Duration `env:"API_KEY_VERIFY_TIMEOUT" default:"1s" yaml:"verify_timeout"`
+}
+
+func (a *APIKeys) Validate() error {
+	var errs []error
+	if a.Required && a.UserServiceAddress == "" {
+		errs = append(errs, errors.New("API_KEY_REQUIRED needs USER_SERVICE_ADDRESS to verify keys"))
+	}
+	if a.CacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("API_KEY_CACHE_TTL must not be negative, got %s", a.CacheTTL))
+	}
+	if a.VerifyTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("API_KEY_VERIFY_TIMEOUT must be positive, got %s", a.VerifyTimeout))
+	}
+	return errors.Join(errs...)
+}
+
+// Enabled reports whether presented keys are verified.
+func (a APIKeys) Enabled() bool {
+	return a.UserServiceAddress != ""
+}
+
+// Load reads the configuration from the environment, .env and the optional
+// YAML file.
+func Load(yamlFile string) (*Config, error) {
+	cfg := &Config{Database: platformconfig.Database{Name: "inventory_service"}}
+	if err := platformconfig.Load(cfg, platformconfig.WithYAML(yamlFile)); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+func (c *Config) Validate() error {
+	errs := []error{
+		platformconfig.ValidatePort("GRPC_PORT", c.GRPCPort),
+		platformconfig.ValidatePort("HTTP_PORT", c.HTTPPort),
+	}
+	if c.GRPCPort == c.HTTPPort {
+		errs = append(errs, fmt.Errorf("GRPC_PORT and HTTP_PORT must differ, both are %d", c.GRPCPort))
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("SHUTDOWN_TIMEOUT must be positive, got %s", c.ShutdownTimeout))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load("")
+	require.NoError(t, err)
+
+	assert.Equal(t, 30051, cfg.GRPCPort)
+	assert.Equal(t, 30052, cfg.HTTPPort)
+	assert.Equal(t, "gorm", cfg.RepoType)
+	assert.Equal(t, "inventory_service", cfg.Database.Name)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Setenv("GRPC_PORT", "0")
+	t.Setenv("SHUTDOWN_TIMEOUT", "0s")
+
+	_, err := Load("")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GRPC_PORT must be between 1 and 65535, got 0")
+	assert.Contains(t, err.Error(), "SHUTDOWN_TIMEOUT must be positive")
+}
+
+func TestLoad_APIKeys(t *testing.T) {
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.False(t, cfg.APIKeys.Enabled())
+	assert.Equal(t, 30*time.Second, cfg.APIKeys.CacheTTL)
+
+	t.Setenv("API_KEY_REQUIRED", "true")
+	_, err = Load("")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "API_KEY_REQUIRED needs USER_SERVICE_ADDRESS")
+
+	t.Setenv("USER_SERVICE_ADDRESS", "user-service:50051")
+	cfg, err = Load("")
+	require.NoError(t, err)
+	assert.True(t, cfg.APIKeys.Enabled())
+	assert.True(t, cfg.APIKeys.Required)
+}
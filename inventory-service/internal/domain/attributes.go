@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type AttributeType string
+
+const (
+	AttributeTypeString AttributeType = "string"
+	AttributeTypeNumber AttributeType = "number"
+	AttributeTypeBool   AttributeType = "bool"
+)
+
+var ErrInvalidAttribute = errors.New("invalid product attribute")
+
+// AttributeValue is a typed product attribute value. Only the field that
+// matches Type is meaningful.
+type AttributeValue struct {
+	Type   AttributeType
+	String string
+	Number float64
+	Bool   bool
+}
+
+func StringAttribute(v string) AttributeValue {
+	return AttributeValue{Type: AttributeTypeString, String: v}
+}
+
+func NumberAttribute(v float64) AttributeValue {
+	return AttributeValue{Type: AttributeTypeNumber, Number: v}
+}
+
+func BoolAttribute(v bool) AttributeValue {
+	return AttributeValue{Type: AttributeTypeBool, Bool: v}
+}
+
+// AttributeValueOf converts a decoded JSON value into an AttributeValue.
+// Objects, arrays and null are rejected.
+func AttributeValueOf(v interface{}) (AttributeValue, error) {
+	switch val := v.(type) {
+	case string:
+		return StringAttribute(val), nil
+	case bool:
+		return BoolAttribute(val), nil
+	case float64:
+		return NumberAttribute(val), nil
+	case int:
+		return NumberAttribute(float64(val)), nil
+	case json.Number:
+		f, err := val.Float64()
+		if err != nil {
+			return AttributeValue{}, fmt.Errorf("%w: %v", ErrInvalidAttribute, err)
+		}
+		return NumberAttribute(f), nil
+	default:
+		return AttributeValue{}, fmt.Errorf("%w: unsupported value type %T", ErrInvalidAttribute, v)
+	}
+}
+
+// Interface returns the attribute as a plain Go value suitable for JSON.
+func (v AttributeValue) Interface() interface{} {
+	switch v.Type {
+	case AttributeTypeNumber:
+		return v.Number
+	case AttributeTypeBool:
+		return v.Bool
+	default:
+		return v.String
+	}
+}
+
+// Attributes are typed key/value pairs describing a product. They are
+// stored as a JSON object whose values keep their JSON type.
+type Attributes map[string]AttributeValue
+
+func AttributesFromMap(m map[string]interface{}) (Attributes, error) {
+	attrs := make(Attributes, len(m))
+	for key, raw := range m {
+		if key == "" {
+			return nil, fmt.Errorf("%w: empty key", ErrInvalidAttribute)
+		}
+		val, err := AttributeValueOf(raw)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", key, err)
+		}
+		attrs[key] = val
+	}
+	return attrs, nil
+}
+
+func (a Attributes) ToMap() map[string]interface{} {
+	m := make(map[string]interface{}, len(a))
+	for key, val := range a {
+		m[key] = val.Interface()
+	}
+	return m
+}
+
+func (a Attributes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.ToMap())
+}
+
+func (a *Attributes) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var m map[string]interface{}
+	if err := dec.Decode(&m); err != nil {
+		return err
+	}
+	attrs, err := AttributesFromMap(m)
+	if err != nil {
+		return err
+	}
+	*a = attrs
+	return nil
+}
+
+// Value stores attributes in a jsonb column.
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	b, err := a.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan reads attributes back from a jsonb column.
+func (a *Attributes) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = Attributes{}
+		return nil
+	case []byte:
+		return a.UnmarshalJSON(v)
+	case string:
+		return a.UnmarshalJSON([]byte(v))
+	default:
+		return fmt.Errorf("cannot scan %T into Attributes", src)
+	}
+}
+
+// GormDataType lets AutoMigrate create the column with the right type.
+func (Attributes) GormDataType() string {
+	return "jsonb"
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "strings"
+
+// CategorySeparator separates the levels of a category path such as
+// "electronics/audio/headphones".
+const CategorySeparator = "/"
+
+// NormalizeCategory trims every level of a category path and drops empty
+// levels, so " Electronics / Audio/ " becomes "Electronics/Audio".
+func NormalizeCategory(category string) string {
+	parts := strings.Split(category, CategorySeparator)
+	levels := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			levels = append(levels, part)
+		}
+	}
+	return strings.Join(levels, CategorySeparator)
+}
+
+// InCategory reports whether the product belongs to category or to one of
+// its sub-categories.
+func (p *Product) InCategory(category string) bool {
+	category = NormalizeCategory(category)
+	if category == "" {
+		return true
+	}
+	return p.Category == category || strings.HasPrefix(p.Category, category+CategorySeparator)
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,24 +21,60 @@ func (RealClock) Now() time.Time {
 
 var Clock ClockInterface = RealClock{}
 
+type ProductStatus string
+
+const (
+	ProductStatusActive   ProductStatus = "ACTIVE"
+	ProductStatusArchived ProductStatus = "ARCHIVED"
+)
+
+var ErrInvalidProductStatus = errors.New("invalid product status")
+
+func ParseProductStatus(s string) (ProductStatus, error) {
+	switch ProductStatus(strings.ToUpper(strings.TrimSpace(s))) {
+	case ProductStatusActive:
+		return ProductStatusActive, nil
+	case ProductStatusArchived:
+		return ProductStatusArchived, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidProductStatus, s)
+	}
+}
+
 type Product struct {
-	ID        string
-	Name      string
-	Quantity  int
-	Price     float64
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          string
+	SKU         string
+	Name        string
+	Description string
+	Category    string
+	Attributes  Attributes
+	Status      ProductStatus
+	Quantity    int
+	Price       Money `gorm:"embedded;embeddedPrefix:price_"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// ProductFilter narrows ListProducts. Zero values match everything; a
+// Category also matches every category nested below it.
+type ProductFilter struct {
+	Category string
+	Status   ProductStatus
 }
 
-func NewProduct(name string, quantity int, price float64) *Product {
+func NewProduct(name string, quantity int, price Money) *Product {
 	now := Clock.Now()
+	id := uuid.NewString()
 	return &Product{
-		ID:        uuid.NewString(),
-		Name:      name,
-		Quantity:  quantity,
-		Price:     price,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:         id,
+		SKU:        id,
+		Name:       name,
+		Attributes: Attributes{},
+		Status:     ProductStatusActive,
+		Quantity:   quantity,
+		Price:      price,
+		CreatedAt:  now,
+		UpdatedAt:  now,
 	}
 }
 
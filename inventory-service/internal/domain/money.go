@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used when a price is given without a currency and for
+// prices that existed before currencies were stored.
+const DefaultCurrency = "USD"
+
+var (
+	ErrInvalidCurrency  = errors.New("invalid currency code")
+	ErrNegativeAmount   = errors.New("amount must not be negative")
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// currencyExponents lists ISO 4217 currencies whose minor unit is not a
+// hundredth of the major unit. Every other currency uses two decimals.
+var currencyExponents = map[string]int{
+	"BHD": 3, "CLP": 0, "IQD": 3, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0,
+	"KWD": 3, "LYD": 3, "OMR": 3, "PYG": 0, "TND": 3, "UGX": 0, "VND": 0,
+}
+
+// Money is an amount in the minor unit of its currency, e.g. cents for USD,
+// so sums never accumulate rounding errors.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// NewMoney validates amount and currency. The currency code is upper-cased.
+func NewMoney(amount int64, currency string) (Money, error) {
+	m := Money{Amount: amount, Currency: strings.ToUpper(strings.TrimSpace(currency))}
+	if err := m.Validate(); err != nil {
+		return Money{}, err
+	}
+	return m, nil
+}
+
+// ParseMoney parses a decimal amount in major units such as "12.5" without
+// going through float64. More decimals than the currency allows are rejected.
+func ParseMoney(amount, currency string) (Money, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if !isCurrencyCode(currency) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+	}
+	exp := CurrencyExponent(currency)
+
+	raw := strings.TrimSpace(amount)
+	whole, frac, _ := strings.Cut(raw, ".")
+	if whole == "" && frac == "" || len(frac) > exp {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, raw)
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+	minor, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, raw)
+	}
+	return NewMoney(minor, currency)
+}
+
+// CurrencyExponent returns the number of decimals of the currency's minor unit.
+func CurrencyExponent(currency string) int {
+	if exp, ok := currencyExponents[currency]; ok {
+		return exp
+	}
+	return 2
+}
+
+func (m Money) Validate() error {
+	if !isCurrencyCode(m.Currency) {
+		return fmt.Errorf("%w: %q", ErrInvalidCurrency, m.Currency)
+	}
+	if m.Amount < 0 {
+		return ErrNegativeAmount
+	}
+	return nil
+}
+
+// Add returns m+other. Both amounts must be in the same currency.
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Multiply returns the amount for n units, e.g. a line total.
+func (m Money) Multiply(n int) Money {
+	return Money{Amount: m.Amount * int64(n), Currency: m.Currency}
+}
+
+// Sum adds amounts that must all share one currency. The sum of nothing is
+// zero in DefaultCurrency.
+func Sum(amounts ...Money) (Money, error) {
+	if len(amounts) == 0 {
+		return Money{Currency: DefaultCurrency}, nil
+	}
+	total := Money{Currency: amounts[0].Currency}
+	for _, m := range amounts {
+		var err error
+		if total, err = total.Add(m); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Decimal formats the amount in major units, e.g. "12.50" for 1250 USD.
+func (m Money) Decimal() string {
+	exp := CurrencyExponent(m.Currency)
+	sign, amount := "", m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	digits := strconv.FormatInt(amount, 10)
+	if exp == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
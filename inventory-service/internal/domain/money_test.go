@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		amount, currency string
+		want             Money
+		wantErr          error
+	}{
+		{"9.99", "usd", Money{Amount: 999, Currency: "USD"}, nil},
+		{"0.1", "EUR", Money{Amount: 10, Currency: "EUR"}, nil},
+		{".5", "USD", Money{Amount: 50, Currency: "USD"}, nil},
+		{"1500", "JPY", Money{Amount: 1500, Currency: "JPY"}, nil},
+		{"1.234", "KWD", Money{Amount: 1234, Currency: "KWD"}, nil},
+		{"1.5", "JPY", Money{}, ErrInvalidAmount},
+		{"1.005", "USD", Money{}, ErrInvalidAmount},
+		{"abc", "USD", Money{}, ErrInvalidAmount},
+		{"", "USD", Money{}, ErrInvalidAmount},
+		{"-1", "USD", Money{}, ErrNegativeAmount},
+		{"1", "US", Money{}, ErrInvalidCurrency},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.amount, tt.currency)
+		assert.ErrorIs(t, err, tt.wantErr, "%s %s", tt.amount, tt.currency)
+		assert.Equal(t, tt.want, got, "%s %s", tt.amount, tt.currency)
+	}
+}
+
+func TestMoney_Decimal(t *testing.T) {
+	assert.Equal(t, "9.99", Money{Amount: 999, Currency: "USD"}.Decimal())
+	assert.Equal(t, "0.05", Money{Amount: 5, Currency: "USD"}.Decimal())
+	assert.Equal(t, "1500", Money{Amount: 1500, Currency: "JPY"}.Decimal())
+	assert.Equal(t, "0.007", Money{Amount: 7, Currency: "KWD"}.Decimal())
+	assert.Equal(t, "-1.20 EUR", Money{Amount: -120, Currency: "EUR"}.String())
+}
+
+func TestSum(t *testing.T) {
+	total, err := Sum(
+		Money{Amount: 10, Currency: "USD"},
+		Money{Amount: 20, Currency: "USD"}.Multiply(3),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, Money{Amount: 70, Currency: "USD"}, total)
+
+	_, err = Sum(Money{Amount: 10, Currency: "USD"}, Money{Amount: 10, Currency: "EUR"})
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	zero, err := Sum()
+	assert.NoError(t, err)
+	assert.Equal(t, Money{Currency: DefaultCurrency}, zero)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type ProductFormat string
+
+const (
+	ProductFormatCSV    ProductFormat = "csv"
+	ProductFormatNDJSON ProductFormat = "ndjson"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported product format")
+	ErrMalformedImport   = errors.New("malformed import file")
+)
+
+func ParseProductFormat(s string) (ProductFormat, error) {
+	switch ProductFormat(strings.ToLower(strings.TrimSpace(s))) {
+	case ProductFormatCSV:
+		return ProductFormatCSV, nil
+	case ProductFormatNDJSON, "jsonl":
+		return ProductFormatNDJSON, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, s)
+	}
+}
+
+const DefaultImportChunkSize = 500
+
+type ImportOptions struct {
+	Format    ProductFormat
+	DryRun    bool
+	ChunkSize int
+}
+
+// ImportRowError describes why a single input row was rejected. Row is
+// 1-based and counts data rows only, so a CSV header is not included.
+type ImportRowError struct {
+	Row     int
+	SKU     string
+	Message string
+}
+
+type ImportResult struct {
+	Created int
+	Updated int
+	Failed  int
+	DryRun  bool
+	Errors  []ImportRowError
+}
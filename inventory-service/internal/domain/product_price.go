@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrPriceInPast = errors.New("price change cannot take effect in the past")
+
+// ProductPrice is one entry of a product's price history. It applies from
+// EffectiveFrom up to, but not including, EffectiveTo; a nil EffectiveTo
+// means until the next scheduled change, if any.
+type ProductPrice struct {
+	ID            string
+	ProductID     string
+	Price         Money `gorm:"embedded;embeddedPrefix:price_"`
+	EffectiveFrom time.Time
+	EffectiveTo   *time.Time
+	CreatedAt     time.Time
+}
+
+func NewProductPrice(productID string, price Money, effectiveFrom time.Time) *ProductPrice {
+	return &ProductPrice{
+		ID:            uuid.NewString(),
+		ProductID:     productID,
+		Price:         price,
+		EffectiveFrom: effectiveFrom.UTC(),
+		CreatedAt:     Clock.Now().UTC(),
+	}
+}
+
+// EffectiveAt reports whether the price applies at t.
+func (p *ProductPrice) EffectiveAt(t time.Time) bool {
+	return !p.EffectiveFrom.After(t) && (p.EffectiveTo == nil || p.EffectiveTo.After(t))
+}
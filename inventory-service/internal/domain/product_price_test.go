@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductPrice_EffectiveAt(t *testing.T) {
+	from := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	open := &ProductPrice{EffectiveFrom: from}
+	assert.False(t, open.EffectiveAt(from.Add(-time.Second)))
+	assert.True(t, open.EffectiveAt(from))
+	assert.True(t, open.EffectiveAt(to.Add(time.Hour)))
+
+	closed := &ProductPrice{EffectiveFrom: from, EffectiveTo: &to}
+	assert.True(t, closed.EffectiveAt(to.Add(-time.Second)))
+	assert.False(t, closed.EffectiveAt(to), "EffectiveTo is exclusive")
+}
+
+type fixedClock struct{ now time.Time }
+
+func (c fixedClock) Now() time.Time { return c.now }
+
+func TestNewProductPrice_StoresUTC(t *testing.T) {
+	bangkok := time.FixedZone("ICT", 7*60*60)
+	defer func(c ClockInterface) { Clock = c }(Clock)
+	Clock = fixedClock{now: time.Date(2026, time.March, 1, 9, 0, 0, 0, bangkok)}
+
+	from := time.Date(2026, time.March, 2, 7, 0, 0, 0, bangkok)
+	price := NewProductPrice("p1", Money{Amount: 100, Currency: "USD"}, from)
+
+	assert.Equal(t, time.UTC, price.EffectiveFrom.Location())
+	assert.True(t, price.EffectiveFrom.Equal(from))
+	assert.Equal(t, time.UTC, price.CreatedAt.Location())
+	assert.True(t, price.CreatedAt.Equal(Clock.Now()))
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"errors"
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+	// maxSearchTerms keeps pathological queries from turning into huge
+	// tsquery expressions.
+	maxSearchTerms = 10
+)
+
+// HighlightStart and HighlightStop wrap matched words in search snippets.
+const (
+	HighlightStart = "<b>"
+	HighlightStop  = "</b>"
+)
+
+// MatchStart and MatchStop mark the matched words of the excerpts a
+// repository finds. They are control characters, which product text does
+// not use, so NewSearchSnippet can tell them from the text.
+const (
+	MatchStart = "\x02"
+	MatchStop  = "\x03"
+)
+
+var ErrEmptySearchQuery = errors.New("search query is empty")
+
+// ProductSearchQuery is a tokenized search. Every term must match, and a
+// term also matches words it is a prefix of.
+type ProductSearchQuery struct {
+	Terms []string
+	Limit int
+}
+
+type ProductSearchResult struct {
+	Product *Product
+	Rank    float64
+	// Snippet is an HTML excerpt of the product text, made by
+	// NewSearchSnippet: the text is escaped and only the matched words are
+	// markup.
+	Snippet string
+}
+
+// NewSearchSnippet turns an excerpt whose matches are marked with MatchStart
+// and MatchStop into HTML that is safe to render: the text is escaped and
+// each match is wrapped in HighlightStart and HighlightStop. Unpaired marks
+// are dropped or closed, so the tags always pair up.
+func NewSearchSnippet(excerpt string) string {
+	var b strings.Builder
+	open := false
+	for {
+		i := strings.IndexAny(excerpt, MatchStart+MatchStop)
+		if i < 0 {
+			b.WriteString(html.EscapeString(excerpt))
+			break
+		}
+		b.WriteString(html.EscapeString(excerpt[:i]))
+		switch mark := excerpt[i : i+1]; {
+		case mark == MatchStart && !open:
+			b.WriteString(HighlightStart)
+			open = true
+		case mark == MatchStop && open:
+			b.WriteString(HighlightStop)
+			open = false
+		}
+		excerpt = excerpt[i+1:]
+	}
+	if open {
+		b.WriteString(HighlightStop)
+	}
+	return b.String()
+}
+
+// TokenizeSearchText lowercases text and splits it into words made of
+// letters and digits. Duplicates are dropped and the order is kept.
+func TokenizeSearchText(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, 0, len(words))
+	seen := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		if _, ok := seen[w]; ok {
+			continue
+		}
+		seen[w] = struct{}{}
+		terms = append(terms, w)
+	}
+	return terms
+}
+
+// NewProductSearchQuery tokenizes text and clamps limit to
+// (0, MaxSearchLimit], using DefaultSearchLimit when it is not set.
+func NewProductSearchQuery(text string, limit int) (ProductSearchQuery, error) {
+	terms := TokenizeSearchText(text)
+	if len(terms) == 0 {
+		return ProductSearchQuery{}, ErrEmptySearchQuery
+	}
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+	switch {
+	case limit <= 0:
+		limit = DefaultSearchLimit
+	case limit > MaxSearchLimit:
+		limit = MaxSearchLimit
+	}
+	return ProductSearchQuery{Terms: terms, Limit: limit}, nil
+}
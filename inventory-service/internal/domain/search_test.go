@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSearchSnippet(t *testing.T) {
+	tests := []struct {
+		name    string
+		excerpt string
+		want    string
+	}{
+		{"plain text", "Blue widget", "Blue widget"},
+		{"match", "Blue " + MatchStart + "Widget" + MatchStop, "Blue <b>Widget</b>"},
+		{
+			"markup in the text is escaped",
+			MatchStart + "Widget" + MatchStop + ` <script>alert("x")</script> & <b>bold</b>`,
+			`<b>Widget</b> &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; &lt;b&gt;bold&lt;/b&gt;`,
+		},
+		{"unclosed match is closed", MatchStart + "Widget", "<b>Widget</b>"},
+		{"stray marks are dropped", MatchStop + "Blue " + MatchStart + MatchStart + "Widget" + MatchStop + MatchStop, "Blue <b>Widget</b>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewSearchSnippet(tt.excerpt))
+		})
+	}
+}
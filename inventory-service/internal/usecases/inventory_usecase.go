@@ -3,23 +3,41 @@ package usecases
 import (
 	"context"
 	"inventory-service/internal/domain"
+	"io"
+	"time"
 
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"go.uber.org/zap"
 )
 
 type InventoryRepository interface {
 	CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
 	GetProduct(ctx context.Context, productId string) (*domain.Product, error)
+	GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error)
 	UpdateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
-	ListProducts(ctx context.Context) ([]*domain.Product, error)
+	ListProducts(ctx context.Context, filter domain.ProductFilter) ([]*domain.Product, error)
+	SearchProducts(ctx context.Context, query domain.ProductSearchQuery) ([]*domain.ProductSearchResult, error)
+	SchedulePrice(ctx context.Context, price *domain.ProductPrice) error
+	ListProductPrices(ctx context.Context, productID string) ([]*domain.ProductPrice, error)
+	GetEffectivePrices(ctx context.Context, productIDs []string, asOf time.Time) (map[string]domain.Money, error)
+	GetProductsBySKUs(ctx context.Context, skus []string) ([]*domain.Product, error)
+	SaveProducts(ctx context.Context, products []*domain.Product) error
+	ListProductsInBatches(ctx context.Context, batchSize int, fn func([]*domain.Product) error) error
 }
 
 type InventoryUseCase interface {
 	CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
 	GetProduct(ctx context.Context, productId string) (*domain.Product, error)
+	GetProductAsOf(ctx context.Context, productId string, asOf time.Time) (*domain.Product, error)
+	GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error)
 	UpdateProductMetadata(ctx context.Context, product *domain.Product) (*domain.Product, error)
 	UpdateProductStockQuantity(ctx context.Context, productID string, quantityChange int) (*domain.Product, error)
-	ListProducts(ctx context.Context) ([]*domain.Product, error)
+	ListProducts(ctx context.Context, filter domain.ProductFilter) ([]*domain.Product, error)
+	SearchProducts(ctx context.Context, text string, limit int) ([]*domain.ProductSearchResult, error)
+	ScheduleProductPrice(ctx context.Context, productID string, price domain.Money, effectiveFrom time.Time) (*domain.ProductPrice, error)
+	ListProductPrices(ctx context.Context, productID string) ([]*domain.ProductPrice, error)
+	ImportProducts(ctx context.Context, r io.Reader, opts domain.ImportOptions) (*domain.ImportResult, error)
+	ExportProducts(ctx context.Context, w io.Writer, format domain.ProductFormat) error
 }
 
 type InventoryUseCaseImpl struct {
@@ -37,44 +55,132 @@ func NewInventoryUsecase(
 	}
 }
 
+// log returns the request-scoped logger of ctx.
+func (i *InventoryUseCaseImpl) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, i.logger)
+}
+
 func (i *InventoryUseCaseImpl) CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
-	i.logger.Info("CreateProduct called", zap.String("productID", product.ID))
+	i.log(ctx).Debug("CreateProduct called", zap.String("productID", product.ID))
+	if err := product.Price.Validate(); err != nil {
+		return nil, err
+	}
+	product.Category = domain.NormalizeCategory(product.Category)
+	if product.SKU == "" {
+		product.SKU = product.ID
+	}
+	if product.Status == "" {
+		product.Status = domain.ProductStatusActive
+	}
+	if product.Attributes == nil {
+		product.Attributes = domain.Attributes{}
+	}
 	return i.inventoryRepo.CreateProduct(ctx, product)
 }
 
 func (i *InventoryUseCaseImpl) GetProduct(ctx context.Context, productId string) (*domain.Product, error) {
-	i.logger.Info("GetProduct called", zap.String("productID", productId))
-	return i.inventoryRepo.GetProduct(ctx, productId)
+	i.log(ctx).Debug("GetProduct called", zap.String("productID", productId))
+	return i.getProductAsOf(ctx, productId, domain.Clock.Now())
+}
+
+// GetProductAsOf returns the product with the price that was in effect at
+// asOf. The other fields are always current.
+func (i *InventoryUseCaseImpl) GetProductAsOf(ctx context.Context, productId string, asOf time.Time) (*domain.Product, error) {
+	i.log(ctx).Debug("GetProductAsOf called", zap.String("productID", productId), zap.Time("asOf", asOf))
+	return i.getProductAsOf(ctx, productId, asOf)
+}
+
+func (i *InventoryUseCaseImpl) getProductAsOf(ctx context.Context, productId string, asOf time.Time) (*domain.Product, error) {
+	product, err := i.inventoryRepo.GetProduct(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.resolvePrices(ctx, asOf, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (i *InventoryUseCaseImpl) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	i.log(ctx).Debug("GetProductBySKU called", zap.String("sku", sku))
+	product, err := i.inventoryRepo.GetProductBySKU(ctx, sku)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.resolvePrices(ctx, domain.Clock.Now(), product); err != nil {
+		return nil, err
+	}
+	return product, nil
 }
 
 func (i *InventoryUseCaseImpl) UpdateProductMetadata(ctx context.Context, product *domain.Product) (*domain.Product, error) {
-	i.logger.Info("UpdateProductMetadata called", zap.String("productID", product.ID))
+	i.log(ctx).Debug("UpdateProductMetadata called", zap.String("productID", product.ID))
+	if err := product.Price.Validate(); err != nil {
+		return nil, err
+	}
 	existingProduct, err := i.inventoryRepo.GetProduct(ctx, product.ID)
 	if err != nil {
-		i.logger.Error("Failed to get product", zap.String("productID", product.ID), zap.Error(err))
+		i.log(ctx).Error("Failed to get product", zap.String("productID", product.ID), zap.Error(err))
 		return nil, err
 	}
+	if product.SKU != "" {
+		existingProduct.SKU = product.SKU
+	}
 	existingProduct.Name = product.Name
+	existingProduct.Description = product.Description
+	existingProduct.Category = domain.NormalizeCategory(product.Category)
+	existingProduct.Attributes = product.Attributes
+	if product.Status != "" {
+		existingProduct.Status = product.Status
+	}
 	existingProduct.Price = product.Price
 	existingProduct.UpdatedAt = domain.Clock.Now()
 	return i.inventoryRepo.UpdateProduct(ctx, existingProduct)
 }
 
 func (i *InventoryUseCaseImpl) UpdateProductStockQuantity(ctx context.Context, productID string, quantityChange int) (*domain.Product, error) {
-	i.logger.Info("UpdateProductStockQuantity called", zap.String("productID", productID))
-	product, err := i.inventoryRepo.GetProduct(ctx, productID)
+	i.log(ctx).Debug("UpdateProductStockQuantity called", zap.String("productID", productID))
+	product, err := i.getProductAsOf(ctx, productID, domain.Clock.Now())
 	if err != nil {
-		i.logger.Error("Failed to get product", zap.String("productID", productID), zap.Error(err))
+		i.log(ctx).Error("Failed to get product", zap.String("productID", productID), zap.Error(err))
 		return nil, err
 	}
 	if err := product.AdjustStock(quantityChange); err != nil {
-		i.logger.Error("Failed to adjust stock", zap.String("productID", productID), zap.Error(err))
+		i.log(ctx).Error("Failed to adjust stock", zap.String("productID", productID), zap.Error(err))
 		return nil, err
 	}
 	return i.inventoryRepo.UpdateProduct(ctx, product)
 }
 
-func (i *InventoryUseCaseImpl) ListProducts(ctx context.Context) ([]*domain.Product, error) {
-	i.logger.Info("ListProducts called")
-	return i.inventoryRepo.ListProducts(ctx)
+func (i *InventoryUseCaseImpl) ListProducts(ctx context.Context, filter domain.ProductFilter) ([]*domain.Product, error) {
+	i.log(ctx).Debug("ListProducts called", zap.String("category", filter.Category), zap.String("status", string(filter.Status)))
+	filter.Category = domain.NormalizeCategory(filter.Category)
+	products, err := i.inventoryRepo.ListProducts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.resolvePrices(ctx, domain.Clock.Now(), products...); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (i *InventoryUseCaseImpl) SearchProducts(ctx context.Context, text string, limit int) ([]*domain.ProductSearchResult, error) {
+	i.log(ctx).Debug("SearchProducts called", zap.String("query", text), zap.Int("limit", limit))
+	query, err := domain.NewProductSearchQuery(text, limit)
+	if err != nil {
+		return nil, err
+	}
+	results, err := i.inventoryRepo.SearchProducts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	products := make([]*domain.Product, 0, len(results))
+	for _, r := range results {
+		products = append(products, r.Product)
+	}
+	if err := i.resolvePrices(ctx, domain.Clock.Now(), products...); err != nil {
+		return nil, err
+	}
+	return results, nil
 }
@@ -1,9 +1,12 @@
 package usecases
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"inventory-service/internal/domain"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -34,6 +37,14 @@ func (m *MockInventoryRepository) GetProduct(ctx context.Context, productId stri
 	return nil, args.Error(1)
 }
 
+func (m *MockInventoryRepository) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	args := m.Called(ctx, sku)
+	if p, ok := args.Get(0).(*domain.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockInventoryRepository) UpdateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
 	args := m.Called(ctx, product)
 	if p, ok := args.Get(0).(*domain.Product); ok {
@@ -42,14 +53,149 @@ func (m *MockInventoryRepository) UpdateProduct(ctx context.Context, product *do
 	return nil, args.Error(1)
 }
 
-func (m *MockInventoryRepository) ListProducts(ctx context.Context) ([]*domain.Product, error) {
-	args := m.Called(ctx)
+func (m *MockInventoryRepository) ListProducts(ctx context.Context, filter domain.ProductFilter) ([]*domain.Product, error) {
+	args := m.Called(ctx, filter)
 	if p, ok := args.Get(0).([]*domain.Product); ok {
 		return p, args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
+func (m *MockInventoryRepository) SearchProducts(ctx context.Context, query domain.ProductSearchQuery) ([]*domain.ProductSearchResult, error) {
+	args := m.Called(ctx, query)
+	if r, ok := args.Get(0).([]*domain.ProductSearchResult); ok {
+		return r, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockInventoryRepository) GetProductsBySKUs(ctx context.Context, skus []string) ([]*domain.Product, error) {
+	args := m.Called(ctx, skus)
+	if p, ok := args.Get(0).([]*domain.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockInventoryRepository) SaveProducts(ctx context.Context, products []*domain.Product) error {
+	args := m.Called(ctx, products)
+	return args.Error(0)
+}
+
+func (m *MockInventoryRepository) ListProductsInBatches(ctx context.Context, batchSize int, fn func([]*domain.Product) error) error {
+	args := m.Called(ctx, batchSize)
+	if batches, ok := args.Get(0).([][]*domain.Product); ok {
+		for _, batch := range batches {
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockInventoryRepository) SchedulePrice(ctx context.Context, price *domain.ProductPrice) error {
+	args := m.Called(ctx, price)
+	return args.Error(0)
+}
+
+func (m *MockInventoryRepository) ListProductPrices(ctx context.Context, productID string) ([]*domain.ProductPrice, error) {
+	args := m.Called(ctx, productID)
+	if p, ok := args.Get(0).([]*domain.ProductPrice); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockInventoryRepository) GetEffectivePrices(ctx context.Context, productIDs []string, asOf time.Time) (map[string]domain.Money, error) {
+	args := m.Called(ctx, productIDs, asOf)
+	if p, ok := args.Get(0).(map[string]domain.Money); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// newMockInventoryRepository returns a mock without price history, so
+// products keep their stored price unless a test sets up
+// GetEffectivePrices on a bare MockInventoryRepository.
+func newMockInventoryRepository() *MockInventoryRepository {
+	m := new(MockInventoryRepository)
+	m.On("GetEffectivePrices", mock.Anything, mock.Anything, mock.Anything).
+		Return(map[string]domain.Money{}, nil).Maybe()
+	return m
+}
+
+// InMemorySearchRepository mimics the Postgres full-text search with the
+// domain tokenizer: every term must prefix-match a word, name and SKU
+// matches weigh more than description matches, and matched words are
+// highlighted in the snippet.
+type InMemorySearchRepository struct {
+	*MockInventoryRepository
+	products []*domain.Product
+}
+
+func (r *InMemorySearchRepository) SearchProducts(ctx context.Context, query domain.ProductSearchQuery) ([]*domain.ProductSearchResult, error) {
+	var results []*domain.ProductSearchResult
+	for _, p := range r.products {
+		weighted := map[string]float64{}
+		for _, w := range domain.TokenizeSearchText(p.Name + " " + p.SKU) {
+			weighted[w] = 1
+		}
+		for _, w := range domain.TokenizeSearchText(p.Description) {
+			if _, ok := weighted[w]; !ok {
+				weighted[w] = 0.4
+			}
+		}
+
+		var rank float64
+		matchedAll := true
+		for _, term := range query.Terms {
+			var termRank float64
+			for w, weight := range weighted {
+				if strings.HasPrefix(w, term) {
+					termRank += weight
+				}
+			}
+			if termRank == 0 {
+				matchedAll = false
+				break
+			}
+			rank += termRank
+		}
+		if matchedAll {
+			results = append(results, &domain.ProductSearchResult{
+				Product: p,
+				Rank:    rank,
+				Snippet: highlight(p.Name+" "+p.Description, query.Terms),
+			})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank > results[j].Rank
+	})
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+	return results, nil
+}
+
+func highlight(text string, terms []string) string {
+	words := strings.Fields(text)
+	for i, w := range words {
+		for _, term := range terms {
+			if strings.HasPrefix(strings.ToLower(w), term) {
+				words[i] = domain.MatchStart + w + domain.MatchStop
+				break
+			}
+		}
+	}
+	return domain.NewSearchSnippet(strings.Join(words, " "))
+}
+
+func usd(amount int64) domain.Money {
+	return domain.Money{Amount: amount, Currency: "USD"}
+}
+
 type FakeClock struct {
 	fixedTime time.Time
 }
@@ -62,9 +208,9 @@ func (fc FakeClock) Now() time.Time {
 
 func TestInventoryUseCaseImpl_CreateProduct(t *testing.T) {
 	ctx := context.Background()
-	product := domain.NewProduct("Product 1", 100, 9.99)
+	product := domain.NewProduct("Product 1", 100, usd(999))
 
-	mockRepo := new(MockInventoryRepository)
+	mockRepo := newMockInventoryRepository()
 	mockRepo.On("CreateProduct", ctx, product).Return(product, nil)
 
 	logger := zap.NewNop()
@@ -78,9 +224,9 @@ func TestInventoryUseCaseImpl_CreateProduct(t *testing.T) {
 
 func TestInventoryUseCaseImpl_CreateProduct_Error(t *testing.T) {
 	ctx := context.Background()
-	product := domain.NewProduct("Product 1", 100, 9.99)
+	product := domain.NewProduct("Product 1", 100, usd(999))
 
-	mockRepo := new(MockInventoryRepository)
+	mockRepo := newMockInventoryRepository()
 	expectedErr := errors.New("failed to create product")
 	mockRepo.On("CreateProduct", ctx, product).Return((*domain.Product)(nil), expectedErr)
 
@@ -96,9 +242,9 @@ func TestInventoryUseCaseImpl_CreateProduct_Error(t *testing.T) {
 
 func TestInventoryUseCaseImpl_GetProduct(t *testing.T) {
 	ctx := context.Background()
-	product := domain.NewProduct("Product 1", 100, 9.99)
+	product := domain.NewProduct("Product 1", 100, usd(999))
 
-	mockRepo := new(MockInventoryRepository)
+	mockRepo := newMockInventoryRepository()
 	mockRepo.On("GetProduct", ctx, product.ID).Return(product, nil)
 
 	logger := zap.NewNop()
@@ -112,9 +258,9 @@ func TestInventoryUseCaseImpl_GetProduct(t *testing.T) {
 
 func TestInventoryUseCaseImpl_GetProduct_Error(t *testing.T) {
 	ctx := context.Background()
-	product := domain.NewProduct("Product 1", 100, 9.99)
+	product := domain.NewProduct("Product 1", 100, usd(999))
 
-	mockRepo := new(MockInventoryRepository)
+	mockRepo := newMockInventoryRepository()
 	expectedErr := errors.New("failed to get product")
 	mockRepo.On("GetProduct", ctx, product.ID).Return((*domain.Product)(nil), expectedErr)
 
@@ -135,16 +281,16 @@ func TestInventoryUseCaseImpl_UpdateProductMetadata(t *testing.T) {
 	defer func() { domain.Clock = oldClock }()
 
 	ctx := context.Background()
-	originalProduct := domain.NewProduct("Product 1", 100, 9.99)
-	updatedMetadata := domain.NewProduct("Updated Product", originalProduct.Quantity, 19.99)
+	originalProduct := domain.NewProduct("Product 1", 100, usd(999))
+	updatedMetadata := domain.NewProduct("Updated Product", originalProduct.Quantity, usd(1999))
 	updatedMetadata.ID = originalProduct.ID
 
-	mockRepo := new(MockInventoryRepository)
+	mockRepo := newMockInventoryRepository()
 	mockRepo.On("GetProduct", ctx, originalProduct.ID).Return(originalProduct, nil)
 	mockRepo.On("UpdateProduct", ctx, mock.MatchedBy(func(prod *domain.Product) bool {
 		return prod.ID == originalProduct.ID &&
 			prod.Name == "Updated Product" &&
-			prod.Price == 19.99 &&
+			prod.Price == usd(1999) &&
 			prod.Quantity == originalProduct.Quantity &&
 			prod.UpdatedAt.Equal(fixedTime)
 	})).Return(updatedMetadata, nil)
@@ -155,17 +301,39 @@ func TestInventoryUseCaseImpl_UpdateProductMetadata(t *testing.T) {
 	result, err := usecase.UpdateProductMetadata(ctx, updatedMetadata)
 	assert.NoError(t, err)
 	assert.Equal(t, "Updated Product", result.Name)
-	assert.Equal(t, 19.99, result.Price)
+	assert.Equal(t, usd(1999), result.Price)
 	assert.Equal(t, originalProduct.Quantity, result.Quantity)
 	assert.True(t, result.UpdatedAt.Equal(fixedTime))
 	mockRepo.AssertExpectations(t)
 }
 
+func TestInventoryUseCaseImpl_CreateProduct_NegativePrice(t *testing.T) {
+	mockRepo := newMockInventoryRepository()
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+
+	product := domain.NewProduct("Widget", 1, usd(-1))
+	created, err := usecase.CreateProduct(context.Background(), product)
+	assert.ErrorIs(t, err, domain.ErrNegativeAmount)
+	assert.Nil(t, created)
+	mockRepo.AssertNotCalled(t, "CreateProduct", mock.Anything, mock.Anything)
+}
+
+func TestInventoryUseCaseImpl_UpdateProductMetadata_InvalidCurrency(t *testing.T) {
+	mockRepo := newMockInventoryRepository()
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+
+	product := domain.NewProduct("Widget", 1, domain.Money{Amount: 100, Currency: "dollars"})
+	updated, err := usecase.UpdateProductMetadata(context.Background(), product)
+	assert.ErrorIs(t, err, domain.ErrInvalidCurrency)
+	assert.Nil(t, updated)
+	mockRepo.AssertNotCalled(t, "GetProduct", mock.Anything, mock.Anything)
+}
+
 func TestInventoryUseCaseImpl_UpdateProductMetadata_GetError(t *testing.T) {
 	ctx := context.Background()
-	updatedMetadata := domain.NewProduct("Updated Product", 100, 19.99)
+	updatedMetadata := domain.NewProduct("Updated Product", 100, usd(1999))
 
-	mockRepo := new(MockInventoryRepository)
+	mockRepo := newMockInventoryRepository()
 	expectedErr := errors.New("failed to get product")
 	mockRepo.On("GetProduct", ctx, updatedMetadata.ID).Return((*domain.Product)(nil), expectedErr)
 
@@ -181,14 +349,14 @@ func TestInventoryUseCaseImpl_UpdateProductMetadata_GetError(t *testing.T) {
 
 func TestInventoryUseCaseImpl_UpdateProductStockQuantity(t *testing.T) {
 	ctx := context.Background()
-	originalProduct := domain.NewProduct("Product 1", 100, 9.99)
+	originalProduct := domain.NewProduct("Product 1", 100, usd(999))
 	quantityChange := 10
 	expectedQuantity := originalProduct.Quantity + quantityChange
 
 	adjustedProduct := domain.NewProduct(originalProduct.Name, expectedQuantity, originalProduct.Price)
 	adjustedProduct.ID = originalProduct.ID
 
-	mockRepo := new(MockInventoryRepository)
+	mockRepo := newMockInventoryRepository()
 	mockRepo.On("GetProduct", ctx, originalProduct.ID).Return(originalProduct, nil)
 	mockRepo.On("UpdateProduct", ctx, mock.MatchedBy(func(prod *domain.Product) bool {
 		return prod.ID == originalProduct.ID && prod.Quantity == expectedQuantity
@@ -205,9 +373,9 @@ func TestInventoryUseCaseImpl_UpdateProductStockQuantity(t *testing.T) {
 
 func TestInventoryUseCaseImpl_UpdateProductStockQuantity_GetError(t *testing.T) {
 	ctx := context.Background()
-	product := domain.NewProduct("Product 1", 100, 9.99)
+	product := domain.NewProduct("Product 1", 100, usd(999))
 
-	mockRepo := new(MockInventoryRepository)
+	mockRepo := newMockInventoryRepository()
 	expectedErr := errors.New("failed to get product")
 	mockRepo.On("GetProduct", ctx, product.ID).Return((*domain.Product)(nil), expectedErr)
 
@@ -224,17 +392,17 @@ func TestInventoryUseCaseImpl_UpdateProductStockQuantity_GetError(t *testing.T)
 func TestInventoryUseCaseImpl_ListProducts(t *testing.T) {
 	ctx := context.Background()
 	products := []*domain.Product{
-		domain.NewProduct("Product 1", 100, 9.99),
-		domain.NewProduct("Product 2", 200, 19.99),
+		domain.NewProduct("Product 1", 100, usd(999)),
+		domain.NewProduct("Product 2", 200, usd(1999)),
 	}
 
-	mockRepo := new(MockInventoryRepository)
-	mockRepo.On("ListProducts", ctx).Return(products, nil)
+	mockRepo := newMockInventoryRepository()
+	mockRepo.On("ListProducts", ctx, domain.ProductFilter{}).Return(products, nil)
 
 	logger := zap.NewNop()
 	usecase := NewInventoryUsecase(mockRepo, logger)
 
-	listedProducts, err := usecase.ListProducts(ctx)
+	listedProducts, err := usecase.ListProducts(ctx, domain.ProductFilter{})
 	assert.NoError(t, err)
 	assert.Equal(t, products, listedProducts)
 	mockRepo.AssertExpectations(t)
@@ -243,16 +411,465 @@ func TestInventoryUseCaseImpl_ListProducts(t *testing.T) {
 func TestInventoryUseCaseImpl_ListProducts_Error(t *testing.T) {
 	ctx := context.Background()
 
-	mockRepo := new(MockInventoryRepository)
+	mockRepo := newMockInventoryRepository()
 	expectedErr := errors.New("failed to list products")
-	mockRepo.On("ListProducts", ctx).Return(([]*domain.Product)(nil), expectedErr)
+	mockRepo.On("ListProducts", ctx, domain.ProductFilter{}).Return(([]*domain.Product)(nil), expectedErr)
 
 	logger := zap.NewNop()
 	usecase := NewInventoryUsecase(mockRepo, logger)
 
-	listedProducts, err := usecase.ListProducts(ctx)
+	listedProducts, err := usecase.ListProducts(ctx, domain.ProductFilter{})
 	assert.Error(t, err)
 	assert.Nil(t, listedProducts)
 	assert.Equal(t, expectedErr, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestInventoryUseCaseImpl_ListProducts_NormalizesCategory(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := newMockInventoryRepository()
+	mockRepo.On("ListProducts", ctx, domain.ProductFilter{
+		Category: "electronics/audio",
+		Status:   domain.ProductStatusActive,
+	}).Return([]*domain.Product{}, nil)
+
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+	_, err := usecase.ListProducts(ctx, domain.ProductFilter{
+		Category: " electronics / audio/",
+		Status:   domain.ProductStatusActive,
+	})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestInventoryUseCaseImpl_GetProductBySKU(t *testing.T) {
+	ctx := context.Background()
+	product := domain.NewProduct("Product 1", 100, usd(999))
+	product.SKU = "WID-001"
+
+	mockRepo := newMockInventoryRepository()
+	mockRepo.On("GetProductBySKU", ctx, "WID-001").Return(product, nil)
+
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+	got, err := usecase.GetProductBySKU(ctx, "WID-001")
+	assert.NoError(t, err)
+	assert.Equal(t, product, got)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestInventoryUseCaseImpl_CreateProduct_CatalogDefaults(t *testing.T) {
+	ctx := context.Background()
+	product := &domain.Product{ID: "abc", Name: "Widget", Category: "Hardware / Widgets /", Price: usd(100)}
+
+	mockRepo := newMockInventoryRepository()
+	mockRepo.On("CreateProduct", ctx, mock.MatchedBy(func(p *domain.Product) bool {
+		return p.SKU == "abc" &&
+			p.Category == "Hardware/Widgets" &&
+			p.Status == domain.ProductStatusActive &&
+			p.Attributes != nil
+	})).Return(product, nil)
+
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+	_, err := usecase.CreateProduct(ctx, product)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestInventoryUseCaseImpl_UpdateProductMetadata_CatalogFields(t *testing.T) {
+	ctx := context.Background()
+	original := domain.NewProduct("Widget", 5, usd(100))
+	original.SKU = "WID-001"
+	original.Status = domain.ProductStatusArchived
+
+	update := &domain.Product{
+		ID:          original.ID,
+		Name:        "Widget",
+		Description: "Now in red",
+		Category:    "hardware/widgets",
+		Attributes:  domain.Attributes{"color": domain.StringAttribute("red")},
+		Price:       usd(200),
+	}
+
+	mockRepo := newMockInventoryRepository()
+	mockRepo.On("GetProduct", ctx, original.ID).Return(original, nil)
+	mockRepo.On("UpdateProduct", ctx, mock.MatchedBy(func(p *domain.Product) bool {
+		// An empty SKU and status in the update keep the stored values.
+		return p.SKU == "WID-001" &&
+			p.Status == domain.ProductStatusArchived &&
+			p.Description == "Now in red" &&
+			p.Attributes["color"] == domain.StringAttribute("red")
+	})).Return(original, nil)
+
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+	_, err := usecase.UpdateProductMetadata(ctx, update)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func newSearchUseCase() InventoryUseCase {
+	widget := domain.NewProduct("Blue Widget", 10, usd(999))
+	widget.Description = "A sturdy widget for the kitchen"
+	gadget := domain.NewProduct("Kitchen Gadget", 5, usd(1999))
+	gadget.Description = "Works well with any widget"
+	lamp := domain.NewProduct("Desk Lamp", 3, usd(2999))
+	lamp.Description = "Warm light"
+
+	repo := &InMemorySearchRepository{
+		MockInventoryRepository: newMockInventoryRepository(),
+		products:                []*domain.Product{lamp, gadget, widget},
+	}
+	return NewInventoryUsecase(repo, zap.NewNop())
+}
+
+func TestInventoryUseCaseImpl_SearchProducts_RanksNameMatchesFirst(t *testing.T) {
+	usecase := newSearchUseCase()
+
+	results, err := usecase.SearchProducts(context.Background(), "widget", 0)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "Blue Widget", results[0].Product.Name)
+	assert.Equal(t, "Kitchen Gadget", results[1].Product.Name)
+	assert.Greater(t, results[0].Rank, results[1].Rank)
+	assert.Equal(t, "Blue <b>Widget</b> A sturdy <b>widget</b> for the kitchen", results[0].Snippet)
+}
+
+func TestInventoryUseCaseImpl_SearchProducts_PrefixAndAllTerms(t *testing.T) {
+	usecase := newSearchUseCase()
+
+	results, err := usecase.SearchProducts(context.Background(), "Kitch WID", 0)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	results, err = usecase.SearchProducts(context.Background(), "lamp widget", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestInventoryUseCaseImpl_SearchProducts_Limit(t *testing.T) {
+	usecase := newSearchUseCase()
+
+	results, err := usecase.SearchProducts(context.Background(), "widget", 1)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Blue Widget", results[0].Product.Name)
+}
+
+func TestInventoryUseCaseImpl_SearchProducts_EmptyQuery(t *testing.T) {
+	mockRepo := newMockInventoryRepository()
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+
+	results, err := usecase.SearchProducts(context.Background(), "  -- ", 10)
+	assert.ErrorIs(t, err, domain.ErrEmptySearchQuery)
+	assert.Nil(t, results)
+	mockRepo.AssertNotCalled(t, "SearchProducts", mock.Anything, mock.Anything)
+}
+
+func TestInventoryUseCaseImpl_SearchProducts_QueryPassedToRepository(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := newMockInventoryRepository()
+	mockRepo.On("SearchProducts", ctx, domain.ProductSearchQuery{
+		Terms: []string{"blue", "widget"},
+		Limit: domain.MaxSearchLimit,
+	}).Return([]*domain.ProductSearchResult{}, nil)
+
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+	_, err := usecase.SearchProducts(ctx, "Blue, widget! blue", 1000)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestInventoryUseCaseImpl_ImportProducts_CSV(t *testing.T) {
+	ctx := context.Background()
+	existing := domain.NewProduct("Old Widget", 5, usd(100))
+	existing.SKU = "WID-001"
+
+	csvBody := strings.Join([]string{
+		"sku,name,quantity,price",
+		"WID-001,Widget,10,9.99",
+		"GAD-001,Gadget,3,19.99",
+		"BAD-001,,1,1.00",
+		"BAD-002,Broken,x,1.00",
+	}, "\n")
+
+	mockRepo := newMockInventoryRepository()
+	mockRepo.On("GetProductsBySKUs", ctx, []string{"WID-001", "GAD-001"}).Return([]*domain.Product{existing}, nil)
+	mockRepo.On("SaveProducts", ctx, mock.MatchedBy(func(products []*domain.Product) bool {
+		return len(products) == 2 &&
+			products[0].ID == existing.ID && products[0].Name == "Widget" && products[0].Quantity == 10 &&
+			products[1].SKU == "GAD-001" && products[1].Price == usd(1999)
+	})).Return(nil)
+
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+	result, err := usecase.ImportProducts(ctx, strings.NewReader(csvBody), domain.ImportOptions{Format: domain.ProductFormatCSV})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 2, result.Failed)
+	assert.Equal(t, []domain.ImportRowError{
+		{Row: 3, SKU: "BAD-001", Message: "name is required"},
+		{Row: 4, SKU: "BAD-002", Message: `invalid quantity "x"`},
+	}, result.Errors)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestInventoryUseCaseImpl_ImportProducts_NDJSONDryRun(t *testing.T) {
+	ctx := context.Background()
+	ndjson := `{"sku":"WID-001","name":"Widget","quantity":10,"price":9.99}
+
+{"sku":"WID-001","name":"Widget v2","quantity":12,"price":10.99}
+{not json}
+`
+	mockRepo := newMockInventoryRepository()
+	mockRepo.On("GetProductsBySKUs", ctx, []string{"WID-001"}).Return([]*domain.Product{}, nil)
+
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+	result, err := usecase.ImportProducts(ctx, strings.NewReader(ndjson), domain.ImportOptions{
+		Format: domain.ProductFormatNDJSON,
+		DryRun: true,
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 3, result.Errors[0].Row)
+	mockRepo.AssertNotCalled(t, "SaveProducts", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestInventoryUseCaseImpl_ImportProducts_ChunkRollback(t *testing.T) {
+	ctx := context.Background()
+	csvBody := "sku,name,quantity,price\nA,Alpha,1,1\nB,Beta,2,2\nC,Gamma,3,3\n"
+
+	mockRepo := newMockInventoryRepository()
+	mockRepo.On("GetProductsBySKUs", ctx, []string{"A", "B"}).Return([]*domain.Product{}, nil)
+	mockRepo.On("GetProductsBySKUs", ctx, []string{"C"}).Return([]*domain.Product{}, nil)
+	mockRepo.On("SaveProducts", ctx, mock.MatchedBy(func(products []*domain.Product) bool {
+		return len(products) == 2
+	})).Return(errors.New("duplicate key"))
+	mockRepo.On("SaveProducts", ctx, mock.MatchedBy(func(products []*domain.Product) bool {
+		return len(products) == 1
+	})).Return(nil)
+
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+	result, err := usecase.ImportProducts(ctx, strings.NewReader(csvBody), domain.ImportOptions{
+		Format:    domain.ProductFormatCSV,
+		ChunkSize: 2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 0, result.Updated)
+	assert.Equal(t, 2, result.Failed)
+	assert.Equal(t, "A", result.Errors[0].SKU)
+	assert.Equal(t, "B", result.Errors[1].SKU)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestInventoryUseCaseImpl_ImportProducts_CatalogColumns(t *testing.T) {
+	ctx := context.Background()
+	existing := domain.NewProduct("Widget", 5, usd(100))
+	existing.SKU = "WID-001"
+	existing.Description = "kept"
+
+	csvBody := strings.Join([]string{
+		"sku,name,quantity,price,category,status,attributes",
+		`WID-001,Widget,10,9.99, hardware / widgets ,archived,"{""size"":3,""fragile"":true}"`,
+		`BAD-001,Bad,1,1,,deleted,{}`,
+		`BAD-002,Bad,1,1,,,"{""dims"":[1,2]}"`,
+	}, "\n")
+
+	mockRepo := newMockInventoryRepository()
+	mockRepo.On("GetProductsBySKUs", ctx, []string{"WID-001"}).Return([]*domain.Product{existing}, nil)
+	mockRepo.On("SaveProducts", ctx, mock.MatchedBy(func(products []*domain.Product) bool {
+		p := products[0]
+		return len(products) == 1 &&
+			p.Description == "kept" &&
+			p.Category == "hardware/widgets" &&
+			p.Status == domain.ProductStatusArchived &&
+			p.Attributes["size"] == domain.NumberAttribute(3) &&
+			p.Attributes["fragile"] == domain.BoolAttribute(true)
+	})).Return(nil)
+
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+	result, err := usecase.ImportProducts(ctx, strings.NewReader(csvBody), domain.ImportOptions{Format: domain.ProductFormatCSV})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 2, result.Failed)
+	assert.Equal(t, 2, result.Errors[0].Row)
+	assert.Equal(t, 3, result.Errors[1].Row)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestInventoryUseCaseImpl_ImportProducts_Prices(t *testing.T) {
+	ctx := context.Background()
+	csvBody := strings.Join([]string{
+		"sku,name,quantity,price,currency",
+		"A,Alpha,1,0.1,",
+		"B,Beta,1,1500,jpy",
+		"C,Gamma,1,-1,USD",
+		"D,Delta,1,1.005,USD",
+		"E,Epsilon,1,1,US",
+	}, "\n")
+
+	mockRepo := newMockInventoryRepository()
+	mockRepo.On("GetProductsBySKUs", ctx, []string{"A", "B"}).Return([]*domain.Product{}, nil)
+	mockRepo.On("SaveProducts", ctx, mock.MatchedBy(func(products []*domain.Product) bool {
+		return len(products) == 2 &&
+			products[0].Price == usd(10) &&
+			products[1].Price == domain.Money{Amount: 1500, Currency: "JPY"}
+	})).Return(nil)
+
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+	result, err := usecase.ImportProducts(ctx, strings.NewReader(csvBody), domain.ImportOptions{Format: domain.ProductFormatCSV})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 3, result.Failed)
+	assert.Equal(t, "invalid price: amount must not be negative", result.Errors[0].Message)
+	assert.Equal(t, `invalid price: invalid amount: "1.005"`, result.Errors[1].Message)
+	assert.Equal(t, `invalid price: invalid currency code: "US"`, result.Errors[2].Message)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestInventoryUseCaseImpl_ImportProducts_MissingHeaderColumn(t *testing.T) {
+	mockRepo := newMockInventoryRepository()
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+
+	result, err := usecase.ImportProducts(context.Background(), strings.NewReader("sku,name\nA,Alpha\n"), domain.ImportOptions{Format: domain.ProductFormatCSV})
+	assert.ErrorIs(t, err, domain.ErrMalformedImport)
+	assert.Nil(t, result)
+}
+
+func TestInventoryUseCaseImpl_ExportProducts(t *testing.T) {
+	ctx := context.Background()
+	p1 := &domain.Product{
+		ID: "1", SKU: "WID-001", Name: "Widget", Quantity: 10, Price: usd(999),
+		Category:   "hardware/widgets",
+		Status:     domain.ProductStatusActive,
+		Attributes: domain.Attributes{"color": domain.StringAttribute("red")},
+	}
+	p2 := &domain.Product{ID: "2", SKU: "GAD-001", Name: "Gadget, large", Quantity: 3, Price: domain.Money{Amount: 2000, Currency: "EUR"}, Status: domain.ProductStatusArchived}
+
+	mockRepo := newMockInventoryRepository()
+	mockRepo.On("ListProductsInBatches", ctx, mock.AnythingOfType("int")).
+		Return([][]*domain.Product{{p1}, {p2}}, nil)
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+
+	var csvOut bytes.Buffer
+	err := usecase.ExportProducts(ctx, &csvOut, domain.ProductFormatCSV)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"sku,name,quantity,price,currency,description,category,status,attributes",
+		`WID-001,Widget,10,9.99,USD,,hardware/widgets,ACTIVE,"{""color"":""red""}"`,
+		`GAD-001,"Gadget, large",3,20.00,EUR,,,ARCHIVED,{}`,
+	}, "\n")+"\n", csvOut.String())
+
+	var ndjsonOut bytes.Buffer
+	err = usecase.ExportProducts(ctx, &ndjsonOut, domain.ProductFormatNDJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"sku":"WID-001","name":"Widget","quantity":10,"price":9.99,"currency":"USD","description":"","category":"hardware/widgets","status":"ACTIVE","attributes":{"color":"red"}}
+{"sku":"GAD-001","name":"Gadget, large","quantity":3,"price":20.00,"currency":"EUR","description":"","category":"","status":"ARCHIVED","attributes":{}}
+`, ndjsonOut.String())
+}
+
+func TestInventoryUseCaseImpl_GetProductAsOf(t *testing.T) {
+	ctx := context.Background()
+	asOf := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	product := &domain.Product{ID: "1", Name: "Widget", Price: usd(1299)}
+
+	mockRepo := new(MockInventoryRepository)
+	mockRepo.On("GetProduct", ctx, "1").Return(product, nil)
+	mockRepo.On("GetEffectivePrices", ctx, []string{"1"}, asOf).
+		Return(map[string]domain.Money{"1": usd(999)}, nil)
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+
+	got, err := usecase.GetProductAsOf(ctx, "1", asOf)
+	assert.NoError(t, err)
+	assert.Equal(t, usd(999), got.Price)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestInventoryUseCaseImpl_ListProducts_ResolvesCurrentPrices(t *testing.T) {
+	fixedTime := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	oldClock := domain.Clock
+	domain.Clock = FakeClock{fixedTime: fixedTime}
+	defer func() { domain.Clock = oldClock }()
+
+	ctx := context.Background()
+	p1 := &domain.Product{ID: "1", Price: usd(100)}
+	p2 := &domain.Product{ID: "2", Price: usd(200)}
+
+	mockRepo := new(MockInventoryRepository)
+	mockRepo.On("ListProducts", ctx, domain.ProductFilter{}).Return([]*domain.Product{p1, p2}, nil)
+	mockRepo.On("GetEffectivePrices", ctx, []string{"1", "2"}, fixedTime).
+		Return(map[string]domain.Money{"1": usd(150)}, nil)
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+
+	products, err := usecase.ListProducts(ctx, domain.ProductFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, usd(150), products[0].Price)
+	assert.Equal(t, usd(200), products[1].Price, "products without history keep the stored price")
+}
+
+func TestInventoryUseCaseImpl_ScheduleProductPrice(t *testing.T) {
+	fixedTime := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	oldClock := domain.Clock
+	domain.Clock = FakeClock{fixedTime: fixedTime}
+	defer func() { domain.Clock = oldClock }()
+
+	ctx := context.Background()
+	product := &domain.Product{ID: "1", Price: usd(999)}
+	from := fixedTime.Add(24 * time.Hour)
+
+	mockRepo := newMockInventoryRepository()
+	mockRepo.On("GetProduct", ctx, "1").Return(product, nil)
+	mockRepo.On("SchedulePrice", ctx, mock.MatchedBy(func(p *domain.ProductPrice) bool {
+		return p.ProductID == "1" && p.Price == usd(1299) && p.EffectiveFrom.Equal(from)
+	})).Return(nil)
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+
+	scheduled, err := usecase.ScheduleProductPrice(ctx, "1", usd(1299), from)
+	assert.NoError(t, err)
+	assert.Equal(t, from, scheduled.EffectiveFrom)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestInventoryUseCaseImpl_ScheduleProductPrice_DefaultsToNow(t *testing.T) {
+	fixedTime := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	oldClock := domain.Clock
+	domain.Clock = FakeClock{fixedTime: fixedTime}
+	defer func() { domain.Clock = oldClock }()
+
+	ctx := context.Background()
+	mockRepo := newMockInventoryRepository()
+	mockRepo.On("GetProduct", ctx, "1").Return(&domain.Product{ID: "1", Price: usd(999)}, nil)
+	mockRepo.On("SchedulePrice", ctx, mock.Anything).Return(nil)
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+
+	scheduled, err := usecase.ScheduleProductPrice(ctx, "1", usd(1299), time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, fixedTime, scheduled.EffectiveFrom)
+}
+
+func TestInventoryUseCaseImpl_ScheduleProductPrice_Invalid(t *testing.T) {
+	fixedTime := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	oldClock := domain.Clock
+	domain.Clock = FakeClock{fixedTime: fixedTime}
+	defer func() { domain.Clock = oldClock }()
+
+	ctx := context.Background()
+	mockRepo := newMockInventoryRepository()
+	mockRepo.On("GetProduct", ctx, "1").Return(&domain.Product{ID: "1", Price: usd(999)}, nil)
+	usecase := NewInventoryUsecase(mockRepo, zap.NewNop())
+
+	_, err := usecase.ScheduleProductPrice(ctx, "1", usd(1299), fixedTime.Add(-time.Second))
+	assert.ErrorIs(t, err, domain.ErrPriceInPast)
+
+	_, err = usecase.ScheduleProductPrice(ctx, "1", domain.Money{Amount: 1299, Currency: "EUR"}, fixedTime)
+	assert.ErrorIs(t, err, domain.ErrCurrencyMismatch)
+
+	_, err = usecase.ScheduleProductPrice(ctx, "1", usd(-1), fixedTime)
+	assert.ErrorIs(t, err, domain.ErrNegativeAmount)
+
+	mockRepo.AssertNotCalled(t, "SchedulePrice", mock.Anything, mock.Anything)
+}
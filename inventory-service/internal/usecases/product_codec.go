@@ -0,0 +1,287 @@
+package usecases
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"inventory-service/internal/domain"
+)
+
+// ProductRecord is the flat, format-independent shape of a product as it
+// appears in bulk import and export files.
+//
+// Price is a decimal in major units. An empty Currency means
+// domain.DefaultCurrency. The other optional fields are pointers so an
+// import that omits them leaves the stored values untouched.
+type ProductRecord struct {
+	SKU         string             `json:"sku"`
+	Name        string             `json:"name"`
+	Quantity    int                `json:"quantity"`
+	Price       json.Number        `json:"price"`
+	Currency    string             `json:"currency,omitempty"`
+	Description *string            `json:"description,omitempty"`
+	Category    *string            `json:"category,omitempty"`
+	Status      *string            `json:"status,omitempty"`
+	Attributes  *domain.Attributes `json:"attributes,omitempty"`
+}
+
+var (
+	productCSVRequiredColumns = []string{"sku", "name", "quantity", "price"}
+	productCSVHeader          = []string{"sku", "name", "quantity", "price", "currency", "description", "category", "status", "attributes"}
+)
+
+// maxNDJSONLineSize bounds a single NDJSON record so a malformed file
+// without newlines cannot make the scanner buffer the whole body.
+const maxNDJSONLineSize = 1 << 20
+
+// recordError is returned by a productRecordReader when a single row cannot
+// be decoded. The reader stays usable and the caller may continue.
+type recordError struct {
+	msg string
+}
+
+func (e *recordError) Error() string {
+	return e.msg
+}
+
+type productRecordReader interface {
+	Read() (ProductRecord, error)
+}
+
+type productRecordWriter interface {
+	Write(record ProductRecord) error
+	Flush() error
+}
+
+func newProductRecordReader(format domain.ProductFormat, r io.Reader) (productRecordReader, error) {
+	switch format {
+	case domain.ProductFormatCSV:
+		return newCSVProductReader(r)
+	case domain.ProductFormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+		return &ndjsonProductReader{scanner: scanner}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", domain.ErrUnsupportedFormat, format)
+	}
+}
+
+func newProductRecordWriter(format domain.ProductFormat, w io.Writer) (productRecordWriter, error) {
+	switch format {
+	case domain.ProductFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(productCSVHeader); err != nil {
+			return nil, err
+		}
+		return &csvProductWriter{w: cw}, nil
+	case domain.ProductFormatNDJSON:
+		bw := bufio.NewWriter(w)
+		return &ndjsonProductWriter{w: bw, enc: json.NewEncoder(bw)}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", domain.ErrUnsupportedFormat, format)
+	}
+}
+
+type csvProductReader struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+func newCSVProductReader(r io.Reader) (*csvProductReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: missing CSV header", domain.ErrMalformedImport)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrMalformedImport, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range productCSVRequiredColumns {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: CSV header is missing column %q", domain.ErrMalformedImport, required)
+		}
+	}
+	return &csvProductReader{r: cr, columns: columns}, nil
+}
+
+func (c *csvProductReader) Read() (ProductRecord, error) {
+	fields, err := c.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return ProductRecord{}, &recordError{msg: parseErr.Err.Error()}
+		}
+		return ProductRecord{}, err
+	}
+
+	field := func(name string) string {
+		idx, ok := c.columns[name]
+		if !ok || idx >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[idx])
+	}
+	optional := func(name string) *string {
+		if _, ok := c.columns[name]; !ok {
+			return nil
+		}
+		v := field(name)
+		return &v
+	}
+
+	record := ProductRecord{
+		SKU:         field("sku"),
+		Name:        field("name"),
+		Price:       json.Number(field("price")),
+		Currency:    field("currency"),
+		Description: optional("description"),
+		Category:    optional("category"),
+		Status:      optional("status"),
+	}
+	if record.Quantity, err = strconv.Atoi(field("quantity")); err != nil {
+		return record, &recordError{msg: fmt.Sprintf("invalid quantity %q", field("quantity"))}
+	}
+	if raw := optional("attributes"); raw != nil && *raw != "" {
+		var attrs domain.Attributes
+		if err := attrs.UnmarshalJSON([]byte(*raw)); err != nil {
+			return record, &recordError{msg: fmt.Sprintf("invalid attributes: %v", err)}
+		}
+		record.Attributes = &attrs
+	}
+	return record, nil
+}
+
+type ndjsonProductReader struct {
+	scanner *bufio.Scanner
+}
+
+func (n *ndjsonProductReader) Read() (ProductRecord, error) {
+	for n.scanner.Scan() {
+		line := strings.TrimSpace(n.scanner.Text())
+		if line == "" {
+			continue
+		}
+		var record ProductRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return ProductRecord{}, &recordError{msg: fmt.Sprintf("invalid JSON: %v", err)}
+		}
+		record.SKU = strings.TrimSpace(record.SKU)
+		record.Name = strings.TrimSpace(record.Name)
+		return record, nil
+	}
+	if err := n.scanner.Err(); err != nil {
+		return ProductRecord{}, err
+	}
+	return ProductRecord{}, io.EOF
+}
+
+type csvProductWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvProductWriter) Write(record ProductRecord) error {
+	attrs := []byte("{}")
+	if record.Attributes != nil {
+		var err error
+		if attrs, err = record.Attributes.MarshalJSON(); err != nil {
+			return err
+		}
+	}
+	return c.w.Write([]string{
+		record.SKU,
+		record.Name,
+		strconv.Itoa(record.Quantity),
+		record.Price.String(),
+		record.Currency,
+		stringOrEmpty(record.Description),
+		stringOrEmpty(record.Category),
+		stringOrEmpty(record.Status),
+		string(attrs),
+	})
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func (c *csvProductWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonProductWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (n *ndjsonProductWriter) Write(record ProductRecord) error {
+	return n.enc.Encode(record)
+}
+
+func (n *ndjsonProductWriter) Flush() error {
+	return n.w.Flush()
+}
+
+func productToRecord(product *domain.Product) ProductRecord {
+	status := string(product.Status)
+	attrs := product.Attributes
+	if attrs == nil {
+		attrs = domain.Attributes{}
+	}
+	return ProductRecord{
+		SKU:         product.SKU,
+		Name:        product.Name,
+		Quantity:    product.Quantity,
+		Price:       json.Number(product.Price.Decimal()),
+		Currency:    product.Price.Currency,
+		Description: &product.Description,
+		Category:    &product.Category,
+		Status:      &status,
+		Attributes:  &attrs,
+	}
+}
+
+func validateProductRecord(record ProductRecord) string {
+	switch {
+	case record.SKU == "":
+		return "sku is required"
+	case record.Name == "":
+		return "name is required"
+	case record.Quantity < 0:
+		return "quantity must not be negative"
+	}
+	if _, err := recordPrice(record); err != nil {
+		return fmt.Sprintf("invalid price: %v", err)
+	}
+	if record.Status != nil && *record.Status != "" {
+		if _, err := domain.ParseProductStatus(*record.Status); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+func recordPrice(record ProductRecord) (domain.Money, error) {
+	currency := record.Currency
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+	return domain.ParseMoney(record.Price.String(), currency)
+}
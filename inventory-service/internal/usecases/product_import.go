@@ -0,0 +1,204 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"inventory-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	exportBatchSize = 500
+	// maxImportRowErrors caps how many row errors are kept in an
+	// ImportResult; Failed still counts every rejected row.
+	maxImportRowErrors = 1000
+)
+
+type importRow struct {
+	row    int
+	record ProductRecord
+}
+
+func (i *InventoryUseCaseImpl) ImportProducts(ctx context.Context, r io.Reader, opts domain.ImportOptions) (*domain.ImportResult, error) {
+	i.log(ctx).Debug("ImportProducts called", zap.String("format", string(opts.Format)), zap.Bool("dryRun", opts.DryRun))
+
+	reader, err := newProductRecordReader(opts.Format, r)
+	if err != nil {
+		return nil, err
+	}
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = domain.DefaultImportChunkSize
+	}
+
+	result := &domain.ImportResult{DryRun: opts.DryRun}
+	chunk := make([]importRow, 0, chunkSize)
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var recErr *recordError
+			if !errors.As(err, &recErr) {
+				return result, fmt.Errorf("failed to read import row %d: %w", row, err)
+			}
+			addImportRowError(result, row, record.SKU, recErr.Error())
+			continue
+		}
+		if msg := validateProductRecord(record); msg != "" {
+			addImportRowError(result, row, record.SKU, msg)
+			continue
+		}
+
+		chunk = append(chunk, importRow{row: row, record: record})
+		if len(chunk) == chunkSize {
+			if err := i.importChunk(ctx, chunk, opts.DryRun, result); err != nil {
+				return result, err
+			}
+			chunk = chunk[:0]
+		}
+	}
+	if len(chunk) > 0 {
+		if err := i.importChunk(ctx, chunk, opts.DryRun, result); err != nil {
+			return result, err
+		}
+	}
+
+	i.log(ctx).Info("ImportProducts finished",
+		zap.Int("created", result.Created),
+		zap.Int("updated", result.Updated),
+		zap.Int("failed", result.Failed),
+		zap.Bool("dryRun", result.DryRun))
+	return result, nil
+}
+
+// importChunk upserts one chunk of validated rows by SKU. The chunk is saved
+// in a single transaction; if that fails every row in it is reported as
+// failed and the import continues with the next chunk.
+func (i *InventoryUseCaseImpl) importChunk(ctx context.Context, chunk []importRow, dryRun bool, result *domain.ImportResult) error {
+	skus := make([]string, 0, len(chunk))
+	seen := make(map[string]struct{}, len(chunk))
+	for _, r := range chunk {
+		if _, ok := seen[r.record.SKU]; ok {
+			continue
+		}
+		seen[r.record.SKU] = struct{}{}
+		skus = append(skus, r.record.SKU)
+	}
+
+	existing, err := i.inventoryRepo.GetProductsBySKUs(ctx, skus)
+	if err != nil {
+		i.log(ctx).Error("Failed to look up products by SKU", zap.Error(err))
+		return err
+	}
+	bySKU := make(map[string]*domain.Product, len(existing))
+	for _, p := range existing {
+		bySKU[p.SKU] = p
+	}
+
+	var (
+		now              = domain.Clock.Now()
+		pending          = make(map[string]*domain.Product, len(chunk))
+		toSave           = make([]*domain.Product, 0, len(chunk))
+		created, updated int
+	)
+	for _, r := range chunk {
+		rec := r.record
+		if p, ok := pending[rec.SKU]; ok {
+			applyProductRecord(p, rec, now)
+			updated++
+			continue
+		}
+		if p, ok := bySKU[rec.SKU]; ok {
+			applyProductRecord(p, rec, now)
+			pending[rec.SKU] = p
+			toSave = append(toSave, p)
+			updated++
+			continue
+		}
+		p := domain.NewProduct(rec.Name, rec.Quantity, domain.Money{})
+		p.SKU = rec.SKU
+		applyProductRecord(p, rec, now)
+		pending[rec.SKU] = p
+		toSave = append(toSave, p)
+		created++
+	}
+
+	if !dryRun {
+		if err := i.inventoryRepo.SaveProducts(ctx, toSave); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			i.log(ctx).Error("Failed to save import chunk", zap.Int("rows", len(chunk)), zap.Error(err))
+			for _, r := range chunk {
+				addImportRowError(result, r.row, r.record.SKU, fmt.Sprintf("chunk rolled back: %v", err))
+			}
+			return nil
+		}
+	}
+
+	result.Created += created
+	result.Updated += updated
+	return nil
+}
+
+func applyProductRecord(p *domain.Product, rec ProductRecord, now time.Time) {
+	p.Name = rec.Name
+	p.Quantity = rec.Quantity
+	// validateProductRecord has already rejected invalid prices and statuses.
+	p.Price, _ = recordPrice(rec)
+	if rec.Description != nil {
+		p.Description = *rec.Description
+	}
+	if rec.Category != nil {
+		p.Category = domain.NormalizeCategory(*rec.Category)
+	}
+	if rec.Status != nil && *rec.Status != "" {
+		p.Status, _ = domain.ParseProductStatus(*rec.Status)
+	}
+	if rec.Attributes != nil {
+		p.Attributes = *rec.Attributes
+	}
+	p.UpdatedAt = now
+}
+
+func addImportRowError(result *domain.ImportResult, row int, sku, msg string) {
+	result.Failed++
+	if len(result.Errors) < maxImportRowErrors {
+		result.Errors = append(result.Errors, domain.ImportRowError{Row: row, SKU: sku, Message: msg})
+	}
+}
+
+func (i *InventoryUseCaseImpl) ExportProducts(ctx context.Context, w io.Writer, format domain.ProductFormat) error {
+	i.log(ctx).Debug("ExportProducts called", zap.String("format", string(format)))
+
+	writer, err := newProductRecordWriter(format, w)
+	if err != nil {
+		return err
+	}
+
+	now := domain.Clock.Now()
+	err = i.inventoryRepo.ListProductsInBatches(ctx, exportBatchSize, func(products []*domain.Product) error {
+		if err := i.resolvePrices(ctx, now, products...); err != nil {
+			return err
+		}
+		for _, p := range products {
+			if err := writer.Write(productToRecord(p)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		i.log(ctx).Error("Failed to export products", zap.Error(err))
+		return err
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"inventory-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// ScheduleProductPrice records a price that takes effect at effectiveFrom,
+// or now when it is zero. Prices already scheduled after effectiveFrom are
+// kept; the new price applies until the next of them.
+func (i *InventoryUseCaseImpl) ScheduleProductPrice(ctx context.Context, productID string, price domain.Money, effectiveFrom time.Time) (*domain.ProductPrice, error) {
+	i.log(ctx).Debug("ScheduleProductPrice called",
+		zap.String("productID", productID),
+		zap.Stringer("price", price),
+		zap.Time("effectiveFrom", effectiveFrom))
+
+	if err := price.Validate(); err != nil {
+		return nil, err
+	}
+	now := domain.Clock.Now()
+	if effectiveFrom.IsZero() {
+		effectiveFrom = now
+	}
+	if effectiveFrom.Before(now) {
+		return nil, domain.ErrPriceInPast
+	}
+
+	product, err := i.inventoryRepo.GetProduct(ctx, productID)
+	if err != nil {
+		i.log(ctx).Error("Failed to get product", zap.String("productID", productID), zap.Error(err))
+		return nil, err
+	}
+	if product.Price.Currency != price.Currency {
+		return nil, fmt.Errorf("%w: product is priced in %s, got %s", domain.ErrCurrencyMismatch, product.Price.Currency, price.Currency)
+	}
+
+	scheduled := domain.NewProductPrice(productID, price, effectiveFrom)
+	if err := i.inventoryRepo.SchedulePrice(ctx, scheduled); err != nil {
+		i.log(ctx).Error("Failed to schedule price", zap.String("productID", productID), zap.Error(err))
+		return nil, err
+	}
+	return scheduled, nil
+}
+
+func (i *InventoryUseCaseImpl) ListProductPrices(ctx context.Context, productID string) ([]*domain.ProductPrice, error) {
+	i.log(ctx).Debug("ListProductPrices called", zap.String("productID", productID))
+	return i.inventoryRepo.ListProductPrices(ctx, productID)
+}
+
+// resolvePrices replaces the stored price of each product with the one in
+// effect at asOf. Products without a matching price history entry keep the
+// stored price.
+func (i *InventoryUseCaseImpl) resolvePrices(ctx context.Context, asOf time.Time, products ...*domain.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(products))
+	for _, p := range products {
+		ids = append(ids, p.ID)
+	}
+	prices, err := i.inventoryRepo.GetEffectivePrices(ctx, ids, asOf)
+	if err != nil {
+		i.log(ctx).Error("Failed to resolve product prices", zap.Int("count", len(ids)), zap.Error(err))
+		return err
+	}
+	for _, p := range products {
+		if price, ok := prices[p.ID]; ok {
+			p.Price = price
+		}
+	}
+	return nil
+}
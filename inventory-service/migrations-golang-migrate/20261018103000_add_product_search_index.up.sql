@@ -0,0 +1,2 @@
+-- Create index "products_search_idx" to table: "products"
+CREATE INDEX "products_search_idx" ON "products" USING GIN ((setweight(to_tsvector('simple', name), 'A') || setweight(to_tsvector('simple', sku), 'A') || setweight(to_tsvector('simple', description), 'B')));
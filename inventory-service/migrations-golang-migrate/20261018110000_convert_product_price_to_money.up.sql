@@ -0,0 +1,6 @@
+-- Modify "products" table
+ALTER TABLE "products" ADD COLUMN "price_amount" bigint NULL, ADD COLUMN "price_currency" character varying(3) NOT NULL DEFAULT 'USD';
+-- Convert existing prices, which were stored in USD major units, to cents
+UPDATE "products" SET "price_amount" = round("price" * 100);
+-- Modify "products" table
+ALTER TABLE "products" ALTER COLUMN "price_amount" SET NOT NULL, DROP COLUMN "price", ADD CONSTRAINT "products_price_amount_check" CHECK (price_amount >= 0);
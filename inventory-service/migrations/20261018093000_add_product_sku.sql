@@ -0,0 +1,8 @@
+-- Modify "products" table
+ALTER TABLE "products" ADD COLUMN "sku" character varying(64) NULL;
+-- Backfill existing rows so every product has a SKU
+UPDATE "products" SET "sku" = "id" WHERE "sku" IS NULL;
+-- Modify "products" table
+ALTER TABLE "products" ALTER COLUMN "sku" SET NOT NULL;
+-- Create index "products_sku_key" to table: "products"
+CREATE UNIQUE INDEX "products_sku_key" ON "products" ("sku");
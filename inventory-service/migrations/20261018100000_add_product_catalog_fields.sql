@@ -0,0 +1,6 @@
+-- Modify "products" table
+ALTER TABLE "products" ADD COLUMN "description" text NOT NULL DEFAULT '', ADD COLUMN "category" character varying(255) NOT NULL DEFAULT '', ADD COLUMN "attributes" jsonb NOT NULL DEFAULT '{}', ADD COLUMN "status" character varying(16) NOT NULL DEFAULT 'ACTIVE';
+-- Create index "products_category_idx" to table: "products"
+CREATE INDEX "products_category_idx" ON "products" ("category" varchar_pattern_ops);
+-- Create index "products_status_idx" to table: "products"
+CREATE INDEX "products_status_idx" ON "products" ("status");
@@ -0,0 +1,6 @@
+-- Create "product_prices" table
+CREATE TABLE "product_prices" ("id" character varying(255) NOT NULL DEFAULT gen_random_uuid(), "product_id" character varying(255) NOT NULL, "price_amount" bigint NOT NULL, "price_currency" character varying(3) NOT NULL, "effective_from" timestamp NOT NULL, "effective_to" timestamp NULL, "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY ("id"), CONSTRAINT "product_prices_product_id_fkey" FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "product_prices_price_amount_check" CHECK (price_amount >= 0), CONSTRAINT "product_prices_effective_range_check" CHECK ((effective_to IS NULL) OR (effective_to > effective_from)));
+-- Create index "product_prices_product_id_effective_from_key" to table: "product_prices"
+CREATE UNIQUE INDEX "product_prices_product_id_effective_from_key" ON "product_prices" ("product_id", "effective_from");
+-- Start the history of existing products with their current price
+INSERT INTO "product_prices" ("product_id", "price_amount", "price_currency", "effective_from") SELECT "id", "price_amount", "price_currency", "created_at" FROM "products";
@@ -0,0 +1,2 @@
+-- Modify "product_prices" table
+ALTER TABLE "product_prices" ALTER COLUMN "effective_from" TYPE timestamptz USING "effective_from" AT TIME ZONE 'UTC', ALTER COLUMN "effective_to" TYPE timestamptz USING "effective_to" AT TIME ZONE 'UTC';
@@ -4,44 +4,51 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"notification-service/internal/adapters/email"
+	"notification-service/internal/adapters/handlers"
+	"notification-service/internal/adapters/httpapi"
 	"notification-service/internal/adapters/kafka"
+	"notification-service/internal/adapters/publisher"
+	"notification-service/internal/adapters/repository"
+	"notification-service/internal/adapters/templates"
+	"notification-service/internal/adapters/webhook"
 	ws "notification-service/internal/adapters/websocket"
+	"notification-service/internal/clients"
+	"notification-service/internal/config"
+	"notification-service/internal/domain"
 	"notification-service/internal/usecases"
 
+	"github.com/IBM/sarama"
 	"github.com/gorilla/websocket"
-	"github.com/joho/godotenv"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/apikey"
+	platformconfig "github.com/jakkapat-chongsuwat/go-microservice/platform/config"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health/kafkahealth"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lazy"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lifecycle"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging/grpclog"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics/grpcmetrics"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry/grpctrace"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/user_service"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
-// Configuration structs
-type Config struct {
-	Kafka          KafkaConfig
-	SchemaRegistry string
-	WebSocket      WebSocketConfig
-}
-
-type KafkaConfig struct {
-	Brokers []string
-	GroupID string
-	Topic   string
-}
-
-type WebSocketConfig struct {
-	Port            string
-	ReadBufferSize  int
-	WriteBufferSize int
-}
-
 // WebSocket upgrader with configurable buffer sizes and permissive CORS
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
@@ -54,41 +61,130 @@ var upgrader = websocket.Upgrader{
 }
 
 func main() {
-	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using system environment variables")
+	configFile := flag.String("config", "", "path to a YAML config file")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration, secrets redacted, and exit")
+	flag.Parse()
+
+	// Load configuration from the environment, .env and the optional YAML file
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *printConfig {
+		if err := platformconfig.Print(os.Stdout, cfg); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
 	// Initialize logger
-	logger, err := initLogger()
+	logger, err := initLogger(cfg.DevMode)
 	if err != nil {
 		log.Fatalf("Failed to create logger: %v", err)
 	}
 	defer logger.Sync()
+	logger.Info("Configuration loaded", zap.Any("config", platformconfig.Values(cfg)))
 
-	// Load configuration
-	config := loadConfig()
-	logger.Info("Configuration loaded",
-		zap.Strings("kafka_brokers", config.Kafka.Brokers),
-		zap.String("kafka_topic", config.Kafka.Topic),
-		zap.String("ws_port", config.WebSocket.Port),
-	)
+	upgrader.ReadBufferSize = cfg.WebSocket.ReadBufferSize
+	upgrader.WriteBufferSize = cfg.WebSocket.WriteBufferSize
+
+	// Initialize tracing; the Kafka consumer continues traces started by the producers
+	shutdownTracing, err := telemetry.Setup(context.Background(), telemetry.ConfigFromEnv("notification-service"))
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", zap.Error(err))
+	}
+
+	// Readiness checks for the dependencies of the consumer
+	kafkaClient, err := sarama.NewClient(cfg.Kafka.Brokers, sarama.NewConfig())
+	if err != nil {
+		logger.Fatal("Failed to create Kafka client for health checks", zap.Error(err))
+	}
+	checker := health.NewChecker(0)
+	checker.Register("kafka", kafkahealth.Brokers(kafkaClient))
+	checker.Register("schema-registry", kafkahealth.SchemaRegistry(nil, cfg.SchemaRegistry))
 
 	// Initialize WebSocket hub
 	hub := ws.NewHub(logger)
 
+	// User preferences, such as the locale of their notifications
+	preferenceRepo := repository.NewInMemoryPreferenceRepo()
+	apiKeyVerifier, userServiceConn := buildAPIKeyVerifier(cfg.APIKeys, logger)
+	preferencesHandler := httpapi.NewPreferencesHandler(usecases.NewPreferencesUseCase(preferenceRepo, logger),
+		cfg.Webhooks.AdminToken, apiKeyVerifier, logger)
+
+	// Webhook subscriptions of partners and the log of their deliveries
+	subscriptionRepo := repository.NewInMemorySubscriptionRepo()
+	deliveryRepo := repository.NewInMemoryDeliveryRepo()
+	dispatcher := webhook.NewDispatcher(webhook.Config{
+		Timeout:              cfg.Webhooks.Timeout,
+		MaxAttempts:          cfg.Webhooks.MaxAttempts,
+		Backoff:              lazy.Backoff{Initial: cfg.Webhooks.InitialBackoff, Max: cfg.Webhooks.MaxBackoff, Multiplier: 2},
+		DisableAfter:         cfg.Webhooks.DisableAfter,
+		AllowPrivateNetworks: cfg.Webhooks.AllowPrivateNetworks,
+	}, subscriptionRepo, deliveryRepo, logger)
+	webhooksHandler := httpapi.NewWebhooksHandler(
+		usecases.NewWebhookUseCase(subscriptionRepo, deliveryRepo, dispatcher, dispatcher, logger),
+		cfg.Webhooks.AdminToken, logger)
+
 	// Setup HTTP server with all routes
-	server := setupHTTPServer(config.WebSocket.Port, hub, logger)
+	server := setupHTTPServer(cfg.WebSocket.Port, hub, checker, preferencesHandler, webhooksHandler, logger)
+
+	// Message templates, reloaded from TEMPLATES_DIR when it is set
+	messageTemplates, err := templates.Load(cfg.Templates.Dir, logger)
+	if err != nil {
+		logger.Fatal("Failed to load message templates", zap.Error(err))
+	}
+
+	// Notifications go to WebSocket clients and webhooks, and by email when
+	// SMTP_HOST is set
+	channels := []publisher.Channel{
+		{Name: domain.ChannelWebSocket, Publisher: hub},
+		{Name: webhook.Channel, Publisher: dispatcher},
+	}
+	var mailer *email.Publisher
+	if cfg.Email.SMTPHost != "" {
+		mailer, err = email.NewPublisher(email.Config{
+			Addr:        net.JoinHostPort(cfg.Email.SMTPHost, strconv.Itoa(cfg.Email.SMTPPort)),
+			Username:    cfg.Email.Username,
+			Password:    cfg.Email.Password,
+			From:        cfg.Email.From,
+			Timeout:     cfg.Email.Timeout,
+			MaxAttempts: cfg.Email.MaxAttempts,
+			Backoff:     lazy.Backoff{Initial: cfg.Email.InitialBackoff, Max: cfg.Email.MaxBackoff, Multiplier: 2},
+			QueueSize:   cfg.Email.QueueSize,
+			Workers:     cfg.Email.Workers,
+		}, messageTemplates, preferenceRepo, logger)
+		if err != nil {
+			logger.Fatal("Failed to create email publisher", zap.Error(err))
+		}
+		channels = append(channels, publisher.Channel{Name: domain.ChannelEmail, Publisher: mailer})
+	}
+	notificationPublisher := publisher.NewComposite(preferenceRepo, logger, channels...)
 
 	// Setup notification use case
-	notificationUseCase := usecases.NewNotificationUseCase(logger, hub)
+	notificationUseCase := usecases.NewNotificationUseCase(logger, notificationPublisher)
 
 	// Setup Kafka consumer
+	var exactlyOnce *kafka.ExactlyOnce
+	if cfg.Kafka.ExactlyOnce {
+		exactlyOnce = &kafka.ExactlyOnce{
+			OutputTopic:           cfg.Kafka.OutputTopic,
+			TransactionalIDPrefix: cfg.Kafka.TransactionalIDPrefix,
+		}
+	}
+	registry := handlers.NewDefaultRegistry(handlers.Topics{
+		Order:     cfg.Kafka.OrderTopic,
+		User:      cfg.Kafka.UserTopic,
+		Inventory: cfg.Kafka.InventoryTopic,
+	}, handlers.NewMessages(messageTemplates, preferenceRepo, logger))
 	consumerGroup, err := kafka.NewKafkaConsumerGroup(
-		config.Kafka.Brokers,
-		config.Kafka.GroupID,
-		config.Kafka.Topic,
-		config.SchemaRegistry,
+		cfg.Kafka.Brokers,
+		cfg.Kafka.GroupID,
+		cfg.Kafka.Topics,
+		cfg.Kafka.OrderTopic,
+		cfg.SchemaRegistry,
+		registry,
+		exactlyOnce,
 		notificationUseCase,
 		logger,
 	)
@@ -96,105 +192,72 @@ func main() {
 		logger.Fatal("Failed to create Kafka consumer group", zap.Error(err))
 	}
 
-	// Start the HTTP server
-	go func() {
-		logger.Info("Starting WebSocket server", zap.String("port", config.WebSocket.Port))
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Fatal("WebSocket server failed", zap.Error(err))
-		}
-	}()
-
-	// Start Kafka consumer in a goroutine
-	ctx, cancel := context.WithCancel(context.Background())
-	go func() {
+	// Stop the HTTP server first, then the consumer, then flush what is left
+	runner := lifecycle.NewRunner(logger, cfg.ShutdownTimeout)
+	runner.OnStop("tracing", shutdownTracing)
+	runner.OnStop("kafka client", lifecycle.Close(kafkaClient))
+	if userServiceConn != nil {
+		runner.OnStop("user-service connection", lifecycle.Close(userServiceConn))
+	}
+	runner.Go("template reloader", func(ctx context.Context) error {
+		return messageTemplates.Watch(ctx, cfg.Templates.ReloadInterval)
+	})
+	runner.Go("webhook dispatcher", dispatcher.Run)
+	if mailer != nil {
+		runner.Go("email sender", mailer.Run)
+	}
+	runner.Go("kafka consumer", func(ctx context.Context) error {
 		logger.Info("Starting Kafka consumer",
-			zap.String("topic", config.Kafka.Topic),
-			zap.String("group_id", config.Kafka.GroupID))
-		if err := consumerGroup.Start(ctx); err != nil {
-			logger.Error("Error during Kafka consumption", zap.Error(err))
-		}
-	}()
-
-	// Graceful shutdown handling
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	// Block until we receive a termination signal
-	sig := <-sigChan
-	logger.Info("Received termination signal", zap.String("signal", sig.String()))
-
-	// Create a deadline for graceful shutdown
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer shutdownCancel()
-
-	// Stop Kafka consumer
-	cancel()
-
-	// Shutdown HTTP server
-	if err := server.Shutdown(shutdownCtx); err != nil {
-		logger.Error("HTTP server shutdown error", zap.Error(err))
+			zap.Strings("topics", cfg.Kafka.Topics),
+			zap.String("group_id", cfg.Kafka.GroupID),
+			zap.Bool("exactly_once", cfg.Kafka.ExactlyOnce))
+		return consumerGroup.Start(ctx)
+	})
+	logger.Info("Starting WebSocket server", zap.Int("port", cfg.WebSocket.Port))
+	runner.Add("http", lifecycle.HTTP(server))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := runner.Run(ctx); err != nil {
+		logger.Error("Notification service stopped with errors", zap.Error(err))
+		logger.Sync()
+		os.Exit(1)
 	}
 
 	logger.Info("Notification service gracefully stopped")
 }
 
 // initLogger creates a production-ready zap logger
-func initLogger() (*zap.Logger, error) {
+func initLogger(devMode bool) (*zap.Logger, error) {
 	// Use development logger if in dev mode
-	if os.Getenv("DEV_MODE") == "true" {
+	if devMode {
 		return zap.NewDevelopment()
 	}
 	return zap.NewProduction()
 }
 
-// loadConfig loads all configuration from environment variables
-func loadConfig() Config {
-	// Kafka configuration
-	brokersEnv := os.Getenv("KAFKA_BROKERS")
-	if brokersEnv == "" {
-		brokersEnv = "localhost:9092"
-	}
-	brokers := strings.Split(brokersEnv, ",")
-
-	groupID := os.Getenv("KAFKA_GROUP_ID")
-	if groupID == "" {
-		groupID = "notification-consumer-group"
-	}
-
-	topic := os.Getenv("KAFKA_TOPIC")
-	if topic == "" {
-		topic = "notifications"
-	}
-
-	// Schema Registry URL
-	schemaRegistryURL := os.Getenv("SCHEMA_REGISTRY_URL")
-	if schemaRegistryURL == "" {
-		schemaRegistryURL = "http://localhost:8081"
-	}
-
-	// WebSocket configuration
-	wsPort := os.Getenv("WS_PORT")
-	if wsPort == "" {
-		wsPort = "20052" // Default to 20052 as specified in your k8s config
+// setupHTTPServer configures the HTTP server with all routes
+// buildAPIKeyVerifier returns the verifier of the API keys of users and its
+// connection to user-service, or nils when keys are not verified.
+func buildAPIKeyVerifier(cfg config.APIKeys, logger *zap.Logger) (apikey.Verifier, *grpc.ClientConn) {
+	if !cfg.Enabled() {
+		logger.Info("API keys are not verified, USER_SERVICE_ADDRESS is not set")
+		return nil, nil
 	}
-
-	return Config{
-		Kafka: KafkaConfig{
-			Brokers: brokers,
-			GroupID: groupID,
-			Topic:   topic,
-		},
-		SchemaRegistry: schemaRegistryURL,
-		WebSocket: WebSocketConfig{
-			Port:            wsPort,
-			ReadBufferSize:  1024,
-			WriteBufferSize: 1024,
-		},
+	conn, err := grpc.NewClient(cfg.UserServiceAddress,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpctrace.DialOption(),
+		grpcmetrics.DialOption(),
+		grpclog.DialOption(),
+	)
+	if err != nil {
+		logger.Fatal("Invalid gRPC target", zap.String("address", cfg.UserServiceAddress), zap.Error(err))
 	}
+	verifier := clients.NewAPIKeyVerifier(user_service.NewUserServiceClient(conn), cfg.VerifyTimeout)
+	return apikey.NewCache(verifier, cfg.CacheTTL), conn
 }
 
-// setupHTTPServer configures the HTTP server with all routes
-func setupHTTPServer(port string, hub *ws.Hub, logger *zap.Logger) *http.Server {
+func setupHTTPServer(port int, hub *ws.Hub, checker *health.Checker, preferences *httpapi.PreferencesHandler, webhooks *httpapi.WebhooksHandler, logger *zap.Logger) *http.Server {
 	// Create router
 	mux := http.NewServeMux()
 
@@ -210,13 +273,30 @@ func setupHTTPServer(port string, hub *ws.Hub, logger *zap.Logger) *http.Server
 		fmt.Fprintf(w, "Notification Service API\n")
 		fmt.Fprintf(w, "Available endpoints:\n")
 		fmt.Fprintf(w, "- /health: Service health check\n")
+		fmt.Fprintf(w, "- /livez, /readyz: Liveness and readiness probes\n")
+		fmt.Fprintf(w, "- /metrics: Prometheus metrics\n")
 		fmt.Fprintf(w, "- /ws, /websocket, /socket: WebSocket connections\n")
+		fmt.Fprintf(w, "- /users/{userID}/preferences: Notification preferences\n")
+		fmt.Fprintf(w, "- /webhooks: Webhook subscriptions and their deliveries\n")
 		fmt.Fprintf(w, "- /debug: Debug information\n")
 	})
 
 	// Define your health route with exact matching
 	mux.HandleFunc("/health", healthHandler())
 
+	// Kubernetes probes
+	mux.Handle("/livez", health.LiveHandler())
+	mux.Handle("/readyz", health.ReadyHandler(checker))
+
+	// Prometheus metrics
+	mux.Handle("/metrics", metrics.Handler())
+
+	// Preferences API
+	preferences.Register(mux)
+
+	// Webhooks API
+	webhooks.Register(mux)
+
 	// WebSocket routes
 	wsHandlerFunc := wsHandler(hub, logger)
 	mux.HandleFunc("/ws", wsHandlerFunc)
@@ -238,7 +318,7 @@ func setupHTTPServer(port string, hub *ws.Hub, logger *zap.Logger) *http.Server
 
 	// Configure server
 	server := &http.Server{
-		Addr:         ":" + port,
+		Addr:         fmt.Sprintf(":%d", port),
 		Handler:      loggingMiddleware(logger)(mux),
 		ReadTimeout:  120 * time.Second,
 		WriteTimeout: 120 * time.Second,
@@ -0,0 +1,258 @@
+// Package email delivers notifications by email over SMTP, to the users
+// who set an address in their preferences and did not turn email off.
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"sync"
+	"time"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/interfaces"
+	"notification-service/internal/usecases"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lazy"
+	"go.uber.org/zap"
+)
+
+// Renderer renders the template name in locale, falling back to a default
+// locale. *templates.Templates implements it.
+type Renderer interface {
+	Render(name, locale string, data any) (string, error)
+}
+
+// footerTemplate is the template of the footer of every email.
+const footerTemplate = "email_footer"
+
+type Config struct {
+	// Addr is the host:port of the SMTP server. STARTTLS is used when the
+	// server offers it.
+	Addr string
+	// Username and Password authenticate with PLAIN auth when Username is
+	// set; net/smtp refuses it over unencrypted connections to other hosts
+	// than localhost.
+	Username string
+	Password string
+	// From is the sender address, e.g. "Shop <notifications@example.com>".
+	From string
+	// Timeout bounds each attempt to send an email.
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts to send an email; temporary
+	// failures are retried after waiting according to Backoff.
+	MaxAttempts int
+	Backoff     lazy.Backoff
+	// QueueSize bounds the emails waiting to be sent; when the queue is
+	// full, new emails are dropped.
+	QueueSize int
+	// Workers is the number of emails sent at the same time.
+	Workers int
+}
+
+// Publisher sends each notification for a user as an email with a text
+// and an HTML part. The body is the message of the notification, rendered
+// from the same templates as for WebSocket clients. The emails are queued
+// and sent in the background, until Run returns.
+type Publisher struct {
+	cfg         Config
+	from        *mail.Address
+	templates   Renderer
+	preferences usecases.PreferenceRepository
+	logger      *zap.Logger
+	queue       chan *outgoing
+}
+
+// outgoing is an email waiting in the queue.
+type outgoing struct {
+	notif *domain.Notification
+	to    string
+	msg   []byte
+}
+
+var _ interfaces.NotificationPublisher = (*Publisher)(nil)
+
+func NewPublisher(cfg Config, templates Renderer, preferences usecases.PreferenceRepository, logger *zap.Logger) (*Publisher, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("email sender %q: %w", cfg.From, err)
+	}
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.QueueSize < 1 {
+		cfg.QueueSize = 1
+	}
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	return &Publisher{
+		cfg:         cfg,
+		from:        from,
+		templates:   templates,
+		preferences: preferences,
+		logger:      logger,
+		queue:       make(chan *outgoing, cfg.QueueSize),
+	}, nil
+}
+
+// Run sends the queued emails with Workers workers until ctx is done, and
+// then waits for the attempts in flight. Emails still queued are dropped.
+func (p *Publisher) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for i := 0; i < p.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case email := <-p.queue:
+					p.deliver(ctx, email)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if n := len(p.queue); n > 0 {
+		emailsTotal.WithLabelValues("dropped").Add(float64(n))
+		p.logger.Warn("Emails not sent", zap.Int("count", n))
+	}
+	return nil
+}
+
+// PublishNotification queues an email of notif to its user and returns
+// without waiting for it. Notifications to everyone and users without an
+// address or who turned email off are skipped. When the queue is full the
+// email is dropped and logged.
+func (p *Publisher) PublishNotification(notif *domain.Notification) error {
+	if notif.UserID == "" {
+		return nil
+	}
+	prefs, err := p.preferences.Get(context.Background(), notif.UserID)
+	if errors.Is(err, domain.ErrPreferencesNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get preferences of user %s: %w", notif.UserID, err)
+	}
+	if prefs.Email == "" || !prefs.Wants(domain.ChannelEmail) {
+		return nil
+	}
+
+	msg, err := p.compose(notif, prefs.Email)
+	if err != nil {
+		emailsTotal.WithLabelValues("failed").Inc()
+		return err
+	}
+	select {
+	case p.queue <- &outgoing{notif: notif, to: prefs.Email, msg: msg}:
+	default:
+		emailsTotal.WithLabelValues("dropped").Inc()
+		p.logger.Error("Email queue full, dropping email",
+			zap.String("notificationID", notif.ID),
+			zap.String("userID", notif.UserID),
+			zap.Int("queueSize", p.cfg.QueueSize))
+	}
+	return nil
+}
+
+// deliver sends email, retrying temporary failures, until it is sent, fails
+// for good, or ctx is done, and logs the outcome.
+func (p *Publisher) deliver(ctx context.Context, email *outgoing) {
+	logger := p.logger.With(zap.String("notificationID", email.notif.ID), zap.String("userID", email.notif.UserID))
+	for attempt := 1; ; attempt++ {
+		err := p.send(email.to, email.msg)
+		if err == nil {
+			emailsTotal.WithLabelValues("sent").Inc()
+			logger.Info("Email sent", zap.Int("attempt", attempt))
+			return
+		}
+		if permanent(err) || attempt == p.cfg.MaxAttempts {
+			emailsTotal.WithLabelValues("failed").Inc()
+			logger.Error("Failed to send email",
+				zap.Int("attempt", attempt),
+				zap.Int("maxAttempts", p.cfg.MaxAttempts),
+				zap.Error(err))
+			return
+		}
+		delay := p.cfg.Backoff.Delay(attempt)
+		emailRetries.Inc()
+		logger.Warn("Failed to send email, retrying",
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", delay),
+			zap.Error(err))
+		select {
+		case <-ctx.Done():
+			emailsTotal.WithLabelValues("dropped").Inc()
+			logger.Warn("Email not sent, stopping", zap.Int("attempt", attempt))
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// send delivers msg to one recipient like smtp.SendMail, within the
+// timeout.
+func (p *Publisher) send(to string, msg []byte) error {
+	conn, err := net.DialTimeout("tcp", p.cfg.Addr, p.cfg.Timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if p.cfg.Timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(p.cfg.Timeout)); err != nil {
+			return err
+		}
+	}
+	host, _, err := net.SplitHostPort(p.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if p.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", p.cfg.Username, p.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(p.from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// permanent reports whether err is a permanent SMTP failure, a 5xx reply,
+// which sending again does not fix.
+func permanent(err error) bool {
+	var reply *textproto.Error
+	return errors.As(err, &reply) && reply.Code >= 500
+}
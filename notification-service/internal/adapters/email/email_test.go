@@ -0,0 +1,235 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"testing"
+	"time"
+
+	"notification-service/internal/adapters/email/smtptest"
+	"notification-service/internal/adapters/repository"
+	"notification-service/internal/adapters/templates"
+	"notification-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lazy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newPublisher returns a running publisher sending to server with the
+// compiled-in templates, for users with preferences, and its logs.
+func newPublisher(t *testing.T, server *smtptest.Server, username, password string, preferences ...domain.Preferences) (*Publisher, *observer.ObservedLogs) {
+	t.Helper()
+	core, logs := observer.New(zap.InfoLevel)
+	p := newStoppedPublisher(t, Config{
+		Addr:        server.Addr,
+		Username:    username,
+		Password:    password,
+		From:        "Shop <notifications@shop.example>",
+		Timeout:     time.Second,
+		MaxAttempts: 3,
+		Backoff:     lazy.Backoff{Initial: time.Millisecond, Max: 2 * time.Millisecond, Multiplier: 2},
+		QueueSize:   10,
+	}, zap.New(core), preferences...)
+	run(t, p)
+	return p, logs
+}
+
+func newStoppedPublisher(t *testing.T, cfg Config, logger *zap.Logger, preferences ...domain.Preferences) *Publisher {
+	t.Helper()
+	tmpl, err := templates.Load("", zap.NewNop())
+	require.NoError(t, err)
+	repo := repository.NewInMemoryPreferenceRepo()
+	for _, prefs := range preferences {
+		require.NoError(t, repo.Save(context.Background(), &prefs))
+	}
+	p, err := NewPublisher(cfg, tmpl, repo, logger)
+	require.NoError(t, err)
+	return p
+}
+
+// run runs p until the test ends.
+func run(t *testing.T, p *Publisher) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- p.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+}
+
+// received waits for server to have n messages and returns them.
+func received(t *testing.T, server *smtptest.Server, n int) []smtptest.Message {
+	t.Helper()
+	require.Eventually(t, func() bool { return len(server.Messages()) == n }, time.Second, time.Millisecond)
+	return server.Messages()
+}
+
+// failure waits for the log of an email that failed for good and returns
+// its fields.
+func failure(t *testing.T, logs *observer.ObservedLogs) map[string]any {
+	t.Helper()
+	require.Eventually(t, func() bool { return logs.FilterMessage("Failed to send email").Len() > 0 }, time.Second, time.Millisecond)
+	return logs.FilterMessage("Failed to send email").TakeAll()[0].ContextMap()
+}
+
+type parsedEmail struct {
+	header mail.Header
+	parts  map[string]string
+}
+
+// parse reads the headers of data and its parts by media type.
+func parse(t *testing.T, data []byte) parsedEmail {
+	t.Helper()
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	require.NoError(t, err)
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	parsed := parsedEmail{header: msg.Header, parts: make(map[string]string)}
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		partType, _, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+		parsed.parts[partType] = string(body)
+	}
+	return parsed
+}
+
+func shipped(userID, locale, message string) *domain.Notification {
+	notif := domain.NewNotificationWithID("order-1", "SHIPPED", message)
+	notif.UserID, notif.Locale = userID, locale
+	return notif
+}
+
+func TestPublishNotification(t *testing.T) {
+	server := smtptest.NewServer("mailer", "s3cret")
+	defer server.Close()
+	p, _ := newPublisher(t, server, "mailer", "s3cret",
+		domain.Preferences{UserID: "user-1", Email: "ada@example.com", Locale: "th"})
+
+	require.NoError(t, p.PublishNotification(shipped("user-1", "th", "คำสั่งซื้อ <order-1> ถูกจัดส่งแล้ว & กำลังไป")))
+
+	messages := received(t, server, 1)
+	assert.Equal(t, "notifications@shop.example", messages[0].From)
+	assert.Equal(t, []string{"ada@example.com"}, messages[0].To)
+
+	email := parse(t, messages[0].Data)
+	subject, err := new(mime.WordDecoder).DecodeHeader(email.header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "คำสั่งซื้อ <order-1> ถูกจัดส่งแล้ว & กำลังไป", subject)
+	assert.Equal(t, `"Shop" <notifications@shop.example>`, email.header.Get("From"))
+	assert.Equal(t, "<ada@example.com>", email.header.Get("To"))
+	assert.Contains(t, email.header.Get("Message-ID"), "@shop.example>")
+
+	text := email.parts["text/plain"]
+	assert.Contains(t, text, "คำสั่งซื้อ <order-1> ถูกจัดส่งแล้ว & กำลังไป")
+	assert.Contains(t, text, "คุณได้รับอีเมลนี้", "the footer in the locale of the notification")
+
+	html := email.parts["text/html"]
+	assert.Contains(t, html, `<html lang="th">`)
+	assert.Contains(t, html, "คำสั่งซื้อ &lt;order-1&gt; ถูกจัดส่งแล้ว &amp; กำลังไป", "the message is escaped")
+	assert.Contains(t, html, "คุณได้รับอีเมลนี้")
+}
+
+func TestPublishNotification_Skipped(t *testing.T) {
+	server := smtptest.NewServer("", "")
+	defer server.Close()
+	p, _ := newPublisher(t, server, "", "",
+		domain.Preferences{UserID: "no-email", Locale: "en"},
+		domain.Preferences{UserID: "websocket-only", Email: "bob@example.com", Channels: []string{domain.ChannelWebSocket}},
+	)
+
+	for _, notif := range []*domain.Notification{
+		shipped("", "", "Broadcasts are not emailed"),
+		shipped("no-preferences", "", "Hi"),
+		shipped("no-email", "", "Hi"),
+		shipped("websocket-only", "", "Hi"),
+	} {
+		require.NoError(t, p.PublishNotification(notif))
+	}
+	assert.Zero(t, server.Attempts())
+}
+
+func TestPublishNotification_Retries(t *testing.T) {
+	server := smtptest.NewServer("", "")
+	defer server.Close()
+	p, logs := newPublisher(t, server, "", "", domain.Preferences{UserID: "user-1", Email: "ada@example.com"})
+
+	server.FailNext("451 4.3.0 try again later", "421 4.7.0 too busy")
+	require.NoError(t, p.PublishNotification(shipped("user-1", "", "Your order order-1 has shipped.")))
+	received(t, server, 1)
+	assert.Equal(t, 3, server.Attempts())
+
+	server.FailNext("451 4.3.0 try again later", "451 4.3.0 try again later", "451 4.3.0 try again later")
+	require.NoError(t, p.PublishNotification(shipped("user-1", "", "Your order order-1 has shipped.")))
+	fields := failure(t, logs)
+	assert.Equal(t, int64(3), fields["attempt"])
+	assert.Equal(t, int64(3), fields["maxAttempts"])
+	assert.Equal(t, 6, server.Attempts())
+}
+
+func TestPublishNotification_PermanentFailure(t *testing.T) {
+	server := smtptest.NewServer("", "")
+	defer server.Close()
+	p, logs := newPublisher(t, server, "", "", domain.Preferences{UserID: "user-1", Email: "ada@example.com"})
+
+	server.FailNext("550 5.1.1 mailbox unavailable")
+	require.NoError(t, p.PublishNotification(shipped("user-1", "", "Your order order-1 has shipped.")))
+
+	fields := failure(t, logs)
+	assert.Equal(t, int64(1), fields["attempt"])
+	assert.Contains(t, fields["error"], "550")
+	assert.Equal(t, 1, server.Attempts(), "a 5xx reply is not retried")
+}
+
+func TestPublishNotification_WrongCredentials(t *testing.T) {
+	server := smtptest.NewServer("mailer", "s3cret")
+	defer server.Close()
+	p, logs := newPublisher(t, server, "mailer", "wrong", domain.Preferences{UserID: "user-1", Email: "ada@example.com"})
+
+	require.NoError(t, p.PublishNotification(shipped("user-1", "", "Your order order-1 has shipped.")))
+
+	assert.Contains(t, failure(t, logs)["error"], "535")
+	assert.Empty(t, server.Messages())
+}
+
+// TestPublishNotification_Queued checks that publishing does not wait for
+// the SMTP server, and drops emails when the queue is full.
+func TestPublishNotification_Queued(t *testing.T) {
+	server := smtptest.NewServer("", "")
+	defer server.Close()
+	core, logs := observer.New(zap.InfoLevel)
+	p := newStoppedPublisher(t, Config{
+		Addr:      server.Addr,
+		From:      "notifications@shop.example",
+		Timeout:   time.Second,
+		QueueSize: 1,
+	}, zap.New(core), domain.Preferences{UserID: "user-1", Email: "ada@example.com"})
+
+	require.NoError(t, p.PublishNotification(shipped("user-1", "", "First")))
+	require.NoError(t, p.PublishNotification(shipped("user-1", "", "Second")))
+	assert.Zero(t, server.Attempts(), "nothing is sent until Run")
+	assert.Equal(t, 1, logs.FilterMessage("Email queue full, dropping email").Len())
+
+	run(t, p)
+	messages := received(t, server, 1)
+	email := parse(t, messages[0].Data)
+	assert.Equal(t, "First", email.header.Get("Subject"))
+}
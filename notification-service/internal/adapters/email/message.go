@@ -0,0 +1,91 @@
+package email
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"notification-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+//go:embed layout.html
+var layoutHTML string
+
+// layout wraps the message in the HTML part. html/template escapes the
+// message and footer, which are plain text.
+var layout = template.Must(template.New("layout.html").Parse(layoutHTML))
+
+type layoutData struct {
+	Lang    string
+	Subject string
+	Message string
+	Footer  string
+}
+
+// compose writes the email of notif to the address to, as multipart/
+// alternative with a text and an HTML part. The subject is the message.
+func (p *Publisher) compose(notif *domain.Notification, to string) ([]byte, error) {
+	footer, err := p.templates.Render(footerTemplate, notif.Locale, nil)
+	if err != nil {
+		return nil, fmt.Errorf("render email footer: %w", err)
+	}
+	data := layoutData{Lang: notif.Locale, Subject: notif.Message, Message: notif.Message, Footer: footer}
+	var html bytes.Buffer
+	if err := layout.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("render email layout: %w", err)
+	}
+	text := notif.Message + "\n\n-- \n" + footer + "\n"
+
+	var b bytes.Buffer
+	body := multipart.NewWriter(&b)
+	header := func(key, value string) { fmt.Fprintf(&b, "%s: %s\r\n", key, value) }
+	header("From", p.from.String())
+	header("To", (&mail.Address{Address: to}).String())
+	header("Subject", mime.QEncoding.Encode("utf-8", notif.Message))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", fmt.Sprintf("<%s@%s>", uuid.NewString(), domainOf(p.from.Address)))
+	header("MIME-Version", "1.0")
+	header("Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": body.Boundary()}))
+	b.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html.String()},
+	} {
+		w, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func domainOf(address string) string {
+	if i := strings.LastIndexByte(address, '@'); i >= 0 {
+		return address[i+1:]
+	}
+	return "localhost"
+}
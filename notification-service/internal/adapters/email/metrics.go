@@ -0,0 +1,18 @@
+package email
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	emailsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "email_notifications_total",
+		Help: "Number of notification emails by outcome: sent, failed after all attempts, or dropped unsent.",
+	}, []string{"outcome"})
+
+	emailRetries = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "email_retries_total",
+		Help: "Number of notification emails sent again after a temporary failure.",
+	})
+)
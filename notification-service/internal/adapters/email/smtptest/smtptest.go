@@ -0,0 +1,197 @@
+// Package smtptest runs an in-process SMTP server for tests, like
+// net/http/httptest does for HTTP. It accepts every message unless told to
+// fail, and keeps what it receives.
+package smtptest
+
+import (
+	"encoding/base64"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Message is an email the server received.
+type Message struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+type Server struct {
+	// Addr is the host:port the server listens on.
+	Addr string
+
+	username, password string
+	listener           net.Listener
+	wg                 sync.WaitGroup
+
+	mu       sync.Mutex
+	conns    map[net.Conn]struct{}
+	messages []Message
+	failures []string
+	attempts int
+}
+
+// NewServer starts a server on a loopback port. With a username, clients
+// must authenticate with PLAIN auth and the password.
+func NewServer(username, password string) *Server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("smtptest: failed to listen: " + err.Error())
+	}
+	s := &Server{Addr: ln.Addr().String(), username: username, password: password, listener: ln, conns: make(map[net.Conn]struct{})}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Close stops the server, closing the open connections.
+func (s *Server) Close() {
+	s.listener.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// FailNext makes the server answer the next messages with replies, one per
+// message, e.g. "451 4.3.0 try again later", instead of accepting them.
+func (s *Server) FailNext(replies ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, replies...)
+}
+
+// Messages returns the messages accepted so far.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// Attempts returns the number of messages sent to the server, accepted or
+// not.
+func (s *Server) Attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attempts
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer func() {
+				s.mu.Lock()
+				delete(s.conns, conn)
+				s.mu.Unlock()
+				conn.Close()
+			}()
+			s.handle(textproto.NewConn(conn))
+		}()
+	}
+}
+
+func (s *Server) handle(c *textproto.Conn) {
+	c.PrintfLine("220 smtptest ESMTP")
+	authenticated := s.username == ""
+	var msg Message
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			c.PrintfLine("250-smtptest")
+			if s.username != "" {
+				c.PrintfLine("250-AUTH PLAIN")
+			}
+			c.PrintfLine("250 8BITMIME")
+		case "AUTH":
+			if s.checkAuth(arg) {
+				authenticated = true
+				c.PrintfLine("235 2.7.0 authenticated")
+			} else {
+				c.PrintfLine("535 5.7.8 invalid credentials")
+			}
+		case "MAIL":
+			if !authenticated {
+				c.PrintfLine("530 5.7.0 authentication required")
+				continue
+			}
+			msg = Message{From: address(arg)}
+			c.PrintfLine("250 2.1.0 ok")
+		case "RCPT":
+			msg.To = append(msg.To, address(arg))
+			c.PrintfLine("250 2.1.5 ok")
+		case "DATA":
+			c.PrintfLine("354 end data with <CR><LF>.<CR><LF>")
+			data, err := io.ReadAll(c.DotReader())
+			if err != nil {
+				return
+			}
+			msg.Data = data
+			c.PrintfLine("%s", s.receive(msg))
+		case "RSET":
+			msg = Message{}
+			c.PrintfLine("250 2.0.0 ok")
+		case "NOOP":
+			c.PrintfLine("250 2.0.0 ok")
+		case "QUIT":
+			c.PrintfLine("221 2.0.0 bye")
+			return
+		default:
+			c.PrintfLine("502 5.5.2 command not implemented")
+		}
+	}
+}
+
+// receive records msg and returns the reply to it.
+func (s *Server) receive(msg Message) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if len(s.failures) > 0 {
+		reply := s.failures[0]
+		s.failures = s.failures[1:]
+		return reply
+	}
+	s.messages = append(s.messages, msg)
+	return "250 2.0.0 queued"
+}
+
+// checkAuth checks the arguments of AUTH, "PLAIN <initial response>".
+func (s *Server) checkAuth(arg string) bool {
+	mechanism, response, _ := strings.Cut(arg, " ")
+	if !strings.EqualFold(mechanism, "PLAIN") {
+		return false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(response)
+	if err != nil {
+		return false
+	}
+	parts := strings.Split(string(decoded), "\x00")
+	return len(parts) == 3 && parts[1] == s.username && parts[2] == s.password
+}
+
+// address returns the address of "FROM:<a@b>" or "TO:<a@b>".
+func address(arg string) string {
+	_, addr, _ := strings.Cut(arg, ":")
+	addr, _, _ = strings.Cut(strings.TrimSpace(addr), " ")
+	return strings.Trim(addr, "<>")
+}
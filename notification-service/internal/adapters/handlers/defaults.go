@@ -0,0 +1,32 @@
+package handlers
+
+// Topics names the topic of each kind of event. A kind with an empty name
+// is not handled.
+type Topics struct {
+	Order     string
+	User      string
+	Inventory string
+}
+
+// NewDefaultRegistry registers the handlers of every known event type on
+// topics, and Fallback for the rest. Messages are rendered by messages from
+// the template named after the kind and type of the event, e.g.
+// order_created.
+func NewDefaultRegistry(topics Topics, messages *Messages) *Registry {
+	r := NewRegistry(Fallback())
+	if topics.Order != "" {
+		r.Register(topics.Order, OrderCreated, orderHandler(messages, "order_created"))
+		r.Register(topics.Order, OrderShipped, orderHandler(messages, "order_shipped"))
+		r.Register(topics.Order, OrderCancelled, orderHandler(messages, "order_cancelled"))
+		r.Register(topics.Order, AnyType, orderHandler(messages, ""))
+	}
+	if topics.User != "" {
+		r.Register(topics.User, UserRegistered, userHandler(messages, "user_registered"))
+		r.Register(topics.User, UserPasswordChanged, userHandler(messages, "user_password_changed"))
+	}
+	if topics.Inventory != "" {
+		r.Register(topics.Inventory, InventoryLowStock, inventoryHandler(messages, "inventory_low_stock"))
+		r.Register(topics.Inventory, InventoryOutOfStock, inventoryHandler(messages, "inventory_out_of_stock"))
+	}
+	return r
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"notification-service/internal/adapters/repository"
+	"notification-service/internal/adapters/templates"
+	"notification-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/events/orderevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var allTopics = Topics{Order: "order-events", User: "user-events", Inventory: "inventory-events"}
+
+// newMessages renders with the compiled-in templates and preferences.
+func newMessages(t *testing.T, preferences ...domain.Preferences) *Messages {
+	t.Helper()
+	tmpl, err := templates.Load("", zap.NewNop())
+	require.NoError(t, err)
+	repo := repository.NewInMemoryPreferenceRepo()
+	for _, prefs := range preferences {
+		require.NoError(t, repo.Save(context.Background(), &prefs))
+	}
+	return NewMessages(tmpl, repo, zap.NewNop())
+}
+
+// orderEvent is an order event as the consumer decodes it.
+func orderEvent(event *orderevents.OrderEvent) *Event {
+	return &Event{Topic: "order-events", Type: event.EventType, ID: event.EventID, Fields: event.AvroNative(), Order: event}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	r := NewDefaultRegistry(allTopics, newMessages(t))
+	order := &orderevents.Order{OrderID: "order-1", UserID: "user-1", TotalQuantity: 3,
+		Total: &orderevents.Money{AmountMinor: 4500, CurrencyCode: "USD"}}
+
+	for _, tt := range []struct {
+		name        string
+		event       *Event
+		wantID      string
+		wantUserID  string
+		wantMessage string
+	}{
+		{
+			name:   "order created",
+			event:  orderEvent(&orderevents.OrderEvent{OrderID: "order-1", EventType: OrderCreated, EventID: "e-1", Order: order}),
+			wantID: "order-1", wantUserID: "user-1",
+			wantMessage: "Your order order-1 has been placed with 3 items, total 45.00 USD.",
+		},
+		{
+			name:        "version 1 order created",
+			event:       orderEvent(&orderevents.OrderEvent{OrderID: "order-1", EventType: OrderCreated}),
+			wantID:      "order-1",
+			wantMessage: "Your order order-1 has been placed.",
+		},
+		{
+			name:   "other order event",
+			event:  orderEvent(&orderevents.OrderEvent{OrderID: "order-1", EventType: "REFUNDED", Message: "Refund issued", Order: order}),
+			wantID: "order-1", wantUserID: "user-1",
+			wantMessage: "Refund issued",
+		},
+		{
+			name: "user registered",
+			event: &Event{Topic: "user-events", Type: UserRegistered, ID: "e-2",
+				Fields: map[string]any{"user_id": "user-1", "name": map[string]any{"string": "Ada"}}},
+			wantID: "user-1", wantUserID: "user-1",
+			wantMessage: "Welcome, Ada!",
+		},
+		{
+			name: "low stock",
+			event: &Event{Topic: "inventory-events", Type: InventoryLowStock,
+				Fields: map[string]any{"product_id": "p-1", "quantity": int32(2)}},
+			wantID:      "p-1",
+			wantMessage: "Only 2 left of product p-1.",
+		},
+		{
+			name: "unknown inventory event",
+			event: &Event{Topic: "inventory-events", Type: "RESTOCKED", Key: "p-1",
+				Fields: map[string]any{"product_id": "p-1", "message": "Back in stock"}},
+			wantID:      "p-1",
+			wantMessage: "Back in stock",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			notifs, err := r.Handle(context.Background(), tt.event)
+			require.NoError(t, err)
+			require.Len(t, notifs, 1)
+			assert.Equal(t, tt.wantID, notifs[0].ID)
+			assert.Equal(t, tt.event.Type, notifs[0].Type)
+			assert.Equal(t, tt.event.ID, notifs[0].EventID)
+			assert.Equal(t, tt.wantUserID, notifs[0].UserID)
+			assert.Equal(t, tt.wantMessage, notifs[0].Message)
+		})
+	}
+}
+
+func TestDefaultRegistry_MissingFields(t *testing.T) {
+	r := NewDefaultRegistry(Topics{Order: "order-events", User: "user-events"}, newMessages(t))
+
+	_, err := r.Handle(context.Background(), &Event{Topic: "order-events", Type: OrderCreated, Fields: map[string]any{}})
+	assert.ErrorContains(t, err, "not an order event")
+	_, err = r.Handle(context.Background(), &Event{Topic: "user-events", Type: UserRegistered, Fields: map[string]any{}})
+	assert.ErrorContains(t, err, "has no user_id")
+}
+
+func TestDefaultRegistry_Locales(t *testing.T) {
+	r := NewDefaultRegistry(allTopics, newMessages(t,
+		domain.Preferences{UserID: "user-th", Locale: "th"},
+		domain.Preferences{UserID: "user-en", Locale: "en"},
+	))
+	registered := func(userID, lang string) string {
+		t.Helper()
+		notifs, err := r.Handle(context.Background(), &Event{Topic: "user-events", Type: UserRegistered, Lang: lang,
+			Fields: map[string]any{"user_id": userID, "name": map[string]any{"string": "Ada"}}})
+		require.NoError(t, err)
+		return notifs[0].Locale + ": " + notifs[0].Message
+	}
+
+	assert.Equal(t, "th: ยินดีต้อนรับ คุณAda!", registered("user-th", ""), "the preference of the user")
+	assert.Equal(t, "en: Welcome, Ada!", registered("user-en", "th"), "the preference wins over the lang header")
+	assert.Equal(t, "th: ยินดีต้อนรับ คุณAda!", registered("user-new", "th-TH"), "the lang header without a preference")
+	assert.Equal(t, "en: Welcome, Ada!", registered("user-new", "de"), "English without a template in the locale")
+}
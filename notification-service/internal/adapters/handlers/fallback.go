@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"notification-service/internal/domain"
+)
+
+// Fallback notifies everyone of an event no handler claims, with the
+// message field of the event if it has one. The notification is named
+// after the event ID or, failing that, the message key.
+func Fallback() Handler {
+	return HandlerFunc(func(_ context.Context, event *Event) ([]*domain.Notification, error) {
+		id := event.ID
+		if id == "" {
+			id = event.Key
+		}
+		if id == "" || event.Type == "" {
+			return nil, fmt.Errorf("event of topic %s has no ID, key or type", event.Topic)
+		}
+		notif := domain.NewNotificationWithID(id, event.Type, stringField(event.Fields, "message"))
+		notif.EventID = event.ID
+		return []*domain.Notification{notif}, nil
+	})
+}
@@ -0,0 +1,16 @@
+package handlers
+
+// stringField returns the string field name of fields, also when it is in
+// a nullable union, and "" when it is missing or not a string.
+func stringField(fields map[string]any, name string) string {
+	switch v := fields[name].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case map[string]any:
+		s, _ := v["string"].(string)
+		return s
+	}
+	return ""
+}
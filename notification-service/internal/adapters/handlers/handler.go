@@ -0,0 +1,95 @@
+// Package handlers turns the events of the consumed topics into
+// notifications. A Registry picks the handler of each event by its topic
+// and type, and hands events no handler claims to a fallback.
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"notification-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/events/orderevents"
+)
+
+// Event is a decoded event of one of the consumed topics.
+type Event struct {
+	Topic string
+	Key   string
+	// Type and ID are the event_type and event_id fields of the event; ID
+	// is empty for events without one.
+	Type string
+	ID   string
+	// Lang is the lang header of the message, the locale to write the
+	// notifications in for recipients without a preference.
+	Lang string
+	// Fields are the fields of the event record in the native form of
+	// goavro: records are maps by field name and unions maps by type name.
+	Fields map[string]any
+	// Order is set for the events of the order topic.
+	Order *orderevents.OrderEvent
+}
+
+// Handler turns an event into the notifications it causes, none if it
+// causes none. Notifications of the same event must have distinct IDs.
+type Handler interface {
+	Handle(ctx context.Context, event *Event) ([]*domain.Notification, error)
+}
+
+// HandlerFunc adapts a function to Handler.
+type HandlerFunc func(ctx context.Context, event *Event) ([]*domain.Notification, error)
+
+func (f HandlerFunc) Handle(ctx context.Context, event *Event) ([]*domain.Notification, error) {
+	return f(ctx, event)
+}
+
+// AnyType registers a handler for the event types of a topic that have no
+// handler of their own.
+const AnyType = "*"
+
+type route struct {
+	topic     string
+	eventType string
+}
+
+// Registry routes events to handlers by topic and event type. It is not
+// safe to register handlers while events are handled.
+type Registry struct {
+	handlers map[route]Handler
+	fallback Handler
+}
+
+var _ Handler = (*Registry)(nil)
+
+// NewRegistry returns a registry that hands events without a handler to
+// fallback.
+func NewRegistry(fallback Handler) *Registry {
+	return &Registry{handlers: make(map[route]Handler), fallback: fallback}
+}
+
+// Register handles the events of eventType on topic with h. eventType may
+// be AnyType. Registering a route twice panics.
+func (r *Registry) Register(topic, eventType string, h Handler) {
+	key := route{topic: topic, eventType: eventType}
+	if _, ok := r.handlers[key]; ok {
+		panic(fmt.Sprintf("handlers: %s events of topic %s registered twice", eventType, topic))
+	}
+	r.handlers[key] = h
+}
+
+// Lookup returns the handler of eventType on topic, that of AnyType on
+// topic if it has none, and the fallback otherwise.
+func (r *Registry) Lookup(topic, eventType string) Handler {
+	if h, ok := r.handlers[route{topic: topic, eventType: eventType}]; ok {
+		return h
+	}
+	if h, ok := r.handlers[route{topic: topic, eventType: AnyType}]; ok {
+		return h
+	}
+	return r.fallback
+}
+
+// Handle hands event to its handler.
+func (r *Registry) Handle(ctx context.Context, event *Event) ([]*domain.Notification, error) {
+	return r.Lookup(event.Topic, event.Type).Handle(ctx, event)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"notification-service/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// named returns a handler that notifies with message name, to tell which
+// handler an event went to.
+func named(name string) Handler {
+	return HandlerFunc(func(context.Context, *Event) ([]*domain.Notification, error) {
+		return []*domain.Notification{{Message: name}}, nil
+	})
+}
+
+func TestRegistry_Routes(t *testing.T) {
+	r := NewRegistry(named("fallback"))
+	r.Register("orders", "CREATED", named("created"))
+	r.Register("orders", AnyType, named("any order"))
+	r.Register("users", "REGISTERED", named("registered"))
+
+	for _, tt := range []struct {
+		topic, eventType, want string
+	}{
+		{"orders", "CREATED", "created"},
+		{"orders", "REFUNDED", "any order"},
+		{"users", "REGISTERED", "registered"},
+		{"users", "DELETED", "fallback"},
+		{"audit", "CREATED", "fallback"},
+	} {
+		notifs, err := r.Handle(context.Background(), &Event{Topic: tt.topic, Type: tt.eventType})
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, notifs[0].Message, "%s event of %s", tt.eventType, tt.topic)
+	}
+}
+
+func TestRegistry_RegisterTwicePanics(t *testing.T) {
+	r := NewRegistry(Fallback())
+	r.Register("orders", "CREATED", named("created"))
+	assert.Panics(t, func() { r.Register("orders", "CREATED", named("again")) })
+}
+
+func TestFallback(t *testing.T) {
+	notifs, err := Fallback().Handle(context.Background(), &Event{
+		Topic: "audit", Key: "k-1", Type: "EXPORTED",
+		Fields: map[string]any{"message": "Export finished"},
+	})
+	require.NoError(t, err)
+	require.Len(t, notifs, 1)
+	assert.Equal(t, "k-1", notifs[0].ID)
+	assert.Equal(t, "EXPORTED", notifs[0].Type)
+	assert.Equal(t, "Export finished", notifs[0].Message)
+
+	notifs, err = Fallback().Handle(context.Background(), &Event{Topic: "audit", Key: "k-1", ID: "event-1", Type: "EXPORTED"})
+	require.NoError(t, err)
+	assert.Equal(t, "event-1", notifs[0].ID)
+	assert.Equal(t, "event-1", notifs[0].EventID)
+
+	_, err = Fallback().Handle(context.Background(), &Event{Topic: "audit", Key: "k-1"})
+	assert.ErrorContains(t, err, "no ID, key or type")
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"notification-service/internal/domain"
+)
+
+// Types of the inventory events. Inventory events carry product_id and may
+// carry quantity, the stock left.
+const (
+	InventoryLowStock   = "LOW_STOCK"
+	InventoryOutOfStock = "OUT_OF_STOCK"
+)
+
+// inventoryHandler notifies everyone of a product's stock with the message
+// template renders.
+func inventoryHandler(messages *Messages, template string) Handler {
+	return HandlerFunc(func(ctx context.Context, event *Event) ([]*domain.Notification, error) {
+		productID := stringField(event.Fields, "product_id")
+		if productID == "" {
+			return nil, fmt.Errorf("%s event of topic %s has no product_id", event.Type, event.Topic)
+		}
+		notif := domain.NewNotificationWithID(productID, event.Type, "")
+		notif.EventID = event.ID
+		if err := messages.Render(ctx, template, event, notif); err != nil {
+			return nil, err
+		}
+		return []*domain.Notification{notif}, nil
+	})
+}
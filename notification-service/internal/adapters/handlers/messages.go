@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/usecases"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"go.uber.org/zap"
+)
+
+// Renderer renders the message template name in locale, falling back to a
+// default locale, and tells the locale it renders in. *templates.Templates
+// implements it.
+type Renderer interface {
+	Render(name, locale string, data any) (string, error)
+	Locale(name, locale string) string
+}
+
+// Messages writes notification messages from templates, in the locale the
+// recipient prefers, else in the lang of the event.
+type Messages struct {
+	templates   Renderer
+	preferences usecases.PreferenceRepository
+	logger      *zap.Logger
+}
+
+// NewMessages renders with templates. preferences may be nil.
+func NewMessages(templates Renderer, preferences usecases.PreferenceRepository, logger *zap.Logger) *Messages {
+	return &Messages{templates: templates, preferences: preferences, logger: logger}
+}
+
+// Render sets the message of notif to template name rendered for event, in
+// the locale of notif.UserID or of the event for notifications to everyone,
+// and its locale to that of the message. The template gets the fields of
+// the event.
+func (m *Messages) Render(ctx context.Context, name string, event *Event, notif *domain.Notification) error {
+	locale := m.locale(ctx, event, notif.UserID)
+	message, err := m.templates.Render(name, locale, templateData(event.Fields))
+	if err != nil {
+		return err
+	}
+	notif.Message, notif.Locale = message, m.templates.Locale(name, locale)
+	return nil
+}
+
+func (m *Messages) locale(ctx context.Context, event *Event, userID string) string {
+	if userID != "" && m.preferences != nil {
+		prefs, err := m.preferences.Get(ctx, userID)
+		switch {
+		case err == nil && prefs.Locale != "":
+			return prefs.Locale
+		case err != nil && !errors.Is(err, domain.ErrPreferencesNotFound):
+			logging.FromContext(ctx, m.logger).Warn("failed to get preferences, using the event's locale",
+				zap.String("userID", userID), zap.Error(err))
+		}
+	}
+	return event.Lang
+}
+
+// templateData returns fields with the unions unwrapped, so templates use
+// {{.order.total_quantity}} rather than naming the union's type. goavro
+// writes a non-null union value as a map from its type name to the value;
+// type names are primitive names or, for named types in a namespace, hold a
+// dot, which field names cannot. Named types without a namespace stay
+// wrapped.
+func templateData(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 1 {
+			for name, value := range v {
+				if primitiveTypes[name] || strings.Contains(name, ".") {
+					return templateData(value)
+				}
+			}
+		}
+		data := make(map[string]any, len(v))
+		for name, value := range v {
+			data[name] = templateData(value)
+		}
+		return data
+	case []any:
+		data := make([]any, len(v))
+		for i, value := range v {
+			data[i] = templateData(value)
+		}
+		return data
+	}
+	return v
+}
+
+var primitiveTypes = map[string]bool{
+	"boolean": true, "int": true, "long": true, "float": true,
+	"double": true, "bytes": true, "string": true,
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	mappers "notification-service/internal/adapters/mapper"
+	"notification-service/internal/domain"
+)
+
+// Types of the order events. Only CREATED is published so far.
+const (
+	OrderCreated   = "CREATED"
+	OrderShipped   = "SHIPPED"
+	OrderCancelled = "CANCELLED"
+)
+
+// orderHandler notifies the user of an order with the message template
+// renders, or the message of the event if template is empty. Version 1
+// events do not carry the order and go to everyone.
+func orderHandler(messages *Messages, template string) Handler {
+	return HandlerFunc(func(ctx context.Context, event *Event) ([]*domain.Notification, error) {
+		notif, err := orderNotification(event)
+		if err != nil {
+			return nil, err
+		}
+		if template != "" {
+			if err := messages.Render(ctx, template, event, notif); err != nil {
+				return nil, err
+			}
+		}
+		return []*domain.Notification{notif}, nil
+	})
+}
+
+func orderNotification(event *Event) (*domain.Notification, error) {
+	if event.Order == nil {
+		return nil, fmt.Errorf("%s event of topic %s is not an order event", event.Type, event.Topic)
+	}
+	notif, err := mappers.OrderEventToNotification(event.Order)
+	if err != nil {
+		return nil, err
+	}
+	if event.Order.Order != nil {
+		notif.UserID = event.Order.Order.UserID
+	}
+	return notif, nil
+}
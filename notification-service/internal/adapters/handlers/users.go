@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"notification-service/internal/domain"
+)
+
+// Types of the user events. User events carry user_id and may carry name.
+const (
+	UserRegistered      = "REGISTERED"
+	UserPasswordChanged = "PASSWORD_CHANGED"
+)
+
+// userHandler notifies the user of the event with the message template
+// renders.
+func userHandler(messages *Messages, template string) Handler {
+	return HandlerFunc(func(ctx context.Context, event *Event) ([]*domain.Notification, error) {
+		userID := stringField(event.Fields, "user_id")
+		if userID == "" {
+			return nil, fmt.Errorf("%s event of topic %s has no user_id", event.Type, event.Topic)
+		}
+		notif := domain.NewNotificationWithID(userID, event.Type, "")
+		notif.EventID = event.ID
+		notif.UserID = userID
+		if err := messages.Render(ctx, template, event, notif); err != nil {
+			return nil, err
+		}
+		return []*domain.Notification{notif}, nil
+	})
+}
@@ -0,0 +1,53 @@
+// Package httpapi serves the HTTP API of notification-service, next to the
+// WebSocket endpoints.
+package httpapi
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"go.uber.org/zap"
+)
+
+// maxBodySize bounds request bodies.
+const maxBodySize = 1 << 20
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// decodeBody reads a JSON body into v, rejecting unknown fields.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// internalError logs err with the request's logger and answers 500 without
+// the details.
+func internalError(w http.ResponseWriter, r *http.Request, logger *zap.Logger, msg string, err error) {
+	logging.FromContext(r.Context(), logger).Error(msg, zap.Error(err))
+	writeError(w, http.StatusInternalServerError, msg)
+}
+
+// isAdmin reports whether r carries adminToken as its bearer token. An empty
+// adminToken admits no one.
+func isAdmin(r *http.Request, adminToken string) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1
+}
+
+// unauthorized answers 401 for missing or invalid credentials.
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	writeError(w, http.StatusUnauthorized, "invalid credentials")
+}
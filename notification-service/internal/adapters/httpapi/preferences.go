@@ -0,0 +1,96 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/usecases"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/apikey"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"go.uber.org/zap"
+)
+
+// PreferencesHandler serves the preferences of a user to the user, who
+// authenticates with an API key they own in the X-API-Key header, and to
+// operators, who present the admin token as a bearer token.
+type PreferencesHandler struct {
+	useCase    usecases.PreferencesUseCase
+	adminToken string
+	// verifier checks the API keys of users; without it only the admin
+	// token is accepted.
+	verifier apikey.Verifier
+	logger   *zap.Logger
+}
+
+func NewPreferencesHandler(uc usecases.PreferencesUseCase, adminToken string, verifier apikey.Verifier, logger *zap.Logger) *PreferencesHandler {
+	return &PreferencesHandler{useCase: uc, adminToken: adminToken, verifier: verifier, logger: logger}
+}
+
+// Register adds the routes of the handler to mux.
+func (h *PreferencesHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /users/{userID}/preferences", h.authorize(h.GetPreferences))
+	mux.HandleFunc("PUT /users/{userID}/preferences", h.authorize(h.UpdatePreferences))
+}
+
+// authorize admits the admin token and the API keys of the user in the
+// path. Missing or invalid credentials get 401, the keys of other users
+// 403, and requests whose key cannot be checked 503.
+func (h *PreferencesHandler) authorize(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if isAdmin(r, h.adminToken) {
+			next(w, r)
+			return
+		}
+		key := r.Header.Get(apikey.Header)
+		if key == "" || h.verifier == nil {
+			unauthorized(w)
+			return
+		}
+
+		principal, err := h.verifier.Verify(r.Context(), key)
+		switch {
+		case errors.Is(err, apikey.ErrInvalidKey):
+			unauthorized(w)
+			return
+		case err != nil:
+			logging.FromContext(r.Context(), h.logger).Error("failed to verify api key", zap.Error(err))
+			writeError(w, http.StatusServiceUnavailable, "API keys cannot be verified, try again later")
+			return
+		}
+		if principal.OwnerID != r.PathValue("userID") {
+			writeError(w, http.StatusForbidden, "the preferences of other users are forbidden")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (h *PreferencesHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	prefs, err := h.useCase.GetPreferences(r.Context(), r.PathValue("userID"))
+	if err != nil {
+		internalError(w, r, h.logger, "failed to get preferences", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, prefs)
+}
+
+func (h *PreferencesHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	var prefs domain.Preferences
+	if err := decodeBody(w, r, &prefs); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+	prefs.UserID = r.PathValue("userID")
+
+	if err := h.useCase.UpdatePreferences(r.Context(), &prefs); err != nil {
+		if errors.Is(err, domain.ErrInvalidLocale) || errors.Is(err, domain.ErrInvalidPreferences) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		internalError(w, r, h.logger, "failed to update preferences", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, prefs)
+}
@@ -0,0 +1,162 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"notification-service/internal/adapters/repository"
+	"notification-service/internal/usecases"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/apikey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// userKey is the API key of user-1; brokenKey cannot be verified.
+const (
+	userKey   = "gmk_user-1"
+	brokenKey = "gmk_broken"
+)
+
+var userKeys = apikey.VerifierFunc(func(ctx context.Context, key string) (apikey.Principal, error) {
+	switch key {
+	case userKey:
+		return apikey.Principal{KeyID: "k1", OwnerID: "user-1"}, nil
+	case brokenKey:
+		return apikey.Principal{}, errors.New("user-service unavailable")
+	default:
+		return apikey.Principal{}, apikey.ErrInvalidKey
+	}
+})
+
+// newPreferencesServer serves the preferences API with adminToken and the
+// keys of userKeys.
+func newPreferencesServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	uc := usecases.NewPreferencesUseCase(repository.NewInMemoryPreferenceRepo(), zap.NewNop())
+	mux := http.NewServeMux()
+	NewPreferencesHandler(uc, adminToken, userKeys, zap.NewNop()).Register(mux)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// do sends a request as user-1.
+func do(t *testing.T, method, url, reqBody string) (int, string) {
+	t.Helper()
+	return send(t, apikey.Header, userKey, method, url, reqBody)
+}
+
+// doWithToken sends a request with token as the bearer token, when it is
+// not empty.
+func doWithToken(t *testing.T, token, method, url, reqBody string) (int, string) {
+	t.Helper()
+	if token == "" {
+		return send(t, "", "", method, url, reqBody)
+	}
+	return send(t, "Authorization", "Bearer "+token, method, url, reqBody)
+}
+
+// send sends a request with the header, when it is not empty.
+func send(t *testing.T, header, value, method, url, reqBody string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(reqBody))
+	require.NoError(t, err)
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestPreferences(t *testing.T) {
+	srv := newPreferencesServer(t)
+	url := srv.URL + "/users/user-1/preferences"
+
+	status, body := do(t, http.MethodGet, url, "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"user_id": "user-1"}`, body, "defaults for a user without preferences")
+
+	status, body = do(t, http.MethodPut, url, `{"locale": "TH_th"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"user_id": "user-1", "locale": "th-th"}`, body)
+
+	status, body = do(t, http.MethodGet, url, "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"user_id": "user-1", "locale": "th-th"}`, body)
+
+	status, body = do(t, http.MethodPut, url, `{"email": "Ada <ada@example.com>", "channels": ["email"]}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"user_id": "user-1", "email": "ada@example.com", "channels": ["email"]}`, body)
+}
+
+func TestPreferences_BadRequests(t *testing.T) {
+	srv := newPreferencesServer(t)
+	url := srv.URL + "/users/user-1/preferences"
+
+	for name, body := range map[string]string{
+		"invalid JSON":    `{"locale":`,
+		"unknown field":   `{"language": "th"}`,
+		"invalid locale":  `{"locale": "not a locale!"}`,
+		"invalid email":   `{"email": "ada at example"}`,
+		"unknown channel": `{"channels": ["sms"]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			status, _ := do(t, http.MethodPut, url, body)
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+	}
+}
+
+func TestPreferences_Auth(t *testing.T) {
+	srv := newPreferencesServer(t)
+	own, other := srv.URL+"/users/user-1/preferences", srv.URL+"/users/user-2/preferences"
+
+	tests := []struct {
+		name          string
+		header, value string
+		url           string
+		want          int
+	}{
+		{"admin token, own preferences", "Authorization", "Bearer " + adminToken, own, http.StatusOK},
+		{"admin token, other user", "Authorization", "Bearer " + adminToken, other, http.StatusOK},
+		{"own key", apikey.Header, userKey, own, http.StatusOK},
+		{"key of another user", apikey.Header, userKey, other, http.StatusForbidden},
+		{"no credentials", "", "", own, http.StatusUnauthorized},
+		{"wrong admin token", "Authorization", "Bearer not-the-admin-token", own, http.StatusUnauthorized},
+		{"invalid key", apikey.Header, "gmk_unknown", own, http.StatusUnauthorized},
+		{"key that cannot be verified", apikey.Header, brokenKey, own, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _ := send(t, tt.header, tt.value, http.MethodGet, tt.url, "")
+			assert.Equal(t, tt.want, status, "GET")
+			status, _ = send(t, tt.header, tt.value, http.MethodPut, tt.url, `{"locale": "th"}`)
+			assert.Equal(t, tt.want, status, "PUT")
+		})
+	}
+}
+
+func TestPreferences_AdminTokenOnlyWithoutVerifier(t *testing.T) {
+	uc := usecases.NewPreferencesUseCase(repository.NewInMemoryPreferenceRepo(), zap.NewNop())
+	mux := http.NewServeMux()
+	NewPreferencesHandler(uc, adminToken, nil, zap.NewNop()).Register(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	url := srv.URL + "/users/user-1/preferences"
+
+	status, _ := do(t, http.MethodGet, url, "")
+	assert.Equal(t, http.StatusUnauthorized, status, "keys are not accepted")
+	status, _ = doWithToken(t, adminToken, http.MethodGet, url, "")
+	assert.Equal(t, http.StatusOK, status)
+}
@@ -0,0 +1,153 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/usecases"
+
+	"go.uber.org/zap"
+)
+
+// WebhooksHandler serves the webhooks API to operators, who authenticate
+// with the admin token as a bearer token. Without a token the API answers
+// every request with 401.
+type WebhooksHandler struct {
+	useCase    usecases.WebhookUseCase
+	adminToken string
+	logger     *zap.Logger
+}
+
+func NewWebhooksHandler(uc usecases.WebhookUseCase, adminToken string, logger *zap.Logger) *WebhooksHandler {
+	return &WebhooksHandler{useCase: uc, adminToken: adminToken, logger: logger}
+}
+
+// Register adds the routes of the handler to mux.
+func (h *WebhooksHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /webhooks", h.authenticate(h.CreateSubscription))
+	mux.HandleFunc("GET /webhooks", h.authenticate(h.ListSubscriptions))
+	mux.HandleFunc("GET /webhooks/{id}", h.authenticate(h.GetSubscription))
+	mux.HandleFunc("PUT /webhooks/{id}", h.authenticate(h.UpdateSubscription))
+	mux.HandleFunc("DELETE /webhooks/{id}", h.authenticate(h.DeleteSubscription))
+	mux.HandleFunc("GET /webhooks/{id}/deliveries", h.authenticate(h.ListDeliveries))
+	mux.HandleFunc("POST /webhooks/{id}/deliveries/{deliveryID}/redeliver", h.authenticate(h.Redeliver))
+}
+
+// authenticate rejects requests without the admin token with 401.
+func (h *WebhooksHandler) authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !isAdmin(r, h.adminToken) {
+			unauthorized(w)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// subscriptionRequest is the body of creating and updating a subscription.
+// Without a secret, creating generates one and updating keeps the current
+// one.
+type subscriptionRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"`
+	Disabled   bool     `json:"disabled"`
+}
+
+func (req *subscriptionRequest) subscription(id string) *domain.Subscription {
+	return &domain.Subscription{ID: id, URL: req.URL, EventTypes: req.EventTypes, Secret: req.Secret, Disabled: req.Disabled}
+}
+
+// CreateSubscription answers with the subscription and its secret, which
+// is not shown again.
+func (h *WebhooksHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var req subscriptionRequest
+	if err := decodeBody(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+	sub := req.subscription("")
+	if err := h.useCase.CreateSubscription(r.Context(), sub); err != nil {
+		h.fail(w, r, "failed to create webhook subscription", err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, sub)
+}
+
+func (h *WebhooksHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.useCase.ListSubscriptions(r.Context())
+	if err != nil {
+		h.fail(w, r, "failed to list webhook subscriptions", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, subs)
+}
+
+func (h *WebhooksHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	sub, err := h.useCase.GetSubscription(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.fail(w, r, "failed to get webhook subscription", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sub)
+}
+
+// UpdateSubscription replaces the URL, event types and state of a
+// subscription; sending "disabled": false enables a disabled one again.
+func (h *WebhooksHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	var req subscriptionRequest
+	if err := decodeBody(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+	sub, err := h.useCase.UpdateSubscription(r.Context(), req.subscription(r.PathValue("id")))
+	if err != nil {
+		h.fail(w, r, "failed to update webhook subscription", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sub)
+}
+
+func (h *WebhooksHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	if err := h.useCase.DeleteSubscription(r.Context(), r.PathValue("id")); err != nil {
+		h.fail(w, r, "failed to delete webhook subscription", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries answers with the delivery log of a subscription, newest
+// first.
+func (h *WebhooksHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := h.useCase.ListDeliveries(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.fail(w, r, "failed to list webhook deliveries", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+// Redeliver queues a delivery again and answers with the new delivery.
+func (h *WebhooksHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.useCase.Redeliver(r.Context(), r.PathValue("id"), r.PathValue("deliveryID"))
+	if err != nil {
+		h.fail(w, r, "failed to redeliver webhook", err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, delivery)
+}
+
+// fail answers with the status of a use case error.
+func (h *WebhooksHandler) fail(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrSubscriptionNotFound), errors.Is(err, domain.ErrDeliveryNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidSubscription):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrSubscriptionDisabled):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		internalError(w, r, h.logger, msg, err)
+	}
+}
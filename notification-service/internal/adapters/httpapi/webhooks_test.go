@@ -0,0 +1,288 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"notification-service/internal/adapters/repository"
+	"notification-service/internal/adapters/webhook"
+	"notification-service/internal/domain"
+	"notification-service/internal/usecases"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lazy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	webhookSecret = "0123456789abcdef0123456789abcdef"
+	adminToken    = "admin-token-0123456789abcdef012345"
+)
+
+type webhooksFixture struct {
+	srv        *httptest.Server
+	dispatcher *webhook.Dispatcher
+}
+
+// newWebhooksFixture serves the webhooks API with adminToken. The test
+// receivers listen on loopback, so private networks are allowed unless
+// the test is about them.
+func newWebhooksFixture(t *testing.T, allowPrivateNetworks bool) *webhooksFixture {
+	t.Helper()
+	subscriptions, deliveries := repository.NewInMemorySubscriptionRepo(), repository.NewInMemoryDeliveryRepo()
+	dispatcher := webhook.NewDispatcher(webhook.Config{
+		Timeout:              time.Second,
+		MaxAttempts:          1,
+		Backoff:              lazy.Backoff{Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 2},
+		DisableAfter:         1,
+		AllowPrivateNetworks: allowPrivateNetworks,
+	}, subscriptions, deliveries, zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- dispatcher.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	uc := usecases.NewWebhookUseCase(subscriptions, deliveries, dispatcher, dispatcher, zap.NewNop())
+	mux := http.NewServeMux()
+	NewWebhooksHandler(uc, adminToken, zap.NewNop()).Register(mux)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return &webhooksFixture{srv: srv, dispatcher: dispatcher}
+}
+
+// do sends a request with the admin token.
+func (f *webhooksFixture) do(t *testing.T, method, url, reqBody string) (int, string) {
+	t.Helper()
+	return doWithToken(t, adminToken, method, url, reqBody)
+}
+
+func (f *webhooksFixture) create(t *testing.T, body string) *domain.Subscription {
+	t.Helper()
+	status, resp := f.do(t, http.MethodPost, f.srv.URL+"/webhooks", body)
+	require.Equal(t, http.StatusCreated, status, resp)
+	var sub domain.Subscription
+	require.NoError(t, json.Unmarshal([]byte(resp), &sub))
+	return &sub
+}
+
+// deliveries waits until the log of subscriptionID has want deliveries,
+// none pending, and returns them, newest first.
+func (f *webhooksFixture) deliveries(t *testing.T, subscriptionID string, want int) []*domain.Delivery {
+	t.Helper()
+	var deliveries []*domain.Delivery
+	require.Eventually(t, func() bool {
+		status, body := f.do(t, http.MethodGet, f.srv.URL+"/webhooks/"+subscriptionID+"/deliveries", "")
+		require.Equal(t, http.StatusOK, status, body)
+		deliveries = nil
+		require.NoError(t, json.Unmarshal([]byte(body), &deliveries))
+		if len(deliveries) != want {
+			return false
+		}
+		for _, delivery := range deliveries {
+			if delivery.Status == domain.DeliveryPending {
+				return false
+			}
+		}
+		return true
+	}, 2*time.Second, 5*time.Millisecond)
+	return deliveries
+}
+
+// webhookReceiver answers with the next of its statuses, then 200, and
+// verifies the signature of every request.
+type webhookReceiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	received int
+	verified int
+}
+
+func newWebhookReceiver(t *testing.T, statuses ...int) *webhookReceiver {
+	r := &webhookReceiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.received++
+		if req.Header.Get(webhook.HeaderSignature) == webhook.Sign(webhookSecret, req.Header.Get(webhook.HeaderTimestamp), body) {
+			r.verified++
+		}
+		status := http.StatusOK
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *webhookReceiver) counts() (received, verified int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.received, r.verified
+}
+
+func TestWebhooks_Subscriptions(t *testing.T) {
+	f := newWebhooksFixture(t, true)
+
+	sub := f.create(t, `{"url": "https://partner.example.com/hooks", "event_types": ["SHIPPED"]}`)
+	assert.NotEmpty(t, sub.ID)
+	assert.Len(t, sub.Secret, 64, "a secret is generated and shown once")
+	assert.Equal(t, []string{"SHIPPED"}, sub.EventTypes)
+	url := f.srv.URL + "/webhooks/" + sub.ID
+
+	status, body := f.do(t, http.MethodGet, url, "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.NotContains(t, body, sub.Secret)
+	assert.NotContains(t, body, `"secret"`)
+
+	status, body = f.do(t, http.MethodGet, f.srv.URL+"/webhooks", "")
+	assert.Equal(t, http.StatusOK, status)
+	var subs []*domain.Subscription
+	require.NoError(t, json.Unmarshal([]byte(body), &subs))
+	require.Len(t, subs, 1)
+	assert.Equal(t, sub.ID, subs[0].ID)
+	assert.Empty(t, subs[0].Secret)
+
+	status, body = f.do(t, http.MethodPut, url, `{"url": "https://partner.example.com/v2/hooks", "disabled": true}`)
+	assert.Equal(t, http.StatusOK, status)
+	var updated domain.Subscription
+	require.NoError(t, json.Unmarshal([]byte(body), &updated))
+	assert.Equal(t, "https://partner.example.com/v2/hooks", updated.URL)
+	assert.Empty(t, updated.EventTypes)
+	assert.True(t, updated.Disabled)
+
+	status, _ = f.do(t, http.MethodDelete, url, "")
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = f.do(t, http.MethodGet, url, "")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = f.do(t, http.MethodDelete, url, "")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestWebhooks_BadRequests(t *testing.T) {
+	f := newWebhooksFixture(t, true)
+
+	for name, body := range map[string]string{
+		"invalid JSON":     `{"url":`,
+		"unknown field":    `{"url": "https://partner.example.com", "events": ["SHIPPED"]}`,
+		"missing URL":      `{}`,
+		"relative URL":     `{"url": "/hooks"}`,
+		"unsupported URL":  `{"url": "ftp://partner.example.com"}`,
+		"empty event type": `{"url": "https://partner.example.com", "event_types": [""]}`,
+		"short secret":     `{"url": "https://partner.example.com", "secret": "short"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			status, _ := f.do(t, http.MethodPost, f.srv.URL+"/webhooks", body)
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+	}
+
+	status, _ := f.do(t, http.MethodPut, f.srv.URL+"/webhooks/unknown", `{"url": "https://partner.example.com"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = f.do(t, http.MethodGet, f.srv.URL+"/webhooks/unknown/deliveries", "")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestWebhooks_Redeliver(t *testing.T) {
+	recv := newWebhookReceiver(t, http.StatusInternalServerError)
+	f := newWebhooksFixture(t, true)
+	sub := f.create(t, `{"url": "`+recv.URL+`", "secret": "`+webhookSecret+`"}`)
+
+	require.NoError(t, f.dispatcher.PublishNotification(domain.NewNotificationWithID("n-1", "SHIPPED", "Order shipped")))
+	deliveries := f.deliveries(t, sub.ID, 1)
+	failed := deliveries[0]
+	assert.Equal(t, domain.DeliveryFailed, failed.Status)
+	assert.Equal(t, "n-1", failed.NotificationID)
+	assert.Equal(t, http.StatusInternalServerError, failed.ResponseStatus)
+
+	redeliver := f.srv.URL + "/webhooks/" + sub.ID + "/deliveries/" + failed.ID + "/redeliver"
+	status, body := f.do(t, http.MethodPost, redeliver, "")
+	assert.Equal(t, http.StatusConflict, status, "the failure disabled the subscription: %s", body)
+
+	status, _ = f.do(t, http.MethodPut, f.srv.URL+"/webhooks/"+sub.ID, `{"url": "`+recv.URL+`"}`)
+	require.Equal(t, http.StatusOK, status)
+	status, body = f.do(t, http.MethodPost, redeliver, "")
+	require.Equal(t, http.StatusAccepted, status, body)
+	var queued domain.Delivery
+	require.NoError(t, json.Unmarshal([]byte(body), &queued))
+	assert.Equal(t, failed.ID, queued.RedeliveryOf)
+
+	deliveries = f.deliveries(t, sub.ID, 2)
+	assert.Equal(t, queued.ID, deliveries[0].ID, "newest first")
+	assert.Equal(t, domain.DeliverySucceeded, deliveries[0].Status)
+	assert.JSONEq(t, string(failed.Payload), string(deliveries[0].Payload))
+
+	received, verified := recv.counts()
+	assert.Equal(t, 2, received)
+	assert.Equal(t, 2, verified, "the secret kept on update signs the redelivery")
+
+	status, _ = f.do(t, http.MethodPost, f.srv.URL+"/webhooks/"+sub.ID+"/deliveries/unknown/redeliver", "")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestWebhooks_Authentication(t *testing.T) {
+	f := newWebhooksFixture(t, true)
+	sub := f.create(t, `{"url": "https://partner.example.com/hooks"}`)
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodPost, "/webhooks"},
+		{http.MethodGet, "/webhooks"},
+		{http.MethodGet, "/webhooks/" + sub.ID},
+		{http.MethodPut, "/webhooks/" + sub.ID},
+		{http.MethodDelete, "/webhooks/" + sub.ID},
+		{http.MethodGet, "/webhooks/" + sub.ID + "/deliveries"},
+		{http.MethodPost, "/webhooks/" + sub.ID + "/deliveries/d-1/redeliver"},
+	} {
+		for _, token := range []string{"", "wrong-token-0123456789abcdef01234"} {
+			status, _ := doWithToken(t, token, route.method, f.srv.URL+route.path, `{"url": "https://attacker.example.com"}`)
+			assert.Equal(t, http.StatusUnauthorized, status, "%s %s with token %q", route.method, route.path, token)
+		}
+	}
+	status, body := f.do(t, http.MethodGet, f.srv.URL+"/webhooks/"+sub.ID, "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "https://partner.example.com/hooks", "the subscription is unchanged")
+
+	mux := http.NewServeMux()
+	NewWebhooksHandler(nil, "", zap.NewNop()).Register(mux)
+	closed := httptest.NewServer(mux)
+	defer closed.Close()
+	status, _ = doWithToken(t, "", http.MethodGet, closed.URL+"/webhooks", "")
+	assert.Equal(t, http.StatusUnauthorized, status, "without an admin token the API is closed")
+}
+
+func TestWebhooks_PrivateAddresses(t *testing.T) {
+	f := newWebhooksFixture(t, false)
+
+	for _, url := range []string{
+		"http://127.0.0.1:8080/hooks",
+		"http://10.1.2.3/hooks",
+		"http://192.168.0.10/hooks",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hooks",
+		"http://[fd00::1]/hooks",
+		"http://0.0.0.0/hooks",
+	} {
+		status, body := f.do(t, http.MethodPost, f.srv.URL+"/webhooks", `{"url": "`+url+`"}`)
+		assert.Equal(t, http.StatusBadRequest, status, url)
+		assert.Contains(t, body, "not a public address", url)
+	}
+
+	sub := f.create(t, `{"url": "https://93.184.215.14/hooks"}`)
+	status, _ := f.do(t, http.MethodPut, f.srv.URL+"/webhooks/"+sub.ID, `{"url": "http://10.0.0.1/hooks"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
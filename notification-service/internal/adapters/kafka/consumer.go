@@ -2,36 +2,58 @@ package kafka
 
 import (
 	"context"
-	"encoding/binary"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"os/signal"
 	"sync"
-	"syscall"
 
+	"notification-service/internal/adapters/handlers"
 	mappers "notification-service/internal/adapters/mapper"
+	"notification-service/internal/domain"
 	"notification-service/internal/usecases"
 
 	"github.com/IBM/sarama"
+	"github.com/jakkapat-chongsuwat/go-microservice/events/orderevents"
+	"github.com/jakkapat-chongsuwat/go-microservice/events/serde"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging/kafkalog"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics/kafkametrics"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry/kafkatrace"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/events"
 	"github.com/linkedin/goavro/v2"
 	"github.com/riferrei/srclient"
 	"go.uber.org/zap"
 )
 
 type KafkaConsumerGroup struct {
-	group    sarama.ConsumerGroup
-	topic    string
-	useCase  usecases.NotificationUseCase
-	logger   *zap.Logger
-	srClient *srclient.SchemaRegistryClient
+	group          sarama.ConsumerGroup
+	groupID        string
+	topics         []string
+	orderTopic     string
+	handlers       *handlers.Registry
+	useCase        usecases.NotificationUseCase
+	logger         *zap.Logger
+	deserializer   *serde.Deserializer
+	exactlyOnce    *ExactlyOnce
+	newTxnProducer func(transactionalID string) (sarama.SyncProducer, error)
 }
 
-func NewKafkaConsumerGroup(brokers []string, groupID, topic, schemaRegistryURL string, useCase usecases.NotificationUseCase, logger *zap.Logger) (*KafkaConsumerGroup, error) {
+// NewKafkaConsumerGroup consumes topics and hands each event to its handler
+// in registry. Events of orderTopic are decoded as order events, those of
+// the other topics as generic Avro records.
+//
+// Messages are consumed at least once, marked after they are handled,
+// unless exactlyOnce is set.
+func NewKafkaConsumerGroup(brokers []string, groupID string, topics []string, orderTopic, schemaRegistryURL string, registry *handlers.Registry, exactlyOnce *ExactlyOnce, useCase usecases.NotificationUseCase, logger *zap.Logger) (*KafkaConsumerGroup, error) {
 	config := sarama.NewConfig()
 	config.Version = sarama.V4_0_0_0
 	config.Consumer.Group.Rebalance.Strategy = sarama.NewBalanceStrategyRoundRobin()
 	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	if exactlyOnce != nil {
+		// Offsets are committed by the transactions only, and records of
+		// aborted transactions are skipped.
+		config.Consumer.IsolationLevel = sarama.ReadCommitted
+		config.Consumer.Offsets.AutoCommit.Enable = false
+	}
 
 	group, err := sarama.NewConsumerGroup(brokers, groupID, config)
 	if err != nil {
@@ -41,23 +63,36 @@ func NewKafkaConsumerGroup(brokers []string, groupID, topic, schemaRegistryURL s
 	srClient := srclient.CreateSchemaRegistryClient(schemaRegistryURL)
 
 	return &KafkaConsumerGroup{
-		group:    group,
-		topic:    topic,
-		useCase:  useCase,
-		logger:   logger,
-		srClient: srClient,
+		group:        group,
+		groupID:      groupID,
+		topics:       topics,
+		orderTopic:   orderTopic,
+		handlers:     registry,
+		useCase:      useCase,
+		logger:       logger,
+		deserializer: serde.NewDeserializer(srClient),
+		exactlyOnce:  exactlyOnce,
+		newTxnProducer: func(transactionalID string) (sarama.SyncProducer, error) {
+			return sarama.NewSyncProducer(brokers, newTransactionalProducerConfig(transactionalID))
+		},
 	}, nil
 }
 
+// Start consumes until ctx is cancelled and then closes the consumer group.
 func (kc *KafkaConsumerGroup) Start(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	consumer := consumerGroupHandler{
-		useCase:  kc.useCase,
-		logger:   kc.logger,
-		srClient: kc.srClient,
-		topic:    kc.topic,
+		useCase:        kc.useCase,
+		logger:         kc.logger,
+		payloadLogger:  logging.Sampled(kc.logger, payloadLogFirst, payloadLogThereafter),
+		deserializer:   kc.deserializer,
+		orderTopic:     kc.orderTopic,
+		handlers:       kc.handlers,
+		groupID:        kc.groupID,
+		exactlyOnce:    kc.exactlyOnce,
+		newTxnProducer: kc.newTxnProducer,
 	}
 
 	wg := &sync.WaitGroup{}
@@ -69,7 +104,13 @@ func (kc *KafkaConsumerGroup) Start(ctx context.Context) error {
 			if ctx.Err() != nil {
 				return
 			}
-			if err := kc.group.Consume(ctx, []string{kc.topic}, &consumer); err != nil {
+			// Each session gets its own context so the handler can end it
+			// and rejoin the group from the committed offsets.
+			sessionCtx, endSession := context.WithCancel(ctx)
+			consumer.endSession = endSession
+			err := kc.group.Consume(sessionCtx, kc.topics, &consumer)
+			endSession()
+			if err != nil {
 				if ctx.Err() != nil {
 					return
 				}
@@ -78,15 +119,8 @@ func (kc *KafkaConsumerGroup) Start(ctx context.Context) error {
 		}
 	}()
 
-	sigterm := make(chan os.Signal, 1)
-	signal.Notify(sigterm, syscall.SIGINT, syscall.SIGTERM)
-	select {
-	case <-ctx.Done():
-		kc.logger.Info("context cancelled")
-	case <-sigterm:
-		kc.logger.Info("termination signal received")
-		cancel()
-	}
+	<-ctx.Done()
+	kc.logger.Info("context cancelled")
 
 	if err := kc.group.Close(); err != nil {
 		kc.logger.Error("error closing consumer group", zap.Error(err))
@@ -95,11 +129,28 @@ func (kc *KafkaConsumerGroup) Start(ctx context.Context) error {
 	return nil
 }
 
+// Decoded payloads are logged at debug level, redacted, for the first
+// payloadLogFirst messages each second and every payloadLogThereafter-th
+// message after that.
+const (
+	payloadLogFirst      = 5
+	payloadLogThereafter = 100
+)
+
 type consumerGroupHandler struct {
-	useCase  usecases.NotificationUseCase
-	logger   *zap.Logger
-	srClient *srclient.SchemaRegistryClient
-	topic    string
+	useCase       usecases.NotificationUseCase
+	logger        *zap.Logger
+	payloadLogger *zap.Logger
+	deserializer  *serde.Deserializer
+	orderTopic    string
+	handlers      handlers.Handler
+	groupID       string
+
+	// exactlyOnce and newTxnProducer are set in the exactly-once mode.
+	exactlyOnce    *ExactlyOnce
+	newTxnProducer func(transactionalID string) (sarama.SyncProducer, error)
+	// endSession ends the current session.
+	endSession context.CancelFunc
 }
 
 func (h *consumerGroupHandler) Setup(_ sarama.ConsumerGroupSession) error {
@@ -113,71 +164,166 @@ func (h *consumerGroupHandler) Cleanup(_ sarama.ConsumerGroupSession) error {
 }
 
 func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	if h.exactlyOnce != nil {
+		return h.consumeClaimTransactionally(session, claim)
+	}
 	for msg := range claim.Messages() {
-		h.logger.Info("message received",
-			zap.String("key", string(msg.Key)),
-			zap.String("raw_value", string(msg.Value)),
-			zap.Int32("partition", msg.Partition),
-			zap.Int64("offset", msg.Offset))
+		err := h.handleMessage(session, msg, nil)
+		kafkametrics.ObserveConsume(msg, h.groupID, claim.HighWaterMarkOffset(), err)
+		session.MarkMessage(msg, "")
+	}
+	return nil
+}
 
-		notifMap, err := decodeAvroMessage(h.srClient, msg.Value)
-		if err != nil {
-			h.logger.Error("failed to decode avro message", zap.Error(err))
-			session.MarkMessage(msg, "")
-			continue
-		}
+// handleMessage processes one message inside a consumer span that continues
+// the producer's trace from the message headers, with a logger carrying the
+// producer's request ID. The returned error is only reported; the message is
+// committed either way.
+//
+// forward, if set, is called with each notification of the event before
+// any is processed; when it fails none is processed.
+func (h *consumerGroupHandler) handleMessage(session sarama.ConsumerGroupSession, msg *sarama.ConsumerMessage, forward func(context.Context, *domain.Notification) error) error {
+	ctx, span := kafkatrace.StartConsumerSpan(session.Context(), msg, h.groupID)
+	defer span.End()
+	ctx = kafkalog.NewContext(ctx, h.logger, msg)
+	logger := logging.FromContext(ctx, h.logger)
 
-		notif, err := mappers.MapRawToNotification(notifMap)
-		if err != nil {
-			h.logger.Error("failed to map raw event to notification", zap.Error(err))
-			session.MarkMessage(msg, "")
-			continue
+	logger.Debug("message received",
+		zap.String("key", string(msg.Key)),
+		zap.Int("size", len(msg.Value)))
+
+	event, err := decodeEvent(h.deserializer, msg, h.orderTopic)
+	if err != nil {
+		logger.Error("failed to decode message", zap.Error(err))
+		kafkatrace.RecordError(span, err)
+		return err
+	}
+	h.payloadLogger.Debug("message payload",
+		zap.String(logging.FieldRequestID, logging.RequestID(ctx)),
+		logging.Payload("payload", event.Fields))
+
+	notifs, err := h.handlers.Handle(ctx, event)
+	if err != nil {
+		logger.Error("failed to handle event",
+			zap.String("eventType", event.Type),
+			zap.Error(err))
+		kafkatrace.RecordError(span, err)
+		return err
+	}
+
+	if forward != nil {
+		for _, notif := range notifs {
+			if err := forward(ctx, notif); err != nil {
+				logger.Error("failed to forward notification", zap.Error(err))
+				kafkatrace.RecordError(span, err)
+				return err
+			}
 		}
+	}
 
-		if err := h.useCase.ProcessNotification(session.Context(), notif); err != nil {
-			h.logger.Error("failed to process notification", zap.Error(err))
+	var errs []error
+	for _, notif := range notifs {
+		if err := h.useCase.ProcessNotification(ctx, notif); err != nil {
+			logger.Error("failed to process notification", zap.String("id", notif.ID), zap.Error(err))
+			errs = append(errs, err)
 		}
-		session.MarkMessage(msg, "")
+	}
+	if err := errors.Join(errs...); err != nil {
+		kafkatrace.RecordError(span, err)
+		return err
 	}
 	return nil
 }
 
-// decodeAvroMessage decodes a message in Confluent's wire format:
-// [magic byte (0)] + [4-byte schema ID] + [Avro payload].
-func decodeAvroMessage(srClient *srclient.SchemaRegistryClient, data []byte) (map[string]interface{}, error) {
-	if len(data) < 5 {
-		return nil, fmt.Errorf("data too short")
-	}
-	if data[0] != 0 {
-		return nil, fmt.Errorf("unknown magic byte: %v", data[0])
+// decodeEvent decodes msg for the handlers: as an order event on
+// orderTopic, and as an Avro record with event_type and event_id fields on
+// the other topics. The lang header gives the locale of the event.
+func decodeEvent(d *serde.Deserializer, msg *sarama.ConsumerMessage, orderTopic string) (*handlers.Event, error) {
+	event := &handlers.Event{Topic: msg.Topic, Key: string(msg.Key), Lang: header(msg, langHeader)}
+	if msg.Topic == orderTopic {
+		order, err := decodeOrderEvent(d, msg.Value)
+		if err != nil {
+			return nil, err
+		}
+		event.Type, event.ID = order.EventType, order.EventID
+		event.Fields = order.AvroNative()
+		event.Order = order
+		return event, nil
 	}
-	schemaID := int(binary.BigEndian.Uint32(data[1:5]))
-	schema, err := srClient.GetSchema(schemaID)
+
+	record, err := decodeRecord(d, msg.Value)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get schema for id %d: %w", schemaID, err)
+		return nil, err
 	}
+	event.Type, _ = record["event_type"].(string)
+	event.ID, _ = record["event_id"].(string)
+	event.Fields = record
+	return event, nil
+}
+
+// langHeader is the message header with the locale of the event.
+const langHeader = "lang"
+
+func header(msg *sarama.ConsumerMessage, key string) string {
+	for _, h := range msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
 
-	codec, err := goavro.NewCodec(schema.Schema())
+// record is an Avro record in the native form of goavro, for the events
+// without Go types.
+type record map[string]any
+
+func (r *record) DecodeAvro(writer *goavro.Codec, data []byte) error {
+	native, _, err := writer.NativeFromBinary(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create codec: %w", err)
+		return fmt.Errorf("decode record: %w", err)
+	}
+	fields, ok := native.(map[string]any)
+	if !ok {
+		return fmt.Errorf("decode record: schema is not a record but %T", native)
 	}
+	*r = fields
+	return nil
+}
 
-	native, _, err := codec.NativeFromBinary(data[5:])
+// decodeRecord decodes an Avro message of any schema.
+func decodeRecord(d *serde.Deserializer, data []byte) (record, error) {
+	msg, err := d.Parse(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode avro payload: %w", err)
+		return nil, err
+	}
+	var r record
+	if err := d.DecodeAvro(msg, &r); err != nil {
+		return nil, err
 	}
+	return r, nil
+}
 
-	result, ok := native.(map[string]interface{})
-	if !ok {
-		raw, err := codec.TextualFromNative(nil, native)
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert to textual: %w", err)
+// decodeOrderEvent decodes a message in Confluent's wire format, Avro or
+// protobuf as the type of its registered schema says. Avro payloads are
+// read with the schema they were written with, so events of every version
+// decode.
+func decodeOrderEvent(d *serde.Deserializer, data []byte) (*orderevents.OrderEvent, error) {
+	msg, err := d.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	switch msg.Format {
+	case serde.Protobuf:
+		var event events.OrderEvent
+		if err := serde.DecodeProtobuf(msg, &event); err != nil {
+			return nil, err
 		}
-		var m map[string]interface{}
-		if err := json.Unmarshal(raw, &m); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal textual json: %w", err)
+		return mappers.ProtoOrderEventToOrderEvent(&event), nil
+	default:
+		var event orderevents.OrderEvent
+		if err := d.DecodeAvro(msg, &event); err != nil {
+			return nil, err
 		}
-		return m, nil
+		return &event, nil
 	}
-	return result, nil
 }
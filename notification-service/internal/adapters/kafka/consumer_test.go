@@ -10,10 +10,11 @@ import (
 	"testing"
 	"time"
 
+	"notification-service/internal/adapters/handlers"
 	"notification-service/internal/domain"
 
 	"github.com/IBM/sarama"
-	"github.com/linkedin/goavro/v2"
+	"github.com/jakkapat-chongsuwat/go-microservice/events/orderevents"
 	"github.com/riferrei/srclient"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
@@ -109,35 +110,24 @@ func TestConsumerIntegration(t *testing.T) {
 
 	fakeUC := &fakeNotificationUseCase{}
 
-	consumerGroup, err := NewKafkaConsumerGroup(brokers, "test-group", "test-topic", schemaRegistryURL, fakeUC, logger)
+	consumerGroup, err := NewKafkaConsumerGroup(brokers, "test-group", []string{"test-topic"}, "test-topic", schemaRegistryURL,
+		defaultRegistry(t, handlers.Topics{Order: "test-topic"}), nil, fakeUC, logger)
 	require.NoError(t, err)
 
 	srClient := srclient.NewSchemaRegistryClient(schemaRegistryURL)
 	subject := "test-topic-value"
-	schemaStr := `
-    {
-      "type": "record",
-      "name": "Notification",
-      "namespace": "com.example.notification",
-      "fields": [
-        { "name": "id", "type": "string" },
-        { "name": "type", "type": "string" },
-        { "name": "message", "type": "string" }
-      ]
-    }`
-	registeredSchema, err := srClient.CreateSchema(subject, schemaStr, srclient.Avro)
+	registeredSchema, err := srClient.CreateSchema(subject, orderevents.OrderEventSchema, srclient.Avro)
 	require.NoError(t, err)
 	schemaID := registeredSchema.ID()
 
-	codec, err := goavro.NewCodec(schemaStr)
-	require.NoError(t, err)
-
-	native := map[string]interface{}{
-		"id":      "123",
-		"type":    "test",
-		"message": "hello world",
+	event := orderevents.OrderEvent{
+		OrderID:   "123",
+		EventType: "CREATED",
+		Message:   "hello world",
+		Timestamp: time.Now(),
+		Order:     &orderevents.Order{OrderID: "123", UserID: "user-1"},
 	}
-	binaryData, err := codec.BinaryFromNative(nil, native)
+	binaryData, err := event.MarshalAvro()
 	require.NoError(t, err)
 
 	var b bytes.Buffer
@@ -176,6 +166,7 @@ func TestConsumerIntegration(t *testing.T) {
 	require.Len(t, fakeUC.processed, 1)
 	processedNotif := fakeUC.processed[0]
 	require.Equal(t, "123", processedNotif.ID)
-	require.Equal(t, "test", processedNotif.Type)
-	require.Equal(t, "hello world", processedNotif.Message)
+	require.Equal(t, "CREATED", processedNotif.Type)
+	require.Equal(t, "Your order 123 has been placed.", processedNotif.Message)
+	require.Equal(t, "user-1", processedNotif.UserID)
 }
@@ -0,0 +1,165 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/jakkapat-chongsuwat/go-microservice/events/orderevents"
+	"github.com/jakkapat-chongsuwat/go-microservice/events/serde"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/events"
+	"github.com/linkedin/goavro/v2"
+	"github.com/riferrei/srclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const orderEventV1Schema = `{"type": "record", "name": "OrderEvent", "namespace": "com.example.order", "fields": [
+	{"name": "order_id", "type": "string"},
+	{"name": "event_type", "type": "string"},
+	{"name": "message", "type": "string", "default": ""},
+	{"name": "timestamp", "type": {"type": "long", "logicalType": "timestamp-millis"}}
+]}`
+
+// registered is a schema as the registry returns it. SchemaType is left
+// out for Avro schemas.
+type registered struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType,omitempty"`
+}
+
+// fakeRegistry serves schemas by ID like the schema registry does.
+func fakeRegistry(t *testing.T, schemas map[string]registered) *serde.Deserializer {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		schema, ok := schemas[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(schema)
+	}))
+	t.Cleanup(srv.Close)
+	return serde.NewDeserializer(srclient.CreateSchemaRegistryClient(srv.URL))
+}
+
+func confluentMessage(schemaID uint32, payload []byte) []byte {
+	msg := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(msg[1:], schemaID)
+	return append(msg, payload...)
+}
+
+func TestDecodeOrderEvent_SchemaVersions(t *testing.T) {
+	deserializer := fakeRegistry(t, map[string]registered{
+		"/schemas/ids/1": {Schema: orderEventV1Schema},
+		"/schemas/ids/2": {Schema: orderevents.OrderEventSchema},
+	})
+	at := time.UnixMilli(1_700_000_000_000).UTC()
+
+	v1, err := goavro.NewCodec(orderEventV1Schema)
+	require.NoError(t, err)
+	v1Payload, err := v1.BinaryFromNative(nil, map[string]interface{}{
+		"order_id": "order-1", "event_type": "CREATED", "message": "Order created", "timestamp": at,
+	})
+	require.NoError(t, err)
+
+	event, err := decodeOrderEvent(deserializer, confluentMessage(1, v1Payload))
+	require.NoError(t, err)
+	assert.Equal(t, "order-1", event.OrderID)
+	assert.Equal(t, int32(1), event.SchemaVersion)
+	assert.Nil(t, event.Order)
+
+	v2Event := orderevents.OrderEvent{
+		OrderID: "order-2", EventType: "CREATED", Timestamp: at, SchemaVersion: 2,
+		Order: &orderevents.Order{OrderID: "order-2", UserID: "user-1", Items: []orderevents.OrderItem{}, CreatedAt: at},
+	}
+	v2Payload, err := v2Event.MarshalAvro()
+	require.NoError(t, err)
+
+	event, err = decodeOrderEvent(deserializer, confluentMessage(2, v2Payload))
+	require.NoError(t, err)
+	assert.Equal(t, v2Event, *event)
+}
+
+func TestDecodeOrderEvent_Protobuf(t *testing.T) {
+	deserializer := fakeRegistry(t, map[string]registered{
+		"/schemas/ids/3": {Schema: events.OrderEventsProto, SchemaType: "PROTOBUF"},
+	})
+	at := time.UnixMilli(1_700_000_000_000).UTC()
+	payload, err := proto.Marshal(&events.OrderEvent{
+		OrderId: "order-3", EventType: "CREATED", Message: "Order created", Timestamp: timestamppb.New(at),
+		SchemaVersion: 2, Order: &events.Order{OrderId: "order-3", UserId: "user-1", CreatedAt: timestamppb.New(at)},
+	})
+	require.NoError(t, err)
+
+	// A single 0 after the schema ID is the index of the first message of
+	// the .proto file, OrderEvent.
+	event, err := decodeOrderEvent(deserializer, confluentMessage(3, append([]byte{0}, payload...)))
+	require.NoError(t, err)
+	assert.Equal(t, &orderevents.OrderEvent{
+		OrderID: "order-3", EventType: "CREATED", Message: "Order created", Timestamp: at, SchemaVersion: 2,
+		Order: &orderevents.Order{OrderID: "order-3", UserID: "user-1", Items: []orderevents.OrderItem{}, CreatedAt: at},
+	}, event)
+
+	// Indexes 2, 1: the second message, Order.
+	_, err = decodeOrderEvent(deserializer, confluentMessage(3, append([]byte{2, 2}, payload...)))
+	assert.ErrorContains(t, err, "message indexes do not match")
+}
+
+func TestDecodeOrderEvent_Errors(t *testing.T) {
+	deserializer := fakeRegistry(t, map[string]registered{})
+
+	_, err := decodeOrderEvent(deserializer, []byte{0, 0})
+	assert.ErrorContains(t, err, "too short")
+	_, err = decodeOrderEvent(deserializer, []byte{1, 0, 0, 0, 1})
+	assert.ErrorContains(t, err, "magic byte")
+	_, err = decodeOrderEvent(deserializer, confluentMessage(9, nil))
+	assert.ErrorContains(t, err, "schema for id 9")
+}
+
+const userEventSchema = `{"type": "record", "name": "UserEvent", "namespace": "com.example.user", "fields": [
+	{"name": "event_id", "type": "string"},
+	{"name": "event_type", "type": "string"},
+	{"name": "user_id", "type": "string"},
+	{"name": "name", "type": ["null", "string"], "default": null}
+]}`
+
+func TestDecodeEvent(t *testing.T) {
+	deserializer := fakeRegistry(t, map[string]registered{
+		"/schemas/ids/1": {Schema: orderEventV1Schema},
+		"/schemas/ids/4": {Schema: userEventSchema},
+	})
+
+	order, err := decodeEvent(deserializer, &sarama.ConsumerMessage{Topic: "order-events", Key: []byte("order-1"), Value: orderEventV1(t, "order-1")}, "order-events")
+	require.NoError(t, err)
+	assert.Equal(t, "CREATED", order.Type)
+	require.NotNil(t, order.Order)
+	assert.Equal(t, "order-1", order.Order.OrderID)
+	assert.Equal(t, "order-1", order.Fields["order_id"])
+	assert.Empty(t, order.Lang)
+
+	codec, err := goavro.NewCodec(userEventSchema)
+	require.NoError(t, err)
+	payload, err := codec.BinaryFromNative(nil, map[string]interface{}{
+		"event_id": "event-1", "event_type": "REGISTERED", "user_id": "user-1", "name": goavro.Union("string", "Ada"),
+	})
+	require.NoError(t, err)
+
+	user, err := decodeEvent(deserializer, &sarama.ConsumerMessage{Topic: "user-events", Key: []byte("user-1"), Value: confluentMessage(4, payload),
+		Headers: []*sarama.RecordHeader{{Key: []byte("lang"), Value: []byte("th")}}}, "order-events")
+	require.NoError(t, err)
+	assert.Equal(t, "user-events", user.Topic)
+	assert.Equal(t, "user-1", user.Key)
+	assert.Equal(t, "REGISTERED", user.Type)
+	assert.Equal(t, "event-1", user.ID)
+	assert.Equal(t, "th", user.Lang)
+	assert.Nil(t, user.Order)
+	assert.Equal(t, "user-1", user.Fields["user_id"])
+	assert.Equal(t, map[string]interface{}{"string": "Ada"}, user.Fields["name"])
+}
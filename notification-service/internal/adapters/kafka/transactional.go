@@ -0,0 +1,144 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"notification-service/internal/domain"
+
+	"github.com/IBM/sarama"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging/kafkalog"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics/kafkametrics"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry/kafkatrace"
+	"go.uber.org/zap"
+)
+
+// ExactlyOnce turns on the exactly-once mode of the consumer group: every
+// notification is written to OutputTopic, for the email, SMS and other
+// channels, in a transaction that also commits the offset of the event it
+// came from. Either both happen or neither does, so a rebalance cannot
+// duplicate the record. Only committed records are read.
+//
+// The WebSocket push is outside the transaction and stays at-least-once;
+// the use case skips events it has already pushed.
+type ExactlyOnce struct {
+	OutputTopic string
+	// TransactionalIDPrefix is followed by the topic and partition of each
+	// claim, so the producer of a partition's previous owner is fenced off.
+	TransactionalIDPrefix string
+}
+
+// transactionalID is stable per partition rather than per consumer: a new
+// owner of the partition reuses it, and the broker aborts what the old one
+// left open.
+func (e *ExactlyOnce) transactionalID(topic string, partition int32) string {
+	return fmt.Sprintf("%s-%s-%d", e.TransactionalIDPrefix, topic, partition)
+}
+
+// newTransactionalProducerConfig configures an idempotent producer with
+// transactions; sarama requires the rest of the settings for them.
+func newTransactionalProducerConfig(transactionalID string) *sarama.Config {
+	config := sarama.NewConfig()
+	config.Version = sarama.V4_0_0_0
+	config.Producer.Idempotent = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+	config.Producer.Transaction.ID = transactionalID
+	config.Net.MaxOpenRequests = 1
+	return config
+}
+
+// consumeClaimTransactionally handles each message of claim in its own
+// transaction. When a transaction fails it is aborted and the session
+// ended, so the claim is consumed again from the last committed offset.
+func (h *consumerGroupHandler) consumeClaimTransactionally(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	producer, err := h.newTxnProducer(h.exactlyOnce.transactionalID(claim.Topic(), claim.Partition()))
+	if err != nil {
+		h.endSession()
+		return fmt.Errorf("failed to create transactional producer: %w", err)
+	}
+	defer func() {
+		if err := producer.Close(); err != nil {
+			h.logger.Error("error closing transactional producer", zap.Error(err))
+		}
+	}()
+
+	for msg := range claim.Messages() {
+		if err := h.handleMessageInTxn(session, producer, claim, msg); err != nil {
+			h.logger.Error("transaction failed, consuming again from the last commit",
+				zap.String("topic", msg.Topic),
+				zap.Int32("partition", msg.Partition),
+				zap.Int64("offset", msg.Offset),
+				zap.Error(err))
+			h.endSession()
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *consumerGroupHandler) handleMessageInTxn(session sarama.ConsumerGroupSession, producer sarama.SyncProducer, claim sarama.ConsumerGroupClaim, msg *sarama.ConsumerMessage) error {
+	if err := producer.BeginTxn(); err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// Errors from decoding, mapping and the WebSocket push are only
+	// reported, as in the at-least-once mode; the offset is committed.
+	var txnErr error
+	err := h.handleMessage(session, msg, func(ctx context.Context, notif *domain.Notification) error {
+		txnErr = h.forward(ctx, producer, notif)
+		return txnErr
+	})
+	kafkametrics.ObserveConsume(msg, h.groupID, claim.HighWaterMarkOffset(), err)
+
+	if txnErr == nil {
+		txnErr = producer.AddMessageToTxn(msg, h.groupID, nil)
+	}
+	if txnErr == nil {
+		txnErr = producer.CommitTxn()
+	}
+	if txnErr != nil {
+		return abortTxn(producer, txnErr)
+	}
+	return nil
+}
+
+// abortTxn aborts the open transaction after err. A producer in a fatal
+// state cannot abort; the broker times the transaction out instead.
+func abortTxn(producer sarama.SyncProducer, err error) error {
+	if producer.TxnStatus()&sarama.ProducerTxnFlagFatalError != 0 {
+		return err
+	}
+	if abortErr := producer.AbortTxn(); abortErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to abort transaction: %w", abortErr))
+	}
+	return err
+}
+
+// forward writes notif as JSON to the output topic, keyed by its ID, with
+// the trace context and request ID of ctx in the headers.
+func (h *consumerGroupHandler) forward(ctx context.Context, producer sarama.SyncProducer, notif *domain.Notification) error {
+	value, err := json.Marshal(notif)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	msg := &sarama.ProducerMessage{
+		Topic: h.exactlyOnce.OutputTopic,
+		Key:   sarama.StringEncoder(notif.ID),
+		Value: sarama.ByteEncoder(value),
+	}
+	kafkalog.Inject(ctx, msg)
+
+	_, span := kafkatrace.StartProducerSpan(ctx, msg)
+	start := time.Now()
+	partition, offset, err := producer.SendMessage(msg)
+	kafkametrics.ObserveProduce(msg.Topic, start, err)
+	kafkatrace.EndProducerSpan(span, partition, offset, err)
+	if err != nil {
+		return fmt.Errorf("failed to forward notification: %w", err)
+	}
+	return nil
+}
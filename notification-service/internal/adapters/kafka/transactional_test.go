@@ -0,0 +1,193 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"notification-service/internal/adapters/handlers"
+	"notification-service/internal/adapters/repository"
+	"notification-service/internal/adapters/templates"
+	"notification-service/internal/domain"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// txnProducer records what the mock producer leaves out: the offsets added
+// to transactions and how each transaction ended.
+type txnProducer struct {
+	*mocks.SyncProducer
+	offsets []int64
+	ends    []string
+}
+
+func (p *txnProducer) AddMessageToTxn(msg *sarama.ConsumerMessage, groupID string, metadata *string) error {
+	p.offsets = append(p.offsets, msg.Offset)
+	return p.SyncProducer.AddMessageToTxn(msg, groupID, metadata)
+}
+
+func (p *txnProducer) CommitTxn() error {
+	p.ends = append(p.ends, "commit")
+	return p.SyncProducer.CommitTxn()
+}
+
+func (p *txnProducer) AbortTxn() error {
+	p.ends = append(p.ends, "abort")
+	return p.SyncProducer.AbortTxn()
+}
+
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	marked []int64
+}
+
+func (s *fakeSession) Context() context.Context { return context.Background() }
+
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	s.marked = append(s.marked, msg.Offset)
+}
+
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func newFakeClaim(values ...[]byte) *fakeClaim {
+	c := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, len(values))}
+	for i, value := range values {
+		c.messages <- &sarama.ConsumerMessage{Topic: "order-events", Partition: 2, Offset: int64(i), Value: value}
+	}
+	close(c.messages)
+	return c
+}
+
+func (c *fakeClaim) Topic() string                            { return "order-events" }
+func (c *fakeClaim) Partition() int32                         { return 2 }
+func (c *fakeClaim) HighWaterMarkOffset() int64               { return int64(cap(c.messages)) }
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+type recordingUseCase struct {
+	processed []*domain.Notification
+}
+
+func (u *recordingUseCase) ProcessNotification(_ context.Context, notif *domain.Notification) error {
+	u.processed = append(u.processed, notif)
+	return nil
+}
+
+// exactlyOnceHandler returns a handler in the exactly-once mode whose
+// producer is producer, the transactional ID it was created with, and
+// whether the handler ended the session.
+func exactlyOnceHandler(t *testing.T, producer *txnProducer, useCase *recordingUseCase) (*consumerGroupHandler, *string, *bool) {
+	t.Helper()
+	var transactionalID string
+	ended := false
+	logger := zap.NewNop()
+	return &consumerGroupHandler{
+		useCase:       useCase,
+		logger:        logger,
+		payloadLogger: logger,
+		deserializer:  fakeRegistry(t, map[string]registered{"/schemas/ids/1": {Schema: orderEventV1Schema}}),
+		orderTopic:    "order-events",
+		handlers:      defaultRegistry(t, handlers.Topics{Order: "order-events"}),
+		groupID:       "notifications",
+		exactlyOnce:   &ExactlyOnce{OutputTopic: "notifications-outbound", TransactionalIDPrefix: "notification-service"},
+		newTxnProducer: func(id string) (sarama.SyncProducer, error) {
+			transactionalID = id
+			return producer, nil
+		},
+		endSession: func() { ended = true },
+	}, &transactionalID, &ended
+}
+
+// defaultRegistry returns the default handlers of topics with the
+// compiled-in templates.
+func defaultRegistry(t *testing.T, topics handlers.Topics) *handlers.Registry {
+	t.Helper()
+	tmpl, err := templates.Load("", zap.NewNop())
+	require.NoError(t, err)
+	return handlers.NewDefaultRegistry(topics, handlers.NewMessages(tmpl, repository.NewInMemoryPreferenceRepo(), zap.NewNop()))
+}
+
+func newTxnProducer(t *testing.T) *txnProducer {
+	return &txnProducer{SyncProducer: mocks.NewSyncProducer(t, newTransactionalProducerConfig("test"))}
+}
+
+func orderEventV1(t *testing.T, orderID string) []byte {
+	t.Helper()
+	codec, err := goavro.NewCodec(orderEventV1Schema)
+	require.NoError(t, err)
+	payload, err := codec.BinaryFromNative(nil, map[string]interface{}{
+		"order_id": orderID, "event_type": "CREATED", "message": "Order created", "timestamp": time.UnixMilli(1_700_000_000_000),
+	})
+	require.NoError(t, err)
+	return confluentMessage(1, payload)
+}
+
+func TestConsumeClaim_ExactlyOnce(t *testing.T) {
+	producer := newTxnProducer(t)
+	var forwarded []domain.Notification
+	for range 2 {
+		producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			assert.Equal(t, "notifications-outbound", msg.Topic)
+			value, err := msg.Value.Encode()
+			require.NoError(t, err)
+			var notif domain.Notification
+			require.NoError(t, json.Unmarshal(value, &notif))
+			forwarded = append(forwarded, notif)
+			return nil
+		})
+	}
+	useCase := &recordingUseCase{}
+	h, transactionalID, ended := exactlyOnceHandler(t, producer, useCase)
+	session := &fakeSession{}
+
+	err := h.ConsumeClaim(session, newFakeClaim(orderEventV1(t, "order-1"), orderEventV1(t, "order-2")))
+
+	require.NoError(t, err)
+	assert.Equal(t, "notification-service-order-events-2", *transactionalID)
+	require.Len(t, forwarded, 2)
+	assert.Equal(t, "order-1", forwarded[0].ID)
+	assert.Equal(t, "order-2", forwarded[1].ID)
+	assert.Len(t, useCase.processed, 2)
+	assert.Equal(t, []int64{0, 1}, producer.offsets)
+	assert.Equal(t, []string{"commit", "commit"}, producer.ends)
+	assert.Empty(t, session.marked, "offsets are committed by the transactions only")
+	assert.False(t, *ended)
+}
+
+func TestConsumeClaim_ExactlyOnceCommitsUndecodableMessages(t *testing.T) {
+	producer := newTxnProducer(t)
+	useCase := &recordingUseCase{}
+	h, _, ended := exactlyOnceHandler(t, producer, useCase)
+
+	err := h.ConsumeClaim(&fakeSession{}, newFakeClaim([]byte("not an event")))
+
+	require.NoError(t, err)
+	assert.Empty(t, useCase.processed)
+	assert.Equal(t, []int64{0}, producer.offsets)
+	assert.Equal(t, []string{"commit"}, producer.ends)
+	assert.False(t, *ended)
+}
+
+func TestConsumeClaim_ExactlyOnceAbortsFailedForward(t *testing.T) {
+	producer := newTxnProducer(t)
+	producer.ExpectSendMessageAndFail(errors.New("not enough replicas"))
+	useCase := &recordingUseCase{}
+	h, _, ended := exactlyOnceHandler(t, producer, useCase)
+
+	err := h.ConsumeClaim(&fakeSession{}, newFakeClaim(orderEventV1(t, "order-1"), orderEventV1(t, "order-2")))
+
+	require.ErrorContains(t, err, "not enough replicas")
+	assert.Empty(t, useCase.processed, "nothing is pushed for an aborted transaction")
+	assert.Empty(t, producer.offsets)
+	assert.Equal(t, []string{"abort"}, producer.ends)
+	assert.True(t, *ended, "the session ends so the claim is consumed again from the last commit")
+}
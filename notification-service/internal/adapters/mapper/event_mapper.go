@@ -3,34 +3,17 @@ package mappers
 import (
 	"fmt"
 	"notification-service/internal/domain"
-)
-
-func MapRawToNotification(raw map[string]interface{}) (*domain.Notification, error) {
-	var id, typ, msg string
 
-	if v, ok := raw["id"]; ok && v != nil {
-		id = fmt.Sprintf("%v", v)
-	} else if v, ok := raw["order_id"]; ok && v != nil {
-		id = fmt.Sprintf("%v", v)
-	} else if v, ok := raw["user_id"]; ok && v != nil {
-		id = fmt.Sprintf("%v", v)
-	}
-
-	if v, ok := raw["type"]; ok && v != nil {
-		typ = fmt.Sprintf("%v", v)
-	} else if v, ok := raw["event_type"]; ok && v != nil {
-		typ = fmt.Sprintf("%v", v)
-	}
-
-	if v, ok := raw["message"]; ok && v != nil {
-		msg = fmt.Sprintf("%v", v)
-	}
+	"github.com/jakkapat-chongsuwat/go-microservice/events/orderevents"
+)
 
-	if id == "" || typ == "" {
-		return nil, fmt.Errorf("missing required fields: id=%q, type=%q", id, typ)
+// OrderEventToNotification notifies about an order event of any schema
+// version.
+func OrderEventToNotification(event *orderevents.OrderEvent) (*domain.Notification, error) {
+	if event.OrderID == "" || event.EventType == "" {
+		return nil, fmt.Errorf("missing required fields: order_id=%q, event_type=%q", event.OrderID, event.EventType)
 	}
-
-	// Create and return a well-formed Notification.
-	notif := domain.NewNotificationWithID(id, typ, msg)
+	notif := domain.NewNotificationWithID(event.OrderID, event.EventType, event.Message)
+	notif.EventID = event.EventID
 	return notif, nil
 }
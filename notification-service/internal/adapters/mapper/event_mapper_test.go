@@ -0,0 +1,42 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/events/orderevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderEventToNotification(t *testing.T) {
+	v1 := orderevents.OrderEvent{
+		OrderID:       "order-1",
+		EventType:     "CREATED",
+		Message:       "Order created for items: product-1",
+		Timestamp:     time.UnixMilli(1_700_000_000_000),
+		SchemaVersion: 1,
+	}
+	// Version 2 adds an envelope and the order to the version 1 fields.
+	v2 := v1
+	v2.EventID = "event-1"
+	v2.SchemaVersion = 2
+	v2.Source = "order-service"
+	v2.Order = &orderevents.Order{OrderID: "order-1", UserID: "user-1"}
+
+	for name, event := range map[string]orderevents.OrderEvent{"v1": v1, "v2": v2} {
+		t.Run(name, func(t *testing.T) {
+			notif, err := OrderEventToNotification(&event)
+			require.NoError(t, err)
+			assert.Equal(t, "order-1", notif.ID)
+			assert.Equal(t, "CREATED", notif.Type)
+			assert.Equal(t, "Order created for items: product-1", notif.Message)
+			assert.Equal(t, event.EventID, notif.EventID)
+		})
+	}
+}
+
+func TestOrderEventToNotification_MissingFields(t *testing.T) {
+	_, err := OrderEventToNotification(&orderevents.OrderEvent{EventType: "CREATED"})
+	assert.ErrorContains(t, err, "missing required fields")
+}
@@ -0,0 +1,56 @@
+package mappers
+
+import (
+	"github.com/jakkapat-chongsuwat/go-microservice/events/orderevents"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/events"
+)
+
+// ProtoOrderEventToOrderEvent converts an event read from a protobuf topic
+// to the type Avro events decode into, so both formats are handled alike.
+func ProtoOrderEventToOrderEvent(event *events.OrderEvent) *orderevents.OrderEvent {
+	var correlationID *string
+	if id := event.GetCorrelationId(); id != "" {
+		correlationID = &id
+	}
+	return &orderevents.OrderEvent{
+		OrderID:       event.GetOrderId(),
+		EventType:     event.GetEventType(),
+		Message:       event.GetMessage(),
+		Timestamp:     event.GetTimestamp().AsTime(),
+		EventID:       event.GetEventId(),
+		SchemaVersion: event.GetSchemaVersion(),
+		CorrelationID: correlationID,
+		Source:        event.GetSource(),
+		Order:         protoOrderToOrder(event.GetOrder()),
+	}
+}
+
+func protoOrderToOrder(order *events.Order) *orderevents.Order {
+	if order == nil {
+		return nil
+	}
+	items := make([]orderevents.OrderItem, 0, len(order.GetItems()))
+	for _, item := range order.GetItems() {
+		items = append(items, orderevents.OrderItem{
+			ProductID: item.GetProductId(),
+			Quantity:  item.GetQuantity(),
+			UnitPrice: protoMoneyToMoney(item.GetUnitPrice()),
+		})
+	}
+	return &orderevents.Order{
+		OrderID:       order.GetOrderId(),
+		UserID:        order.GetUserId(),
+		Status:        order.GetStatus(),
+		Items:         items,
+		TotalQuantity: order.GetTotalQuantity(),
+		Total:         protoMoneyToMoney(order.GetTotal()),
+		CreatedAt:     order.GetCreatedAt().AsTime(),
+	}
+}
+
+func protoMoneyToMoney(m *events.Money) *orderevents.Money {
+	if m == nil {
+		return nil
+	}
+	return &orderevents.Money{AmountMinor: m.GetAmountMinor(), CurrencyCode: m.GetCurrencyCode()}
+}
@@ -0,0 +1,63 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/events/orderevents"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/events"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestProtoOrderEventToOrderEvent(t *testing.T) {
+	at := time.UnixMilli(1_700_000_000_000).UTC()
+	correlationID := "req-1"
+
+	event := ProtoOrderEventToOrderEvent(&events.OrderEvent{
+		OrderId:       "order-1",
+		EventType:     "CREATED",
+		Message:       "Order created for items: product-1",
+		Timestamp:     timestamppb.New(at),
+		EventId:       "event-1",
+		SchemaVersion: 2,
+		CorrelationId: correlationID,
+		Source:        "order-service",
+		Order: &events.Order{
+			OrderId:       "order-1",
+			UserId:        "user-1",
+			Status:        "CREATED",
+			Items:         []*events.OrderItem{{ProductId: "product-1", Quantity: 2, UnitPrice: &events.Money{AmountMinor: 1999, CurrencyCode: "USD"}}},
+			TotalQuantity: 2,
+			CreatedAt:     timestamppb.New(at),
+		},
+	})
+
+	assert.Equal(t, &orderevents.OrderEvent{
+		OrderID:       "order-1",
+		EventType:     "CREATED",
+		Message:       "Order created for items: product-1",
+		Timestamp:     at,
+		EventID:       "event-1",
+		SchemaVersion: 2,
+		CorrelationID: &correlationID,
+		Source:        "order-service",
+		Order: &orderevents.Order{
+			OrderID:       "order-1",
+			UserID:        "user-1",
+			Status:        "CREATED",
+			Items:         []orderevents.OrderItem{{ProductID: "product-1", Quantity: 2, UnitPrice: &orderevents.Money{AmountMinor: 1999, CurrencyCode: "USD"}}},
+			TotalQuantity: 2,
+			CreatedAt:     at,
+		},
+	}, event)
+}
+
+func TestProtoOrderEventToOrderEvent_Empty(t *testing.T) {
+	event := ProtoOrderEventToOrderEvent(&events.OrderEvent{OrderId: "order-1", EventType: "CREATED"})
+
+	assert.Nil(t, event.CorrelationID)
+	assert.Nil(t, event.Order)
+	_, err := OrderEventToNotification(event)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,89 @@
+// Package publisher fans notifications out to the delivery channels.
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/interfaces"
+	"notification-service/internal/usecases"
+
+	"go.uber.org/zap"
+)
+
+// Channel is a publisher and the name of its channel in the preferences of
+// users, e.g. domain.ChannelEmail.
+type Channel struct {
+	Name      string
+	Publisher interfaces.NotificationPublisher
+}
+
+// Composite publishes each notification on every channel its user wants,
+// and notifications to everyone on every channel. Channels users do not
+// choose, those not in domain.Channels such as webhooks, get every
+// notification.
+type Composite struct {
+	channels    []Channel
+	preferences usecases.PreferenceRepository
+	logger      *zap.Logger
+}
+
+var _ interfaces.NotificationPublisher = (*Composite)(nil)
+
+func NewComposite(preferences usecases.PreferenceRepository, logger *zap.Logger, channels ...Channel) *Composite {
+	return &Composite{channels: channels, preferences: preferences, logger: logger}
+}
+
+// PublishNotification publishes notif on the channels concurrently, so a
+// slow channel does not hold up the others, and returns once all are done.
+// A channel that fails does not stop the others; the errors are joined.
+func (c *Composite) PublishNotification(notif *domain.Notification) error {
+	channels, err := c.channelsOf(notif)
+	if err != nil {
+		return err
+	}
+	errs := make([]error, len(channels))
+	var wg sync.WaitGroup
+	for i, channel := range channels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := channel.Publisher.PublishNotification(notif); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", channel.Name, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// channelsOf returns the channels to publish notif on. Users without
+// preferences get every channel.
+func (c *Composite) channelsOf(notif *domain.Notification) ([]Channel, error) {
+	if notif.UserID == "" {
+		return c.channels, nil
+	}
+	prefs, err := c.preferences.Get(context.Background(), notif.UserID)
+	if errors.Is(err, domain.ErrPreferencesNotFound) {
+		return c.channels, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get preferences of user %s: %w", notif.UserID, err)
+	}
+	var channels []Channel
+	for _, channel := range c.channels {
+		if !slices.Contains(domain.Channels, channel.Name) || prefs.Wants(channel.Name) {
+			channels = append(channels, channel)
+		} else {
+			c.logger.Debug("Channel turned off by the user",
+				zap.String("channel", channel.Name),
+				zap.String("userID", notif.UserID),
+				zap.String("notificationID", notif.ID))
+		}
+	}
+	return channels, nil
+}
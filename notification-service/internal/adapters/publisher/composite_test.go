@@ -0,0 +1,153 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"notification-service/internal/adapters/email"
+	"notification-service/internal/adapters/email/smtptest"
+	"notification-service/internal/adapters/handlers"
+	"notification-service/internal/adapters/repository"
+	"notification-service/internal/adapters/templates"
+	"notification-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/events/orderevents"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lazy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type recordingPublisher struct {
+	mu        sync.Mutex
+	published []*domain.Notification
+	err       error
+}
+
+func (r *recordingPublisher) PublishNotification(notif *domain.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.published = append(r.published, notif)
+	return r.err
+}
+
+func (r *recordingPublisher) ids() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for _, notif := range r.published {
+		ids = append(ids, notif.ID)
+	}
+	return ids
+}
+
+func savePreferences(t *testing.T, repo *repository.InMemoryPreferenceRepository, preferences ...domain.Preferences) {
+	t.Helper()
+	for _, prefs := range preferences {
+		require.NoError(t, repo.Save(context.Background(), &prefs))
+	}
+}
+
+func TestComposite_Channels(t *testing.T) {
+	repo := repository.NewInMemoryPreferenceRepo()
+	savePreferences(t, repo,
+		domain.Preferences{UserID: "email-only", Channels: []string{domain.ChannelEmail}},
+		domain.Preferences{UserID: "websocket-only", Channels: []string{domain.ChannelWebSocket}},
+		domain.Preferences{UserID: "both", Locale: "th"},
+	)
+	ws, mail, hooks := &recordingPublisher{}, &recordingPublisher{}, &recordingPublisher{}
+	c := NewComposite(repo, zap.NewNop(),
+		Channel{Name: domain.ChannelWebSocket, Publisher: ws},
+		Channel{Name: domain.ChannelEmail, Publisher: mail},
+		Channel{Name: "webhook", Publisher: hooks})
+
+	for _, userID := range []string{"", "email-only", "websocket-only", "both", "no-preferences"} {
+		notif := domain.NewNotificationWithID("to-"+userID, "SHIPPED", "Shipped")
+		notif.UserID = userID
+		require.NoError(t, c.PublishNotification(notif))
+	}
+
+	assert.Equal(t, []string{"to-", "to-websocket-only", "to-both", "to-no-preferences"}, ws.ids())
+	assert.Equal(t, []string{"to-", "to-email-only", "to-both", "to-no-preferences"}, mail.ids())
+	assert.Len(t, hooks.ids(), 5, "channels users do not choose get every notification")
+}
+
+func TestComposite_FailedChannel(t *testing.T) {
+	ws, mail := &recordingPublisher{}, &recordingPublisher{err: errors.New("connection refused")}
+	c := NewComposite(repository.NewInMemoryPreferenceRepo(), zap.NewNop(),
+		Channel{Name: domain.ChannelWebSocket, Publisher: ws},
+		Channel{Name: domain.ChannelEmail, Publisher: mail})
+
+	err := c.PublishNotification(domain.NewNotificationWithID("n-1", "SHIPPED", "Shipped"))
+
+	assert.EqualError(t, err, "email: connection refused")
+	assert.Equal(t, []string{"n-1"}, ws.ids(), "the other channels still get the notification")
+}
+
+// TestComposite_SameMessage follows an order event through the handlers to
+// both channels: the email carries the message WebSocket clients get.
+func TestComposite_SameMessage(t *testing.T) {
+	tmpl, err := templates.Load("", zap.NewNop())
+	require.NoError(t, err)
+	repo := repository.NewInMemoryPreferenceRepo()
+	savePreferences(t, repo, domain.Preferences{UserID: "user-1", Locale: "th", Email: "ada@example.com"})
+
+	server := smtptest.NewServer("", "")
+	defer server.Close()
+	mailer, err := email.NewPublisher(email.Config{
+		Addr:        server.Addr,
+		From:        "notifications@shop.example",
+		Timeout:     time.Second,
+		MaxAttempts: 1,
+		Backoff:     lazy.DefaultBackoff,
+		QueueSize:   1,
+	}, tmpl, repo, zap.NewNop())
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = mailer.Run(ctx) }()
+	ws := &recordingPublisher{}
+	c := NewComposite(repo, zap.NewNop(),
+		Channel{Name: domain.ChannelWebSocket, Publisher: ws},
+		Channel{Name: domain.ChannelEmail, Publisher: mailer})
+
+	registry := handlers.NewDefaultRegistry(handlers.Topics{Order: "order-events"}, handlers.NewMessages(tmpl, repo, zap.NewNop()))
+	order := &orderevents.OrderEvent{OrderID: "order-1", EventType: handlers.OrderShipped, EventID: "e-1",
+		Order: &orderevents.Order{OrderID: "order-1", UserID: "user-1"}}
+	notifs, err := registry.Handle(context.Background(), &handlers.Event{
+		Topic: "order-events", Type: order.EventType, ID: order.EventID, Fields: order.AvroNative(), Order: order,
+	})
+	require.NoError(t, err)
+	require.Len(t, notifs, 1)
+
+	require.NoError(t, c.PublishNotification(notifs[0]))
+
+	require.Len(t, ws.published, 1)
+	message := ws.published[0].Message
+	assert.Equal(t, "คำสั่งซื้อ order-1 ของคุณถูกจัดส่งแล้ว", message)
+
+	require.Eventually(t, func() bool { return len(server.Messages()) == 1 }, time.Second, time.Millisecond)
+	messages := server.Messages()
+	assert.Equal(t, []string{"ada@example.com"}, messages[0].To)
+	msg, err := netmail.ReadMessage(bytes.NewReader(messages[0].Data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, message, subject)
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	text, err := multipart.NewReader(msg.Body, params["boundary"]).NextPart()
+	require.NoError(t, err)
+	body, err := io.ReadAll(text)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(body), message+"\n"), "the text part starts with the message: %q", body)
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/usecases"
+)
+
+// maxDeliveriesPerSubscription bounds the delivery log of a subscription;
+// older deliveries are forgotten.
+const maxDeliveriesPerSubscription = 1000
+
+// InMemoryDeliveryRepository keeps the latest webhook deliveries of each
+// subscription for the life of the process.
+type InMemoryDeliveryRepository struct {
+	mu    sync.RWMutex
+	store map[string]domain.Delivery
+	// bySubscription holds the delivery IDs of each subscription, oldest
+	// first.
+	bySubscription map[string][]string
+}
+
+var _ usecases.DeliveryRepository = (*InMemoryDeliveryRepository)(nil)
+
+func NewInMemoryDeliveryRepo() *InMemoryDeliveryRepository {
+	return &InMemoryDeliveryRepository{
+		store:          make(map[string]domain.Delivery),
+		bySubscription: make(map[string][]string),
+	}
+}
+
+func (r *InMemoryDeliveryRepository) Save(_ context.Context, delivery *domain.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.store[delivery.ID]; !ok {
+		ids := append(r.bySubscription[delivery.SubscriptionID], delivery.ID)
+		if len(ids) > maxDeliveriesPerSubscription {
+			delete(r.store, ids[0])
+			ids = ids[1:]
+		}
+		r.bySubscription[delivery.SubscriptionID] = ids
+	}
+	r.store[delivery.ID] = *delivery
+	return nil
+}
+
+func (r *InMemoryDeliveryRepository) Get(_ context.Context, id string) (*domain.Delivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	delivery, ok := r.store[id]
+	if !ok {
+		return nil, domain.ErrDeliveryNotFound
+	}
+	return &delivery, nil
+}
+
+func (r *InMemoryDeliveryRepository) List(_ context.Context, subscriptionID string) ([]*domain.Delivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := r.bySubscription[subscriptionID]
+	deliveries := make([]*domain.Delivery, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		delivery := r.store[ids[i]]
+		deliveries = append(deliveries, &delivery)
+	}
+	return deliveries, nil
+}
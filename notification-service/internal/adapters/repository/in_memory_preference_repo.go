@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/usecases"
+)
+
+// InMemoryPreferenceRepository keeps preferences for the life of the
+// process.
+type InMemoryPreferenceRepository struct {
+	mu    sync.RWMutex
+	store map[string]domain.Preferences
+}
+
+var _ usecases.PreferenceRepository = (*InMemoryPreferenceRepository)(nil)
+
+func NewInMemoryPreferenceRepo() *InMemoryPreferenceRepository {
+	return &InMemoryPreferenceRepository{store: make(map[string]domain.Preferences)}
+}
+
+func (r *InMemoryPreferenceRepository) Get(_ context.Context, userID string) (*domain.Preferences, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	prefs, ok := r.store[userID]
+	if !ok {
+		return nil, domain.ErrPreferencesNotFound
+	}
+	return &prefs, nil
+}
+
+func (r *InMemoryPreferenceRepository) Save(_ context.Context, prefs *domain.Preferences) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *prefs
+	stored.Channels = slices.Clone(prefs.Channels)
+	r.store[prefs.UserID] = stored
+	return nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/usecases"
+)
+
+// InMemorySubscriptionRepository keeps webhook subscriptions for the life
+// of the process.
+type InMemorySubscriptionRepository struct {
+	mu    sync.RWMutex
+	store map[string]domain.Subscription
+	order []string
+}
+
+var _ usecases.SubscriptionRepository = (*InMemorySubscriptionRepository)(nil)
+
+func NewInMemorySubscriptionRepo() *InMemorySubscriptionRepository {
+	return &InMemorySubscriptionRepository{store: make(map[string]domain.Subscription)}
+}
+
+func (r *InMemorySubscriptionRepository) Create(_ context.Context, sub *domain.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store[sub.ID] = copySubscription(sub)
+	r.order = append(r.order, sub.ID)
+	return nil
+}
+
+func (r *InMemorySubscriptionRepository) Get(_ context.Context, id string) (*domain.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sub, ok := r.store[id]
+	if !ok {
+		return nil, domain.ErrSubscriptionNotFound
+	}
+	c := copySubscription(&sub)
+	return &c, nil
+}
+
+func (r *InMemorySubscriptionRepository) List(_ context.Context) ([]*domain.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	subs := make([]*domain.Subscription, 0, len(r.order))
+	for _, id := range r.order {
+		sub := r.store[id]
+		c := copySubscription(&sub)
+		subs = append(subs, &c)
+	}
+	return subs, nil
+}
+
+func (r *InMemorySubscriptionRepository) Update(_ context.Context, id string, update func(*domain.Subscription) error) (*domain.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sub, ok := r.store[id]
+	if !ok {
+		return nil, domain.ErrSubscriptionNotFound
+	}
+	updated := copySubscription(&sub)
+	if err := update(&updated); err != nil {
+		return nil, err
+	}
+	r.store[id] = copySubscription(&updated)
+	return &updated, nil
+}
+
+func (r *InMemorySubscriptionRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.store[id]; !ok {
+		return domain.ErrSubscriptionNotFound
+	}
+	delete(r.store, id)
+	r.order = slices.DeleteFunc(r.order, func(other string) bool { return other == id })
+	return nil
+}
+
+func copySubscription(sub *domain.Subscription) domain.Subscription {
+	c := *sub
+	c.EventTypes = slices.Clone(sub.EventTypes)
+	return c
+}
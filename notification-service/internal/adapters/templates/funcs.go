@@ -0,0 +1,67 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var funcs = template.FuncMap{
+	"money": money,
+}
+
+// currencyExponents lists the ISO 4217 currencies whose minor unit is not
+// a hundredth of the major unit.
+var currencyExponents = map[string]int{
+	"BHD": 3, "CLP": 0, "IQD": 3, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0,
+	"KWD": 3, "LYD": 3, "OMR": 3, "PYG": 0, "TND": 3, "UGX": 0, "VND": 0,
+}
+
+// money formats a Money record, with amount_minor and currency_code, in
+// major units, e.g. "12.50 USD".
+func money(v any) (string, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("money: want a Money record, got %T", v)
+	}
+	amount, err := toInt64(m["amount_minor"])
+	if err != nil {
+		return "", fmt.Errorf("money: amount_minor: %w", err)
+	}
+	currency, _ := m["currency_code"].(string)
+	exp, ok := currencyExponents[currency]
+	if !ok {
+		exp = 2
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	digits := strconv.FormatInt(amount, 10)
+	if exp > 0 {
+		if len(digits) <= exp {
+			digits = strings.Repeat("0", exp-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+	}
+	return strings.TrimSpace(sign + digits + " " + currency), nil
+}
+
+func toInt64(v any) (int64, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case int32:
+		return int64(n), nil
+	case int:
+		return int64(n), nil
+	case float64:
+		return int64(n), nil
+	case json.Number:
+		return n.Int64()
+	}
+	return 0, fmt.Errorf("want an integer, got %T", v)
+}
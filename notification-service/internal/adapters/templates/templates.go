@@ -0,0 +1,244 @@
+// Package templates renders notification messages from named text/template
+// templates with per-locale variants. The templates are compiled into the
+// binary; a directory of template files can replace them and is reloaded
+// when its files change.
+//
+// A template file is named <name>.<locale>.tmpl, e.g. order_created.th.tmpl.
+// Every template must have a DefaultLocale variant, which is used when the
+// requested locale has none.
+package templates
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"notification-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// DefaultLocale is the locale every template has.
+const DefaultLocale = "en"
+
+const ext = ".tmpl"
+
+//go:embed files/*.tmpl
+var embedded embed.FS
+
+// Templates renders the current set of templates. It is safe for
+// concurrent use, also while the templates are reloaded.
+type Templates struct {
+	dir    string
+	loaded atomic.Pointer[loaded]
+	logger *zap.Logger
+}
+
+// set is the templates of one load, by name and locale.
+type set map[string]map[string]*template.Template
+
+type loaded struct {
+	set set
+	// fingerprint is that of the directory the set was read from.
+	fingerprint string
+}
+
+// Load returns the compiled-in templates, or those read from dir when it is
+// not empty.
+func Load(dir string, logger *zap.Logger) (*Templates, error) {
+	t := &Templates{dir: dir, logger: logger}
+	if err := t.Reload(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Templates) fs() (fs.FS, string) {
+	if t.dir == "" {
+		sub, _ := fs.Sub(embedded, "files")
+		return sub, "embedded templates"
+	}
+	return os.DirFS(t.dir), "template directory " + t.dir
+}
+
+// Reload reads the templates again. On error the current templates stay.
+func (t *Templates) Reload() error {
+	var fp string
+	if t.dir != "" {
+		var err error
+		if fp, err = fingerprint(t.dir); err != nil {
+			return fmt.Errorf("template directory %s: %w", t.dir, err)
+		}
+	}
+	fsys, source := t.fs()
+	s, err := parse(fsys)
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+	t.loaded.Store(&loaded{set: s, fingerprint: fp})
+	return nil
+}
+
+func parse(fsys fs.FS) (set, error) {
+	paths, err := fs.Glob(fsys, "*"+ext)
+	if err != nil {
+		return nil, err
+	}
+	s := make(set)
+	var errs []error
+	for _, path := range paths {
+		name, locale, ok := strings.Cut(strings.TrimSuffix(path, ext), ".")
+		if !ok || domain.NormalizeLocale(locale) != locale {
+			errs = append(errs, fmt.Errorf("%s: want <name>.<locale>%s with a lower-case locale", path, ext))
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		tmpl, err := template.New(path).Option("missingkey=zero").Funcs(funcs).Parse(string(data))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if s[name] == nil {
+			s[name] = make(map[string]*template.Template)
+		}
+		s[name][locale] = tmpl
+	}
+	for name, locales := range s {
+		if locales[DefaultLocale] == nil {
+			errs = append(errs, fmt.Errorf("template %s has no %s variant", name, DefaultLocale))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(s) == 0 {
+		return nil, fmt.Errorf("no %s files", ext)
+	}
+	return s, nil
+}
+
+// Render executes template name in locale, or in its language if there is
+// no variant for the region, e.g. th for th-TH, or in DefaultLocale.
+func (t *Templates) Render(name, locale string, data any) (string, error) {
+	locales, ok := t.loaded.Load().set[name]
+	if !ok {
+		return "", fmt.Errorf("no template %q", name)
+	}
+	tmpl := locales[variant(locales, locale)]
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("render %s: %w", tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
+
+// Locale returns the locale Render writes template name in for locale.
+func (t *Templates) Locale(name, locale string) string {
+	return variant(t.loaded.Load().set[name], locale)
+}
+
+// variant returns the locale of the variant for locale among locales.
+func variant(locales map[string]*template.Template, locale string) string {
+	for locale = domain.NormalizeLocale(locale); locale != ""; {
+		if _, ok := locales[locale]; ok {
+			return locale
+		}
+		i := strings.LastIndexByte(locale, '-')
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return DefaultLocale
+}
+
+// Names returns the names of the templates and their locales, sorted.
+func (t *Templates) Names() map[string][]string {
+	names := make(map[string][]string)
+	for name, locales := range t.loaded.Load().set {
+		for locale := range locales {
+			names[name] = append(names[name], locale)
+		}
+		sort.Strings(names[name])
+	}
+	return names
+}
+
+// Watch reloads the templates from the directory when its files have
+// changed and stayed unchanged for interval, until ctx is done. Templates
+// that fail to load are logged and the previous ones kept. The compiled-in
+// templates never change; Watch then just waits for ctx.
+func (t *Templates) Watch(ctx context.Context, interval time.Duration) error {
+	if t.dir == "" {
+		<-ctx.Done()
+		return nil
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	last := t.loaded.Load().fingerprint
+	// changed is the fingerprint of a change seen on the previous tick. A
+	// change is loaded once it holds for a tick, so files caught halfway
+	// through being written are not.
+	var changed string
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			current, err := fingerprint(t.dir)
+			if err != nil {
+				t.logger.Warn("Failed to read template directory", zap.String("dir", t.dir), zap.Error(err))
+				continue
+			}
+			if current == last {
+				changed = ""
+				continue
+			}
+			if current != changed {
+				changed = current
+				continue
+			}
+			last, changed = current, ""
+			if err := t.Reload(); err != nil {
+				t.logger.Error("Failed to reload templates, keeping the previous ones", zap.Error(err))
+				continue
+			}
+			t.logger.Info("Templates reloaded", zap.String("dir", t.dir))
+		}
+	}
+}
+
+// fingerprint changes when a template file of dir is added, removed or
+// modified. Files are stat'ed through symlinks: in a mounted ConfigMap the
+// symlinks stay the same while the files they point to are swapped.
+func fingerprint(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ext) {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s %d %d\n", entry.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,182 @@
+package templates
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// TestTemplates_Golden renders every template in every locale with the data
+// of testdata/data/<name>.json and compares it to
+// testdata/golden/<name>.<locale>.golden. Run with -update after changing
+// a template.
+func TestTemplates_Golden(t *testing.T) {
+	tmpl, err := Load("", zap.NewNop())
+	require.NoError(t, err)
+
+	for name, locales := range tmpl.Names() {
+		raw, err := os.ReadFile(filepath.Join("testdata", "data", name+".json"))
+		require.NoError(t, err, "every template needs test data")
+		var data map[string]any
+		require.NoError(t, json.Unmarshal(raw, &data))
+
+		for _, locale := range locales {
+			t.Run(name+"."+locale, func(t *testing.T) {
+				got, err := tmpl.Render(name, locale, data)
+				require.NoError(t, err)
+
+				golden := filepath.Join("testdata", "golden", name+"."+locale+".golden")
+				if *update {
+					require.NoError(t, os.WriteFile(golden, []byte(got+"\n"), 0o644))
+				}
+				want, err := os.ReadFile(golden)
+				require.NoError(t, err)
+				assert.Equal(t, string(want), got+"\n")
+			})
+		}
+	}
+}
+
+func TestRender_Locales(t *testing.T) {
+	tmpl, err := Load("", zap.NewNop())
+	require.NoError(t, err)
+	data := map[string]any{"order_id": "order-1"}
+
+	for locale, want := range map[string]string{
+		"":      "Your order order-1 has shipped.",
+		"en":    "Your order order-1 has shipped.",
+		"th":    "คำสั่งซื้อ order-1 ของคุณถูกจัดส่งแล้ว",
+		"th_TH": "คำสั่งซื้อ order-1 ของคุณถูกจัดส่งแล้ว",
+		"fr-CA": "Your order order-1 has shipped.",
+		"!!":    "Your order order-1 has shipped.",
+	} {
+		got, err := tmpl.Render("order_shipped", locale, data)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, "locale %q", locale)
+	}
+	assert.Equal(t, "th", tmpl.Locale("order_shipped", "th_TH"))
+	assert.Equal(t, "en", tmpl.Locale("order_shipped", "fr-CA"))
+
+	_, err = tmpl.Render("order_lost", "en", data)
+	assert.ErrorContains(t, err, `no template "order_lost"`)
+}
+
+func TestMoney(t *testing.T) {
+	for _, tt := range []struct {
+		amount   any
+		currency string
+		want     string
+	}{
+		{int64(1250), "USD", "12.50 USD"},
+		{int64(5), "USD", "0.05 USD"},
+		{int32(1500), "JPY", "1500 JPY"},
+		{int64(1234), "KWD", "1.234 KWD"},
+		{float64(-250), "EUR", "-2.50 EUR"},
+	} {
+		got, err := money(map[string]any{"amount_minor": tt.amount, "currency_code": tt.currency})
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, err := money("12.50")
+	assert.Error(t, err)
+}
+
+func writeTemplate(t *testing.T, dir, file, text string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(text), 0o644))
+}
+
+func TestLoad_Dir(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "greeting.th.tmpl", "สวัสดี")
+
+	_, err := Load(dir, zap.NewNop())
+	assert.ErrorContains(t, err, "template greeting has no en variant")
+
+	writeTemplate(t, dir, "greeting.TH.tmpl", "สวัสดี")
+	_, err = Load(dir, zap.NewNop())
+	assert.ErrorContains(t, err, "greeting.TH.tmpl: want <name>.<locale>.tmpl")
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "greeting.TH.tmpl")))
+	writeTemplate(t, dir, "greeting.en.tmpl", "Hello {{.name}}")
+	tmpl, err := Load(dir, zap.NewNop())
+	require.NoError(t, err)
+	got, err := tmpl.Render("greeting", "en", map[string]any{"name": "Ada"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello Ada", got)
+}
+
+func TestWatch_ReloadsChangedTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "greeting.en.tmpl", "Hello")
+	tmpl, err := Load(dir, zap.NewNop())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- tmpl.Watch(ctx, 10*time.Millisecond) }()
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+
+	render := func() string {
+		got, err := tmpl.Render("greeting", "en", nil)
+		require.NoError(t, err)
+		return got
+	}
+
+	writeTemplate(t, dir, "greeting.en.tmpl", "Hi there")
+	assert.Eventually(t, func() bool { return render() == "Hi there" }, time.Second, 10*time.Millisecond)
+
+	// A broken template is not loaded; the last good ones stay.
+	writeTemplate(t, dir, "greeting.en.tmpl", "Hi {{.name")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "Hi there", render())
+
+	writeTemplate(t, dir, "greeting.en.tmpl", "Hey")
+	assert.Eventually(t, func() bool { return render() == "Hey" }, time.Second, 10*time.Millisecond)
+}
+
+// TestWatch_FollowsSymlinks updates the templates the way Kubernetes updates
+// a mounted ConfigMap: the template files are symlinks through ..data,
+// which is swapped to a new directory while the symlinks stay the same.
+func TestWatch_FollowsSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "v1"), 0o755))
+	writeTemplate(t, dir, "v1/greeting.en.tmpl", "Hello")
+	require.NoError(t, os.Symlink("v1", filepath.Join(dir, "..data")))
+	require.NoError(t, os.Symlink("..data/greeting.en.tmpl", filepath.Join(dir, "greeting.en.tmpl")))
+	tmpl, err := Load(dir, zap.NewNop())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- tmpl.Watch(ctx, 10*time.Millisecond) }()
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "v2"), 0o755))
+	writeTemplate(t, dir, "v2/greeting.en.tmpl", "Hi there")
+	require.NoError(t, os.Symlink("v2", filepath.Join(dir, "..data_tmp")))
+	require.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+
+	assert.Eventually(t, func() bool {
+		got, err := tmpl.Render("greeting", "en", nil)
+		require.NoError(t, err)
+		return got == "Hi there"
+	}, time.Second, 10*time.Millisecond)
+}
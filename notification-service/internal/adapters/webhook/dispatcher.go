@@ -0,0 +1,401 @@
+// Package webhook delivers notifications to the webhook subscriptions of
+// partners. Each delivery is a JSON POST of the notification, signed with
+// the subscription's secret, and retried with backoff until it succeeds or
+// runs out of attempts. The deliveries of a subscription are sent one at a
+// time, in the order of the notifications.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"notification-service/internal/domain"
+	"notification-service/internal/domain/interfaces"
+	"notification-service/internal/usecases"
+
+	"github.com/google/uuid"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lazy"
+	"go.uber.org/zap"
+)
+
+// Channel names webhooks among the channels of a publisher.Composite.
+const Channel = "webhook"
+
+// Headers of every delivery.
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Sign returns the signature header of a delivery: "sha256=" followed by
+// the hex HMAC-SHA256, keyed with secret, of the timestamp header, a dot
+// and the body. Receivers should compare it in constant time and reject
+// old timestamps, so captured deliveries cannot be replayed.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type Config struct {
+	// Timeout bounds each attempt of a delivery.
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts of a delivery; failed attempts
+	// are retried after waiting according to Backoff.
+	MaxAttempts int
+	Backoff     lazy.Backoff
+	// DisableAfter is the number of deliveries in a row that fail before
+	// the subscription is disabled; zero never disables it.
+	DisableAfter int
+	// AllowPrivateNetworks lets subscriptions reach private, loopback and
+	// link-local addresses, which are refused by default so partners
+	// cannot probe the internal network.
+	AllowPrivateNetworks bool
+}
+
+// Dispatcher queues a delivery of every notification to each subscription
+// that wants it, and sends the deliveries of each subscription in order in
+// the background, until Run returns.
+type Dispatcher struct {
+	cfg           Config
+	client        *http.Client
+	subscriptions usecases.SubscriptionRepository
+	deliveries    usecases.DeliveryRepository
+	logger        *zap.Logger
+
+	// ctx ends the workers when Run returns.
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu sync.Mutex
+	// queues hold the IDs of the deliveries waiting for each subscription
+	// with a worker; a worker exits and removes its queue once it is empty.
+	queues map[string][]string
+}
+
+var (
+	_ interfaces.NotificationPublisher = (*Dispatcher)(nil)
+	_ usecases.DeliveryQueue           = (*Dispatcher)(nil)
+	_ usecases.DestinationChecker      = (*Dispatcher)(nil)
+)
+
+func NewDispatcher(cfg Config, subscriptions usecases.SubscriptionRepository, deliveries usecases.DeliveryRepository, logger *zap.Logger) *Dispatcher {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !cfg.AllowPrivateNetworks {
+		// The address is checked when connecting, after DNS resolution, so
+		// a name cannot resolve to a public address when the subscription
+		// is saved and to a private one when it is delivered. A proxy
+		// would hide the address.
+		transport.Proxy = nil
+		transport.DialContext = (&net.Dialer{Timeout: 30 * time.Second, Control: checkDial}).DialContext
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: transport,
+			// A redirect is a failed delivery; the partner should fix the URL.
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		logger:        logger,
+		ctx:           ctx,
+		cancel:        cancel,
+		queues:        make(map[string][]string),
+	}
+}
+
+// Run waits for ctx and then stops the workers, waiting for the attempts
+// in flight. Deliveries still queued stay pending in the log.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	<-ctx.Done()
+	d.mu.Lock()
+	d.cancel()
+	d.mu.Unlock()
+	d.wg.Wait()
+	return nil
+}
+
+// PublishNotification logs and queues a delivery of notif for every enabled
+// subscription of its type. It does not wait for the deliveries.
+func (d *Dispatcher) PublishNotification(notif *domain.Notification) error {
+	ctx := context.Background()
+	subs, err := d.subscriptions.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list webhook subscriptions: %w", err)
+	}
+	payload, err := json.Marshal(notif)
+	if err != nil {
+		return fmt.Errorf("encode webhook payload: %w", err)
+	}
+	var errs []error
+	for _, sub := range subs {
+		if sub.Disabled || !sub.Matches(notif.Type) {
+			continue
+		}
+		delivery := domain.NewDelivery(uuid.NewString(), sub.ID, notif.ID, notif.Type, payload)
+		if err := d.deliveries.Save(ctx, delivery); err != nil {
+			errs = append(errs, fmt.Errorf("save webhook delivery for subscription %s: %w", sub.ID, err))
+			continue
+		}
+		d.Enqueue(delivery)
+	}
+	return errors.Join(errs...)
+}
+
+// Enqueue queues a pending delivery behind the others of its subscription.
+func (d *Dispatcher) Enqueue(delivery *domain.Delivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.ctx.Err() != nil {
+		return
+	}
+	queue, working := d.queues[delivery.SubscriptionID]
+	d.queues[delivery.SubscriptionID] = append(queue, delivery.ID)
+	if !working {
+		d.wg.Add(1)
+		go d.work(delivery.SubscriptionID)
+	}
+}
+
+// CheckDestination refuses URLs whose host resolves to an address that is
+// not public, unless AllowPrivateNetworks is set.
+func (d *Dispatcher) CheckDestination(ctx context.Context, u *url.URL) error {
+	if d.cfg.AllowPrivateNetworks {
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("%w: cannot resolve %s: %v", domain.ErrInvalidSubscription, u.Hostname(), err)
+	}
+	for _, addr := range addrs {
+		if !publicAddress(addr) {
+			return fmt.Errorf("%w: %s resolves to %s, which is not a public address", domain.ErrInvalidSubscription, u.Hostname(), addr.Unmap())
+		}
+	}
+	return nil
+}
+
+// nonPublic are the ranges that publicAddress refuses besides private,
+// loopback, link-local, multicast and unspecified addresses.
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),   // reserved
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64, which may map to private IPv4
+}
+
+// publicAddress reports whether webhooks may be sent to addr.
+func publicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublic {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkDial is the net.Dialer Control that refuses connections to addresses
+// that are not public.
+func checkDial(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !publicAddress(addrPort.Addr()) {
+		return fmt.Errorf("webhook destination %s is not a public address", addrPort.Addr().Unmap())
+	}
+	return nil
+}
+
+// work sends the queued deliveries of a subscription one by one.
+func (d *Dispatcher) work(subscriptionID string) {
+	defer d.wg.Done()
+	for {
+		id, ok := d.next(subscriptionID)
+		if !ok {
+			return
+		}
+		d.deliver(id)
+	}
+}
+
+// next takes the next delivery off the queue of a subscription, and removes
+// the queue when it is empty or the dispatcher is stopping.
+func (d *Dispatcher) next(subscriptionID string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	queue := d.queues[subscriptionID]
+	if len(queue) == 0 || d.ctx.Err() != nil {
+		delete(d.queues, subscriptionID)
+		return "", false
+	}
+	d.queues[subscriptionID] = queue[1:]
+	return queue[0], true
+}
+
+// deliver attempts a delivery until it succeeds, fails for good, or the
+// dispatcher stops, and logs the outcome of each attempt.
+func (d *Dispatcher) deliver(id string) {
+	ctx := d.ctx
+	delivery, err := d.deliveries.Get(ctx, id)
+	if err != nil {
+		d.logger.Error("Failed to get webhook delivery", zap.String("deliveryID", id), zap.Error(err))
+		return
+	}
+	logger := d.logger.With(
+		zap.String("subscriptionID", delivery.SubscriptionID),
+		zap.String("deliveryID", delivery.ID),
+		zap.String("notificationID", delivery.NotificationID))
+
+	for attempt := 1; ; attempt++ {
+		// The subscription is read for every attempt, so changes to its URL
+		// and secret apply to the deliveries being retried. Failing to read
+		// it fails the attempt.
+		sub, err := d.subscriptions.Get(ctx, delivery.SubscriptionID)
+		switch {
+		case errors.Is(err, domain.ErrSubscriptionNotFound):
+			d.finish(ctx, delivery, domain.DeliveryFailed, "subscription deleted", logger)
+			return
+		case err == nil && sub.Disabled:
+			d.finish(ctx, delivery, domain.DeliveryFailed, domain.ErrSubscriptionDisabled.Error(), logger)
+			return
+		}
+
+		delivery.Attempts++
+		if err != nil {
+			delivery.ResponseStatus, err = 0, fmt.Errorf("get webhook subscription: %w", err)
+		} else {
+			delivery.ResponseStatus, err = d.post(ctx, sub, delivery)
+		}
+		if err == nil {
+			d.finish(ctx, delivery, domain.DeliverySucceeded, "", logger)
+			d.recordResult(ctx, sub.ID, true, logger)
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if attempt == d.cfg.MaxAttempts {
+			d.finish(ctx, delivery, domain.DeliveryFailed, err.Error(), logger)
+			d.recordResult(ctx, delivery.SubscriptionID, false, logger)
+			return
+		}
+
+		delivery.LastError = err.Error()
+		delivery.UpdatedAt = time.Now()
+		if err := d.deliveries.Save(ctx, delivery); err != nil {
+			logger.Error("Failed to save webhook delivery", zap.Error(err))
+		}
+		delay := d.cfg.Backoff.Delay(attempt)
+		attemptRetries.Inc()
+		logger.Warn("Webhook delivery failed, retrying",
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", delay),
+			zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// post sends one attempt of delivery to sub and returns the HTTP status of
+// the response, zero if there was none. Statuses other than 2xx fail.
+func (d *Dispatcher) post(ctx context.Context, sub *domain.Subscription, delivery *domain.Delivery) (int, error) {
+	start := time.Now()
+	defer func() { attemptDuration.Observe(time.Since(start).Seconds()) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "notification-service-webhooks")
+	req.Header.Set(HeaderID, delivery.ID)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) finish(ctx context.Context, delivery *domain.Delivery, status domain.DeliveryStatus, lastError string, logger *zap.Logger) {
+	delivery.Status, delivery.LastError, delivery.UpdatedAt = status, lastError, time.Now()
+	if err := d.deliveries.Save(ctx, delivery); err != nil {
+		logger.Error("Failed to save webhook delivery", zap.Error(err))
+	}
+	deliveriesTotal.WithLabelValues(string(status)).Inc()
+	if status == domain.DeliverySucceeded {
+		logger.Info("Webhook delivered", zap.Int("attempts", delivery.Attempts))
+	} else {
+		logger.Warn("Webhook delivery failed", zap.Int("attempts", delivery.Attempts), zap.String("error", lastError))
+	}
+}
+
+// recordResult counts the failed deliveries in a row of a subscription,
+// and disables it after DisableAfter.
+func (d *Dispatcher) recordResult(ctx context.Context, subscriptionID string, succeeded bool, logger *zap.Logger) {
+	disabled := false
+	_, err := d.subscriptions.Update(ctx, subscriptionID, func(sub *domain.Subscription) error {
+		if succeeded {
+			sub.FailedDeliveries = 0
+			return nil
+		}
+		sub.FailedDeliveries++
+		if d.cfg.DisableAfter > 0 && sub.FailedDeliveries >= d.cfg.DisableAfter && !sub.Disabled {
+			sub.Disabled, disabled = true, true
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, domain.ErrSubscriptionNotFound) {
+		logger.Error("Failed to update webhook subscription", zap.Error(err))
+		return
+	}
+	if disabled {
+		subscriptionsDisabled.Inc()
+		logger.Warn("Webhook subscription disabled after failed deliveries", zap.Int("failedDeliveries", d.cfg.DisableAfter))
+	}
+}
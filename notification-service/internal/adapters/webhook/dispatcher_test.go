@@ -0,0 +1,349 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"notification-service/internal/adapters/repository"
+	"notification-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lazy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const secret = "0123456789abcdef0123456789abcdef"
+
+// receiver records the deliveries it gets and answers with the next of
+// its statuses, then 200.
+type receiver struct {
+	*httptest.Server
+	t *testing.T
+
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	r := &receiver{t: t, statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(r.serve))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) serve(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	require.NoError(r.t, err)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+// notificationIDs returns the notification of each request received.
+func (r *receiver) notificationIDs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]string, len(r.bodies))
+	for i, body := range r.bodies {
+		var notif domain.Notification
+		require.NoError(r.t, json.Unmarshal(body, &notif))
+		ids[i] = notif.ID
+	}
+	return ids
+}
+
+// flakySubscriptions fails the first failGets reads of a subscription.
+type flakySubscriptions struct {
+	*repository.InMemorySubscriptionRepository
+	failGets atomic.Int32
+}
+
+func (f *flakySubscriptions) Get(ctx context.Context, id string) (*domain.Subscription, error) {
+	if f.failGets.Add(-1) >= 0 {
+		return nil, errors.New("connection reset")
+	}
+	return f.InMemorySubscriptionRepository.Get(ctx, id)
+}
+
+type fixture struct {
+	dispatcher    *Dispatcher
+	subscriptions *flakySubscriptions
+	deliveries    *repository.InMemoryDeliveryRepository
+}
+
+// newFixture runs a dispatcher with cfg. The receivers of the tests listen
+// on loopback, so set cfg.AllowPrivateNetworks to deliver to them.
+func newFixture(t *testing.T, cfg Config) *fixture {
+	t.Helper()
+	f := &fixture{
+		subscriptions: &flakySubscriptions{InMemorySubscriptionRepository: repository.NewInMemorySubscriptionRepo()},
+		deliveries:    repository.NewInMemoryDeliveryRepo(),
+	}
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = 3
+	}
+	cfg.Timeout = time.Second
+	cfg.Backoff = lazy.Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond, Multiplier: 2}
+	f.dispatcher = NewDispatcher(cfg, f.subscriptions, f.deliveries, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- f.dispatcher.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+	return f
+}
+
+func (f *fixture) subscribe(t *testing.T, url string, eventTypes ...string) string {
+	t.Helper()
+	sub := &domain.Subscription{ID: "sub-" + url, URL: url, EventTypes: eventTypes, Secret: secret}
+	require.NoError(t, f.subscriptions.Create(context.Background(), sub))
+	return sub.ID
+}
+
+// settled waits until no delivery of subscriptionID is pending and returns
+// them, oldest first.
+func (f *fixture) settled(t *testing.T, subscriptionID string, want int) []*domain.Delivery {
+	t.Helper()
+	var deliveries []*domain.Delivery
+	require.Eventually(t, func() bool {
+		var err error
+		deliveries, err = f.deliveries.List(context.Background(), subscriptionID)
+		require.NoError(t, err)
+		if len(deliveries) != want {
+			return false
+		}
+		for _, delivery := range deliveries {
+			if delivery.Status == domain.DeliveryPending {
+				return false
+			}
+		}
+		return true
+	}, 2*time.Second, 5*time.Millisecond)
+	for i, j := 0, len(deliveries)-1; i < j; i, j = i+1, j-1 {
+		deliveries[i], deliveries[j] = deliveries[j], deliveries[i]
+	}
+	return deliveries
+}
+
+func notification(id, eventType string) *domain.Notification {
+	notif := domain.NewNotificationWithID(id, eventType, "Order "+id+" "+eventType)
+	notif.EventID, notif.UserID = "event-"+id, "user-1"
+	return notif
+}
+
+func TestDispatcher_SignsDeliveries(t *testing.T) {
+	recv := newReceiver(t)
+	f := newFixture(t, Config{AllowPrivateNetworks: true})
+	subID := f.subscribe(t, recv.URL)
+
+	require.NoError(t, f.dispatcher.PublishNotification(notification("order-1", "SHIPPED")))
+
+	deliveries := f.settled(t, subID, 1)
+	assert.Equal(t, domain.DeliverySucceeded, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, http.StatusOK, deliveries[0].ResponseStatus)
+
+	require.Len(t, recv.requests, 1)
+	req, body := recv.requests[0], recv.bodies[0]
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Equal(t, deliveries[0].ID, req.Header.Get(HeaderID))
+	assert.Equal(t, "SHIPPED", req.Header.Get(HeaderEvent))
+
+	timestamp := req.Header.Get(HeaderTimestamp)
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), time.Unix(sent, 0), time.Minute)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + string(body)))
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), req.Header.Get(HeaderSignature))
+
+	var notif domain.Notification
+	require.NoError(t, json.Unmarshal(body, &notif))
+	assert.Equal(t, "order-1", notif.ID)
+	assert.Equal(t, "event-order-1", notif.EventID)
+	assert.Equal(t, "Order order-1 SHIPPED", notif.Message)
+}
+
+func TestDispatcher_RetriesInOrder(t *testing.T) {
+	recv := newReceiver(t, http.StatusInternalServerError, http.StatusServiceUnavailable)
+	f := newFixture(t, Config{MaxAttempts: 3, AllowPrivateNetworks: true})
+	subID := f.subscribe(t, recv.URL)
+
+	for _, id := range []string{"n-1", "n-2", "n-3"} {
+		require.NoError(t, f.dispatcher.PublishNotification(notification(id, "SHIPPED")))
+	}
+
+	deliveries := f.settled(t, subID, 3)
+	assert.Equal(t, []string{"n-1", "n-1", "n-1", "n-2", "n-3"}, recv.notificationIDs(),
+		"later deliveries wait for the one being retried")
+	for _, delivery := range deliveries {
+		assert.Equal(t, domain.DeliverySucceeded, delivery.Status)
+	}
+	assert.Equal(t, 3, deliveries[0].Attempts)
+	assert.Equal(t, 1, deliveries[1].Attempts)
+}
+
+func TestDispatcher_RetriesFailedSubscriptionReads(t *testing.T) {
+	recv := newReceiver(t)
+	f := newFixture(t, Config{MaxAttempts: 3, AllowPrivateNetworks: true})
+	subID := f.subscribe(t, recv.URL)
+
+	f.subscriptions.failGets.Store(2)
+	require.NoError(t, f.dispatcher.PublishNotification(notification("n-1", "SHIPPED")))
+	deliveries := f.settled(t, subID, 1)
+	assert.Equal(t, domain.DeliverySucceeded, deliveries[0].Status)
+	assert.Equal(t, 3, deliveries[0].Attempts)
+	assert.Equal(t, []string{"n-1"}, recv.notificationIDs())
+
+	f.subscriptions.failGets.Store(3)
+	require.NoError(t, f.dispatcher.PublishNotification(notification("n-2", "SHIPPED")))
+	deliveries = f.settled(t, subID, 2)
+	assert.Equal(t, domain.DeliveryFailed, deliveries[1].Status)
+	assert.Equal(t, "get webhook subscription: connection reset", deliveries[1].LastError)
+	assert.Equal(t, []string{"n-1"}, recv.notificationIDs())
+}
+
+func TestDispatcher_OrderPerSubscription(t *testing.T) {
+	slow := newReceiver(t, http.StatusInternalServerError, http.StatusInternalServerError)
+	fast := newReceiver(t)
+	f := newFixture(t, Config{MaxAttempts: 3, AllowPrivateNetworks: true})
+	slowID, fastID := f.subscribe(t, slow.URL), f.subscribe(t, fast.URL)
+
+	for _, id := range []string{"n-1", "n-2", "n-3", "n-4"} {
+		require.NoError(t, f.dispatcher.PublishNotification(notification(id, "SHIPPED")))
+	}
+
+	f.settled(t, slowID, 4)
+	f.settled(t, fastID, 4)
+	assert.Equal(t, []string{"n-1", "n-1", "n-1", "n-2", "n-3", "n-4"}, slow.notificationIDs())
+	assert.Equal(t, []string{"n-1", "n-2", "n-3", "n-4"}, fast.notificationIDs())
+}
+
+func TestDispatcher_EventFilter(t *testing.T) {
+	recv := newReceiver(t)
+	f := newFixture(t, Config{AllowPrivateNetworks: true})
+	subID := f.subscribe(t, recv.URL, "SHIPPED", "CANCELLED")
+
+	for _, notif := range []*domain.Notification{
+		notification("n-1", "CREATED"),
+		notification("n-2", "SHIPPED"),
+		notification("n-3", "CANCELLED"),
+	} {
+		require.NoError(t, f.dispatcher.PublishNotification(notif))
+	}
+
+	f.settled(t, subID, 2)
+	assert.Equal(t, []string{"n-2", "n-3"}, recv.notificationIDs())
+}
+
+func TestDispatcher_DisablesFailingSubscription(t *testing.T) {
+	recv := newReceiver(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusGone, http.StatusGone)
+	f := newFixture(t, Config{MaxAttempts: 2, DisableAfter: 2, AllowPrivateNetworks: true})
+	subID := f.subscribe(t, recv.URL)
+
+	for _, id := range []string{"n-1", "n-2", "n-3"} {
+		require.NoError(t, f.dispatcher.PublishNotification(notification(id, "SHIPPED")))
+	}
+
+	deliveries := f.settled(t, subID, 3)
+	assert.Equal(t, []string{"n-1", "n-1", "n-2", "n-2"}, recv.notificationIDs())
+	for _, delivery := range deliveries {
+		assert.Equal(t, domain.DeliveryFailed, delivery.Status)
+	}
+	assert.Equal(t, "receiver answered 410 Gone", deliveries[1].LastError)
+	assert.Equal(t, http.StatusGone, deliveries[1].ResponseStatus)
+	assert.Equal(t, "webhook subscription is disabled", deliveries[2].LastError)
+	assert.Zero(t, deliveries[2].Attempts)
+
+	sub, err := f.subscriptions.Get(context.Background(), subID)
+	require.NoError(t, err)
+	assert.True(t, sub.Disabled)
+	assert.Equal(t, 2, sub.FailedDeliveries)
+
+	require.NoError(t, f.dispatcher.PublishNotification(notification("n-4", "SHIPPED")))
+	deliveries, err = f.deliveries.List(context.Background(), subID)
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 3, "disabled subscriptions get no deliveries")
+}
+
+func TestDispatcher_SuccessResetsFailures(t *testing.T) {
+	recv := newReceiver(t, http.StatusInternalServerError)
+	f := newFixture(t, Config{MaxAttempts: 1, DisableAfter: 2, AllowPrivateNetworks: true})
+	subID := f.subscribe(t, recv.URL)
+
+	for _, id := range []string{"n-1", "n-2", "n-3"} {
+		require.NoError(t, f.dispatcher.PublishNotification(notification(id, "SHIPPED")))
+	}
+
+	f.settled(t, subID, 3)
+	sub, err := f.subscriptions.Get(context.Background(), subID)
+	require.NoError(t, err)
+	assert.False(t, sub.Disabled)
+	assert.Zero(t, sub.FailedDeliveries)
+}
+
+func TestDispatcher_RefusesPrivateAddresses(t *testing.T) {
+	recv := newReceiver(t)
+	f := newFixture(t, Config{MaxAttempts: 1})
+	// The subscription was saved while its host resolved to a public
+	// address; it now resolves to loopback.
+	subID := f.subscribe(t, recv.URL)
+
+	require.NoError(t, f.dispatcher.PublishNotification(notification("n-1", "SHIPPED")))
+
+	deliveries := f.settled(t, subID, 1)
+	assert.Equal(t, domain.DeliveryFailed, deliveries[0].Status)
+	assert.Contains(t, deliveries[0].LastError, "webhook destination 127.0.0.1 is not a public address")
+	assert.Empty(t, recv.notificationIDs(), "the connection is refused before it is made")
+}
+
+func TestPublicAddress(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"93.184.215.14":         true,
+		"2606:2800:21f:cb07::1": true,
+		"127.0.0.1":             false,
+		"10.0.0.1":              false,
+		"172.16.5.4":            false,
+		"192.168.1.1":           false,
+		"169.254.169.254":       false,
+		"100.64.0.1":            false,
+		"0.0.0.0":               false,
+		"255.255.255.255":       false,
+		"224.0.0.1":             false,
+		"::1":                   false,
+		"::":                    false,
+		"fe80::1":               false,
+		"fd00:ec2::254":         false,
+		"::ffff:10.0.0.1":       false,
+		"64:ff9b::a00:1":        false,
+	} {
+		assert.Equal(t, want, publicAddress(netip.MustParseAddr(addr)), addr)
+	}
+}
@@ -0,0 +1,31 @@
+package webhook
+
+import (
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	deliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "webhook_deliveries_total",
+		Help: "Number of finished webhook deliveries by status: succeeded or failed.",
+	}, []string{"status"})
+
+	attemptRetries = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "webhook_retries_total",
+		Help: "Number of webhook delivery attempts retried after a failure.",
+	})
+
+	attemptDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "webhook_attempt_duration_seconds",
+		Help:    "Time spent on each attempt of a webhook delivery.",
+		Buckets: metrics.LatencyBuckets,
+	})
+
+	subscriptionsDisabled = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "webhook_subscriptions_disabled_total",
+		Help: "Number of webhook subscriptions disabled after failed deliveries.",
+	})
+)
@@ -3,6 +3,7 @@ package websocket
 import (
 	"encoding/json"
 	"sync"
+	"time"
 
 	"notification-service/internal/domain"
 	"notification-service/internal/domain/interfaces"
@@ -31,6 +32,7 @@ func (h *Hub) Add(conn *websocket.Conn) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	h.connections[conn] = true
+	connectionsGauge.Set(float64(len(h.connections)))
 	h.logger.Info("WebSocket connection added", zap.String("remoteAddr", conn.RemoteAddr().String()))
 }
 
@@ -38,6 +40,7 @@ func (h *Hub) Remove(conn *websocket.Conn) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	delete(h.connections, conn)
+	connectionsGauge.Set(float64(len(h.connections)))
 	h.logger.Info("WebSocket connection removed", zap.String("remoteAddr", conn.RemoteAddr().String()))
 }
 
@@ -50,8 +53,11 @@ func (h *Hub) Broadcast(message interface{}) error {
 		return err
 	}
 	h.logger.Info("Broadcasting message", zap.Int("connectionCount", len(h.connections)))
+	start := time.Now()
+	defer func() { broadcastDuration.Observe(time.Since(start).Seconds()) }()
 	for conn := range h.connections {
 		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			writeErrors.Inc()
 			h.logger.Error("Failed to write message to connection", zap.String("remoteAddr", conn.RemoteAddr().String()), zap.Error(err))
 		}
 	}
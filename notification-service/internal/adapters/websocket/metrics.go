@@ -0,0 +1,26 @@
+package websocket
+
+import (
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	connectionsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "websocket_connections",
+		Help: "Number of WebSocket connections registered with the hub.",
+	})
+
+	broadcastDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "websocket_broadcast_duration_seconds",
+		Help:    "Time spent fanning a message out to every connection.",
+		Buckets: metrics.LatencyBuckets,
+	})
+
+	writeErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "websocket_write_errors_total",
+		Help: "Number of failed writes to WebSocket connections.",
+	})
+)
@@ -0,0 +1,57 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gws "github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHubMetrics(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	registered := make(chan *gws.Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&gws.Upgrader{}).Upgrade(w, r, nil)
+		require.NoError(t, err)
+		hub.Add(conn)
+		registered <- conn
+	}))
+	defer server.Close()
+
+	client, _, err := gws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	var conn *gws.Conn
+	select {
+	case conn = <-registered:
+	case <-time.After(time.Second):
+		t.Fatal("connection was not registered")
+	}
+	assert.Equal(t, 1.0, testutil.ToFloat64(connectionsGauge))
+
+	before := broadcastCount(t)
+	require.NoError(t, hub.Broadcast("ping"))
+	assert.Equal(t, before+1, broadcastCount(t))
+	_, data, err := client.ReadMessage()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "ping")
+
+	hub.Remove(conn)
+	assert.Zero(t, testutil.ToFloat64(connectionsGauge))
+}
+
+func broadcastCount(t *testing.T) uint64 {
+	t.Helper()
+	metric := &dto.Metric{}
+	require.NoError(t, broadcastDuration.Write(metric))
+	return metric.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,49 @@
+// Package clients holds the gRPC clients notification-service uses to call other
+// services.
+package clients
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/apikey"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/user_service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyVerifier verifies API keys with user-service's VerifyAPIKey RPC.
+type APIKeyVerifier struct {
+	client  user_service.UserServiceClient
+	timeout time.Duration
+}
+
+var _ apikey.Verifier = (*APIKeyVerifier)(nil)
+
+func NewAPIKeyVerifier(client user_service.UserServiceClient, timeout time.Duration) *APIKeyVerifier {
+	return &APIKeyVerifier{client: client, timeout: timeout}
+}
+
+func (v *APIKeyVerifier) Verify(ctx context.Context, key string) (apikey.Principal, error) {
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+
+	resp, err := v.client.VerifyAPIKey(ctx, &user_service.VerifyAPIKeyRequest{Key: key})
+	if status.Code(err) == codes.Unauthenticated {
+		return apikey.Principal{}, apikey.ErrInvalidKey
+	}
+	if err != nil {
+		return apikey.Principal{}, fmt.Errorf("verify api key: %w", err)
+	}
+
+	p := apikey.Principal{
+		KeyID:   resp.GetKeyId(),
+		OwnerID: resp.GetOwnerId(),
+		Scopes:  resp.GetScopes(),
+	}
+	if resp.GetExpiresAt() != nil {
+		p.ExpiresAt = resp.GetExpiresAt().AsTime()
+	}
+	return p, nil
+}
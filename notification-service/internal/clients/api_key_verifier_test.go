@@ -0,0 +1,50 @@
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/apikey"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/user_service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type fakeUserServiceClient struct {
+	user_service.UserServiceClient
+	resp *user_service.VerifyAPIKeyResponse
+	err  error
+}
+
+func (f *fakeUserServiceClient) VerifyAPIKey(ctx context.Context, in *user_service.VerifyAPIKeyRequest, _ ...grpc.CallOption) (*user_service.VerifyAPIKeyResponse, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		return nil, status.Error(codes.Internal, "no deadline")
+	}
+	return f.resp, f.err
+}
+
+func TestAPIKeyVerifier(t *testing.T) {
+	expires := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	client := &fakeUserServiceClient{resp: &user_service.VerifyAPIKeyResponse{
+		KeyId: "k1", OwnerId: "u1", Scopes: []string{apikey.ScopeUsersRead}, ExpiresAt: timestamppb.New(expires),
+	}}
+	verifier := NewAPIKeyVerifier(client, time.Second)
+
+	p, err := verifier.Verify(context.Background(), "gmk_key")
+	require.NoError(t, err)
+	assert.Equal(t, apikey.Principal{KeyID: "k1", OwnerID: "u1", Scopes: []string{apikey.ScopeUsersRead}, ExpiresAt: expires}, p)
+
+	client.err = status.Error(codes.Unauthenticated, "invalid api key")
+	_, err = verifier.Verify(context.Background(), "gmk_key")
+	assert.ErrorIs(t, err, apikey.ErrInvalidKey)
+
+	client.err = status.Error(codes.Unavailable, "connection refused")
+	_, err = verifier.Verify(context.Background(), "gmk_key")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, apikey.ErrInvalidKey)
+}
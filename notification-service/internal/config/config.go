@@ -0,0 +1,224 @@
+// Package config is the typed configuration of notification-service.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"time"
+
+	platformconfig "github.com/jakkapat-chongsuwat/go-microservice/platform/config"
+)
+
+type Config struct {
+	DevMode         bool          `env:"DEV_MODE" yaml:"dev_mode"`
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" default:"25s" yaml:"shutdown_timeout"`
+
+	Kafka          Kafka     `yaml:"kafka"`
+	SchemaRegistry string    `env:"SCHEMA_REGISTRY_URL" default:"http://localhost:8081" yaml:"schema_registry_url" validate:"required"`
+	WebSocket      WebSocket `yaml:"websocket"`
+	Templates      Templates `yaml:"templates"`
+	Email          Email     `yaml:"email"`
+	Webhooks       Webhooks  `yaml:"webhooks"`
+	APIKeys        APIKeys   `yaml:"api_keys"`
+}
+
+type Kafka struct {
+	Brokers []string `env:"KAFKA_BROKERS" default:"localhost:9092" yaml:"brokers" validate:"required"`
+	GroupID string   `env:"KAFKA_GROUP_ID" default:"notification-consumer-group" yaml:"group_id" validate:"required"`
+	// Topics are consumed. The order, user and inventory topics name the
+	// topic of each kind of event; events of other topics get the fallback
+	// handler.
+	Topics         []string `env:"KAFKA_TOPICS" default:"order-events,user-events,inventory-events" yaml:"topics" validate:"required"`
+	OrderTopic     string   `env:"KAFKA_ORDER_TOPIC" default:"order-events" yaml:"order_topic"`
+	UserTopic      string   `env:"KAFKA_USER_TOPIC" default:"user-events" yaml:"user_topic"`
+	InventoryTopic string   `env:"KAFKA_INVENTORY_TOPIC" default:"inventory-events" yaml:"inventory_topic"`
+
+	// ExactlyOnce writes every notification to OutputTopic in a
+	// transaction with the consumer offsets, and reads committed records
+	// only.
+	ExactlyOnce           bool   `env:"KAFKA_EXACTLY_ONCE" yaml:"exactly_once"`
+	OutputTopic           string `env:"KAFKA_OUTPUT_TOPIC" default:"notifications-outbound" yaml:"output_topic"`
+	TransactionalIDPrefix string `env:"KAFKA_TRANSACTIONAL_ID_PREFIX" default:"notification-service" yaml:"transactional_id_prefix"`
+}
+
+type WebSocket struct {
+	Port            int `env:"WS_PORT" default:"20052" yaml:"port"`
+	ReadBufferSize  int `env:"WS_READ_BUFFER_SIZE" default:"1024" yaml:"read_buffer_size"`
+	WriteBufferSize int `env:"WS_WRITE_BUFFER_SIZE" default:"1024" yaml:"write_buffer_size"`
+}
+
+// Templates locate the message templates. Without Dir the compiled-in
+// templates are used; a directory is checked for changes every
+// ReloadInterval.
+type Templates struct {
+	Dir            string        `env:"TEMPLATES_DIR" yaml:"dir"`
+	ReloadInterval time.Duration `env:"TEMPLATES_RELOAD_INTERVAL" default:"5s" yaml:"reload_interval"`
+}
+
+// Email sends notifications by email through the SMTP server at SMTPHost
+// when it is set. Emails wait in a queue of QueueSize and are sent by
+// Workers in the background. A failed email is tried up to MaxAttempts
+// times, waiting with jittered exponential backoff from InitialBackoff up to
+// MaxBackoff.
+type Email struct {
+	SMTPHost       string        `env:"SMTP_HOST" yaml:"smtp_host"`
+	SMTPPort       int           `env:"SMTP_PORT" default:"587" yaml:"smtp_port"`
+	Username       string        `env:"SMTP_USERNAME" yaml:"username"`
+	Password       string        `env:"SMTP_PASSWORD" yaml:"password" secret:"true"`
+	From           string        `env:"EMAIL_FROM" default:"Notifications <notifications@localhost>" yaml:"from"`
+	Timeout        time.Duration `env:"SMTP_TIMEOUT" default:"10s" yaml:"timeout"`
+	MaxAttempts    int           `env:"EMAIL_MAX_ATTEMPTS" default:"3" yaml:"max_attempts"`
+	InitialBackoff time.Duration `env:"EMAIL_INITIAL_BACKOFF" default:"1s" yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `env:"EMAIL_MAX_BACKOFF" default:"10s" yaml:"max_backoff"`
+	QueueSize      int           `env:"EMAIL_QUEUE_SIZE" default:"1000" yaml:"queue_size"`
+	Workers        int           `env:"EMAIL_WORKERS" default:"4" yaml:"workers"`
+}
+
+// Webhooks deliver notifications to the webhook subscriptions of partners.
+// A failed delivery is tried up to MaxAttempts times, each bounded by
+// Timeout, waiting with jittered exponential backoff from InitialBackoff up
+// to MaxBackoff. A subscription is disabled after DisableAfter failed
+// deliveries in a row; zero never disables it. Subscriptions may not reach
+// private, loopback or link-local addresses unless AllowPrivateNetworks is
+// set. The webhooks API takes AdminToken as a bearer token; without it the
+// API is closed. The token also opens the preferences of every user.
+type Webhooks struct {
+	Timeout        time.Duration `env:"WEBHOOK_TIMEOUT" default:"10s" yaml:"timeout"`
+	MaxAttempts    int           `env:"WEBHOOK_MAX_ATTEMPTS" default:"5" yaml:"max_attempts"`
+	InitialBackoff time.Duration `env:"WEBHOOK_INITIAL_BACKOFF" default:"1s" yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `env:"WEBHOOK_MAX_BACKOFF" default:"1m" yaml:"max_backoff"`
+	DisableAfter   int           `env:"WEBHOOK_DISABLE_AFTER" default:"5" yaml:"disable_after"`
+
+	AllowPrivateNetworks bool   `env:"WEBHOOK_ALLOW_PRIVATE_NETWORKS" yaml:"allow_private_networks"`
+	AdminToken           string `env:"WEBHOOK_ADMIN_TOKEN" yaml:"admin_token" secret:"true"`
+}
+
+const minAdminTokenLength = 32
+
+// APIKeys let users read and change their own preferences with the API keys
+// user-service issues them. Keys are verified with user-service; without its
+// address only the webhooks admin token opens the preferences API.
+type APIKeys struct {
+	UserServiceAddress string        `env:"USER_SERVICE_ADDRESS" yaml:"user_service_address"`
+	CacheTTL           time.Duration `env:"API_KEY_CACHE_TTL" default:"30s" yaml:"cache_ttl"`
+	VerifyTimeout      time.Duration `env:"API_KEY_VERIFY_TIMEOUT" default:"1s" yaml:"verify_timeout"`
+}
+
+func (a *APIKeys) Validate() error {
+	var errs []error
+	if a.CacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("API_KEY_CACHE_TTL must not be negative, got %s", a.CacheTTL))
+	}
+	if a.VerifyTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("API_KEY_VERIFY_TIMEOUT must be positive, got %s", a.VerifyTimeout))
+	}
+	return errors.Join(errs...)
+}
+
+// Enabled reports whether API keys are verified.
+func (a APIKeys) Enabled() bool {
+	return a.UserServiceAddress != ""
+}
+
+// Load reads the configuration from the environment, .env and the optional
+// YAML file.
+func Load(yamlFile string) (*Config, error) {
+	cfg := &Config{}
+	if err := platformconfig.Load(cfg, platformconfig.WithYAML(yamlFile)); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+func (c *Config) Validate() error {
+	errs := []error{platformconfig.ValidatePort("WS_PORT", c.WebSocket.Port)}
+	if c.WebSocket.ReadBufferSize <= 0 || c.WebSocket.WriteBufferSize <= 0 {
+		errs = append(errs, errors.New("WS_READ_BUFFER_SIZE and WS_WRITE_BUFFER_SIZE must be positive"))
+	}
+	if c.Templates.ReloadInterval <= 0 {
+		errs = append(errs, fmt.Errorf("TEMPLATES_RELOAD_INTERVAL must be positive, got %s", c.Templates.ReloadInterval))
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("SHUTDOWN_TIMEOUT must be positive, got %s", c.ShutdownTimeout))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the settings of the exactly-once mode when it is on.
+func (k *Kafka) Validate() error {
+	if !k.ExactlyOnce {
+		return nil
+	}
+	var errs []error
+	if k.OutputTopic == "" {
+		errs = append(errs, errors.New("KAFKA_OUTPUT_TOPIC is required when KAFKA_EXACTLY_ONCE is set"))
+	}
+	if slices.Contains(k.Topics, k.OutputTopic) {
+		errs = append(errs, fmt.Errorf("KAFKA_OUTPUT_TOPIC must differ from the consumed topics, got %q", k.OutputTopic))
+	}
+	if k.TransactionalIDPrefix == "" {
+		errs = append(errs, errors.New("KAFKA_TRANSACTIONAL_ID_PREFIX is required when KAFKA_EXACTLY_ONCE is set"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the email settings when email is on.
+func (e *Email) Validate() error {
+	if e.SMTPHost == "" {
+		return nil
+	}
+	errs := []error{platformconfig.ValidatePort("SMTP_PORT", e.SMTPPort)}
+	if _, err := mail.ParseAddress(e.From); err != nil {
+		errs = append(errs, fmt.Errorf("EMAIL_FROM must be an email address, got %q: %w", e.From, err))
+	}
+	if e.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("EMAIL_MAX_ATTEMPTS must be at least 1, got %d", e.MaxAttempts))
+	}
+	if e.QueueSize < 1 {
+		errs = append(errs, fmt.Errorf("EMAIL_QUEUE_SIZE must be at least 1, got %d", e.QueueSize))
+	}
+	if e.Workers < 1 {
+		errs = append(errs, fmt.Errorf("EMAIL_WORKERS must be at least 1, got %d", e.Workers))
+	}
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"SMTP_TIMEOUT", e.Timeout},
+		{"EMAIL_INITIAL_BACKOFF", e.InitialBackoff},
+		{"EMAIL_MAX_BACKOFF", e.MaxBackoff},
+	} {
+		if d.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", d.name, d.value))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *Webhooks) Validate() error {
+	var errs []error
+	if w.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be at least 1, got %d", w.MaxAttempts))
+	}
+	if w.DisableAfter < 0 {
+		errs = append(errs, fmt.Errorf("WEBHOOK_DISABLE_AFTER must not be negative, got %d", w.DisableAfter))
+	}
+	if w.AdminToken != "" && len(w.AdminToken) < minAdminTokenLength {
+		errs = append(errs, fmt.Errorf("WEBHOOK_ADMIN_TOKEN must have at least %d characters", minAdminTokenLength))
+	}
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"WEBHOOK_TIMEOUT", w.Timeout},
+		{"WEBHOOK_INITIAL_BACKOFF", w.InitialBackoff},
+		{"WEBHOOK_MAX_BACKOFF", w.MaxBackoff},
+	} {
+		if d.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", d.name, d.value))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load("")
+	require.NoError(t, err)
+
+	assert.False(t, cfg.DevMode)
+	assert.Equal(t, 25*time.Second, cfg.ShutdownTimeout)
+	assert.Equal(t, []string{"localhost:9092"}, cfg.Kafka.Brokers)
+	assert.Equal(t, "notification-consumer-group", cfg.Kafka.GroupID)
+	assert.Equal(t, []string{"order-events", "user-events", "inventory-events"}, cfg.Kafka.Topics)
+	assert.Equal(t, "order-events", cfg.Kafka.OrderTopic)
+	assert.False(t, cfg.Kafka.ExactlyOnce)
+	assert.Equal(t, "notifications-outbound", cfg.Kafka.OutputTopic)
+	assert.Equal(t, "notification-service", cfg.Kafka.TransactionalIDPrefix)
+	assert.Equal(t, 20052, cfg.WebSocket.Port)
+	assert.Equal(t, 1024, cfg.WebSocket.ReadBufferSize)
+	assert.Empty(t, cfg.Templates.Dir)
+	assert.Equal(t, 5*time.Second, cfg.Templates.ReloadInterval)
+	assert.Empty(t, cfg.Email.SMTPHost)
+	assert.Equal(t, 587, cfg.Email.SMTPPort)
+	assert.Equal(t, 3, cfg.Email.MaxAttempts)
+	assert.Equal(t, 1000, cfg.Email.QueueSize)
+	assert.Equal(t, 4, cfg.Email.Workers)
+	assert.Equal(t, 5, cfg.Webhooks.MaxAttempts)
+	assert.Equal(t, time.Minute, cfg.Webhooks.MaxBackoff)
+	assert.Equal(t, 5, cfg.Webhooks.DisableAfter)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Setenv("WS_PORT", "not-a-port")
+	t.Setenv("DEV_MODE", "yes please")
+
+	_, err := Load("")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `WS_PORT: invalid integer "not-a-port"`)
+	assert.Contains(t, err.Error(), `DEV_MODE: invalid boolean "yes please"`)
+}
+
+func TestLoad_ExactlyOnce(t *testing.T) {
+	t.Setenv("KAFKA_EXACTLY_ONCE", "true")
+	t.Setenv("KAFKA_OUTPUT_TOPIC", "user-events")
+
+	_, err := Load("")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "KAFKA_OUTPUT_TOPIC must differ from the consumed topics")
+
+	t.Setenv("KAFKA_OUTPUT_TOPIC", "notifications-outbound")
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.True(t, cfg.Kafka.ExactlyOnce)
+}
+
+func TestLoad_Topics(t *testing.T) {
+	t.Setenv("KAFKA_TOPICS", "orders-v2, audit")
+	t.Setenv("KAFKA_ORDER_TOPIC", "orders-v2")
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders-v2", "audit"}, cfg.Kafka.Topics)
+	assert.Equal(t, "orders-v2", cfg.Kafka.OrderTopic)
+}
+
+func TestLoad_Email(t *testing.T) {
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("EMAIL_FROM", "not an address")
+	t.Setenv("EMAIL_MAX_ATTEMPTS", "0")
+	t.Setenv("EMAIL_WORKERS", "0")
+
+	_, err := Load("")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "EMAIL_FROM must be an email address")
+	assert.Contains(t, err.Error(), "EMAIL_MAX_ATTEMPTS must be at least 1")
+	assert.Contains(t, err.Error(), "EMAIL_WORKERS must be at least 1")
+
+	t.Setenv("EMAIL_FROM", "Shop <notifications@shop.example>")
+	t.Setenv("EMAIL_MAX_ATTEMPTS", "5")
+	t.Setenv("EMAIL_WORKERS", "2")
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "smtp.example.com", cfg.Email.SMTPHost)
+	assert.Equal(t, 5, cfg.Email.MaxAttempts)
+}
+
+func TestLoad_Webhooks(t *testing.T) {
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "0")
+	t.Setenv("WEBHOOK_TIMEOUT", "0s")
+	t.Setenv("WEBHOOK_ADMIN_TOKEN", "short")
+
+	_, err := Load("")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WEBHOOK_MAX_ATTEMPTS must be at least 1")
+	assert.Contains(t, err.Error(), "WEBHOOK_TIMEOUT must be positive")
+	assert.Contains(t, err.Error(), "WEBHOOK_ADMIN_TOKEN must have at least 32 characters")
+	assert.NotContains(t, err.Error(), "short", "the token is not echoed")
+}
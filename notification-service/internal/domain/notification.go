@@ -5,9 +5,17 @@ import (
 )
 
 type Notification struct {
-	ID        string    `json:"id"`
-	Type      string    `json:"type"`
-	Message   string    `json:"message"`
+	ID string `json:"id"`
+	// EventID is the ID of the event the notification is about, empty for
+	// events that predate event IDs.
+	EventID string `json:"event_id,omitempty"`
+	// UserID is the user the notification is for, empty for notifications
+	// to everyone.
+	UserID  string `json:"user_id,omitempty"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	// Locale is the locale Message is written in, empty if unknown.
+	Locale    string    `json:"locale,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
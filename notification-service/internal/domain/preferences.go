@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrPreferencesNotFound = errors.New("preferences not found")
+	ErrInvalidLocale       = errors.New("invalid locale")
+	ErrInvalidPreferences  = errors.New("invalid preferences")
+)
+
+// Channels notifications are delivered on.
+const (
+	ChannelWebSocket = "websocket"
+	ChannelEmail     = "email"
+)
+
+// Channels are the known delivery channels.
+var Channels = []string{ChannelWebSocket, ChannelEmail}
+
+// Preferences are the notification settings of a user.
+type Preferences struct {
+	UserID string `json:"user_id"`
+	// Locale is the language tag notifications are written in, e.g. "th";
+	// empty for the language of the event.
+	Locale string `json:"locale,omitempty"`
+	// Email is the address email notifications go to; without one the user
+	// gets none.
+	Email string `json:"email,omitempty"`
+	// Channels are those the user wants notifications on; empty for all.
+	Channels []string `json:"channels,omitempty"`
+}
+
+// Wants reports whether the user wants notifications on channel.
+func (p *Preferences) Wants(channel string) bool {
+	return len(p.Channels) == 0 || slices.Contains(p.Channels, channel)
+}
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// NormalizeLocale lower-cases a language tag and separates its parts with
+// '-', so "pt_BR" and "pt-br" name the same locale. It returns "" for
+// anything that is not a language tag.
+func NormalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if !localePattern.MatchString(locale) {
+		return ""
+	}
+	return locale
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrInvalidSubscription  = errors.New("invalid webhook subscription")
+	ErrSubscriptionDisabled = errors.New("webhook subscription is disabled")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+)
+
+// Subscription is a partner's request to get notifications POSTed to URL.
+type Subscription struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// EventTypes are the types of the notifications delivered, e.g.
+	// "SHIPPED"; empty for all.
+	EventTypes []string `json:"event_types,omitempty"`
+	// Secret signs the deliveries. It is only shown when the subscription
+	// is created.
+	Secret string `json:"secret,omitempty"`
+	// Disabled subscriptions get no deliveries. A subscription is disabled
+	// after FailedDeliveries deliveries in a row failed.
+	Disabled         bool      `json:"disabled"`
+	FailedDeliveries int       `json:"failed_deliveries"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Matches reports whether the subscription wants notifications of
+// eventType.
+func (s *Subscription) Matches(eventType string) bool {
+	return len(s.EventTypes) == 0 || slices.Contains(s.EventTypes, eventType)
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is one notification sent, or to be sent, to a subscription,
+// with the outcome of its attempts.
+type Delivery struct {
+	ID             string `json:"id"`
+	SubscriptionID string `json:"subscription_id"`
+	NotificationID string `json:"notification_id"`
+	EventType      string `json:"event_type"`
+	// RedeliveryOf is the delivery this one sends again, if any.
+	RedeliveryOf string          `json:"redelivery_of,omitempty"`
+	Payload      json.RawMessage `json:"payload"`
+	Status       DeliveryStatus  `json:"status"`
+	Attempts     int             `json:"attempts"`
+	// ResponseStatus is the HTTP status of the last attempt, zero if it got
+	// no response.
+	ResponseStatus int       `json:"response_status,omitempty"`
+	LastError      string    `json:"last_error,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// NewDelivery returns a pending delivery of payload, the notification
+// notificationID of eventType, to subscriptionID.
+func NewDelivery(id, subscriptionID, notificationID, eventType string, payload []byte) *Delivery {
+	now := time.Now()
+	return &Delivery{
+		ID:             id,
+		SubscriptionID: subscriptionID,
+		NotificationID: notificationID,
+		EventType:      eventType,
+		Payload:        payload,
+		Status:         DeliveryPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
@@ -0,0 +1,43 @@
+package usecases
+
+import "sync"
+
+// dedupeWindow is how many event IDs are remembered. Redeliveries come
+// soon after the first delivery, on a rebalance or restart, so a window of
+// recent events is enough.
+const dedupeWindow = 10_000
+
+// recentIDs remembers the last IDs added, forgetting the oldest first.
+type recentIDs struct {
+	mu    sync.Mutex
+	ids   map[string]struct{}
+	order []string
+	next  int
+}
+
+func newRecentIDs(size int) *recentIDs {
+	return &recentIDs{ids: make(map[string]struct{}, size), order: make([]string, 0, size)}
+}
+
+func (r *recentIDs) contains(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.ids[id]
+	return ok
+}
+
+func (r *recentIDs) add(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.ids[id]; ok {
+		return
+	}
+	if len(r.order) < cap(r.order) {
+		r.order = append(r.order, id)
+	} else {
+		delete(r.ids, r.order[r.next])
+		r.order[r.next] = id
+		r.next = (r.next + 1) % len(r.order)
+	}
+	r.ids[id] = struct{}{}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"go.uber.org/zap"
 )
 
@@ -18,6 +19,10 @@ type NotificationUseCase interface {
 type notificationUseCaseImpl struct {
 	logger    *zap.Logger
 	publisher interfaces.NotificationPublisher
+	// delivered holds the event and notification IDs of the
+	// notifications published recently. Kafka delivers at least once, so a
+	// notification of an event seen again is skipped.
+	delivered *recentIDs
 }
 
 var _ NotificationUseCase = (*notificationUseCaseImpl)(nil)
@@ -26,12 +31,19 @@ func NewNotificationUseCase(logger *zap.Logger, publisher interfaces.Notificatio
 	return &notificationUseCaseImpl{
 		logger:    logger,
 		publisher: publisher,
+		delivered: newRecentIDs(dedupeWindow),
 	}
 }
 
+// log returns the request-scoped logger of ctx.
+func (uc *notificationUseCaseImpl) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, uc.logger)
+}
+
 func (uc *notificationUseCaseImpl) ProcessNotification(ctx context.Context, notif *domain.Notification) error {
+	logger := uc.log(ctx)
 	if notif.ID == "" || notif.CreatedAt.IsZero() {
-		uc.logger.Warn("notification missing id or created_at; generating defaults")
+		logger.Warn("notification missing id or created_at; generating defaults")
 		if notif.ID == "" {
 			notif.ID = uuid.NewString()
 		}
@@ -40,16 +52,30 @@ func (uc *notificationUseCaseImpl) ProcessNotification(ctx context.Context, noti
 		}
 	}
 
-	uc.logger.Info("Processing notification",
+	if notif.EventID != "" && uc.delivered.contains(deliveryKey(notif)) {
+		logger.Info("Skipping notification already published",
+			zap.String("id", notif.ID),
+			zap.String("eventID", notif.EventID))
+		return nil
+	}
+
+	logger.Info("Processing notification",
 		zap.String("id", notif.ID),
-		zap.String("type", notif.Type),
-		zap.String("message", notif.Message))
-	fmt.Printf("Processed notification: %+v\n", notif)
+		zap.String("type", notif.Type))
 
 	if err := uc.publisher.PublishNotification(notif); err != nil {
-		uc.logger.Error("failed to publish notification", zap.Error(err))
+		logger.Error("failed to publish notification", zap.Error(err))
 		return fmt.Errorf("publish error: %w", err)
 	}
+	if notif.EventID != "" {
+		uc.delivered.add(deliveryKey(notif))
+	}
 
 	return nil
 }
+
+// deliveryKey identifies a notification across redeliveries of its event;
+// one event may cause several notifications.
+func deliveryKey(notif *domain.Notification) string {
+	return notif.EventID + "/" + notif.ID
+}
@@ -2,13 +2,16 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"notification-service/internal/domain"
 
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 type NoOpPublisher struct{}
@@ -17,6 +20,19 @@ func (n *NoOpPublisher) PublishNotification(notif *domain.Notification) error {
 	return nil
 }
 
+type recordingPublisher struct {
+	published []*domain.Notification
+	err       error
+}
+
+func (r *recordingPublisher) PublishNotification(notif *domain.Notification) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.published = append(r.published, notif)
+	return nil
+}
+
 func TestProcessNotification_GeneratesDefaults(t *testing.T) {
 	logger := zap.NewNop()
 	uc := NewNotificationUseCase(logger, &NoOpPublisher{})
@@ -53,3 +69,60 @@ func TestProcessNotification_UsesExistingValues(t *testing.T) {
 	require.Equal(t, customID, notif.ID, "ID should remain unchanged")
 	require.Equal(t, customTime, notif.CreatedAt, "CreatedAt should remain unchanged")
 }
+
+func TestProcessNotification_LogsWithRequestLogger(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	uc := NewNotificationUseCase(zap.NewNop(), &NoOpPublisher{})
+	ctx := logging.NewContext(context.Background(), zap.New(core), "req-7")
+
+	err := uc.ProcessNotification(ctx, &domain.Notification{ID: "n-1", Type: "ORDER_CREATED", Message: "secret details", CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	require.Equal(t, "req-7", fields[logging.FieldRequestID])
+	require.NotContains(t, fields, "message", "message bodies are not logged")
+}
+
+func TestProcessNotification_SkipsRedeliveredEvents(t *testing.T) {
+	publisher := &recordingPublisher{}
+	uc := NewNotificationUseCase(zap.NewNop(), publisher)
+	ctx := context.Background()
+
+	require.NoError(t, uc.ProcessNotification(ctx, &domain.Notification{ID: "o-1", EventID: "e-1", Type: "ORDER_CREATED"}))
+	require.NoError(t, uc.ProcessNotification(ctx, &domain.Notification{ID: "o-1", EventID: "e-1", Type: "ORDER_CREATED"}))
+	require.NoError(t, uc.ProcessNotification(ctx, &domain.Notification{ID: "o-1", EventID: "e-2", Type: "ORDER_UPDATED"}))
+	require.NoError(t, uc.ProcessNotification(ctx, &domain.Notification{ID: "u-1", EventID: "e-2", Type: "ORDER_UPDATED"}))
+	require.NoError(t, uc.ProcessNotification(ctx, &domain.Notification{ID: "o-2", Type: "ORDER_CREATED"}))
+	require.NoError(t, uc.ProcessNotification(ctx, &domain.Notification{ID: "o-2", Type: "ORDER_CREATED"}))
+
+	require.Len(t, publisher.published, 5, "only the repeated notification of an event is skipped; those without an event ID are always published")
+}
+
+func TestProcessNotification_RetriesFailedPublish(t *testing.T) {
+	publisher := &recordingPublisher{err: errors.New("hub closed")}
+	uc := NewNotificationUseCase(zap.NewNop(), publisher)
+	ctx := context.Background()
+
+	require.Error(t, uc.ProcessNotification(ctx, &domain.Notification{ID: "o-1", EventID: "e-1"}))
+
+	publisher.err = nil
+	require.NoError(t, uc.ProcessNotification(ctx, &domain.Notification{ID: "o-1", EventID: "e-1"}))
+	require.Len(t, publisher.published, 1, "an event whose publish failed is not remembered")
+}
+
+func TestRecentIDs_ForgetsOldest(t *testing.T) {
+	r := newRecentIDs(2)
+	r.add("a")
+	r.add("b")
+	r.add("a")
+	r.add("c")
+
+	require.False(t, r.contains("a"))
+	require.True(t, r.contains("b"))
+	require.True(t, r.contains("c"))
+
+	r.add("d")
+	require.False(t, r.contains("b"))
+	require.True(t, r.contains("d"))
+}
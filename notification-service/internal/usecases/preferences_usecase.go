@@ -0,0 +1,84 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+
+	"notification-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"go.uber.org/zap"
+)
+
+// PreferenceRepository stores the preferences of users. Get returns
+// domain.ErrPreferencesNotFound for a user without preferences.
+type PreferenceRepository interface {
+	Get(ctx context.Context, userID string) (*domain.Preferences, error)
+	Save(ctx context.Context, prefs *domain.Preferences) error
+}
+
+type PreferencesUseCase interface {
+	GetPreferences(ctx context.Context, userID string) (*domain.Preferences, error)
+	UpdatePreferences(ctx context.Context, prefs *domain.Preferences) error
+}
+
+type preferencesUseCaseImpl struct {
+	repo   PreferenceRepository
+	logger *zap.Logger
+}
+
+var _ PreferencesUseCase = (*preferencesUseCaseImpl)(nil)
+
+func NewPreferencesUseCase(repo PreferenceRepository, logger *zap.Logger) PreferencesUseCase {
+	return &preferencesUseCaseImpl{repo: repo, logger: logger}
+}
+
+// GetPreferences returns the preferences of userID, the defaults if the user
+// has set none.
+func (uc *preferencesUseCaseImpl) GetPreferences(ctx context.Context, userID string) (*domain.Preferences, error) {
+	prefs, err := uc.repo.Get(ctx, userID)
+	if errors.Is(err, domain.ErrPreferencesNotFound) {
+		return &domain.Preferences{UserID: userID}, nil
+	}
+	return prefs, err
+}
+
+// UpdatePreferences replaces the preferences of prefs.UserID. The locale is
+// normalized, as is the email address.
+func (uc *preferencesUseCaseImpl) UpdatePreferences(ctx context.Context, prefs *domain.Preferences) error {
+	if prefs.UserID == "" {
+		return fmt.Errorf("user id is required")
+	}
+	if prefs.Locale != "" {
+		locale := domain.NormalizeLocale(prefs.Locale)
+		if locale == "" {
+			return fmt.Errorf("%w: %q", domain.ErrInvalidLocale, prefs.Locale)
+		}
+		prefs.Locale = locale
+	}
+	if prefs.Email != "" {
+		addr, err := mail.ParseAddress(prefs.Email)
+		if err != nil {
+			return fmt.Errorf("%w: email %q: %v", domain.ErrInvalidPreferences, prefs.Email, err)
+		}
+		prefs.Email = addr.Address
+	}
+	for _, channel := range prefs.Channels {
+		if !slices.Contains(domain.Channels, channel) {
+			return fmt.Errorf("%w: unknown channel %q, want one of %s",
+				domain.ErrInvalidPreferences, channel, strings.Join(domain.Channels, ", "))
+		}
+	}
+	if err := uc.repo.Save(ctx, prefs); err != nil {
+		return fmt.Errorf("save preferences: %w", err)
+	}
+	logging.FromContext(ctx, uc.logger).Info("Preferences updated",
+		zap.String("userID", prefs.UserID),
+		zap.String("locale", prefs.Locale),
+		zap.Strings("channels", prefs.Channels))
+	return nil
+}
@@ -0,0 +1,230 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	"notification-service/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"go.uber.org/zap"
+)
+
+// SubscriptionRepository stores webhook subscriptions. Get, Update and
+// Delete return domain.ErrSubscriptionNotFound for unknown IDs.
+type SubscriptionRepository interface {
+	Create(ctx context.Context, sub *domain.Subscription) error
+	Get(ctx context.Context, id string) (*domain.Subscription, error)
+	// List returns the subscriptions in the order they were created.
+	List(ctx context.Context) ([]*domain.Subscription, error)
+	// Update changes the subscription with update atomically and returns
+	// it. If update fails nothing changes.
+	Update(ctx context.Context, id string, update func(*domain.Subscription) error) (*domain.Subscription, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// DeliveryRepository is the log of webhook deliveries. Get returns
+// domain.ErrDeliveryNotFound for unknown IDs.
+type DeliveryRepository interface {
+	// Save adds the delivery or replaces the one with its ID.
+	Save(ctx context.Context, delivery *domain.Delivery) error
+	Get(ctx context.Context, id string) (*domain.Delivery, error)
+	// List returns the deliveries of a subscription, newest first.
+	List(ctx context.Context, subscriptionID string) ([]*domain.Delivery, error)
+}
+
+// DeliveryQueue sends pending deliveries in the background, in order per
+// subscription.
+type DeliveryQueue interface {
+	Enqueue(delivery *domain.Delivery)
+}
+
+// DestinationChecker refuses the URLs webhooks may not be sent to, with an
+// error wrapping domain.ErrInvalidSubscription.
+type DestinationChecker interface {
+	CheckDestination(ctx context.Context, u *url.URL) error
+}
+
+type WebhookUseCase interface {
+	CreateSubscription(ctx context.Context, sub *domain.Subscription) error
+	GetSubscription(ctx context.Context, id string) (*domain.Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]*domain.Subscription, error)
+	UpdateSubscription(ctx context.Context, sub *domain.Subscription) (*domain.Subscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, subscriptionID string) ([]*domain.Delivery, error)
+	Redeliver(ctx context.Context, subscriptionID, deliveryID string) (*domain.Delivery, error)
+}
+
+type webhookUseCaseImpl struct {
+	subscriptions SubscriptionRepository
+	deliveries    DeliveryRepository
+	queue         DeliveryQueue
+	destinations  DestinationChecker
+	logger        *zap.Logger
+}
+
+var _ WebhookUseCase = (*webhookUseCaseImpl)(nil)
+
+func NewWebhookUseCase(subscriptions SubscriptionRepository, deliveries DeliveryRepository, queue DeliveryQueue, destinations DestinationChecker, logger *zap.Logger) WebhookUseCase {
+	return &webhookUseCaseImpl{subscriptions: subscriptions, deliveries: deliveries, queue: queue, destinations: destinations, logger: logger}
+}
+
+// CreateSubscription stores sub with a new ID, and a new secret unless it
+// has one. sub keeps the secret so it can be shown once.
+func (uc *webhookUseCaseImpl) CreateSubscription(ctx context.Context, sub *domain.Subscription) error {
+	if err := uc.validateSubscription(ctx, sub); err != nil {
+		return err
+	}
+	if sub.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return err
+		}
+		sub.Secret = secret
+	}
+	sub.ID = uuid.NewString()
+	sub.Disabled, sub.FailedDeliveries = false, 0
+	sub.CreatedAt = time.Now()
+	if err := uc.subscriptions.Create(ctx, sub); err != nil {
+		return fmt.Errorf("create webhook subscription: %w", err)
+	}
+	logging.FromContext(ctx, uc.logger).Info("Webhook subscription created",
+		zap.String("subscriptionID", sub.ID),
+		zap.String("url", sub.URL),
+		zap.Strings("eventTypes", sub.EventTypes))
+	return nil
+}
+
+// GetSubscription returns the subscription without its secret.
+func (uc *webhookUseCaseImpl) GetSubscription(ctx context.Context, id string) (*domain.Subscription, error) {
+	sub, err := uc.subscriptions.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return redacted(sub), nil
+}
+
+// ListSubscriptions returns the subscriptions without their secrets.
+func (uc *webhookUseCaseImpl) ListSubscriptions(ctx context.Context) ([]*domain.Subscription, error) {
+	subs, err := uc.subscriptions.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, sub := range subs {
+		subs[i] = redacted(sub)
+	}
+	return subs, nil
+}
+
+// UpdateSubscription changes the URL, event types and state of the
+// subscription sub.ID, and its secret if sub has one. Enabling a disabled
+// subscription forgets its failed deliveries.
+func (uc *webhookUseCaseImpl) UpdateSubscription(ctx context.Context, sub *domain.Subscription) (*domain.Subscription, error) {
+	if err := uc.validateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	updated, err := uc.subscriptions.Update(ctx, sub.ID, func(current *domain.Subscription) error {
+		current.URL = sub.URL
+		current.EventTypes = sub.EventTypes
+		if sub.Secret != "" {
+			current.Secret = sub.Secret
+		}
+		if current.Disabled && !sub.Disabled {
+			current.FailedDeliveries = 0
+		}
+		current.Disabled = sub.Disabled
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx, uc.logger).Info("Webhook subscription updated",
+		zap.String("subscriptionID", updated.ID),
+		zap.Bool("disabled", updated.Disabled))
+	return redacted(updated), nil
+}
+
+func (uc *webhookUseCaseImpl) DeleteSubscription(ctx context.Context, id string) error {
+	if err := uc.subscriptions.Delete(ctx, id); err != nil {
+		return err
+	}
+	logging.FromContext(ctx, uc.logger).Info("Webhook subscription deleted", zap.String("subscriptionID", id))
+	return nil
+}
+
+// ListDeliveries returns the delivery log of a subscription, newest first.
+func (uc *webhookUseCaseImpl) ListDeliveries(ctx context.Context, subscriptionID string) ([]*domain.Delivery, error) {
+	if _, err := uc.subscriptions.Get(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	return uc.deliveries.List(ctx, subscriptionID)
+}
+
+// Redeliver queues the payload of a delivery again, as a new delivery. The
+// subscription must be enabled.
+func (uc *webhookUseCaseImpl) Redeliver(ctx context.Context, subscriptionID, deliveryID string) (*domain.Delivery, error) {
+	sub, err := uc.subscriptions.Get(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Disabled {
+		return nil, domain.ErrSubscriptionDisabled
+	}
+	original, err := uc.deliveries.Get(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.SubscriptionID != subscriptionID {
+		return nil, domain.ErrDeliveryNotFound
+	}
+
+	delivery := domain.NewDelivery(uuid.NewString(), subscriptionID, original.NotificationID, original.EventType, original.Payload)
+	delivery.RedeliveryOf = original.ID
+	if err := uc.deliveries.Save(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("save webhook delivery: %w", err)
+	}
+	uc.queue.Enqueue(delivery)
+	logging.FromContext(ctx, uc.logger).Info("Webhook delivery queued again",
+		zap.String("subscriptionID", subscriptionID),
+		zap.String("deliveryID", delivery.ID),
+		zap.String("redeliveryOf", original.ID))
+	return delivery, nil
+}
+
+func (uc *webhookUseCaseImpl) validateSubscription(ctx context.Context, sub *domain.Subscription) error {
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL, got %q", domain.ErrInvalidSubscription, sub.URL)
+	}
+	for _, eventType := range sub.EventTypes {
+		if eventType == "" {
+			return fmt.Errorf("%w: event types must not be empty", domain.ErrInvalidSubscription)
+		}
+	}
+	if sub.Secret != "" && len(sub.Secret) < minSecretLength {
+		return fmt.Errorf("%w: secret must have at least %d characters", domain.ErrInvalidSubscription, minSecretLength)
+	}
+	return uc.destinations.CheckDestination(ctx, u)
+}
+
+const minSecretLength = 16
+
+// newSecret returns 32 random bytes in hex.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func redacted(sub *domain.Subscription) *domain.Subscription {
+	c := *sub
+	c.Secret = ""
+	return &c
+}
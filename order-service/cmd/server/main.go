@@ -2,98 +2,174 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
-	"net"
-	"net/http"
 	"os"
-	"strings"
+	"os/signal"
+	"syscall"
 	"time"
 
 	fiber_http "order-service/internal/adapters/fiber"
 	orderGrpc "order-service/internal/adapters/grpc"
 	"order-service/internal/adapters/kafka"
-	"order-service/internal/adapters/models"
 	"order-service/internal/adapters/repository"
+	"order-service/internal/adapters/schemas"
 	"order-service/internal/clients"
+	"order-service/internal/config"
+	"order-service/internal/domain"
 	"order-service/internal/domain/interfaces"
 	"order-service/internal/usecases"
 
+	"github.com/IBM/sarama"
 	"github.com/gofiber/fiber/v2"
-	"github.com/joho/godotenv"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/breaker"
+	platformconfig "github.com/jakkapat-chongsuwat/go-microservice/platform/config"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/grpcclient"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health/fiberhealth"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health/grpchealth"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health/kafkahealth"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lazy"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lifecycle"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging/fiberlog"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging/grpclog"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics/fibermetrics"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics/gormmetrics"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics/grpcmetrics"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/ratelimit"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/ratelimit/fiberlimit"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/ratelimit/grpclimit"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry/fibertrace"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry/grpctrace"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/inventory_service"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/order_service"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/user_service"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 func main() {
-	loadEnv()
+	configFile := flag.String("config", "", "path to a YAML config file")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration, secrets redacted, and exit")
+	flag.Parse()
 
-	models.LoadSchema("configs/order_event_schema.json")
-	schemaStr := models.OrderEventSchema
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *printConfig {
+		if err := platformconfig.Print(os.Stdout, cfg); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
 	logger := createLogger()
 	defer logger.Sync()
+	logger.Info("Configuration loaded", zap.Any("config", platformconfig.Values(cfg)))
 
-	orderRepo := buildRepository(logger)
+	runner := lifecycle.NewRunner(logger, cfg.ShutdownTimeout)
+	runner.OnStop("tracing", setupTracing(logger))
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	checker := health.NewChecker(0)
+	orderRepo := buildRepository(cfg, logger, checker, runner)
 
-	userSvcConn, err := grpc.DialContext(ctx,
-		getEnv("USER_SERVICE_ADDRESS", "localhost:50051"),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-	)
-	if err != nil {
-		logger.Fatal("failed to dial user service", zap.Error(err))
-	}
+	// Dependencies connect in the background: the servers start right away
+	// and readiness stays down until the dependencies are up.
+	userBreaker := breaker.New("user-service", cfg.Clients.BreakerSettings())
+	userSvcConn := dialService(logger, cfg.UserServiceAddress,
+		grpcclient.DialOptions(cfg.Clients.Policy(user_service.UserService_ServiceDesc.ServiceName, cfg.Clients.UserServiceTimeout), userBreaker)...)
+	runner.OnStop("user-service connection", lifecycle.Close(userSvcConn))
 	realUserClient := clients.NewGRPCUserServiceClient(userSvcConn)
+	checker.Register("user-service", grpchealth.Conn(userSvcConn))
+	checker.Register("user-service-breaker", userBreaker.Check)
 
-	invAddress := getEnv("INVENTORY_SERVICE_ADDRESS", "inventory-service:30051")
-	if err := waitForInventoryService(invAddress, 60*time.Second); err != nil {
-		logger.Fatal("inventory service not reachable", zap.Error(err))
-	}
-
-	invConn, err := grpc.DialContext(ctx,
-		invAddress,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-	)
-	if err != nil {
-		logger.Fatal("failed to dial inventory service", zap.Error(err))
-	}
+	inventoryBreaker := breaker.New("inventory-service", cfg.Clients.BreakerSettings())
+	invConn := dialService(logger, cfg.InventoryServiceAddress,
+		grpcclient.DialOptions(cfg.Clients.Policy(inventory_service.InventoryService_ServiceDesc.ServiceName, cfg.Clients.InventoryServiceTimeout), inventoryBreaker)...)
+	runner.OnStop("inventory-service connection", lifecycle.Close(invConn))
 	realInventoryClient := clients.NewGRPCInventoryServiceClient(invConn)
+	checker.Register("inventory-service", grpchealth.Conn(invConn))
+	checker.Register("inventory-service-breaker", inventoryBreaker.Check)
 
-	kafkaBrokers := strings.Split(getEnv("KAFKA_BROKERS", "localhost:9092"), ",")
-	orderTopic := getEnv("KAFKA_ORDER_TOPIC", "order-events")
-	schemaRegistryURL := getEnv("SCHEMA_REGISTRY_URL", "http://localhost:8081")
-	subject := "order-events-value"
+	kafkaBrokers := cfg.Kafka.Brokers
+	schemaRegistryURL := cfg.Kafka.SchemaRegistryURL
 
-	if err := waitForSchemaRegistry(schemaRegistryURL, 60*time.Second); err != nil {
-		logger.Fatal("schema registry not reachable", zap.Error(err))
+	eventSchemas, err := schemas.Load(cfg.Kafka.SchemaDir)
+	if err != nil {
+		logger.Fatal("failed to load event schemas", zap.Error(err))
 	}
-
-	orderEventProducer, err := kafka.NewOrderEventProducer(kafkaBrokers, orderTopic, schemaRegistryURL, subject, schemaStr)
+	orderEventSchema, err := eventSchemas.Schema(schemas.OrderEvent)
 	if err != nil {
-		logger.Fatal("failed to create order event producer", zap.Error(err))
+		logger.Fatal("failed to load event schemas", zap.Error(err))
+	}
+	orderEventSerializer := kafka.NewOrderEventSerializer(cfg.Kafka.TopicFormat(cfg.Kafka.OrderTopic), orderEventSchema)
+	var compression sarama.CompressionCodec
+	if err := compression.UnmarshalText([]byte(cfg.Kafka.Compression)); err != nil {
+		logger.Fatal("invalid kafka compression", zap.Error(err))
+	}
+	producerOpts := kafka.ProducerOptions{
+		Compression:         compression,
+		FlushFrequency:      cfg.Kafka.FlushFrequency,
+		FlushMessages:       cfg.Kafka.FlushMessages,
+		RetryFailedInterval: cfg.Kafka.RetryFailedInterval,
 	}
-	defer orderEventProducer.Close()
+	var orderEventProducer kafka.Producer
+	if cfg.Kafka.ProducerMode == "async" {
+		orderEventProducer = kafka.NewAsyncOrderEventProducer(kafkaBrokers, cfg.Kafka.OrderTopic, schemaRegistryURL, cfg.Kafka.OrderSubject(), orderEventSerializer, producerOpts, logger)
+	} else {
+		orderEventProducer = kafka.NewOrderEventProducer(kafkaBrokers, cfg.Kafka.OrderTopic, schemaRegistryURL, cfg.Kafka.OrderSubject(), orderEventSerializer, producerOpts, logger)
+	}
+	runner.OnStop("kafka producer", lifecycle.Close(orderEventProducer))
+	checker.Register("order-event-producer", orderEventProducer.Check)
+
+	kafkaClient := lazy.Connect("kafka client", logger, func(ctx context.Context) (sarama.Client, error) {
+		return sarama.NewClient(kafkaBrokers, sarama.NewConfig())
+	})
+	runner.OnStop("kafka client", lifecycle.Close(kafkaClient))
+	checker.Register("kafka", func(ctx context.Context) error {
+		client, err := kafkaClient.Get()
+		if err != nil {
+			return err
+		}
+		return kafkahealth.Brokers(client)(ctx)
+	})
+	checker.Register("schema-registry", kafkahealth.SchemaRegistry(nil, schemaRegistryURL))
 
 	orderUseCase := usecases.NewOrderUsecase(orderRepo, realUserClient, realInventoryClient, orderEventProducer, logger)
 
-	go startGRPC(logger, orderUseCase)
-
-	startHTTP(logger, orderUseCase)
+	healthServer := grpchealth.NewServer()
+	runner.Go("health sync", func(ctx context.Context) error {
+		grpchealth.Sync(ctx, healthServer, checker, 0, order_service.OrderService_ServiceDesc.ServiceName)
+		return nil
+	})
+	limiter := cfg.RateLimit.Limiter(ratelimit.WithClock(func() time.Time { return domain.Clock.Now() }))
+	runner.Add("grpc", newGRPCServer(cfg.GRPCPort, logger, orderUseCase, healthServer, limiter))
+	runner.Add("http", newHTTPServer(cfg.HTTPPort, logger, orderUseCase, checker, limiter))
+
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := runner.Run(sigCtx); err != nil {
+		logger.Error("Order service stopped with errors", zap.Error(err))
+		logger.Sync()
+		os.Exit(1)
+	}
+	logger.Info("Order service stopped")
 }
 
-func loadEnv() {
-	if err := godotenv.Load(".env"); err != nil {
-		log.Println("Warning: no .env file found, proceeding...")
+func setupTracing(logger *zap.Logger) func(context.Context) error {
+	shutdown, err := telemetry.Setup(context.Background(), telemetry.ConfigFromEnv("order-service"))
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", zap.Error(err))
 	}
+	return shutdown
 }
 
 func createLogger() *zap.Logger {
@@ -104,106 +180,92 @@ func createLogger() *zap.Logger {
 	return logger
 }
 
-func buildRepository(logger *zap.Logger) interfaces.IOrderRepository {
-	repoType := getEnv("REPO_TYPE", "gorm")
-	switch repoType {
+func buildRepository(cfg *config.Config, logger *zap.Logger, checker *health.Checker, runner *lifecycle.Runner) interfaces.IOrderRepository {
+	switch cfg.RepoType {
 	case "gorm":
-		return buildGormRepo(logger)
+		return buildGormRepo(cfg.Database, logger, checker, runner)
 	default:
-		return buildGormRepo(logger)
+		return buildGormRepo(cfg.Database, logger, checker, runner)
 	}
 }
 
-func buildGormRepo(logger *zap.Logger) interfaces.IOrderRepository {
-	dbDriver := getEnv("DB_DRIVER", "postgres")
-	db, err := connectGorm(dbDriver, logger)
+func buildGormRepo(dbConfig platformconfig.Database, logger *zap.Logger, checker *health.Checker, runner *lifecycle.Runner) interfaces.IOrderRepository {
+	db, err := connectGorm(dbConfig, logger)
 	if err != nil {
 		logger.Fatal("Failed to connect GORM DB", zap.Error(err))
 	}
+	if err := db.Use(gormmetrics.Plugin{}); err != nil {
+		logger.Fatal("Failed to install GORM metrics", zap.Error(err))
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		logger.Fatal("Failed to get database handle", zap.Error(err))
+	}
+	checker.Register("database", health.Ping(sqlDB))
+	runner.OnStop("database", lifecycle.Close(sqlDB))
 	return repository.NewGormOrderRepo(db, logger)
 }
 
-func connectGorm(driver string, logger *zap.Logger) (*gorm.DB, error) {
-	switch driver {
+// connectGorm opens the database without connecting: the pool connects on
+// first use, and the "database" readiness check reports it until then, so
+// order-service starts whether or not the database is up.
+func connectGorm(dbConfig platformconfig.Database, logger *zap.Logger) (*gorm.DB, error) {
+	switch dbConfig.Driver {
 	case "mysql":
-		return connectMySQL(logger)
+		return connectMySQL(dbConfig, logger)
 	default:
-		return connectPostgres(logger)
+		return connectPostgres(dbConfig, logger)
 	}
 }
 
-func connectPostgres(logger *zap.Logger) (*gorm.DB, error) {
-	host := getEnv("DB_HOST", "localhost")
-	port := getEnv("DB_PORT", "5432")
-	user := getEnv("DB_USER", "devuser")
-	pass := getEnv("DB_PASS", "devpass")
-	dbname := getEnv("DB_NAME", "order_service")
-	dbschema := getEnv("DB_SCHEMA", "public")
-	sslmode := getEnv("DB_SSLMODE", "disable")
-
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s search_path=%s",
-		host, port, user, pass, dbname, sslmode, dbschema)
-	logger.Info("Connecting to Postgres", zap.String("dsn", dsn))
-
-	return gorm.Open(postgres.Open(dsn), &gorm.Config{})
-}
-
-func connectMySQL(logger *zap.Logger) (*gorm.DB, error) {
-	host := getEnv("DB_HOST", "localhost")
-	port := getEnv("DB_PORT", "3306")
-	user := getEnv("DB_USER", "root")
-	pass := getEnv("DB_PASS", "")
-	dbname := getEnv("DB_NAME", "order_service")
-
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", user, pass, host, port, dbname)
-	logger.Info("Connecting to MySQL", zap.String("dsn", dsn))
+func connectPostgres(dbConfig platformconfig.Database, logger *zap.Logger) (*gorm.DB, error) {
+	dsn := dbConfig.DSN()
+	logger.Info("Connecting to Postgres", zap.Stringer("database", dbConfig))
 
-	return gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	return gorm.Open(postgres.Open(dsn), &gorm.Config{DisableAutomaticPing: true})
 }
 
-func startGRPC(logger *zap.Logger, uc interfaces.IOrderUseCase) {
-	port := getEnv("GRPC_PORT", "60051")
-	if err := orderGrpc.StartGRPCServer(port, uc, logger); err != nil {
-		logger.Fatal("failed to start gRPC server", zap.Error(err))
-	}
-}
+func connectMySQL(dbConfig platformconfig.Database, logger *zap.Logger) (*gorm.DB, error) {
+	dsn := dbConfig.DSN()
+	logger.Info("Connecting to MySQL", zap.Stringer("database", dbConfig))
 
-func startHTTP(logger *zap.Logger, uc interfaces.IOrderUseCase) {
-	app := fiber.New()
-	fiber_http.RegisterOrderRoutes(app, fiber_http.NewOrderHTTPHandler(uc, logger))
-	port := getEnv("HTTP_PORT", "8080")
-	log.Fatal(app.Listen(":" + port))
+	// Querying the server version would connect; gorm then assumes a
+	// current MySQL.
+	return gorm.Open(mysql.New(mysql.Config{DSN: dsn, SkipInitializeWithVersion: true}), &gorm.Config{DisableAutomaticPing: true})
 }
 
-func getEnv(key, fallback string) string {
-	val := os.Getenv(key)
-	if val == "" {
-		return fallback
+// dialService creates a client connection without waiting for the server.
+// gRPC connects in the background and reconnects with backoff; calls made
+// while the server is down fail fast with Unavailable.
+func dialService(logger *zap.Logger, address string, opts ...grpc.DialOption) *grpc.ClientConn {
+	conn, err := grpc.NewClient(address, append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpctrace.DialOption(),
+		grpcmetrics.DialOption(),
+		grpclog.DialOption(),
+	}, opts...)...)
+	if err != nil {
+		logger.Fatal("Invalid gRPC target", zap.String("address", address), zap.Error(err))
 	}
-	return val
+	conn.Connect()
+	return conn
 }
 
-func waitForInventoryService(address string, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	for time.Now().Before(deadline) {
-		conn, err := net.DialTimeout("tcp", address, 2*time.Second)
-		if err == nil {
-			conn.Close()
-			return nil
-		}
-		time.Sleep(2 * time.Second)
-	}
-	return fmt.Errorf("inventory service %s not reachable after %s", address, timeout)
+func newGRPCServer(port int, logger *zap.Logger, uc interfaces.IOrderUseCase, healthServer grpc_health_v1.HealthServer, limiter *ratelimit.Limiter) lifecycle.Component {
+	opts := append(grpcmetrics.ServerOptions(), grpctrace.ServerOption())
+	opts = append(opts, grpclog.ServerOptions(logger)...)
+	opts = append(opts, grpclimit.ServerOptions(limiter, logger)...)
+	srv := orderGrpc.NewGRPCServer(uc, logger, healthServer, opts...)
+	logger.Info("Starting gRPC server", zap.Int("port", port))
+	return lifecycle.GRPC(srv, fmt.Sprintf(":%d", port))
 }
 
-func waitForSchemaRegistry(url string, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	for time.Now().Before(deadline) {
-		resp, err := http.Head(url)
-		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			return nil
-		}
-		time.Sleep(2 * time.Second)
-	}
-	return fmt.Errorf("schema registry %s not reachable after %s", url, timeout)
+func newHTTPServer(port int, logger *zap.Logger, uc interfaces.IOrderUseCase, checker *health.Checker, limiter *ratelimit.Limiter) lifecycle.Component {
+	app := fiber.New()
+	app.Use(fibertrace.Middleware(), fiberlog.Middleware(logger), fibermetrics.Middleware(), fiberlimit.Middleware(limiter, logger))
+	app.Get("/metrics", fibermetrics.Handler())
+	fiberhealth.Register(app, checker)
+	fiber_http.RegisterOrderRoutes(app, fiber_http.NewOrderHTTPHandler(uc, logger))
+	logger.Info("Starting HTTP server", zap.Int("port", port))
+	return lifecycle.Fiber(app, fmt.Sprintf(":%d", port))
 }
@@ -8,7 +8,10 @@ import (
 	"order-service/internal/domain/interfaces"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type OrderHTTPHandler struct {
@@ -23,12 +26,15 @@ func NewOrderHTTPHandler(orderUC interfaces.IOrderUseCase, logger *zap.Logger) *
 	}
 }
 
-func (h *OrderHTTPHandler) CreateOrder(c *fiber.Ctx) error {
-	h.logger.Info("CreateOrder endpoint called")
+// log returns the request-scoped logger of c.
+func (h *OrderHTTPHandler) log(c *fiber.Ctx) *zap.Logger {
+	return logging.FromContext(c.UserContext(), h.logger)
+}
 
+func (h *OrderHTTPHandler) CreateOrder(c *fiber.Ctx) error {
 	var req models.CreateOrderRequest
 	if err := c.BodyParser(&req); err != nil {
-		h.logger.Error("failed to parse request body", zap.Error(err))
+		h.log(c).Error("failed to parse request body", zap.Error(err))
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "invalid request payload",
 		})
@@ -48,7 +54,7 @@ func (h *OrderHTTPHandler) CreateOrder(c *fiber.Ctx) error {
 
 	order, items, err := mappers.HTTPCreateOrderRequestToDomain(req)
 	if err != nil {
-		h.logger.Error("mapping failed", zap.Error(err))
+		h.log(c).Error("mapping failed", zap.Error(err))
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -57,14 +63,21 @@ func (h *OrderHTTPHandler) CreateOrder(c *fiber.Ctx) error {
 	ctx := c.UserContext()
 	createdOrder, err := h.orderUseCase.CreateOrderWithItems(ctx, order, items)
 	if err != nil {
-		h.logger.Error("CreateOrderWithItems failed", zap.Error(err))
+		h.log(c).Error("CreateOrderWithItems failed", zap.Error(err))
 		if strings.Contains(err.Error(), "record not found") {
-			h.logger.Error("user not found")
+			h.log(c).Error("user not found")
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error": "user not found",
 			})
 		}
-		h.logger.Error("failed to create order", zap.Error(err))
+		if status.Code(err) == codes.Unavailable {
+			// A downstream service is down or its circuit breaker is open;
+			// the client can retry once it is back.
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": "a required service is unavailable, try again later",
+			})
+		}
+		h.log(c).Error("failed to create order", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -17,6 +18,8 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type FakeOrderUseCase struct {
@@ -207,3 +210,24 @@ func TestCreateOrder_UseCaseError(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 }
+
+func TestCreateOrder_DependencyUnavailable(t *testing.T) {
+	fakeUC := &FakeOrderUseCase{
+		CreateOrderWithItemsFunc: func(ctx context.Context, order *domain.Order, items []*domain.OrderItem) (*domain.Order, error) {
+			return nil, fmt.Errorf("failed to verify user: %w", status.Error(codes.Unavailable, "connection refused"))
+		},
+	}
+	app := fiber.New()
+	RegisterOrderRoutes(app, NewOrderHTTPHandler(fakeUC, zap.NewNop()))
+
+	body, err := json.Marshal(models.CreateOrderRequest{
+		UserID: "user1",
+		Items:  []models.OrderItemRequest{{ProductID: "prod1", Quantity: 2}},
+	})
+	assert.NoError(t, err)
+	req := httptest.NewRequest("POST", "/api/orders", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
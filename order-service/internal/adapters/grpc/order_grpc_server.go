@@ -6,6 +6,7 @@ import (
 	"order-service/internal/adapters/models"
 	"order-service/internal/domain/interfaces"
 
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"github.com/jakkapat-chongsuwat/go-microservice/proto/order_service"
 	"go.uber.org/zap"
 )
@@ -23,9 +24,12 @@ func NewOrderGRPCServer(orderUC interfaces.IOrderUseCase, logger *zap.Logger) *O
 	}
 }
 
-func (s *OrderGRPCServer) CreateOrder(ctx context.Context, req *order_service.CreateOrderRequest) (*order_service.CreateOrderResponse, error) {
-	s.logger.Info("Received CreateOrder request", zap.String("userID", req.UserId))
+// log returns the request-scoped logger of ctx.
+func (s *OrderGRPCServer) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, s.logger)
+}
 
+func (s *OrderGRPCServer) CreateOrder(ctx context.Context, req *order_service.CreateOrderRequest) (*order_service.CreateOrderResponse, error) {
 	var itemReqs []models.OrderItemRequest
 	for _, protoItem := range req.Items {
 		itemReq := mappers.ProtoOrderItemToModel(protoItem)
@@ -34,7 +38,7 @@ func (s *OrderGRPCServer) CreateOrder(ctx context.Context, req *order_service.Cr
 
 	createdOrder, err := s.orderUseCase.CreateOrder(ctx, req.UserId, itemReqs)
 	if err != nil {
-		s.logger.Error("CreateOrder failed", zap.Error(err))
+		s.log(ctx).Error("CreateOrder failed", zap.Error(err))
 		return nil, err
 	}
 
@@ -55,11 +59,9 @@ func (s *OrderGRPCServer) CreateOrder(ctx context.Context, req *order_service.Cr
 }
 
 func (s *OrderGRPCServer) GetOrder(ctx context.Context, req *order_service.GetOrderRequest) (*order_service.GetOrderResponse, error) {
-	s.logger.Info("Received GetOrder request", zap.String("orderID", req.OrderId))
-
 	dOrder, err := s.orderUseCase.GetOrder(ctx, req.OrderId)
 	if err != nil {
-		s.logger.Error("GetOrder failed", zap.Error(err))
+		s.log(ctx).Error("GetOrder failed", zap.Error(err))
 		return nil, err
 	}
 
@@ -3,32 +3,29 @@
 package grpc
 
 import (
-	"fmt"
-	"net"
 	"order-service/internal/domain/interfaces"
 
 	"github.com/jakkapat-chongsuwat/go-microservice/proto/order_service"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
-func StartGRPCServer(port string, orderUseCase interfaces.IOrderUseCase, logger *zap.Logger) error {
-	lis, err := net.Listen("tcp", ":"+port)
-	if err != nil {
-		return fmt.Errorf("failed to listen on port %s: %w", port, err)
-	}
-
-	grpcServer := grpc.NewServer()
+// NewGRPCServer registers the OrderService and the health service on a new
+// gRPC server. The caller serves it and stops it.
+func NewGRPCServer(orderUseCase interfaces.IOrderUseCase, logger *zap.Logger, healthServer grpc_health_v1.HealthServer, opts ...grpc.ServerOption) *grpc.Server {
+	grpcServer := grpc.NewServer(opts...)
 
 	order_service.RegisterOrderServiceServer(
 		grpcServer,
 		NewOrderGRPCServer(orderUseCase, logger),
 	)
 
+	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
+
 	// for testing purpose
 	reflection.Register(grpcServer)
 
-	logger.Info("Starting OrderService gRPC server", zap.String("port", port))
-	return grpcServer.Serve(lis)
+	return grpcServer
 }
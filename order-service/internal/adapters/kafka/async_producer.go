@@ -0,0 +1,293 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/internal/domain"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/jakkapat-chongsuwat/go-microservice/events/serde"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lazy"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics/kafkametrics"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry/kafkatrace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Delivery is the result of sending an order event asynchronously.
+type Delivery struct {
+	Event     domain.OrderEvent
+	Partition int32
+	Offset    int64
+	Err       error
+}
+
+// maxFailed bounds the failed events kept for a retry. When it is reached
+// the oldest are dropped.
+const maxFailed = 1000
+
+// AsyncOrderEventProducer buffers order events and sends them in batches.
+// Results arrive in the background: they are logged, passed to the callback
+// of the send, and failed events are kept and sent again every
+// RetryFailedInterval. A retried event arrives after the events sent since,
+// so consumers must not rely on the order of the events of an order.
+type AsyncOrderEventProducer struct {
+	orderEventEncoder
+	producer *lazy.Value[*asyncProducer]
+	logger   *zap.Logger
+
+	mu     sync.Mutex
+	failed []*pendingEvent
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// pendingEvent travels with a message as its metadata until its result
+// arrives.
+type pendingEvent struct {
+	// ctx is the context of the send without its cancellation, for the
+	// logs and a retry after the request has ended.
+	ctx      context.Context
+	event    domain.OrderEvent
+	callback func(Delivery)
+	span     trace.Span
+	start    time.Time
+}
+
+// NewAsyncOrderEventProducer connects in the background like
+// NewOrderEventProducer.
+func NewAsyncOrderEventProducer(brokers []string, topic, schemaRegistryURL, subject string, serializer serde.Serializer, opts ProducerOptions, logger *zap.Logger) *AsyncOrderEventProducer {
+	newProducer := func(ctx context.Context) (sarama.AsyncProducer, error) {
+		config := newProducerConfig(opts)
+		config.Producer.Flush.Frequency = opts.FlushFrequency
+		config.Producer.Flush.Messages = opts.FlushMessages
+		prod, err := sarama.NewAsyncProducer(brokers, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kafka producer: %w", err)
+		}
+		return prod, nil
+	}
+
+	return newAsyncOrderEventProducer(topic, serializer, schemaRegistration(schemaRegistryURL, subject, serializer), newProducer, opts.RetryFailedInterval, logger)
+}
+
+func newAsyncOrderEventProducer(
+	topic string,
+	serializer serde.Serializer,
+	registerSchema func(context.Context) (int, error),
+	newProducer func(context.Context) (sarama.AsyncProducer, error),
+	retryInterval time.Duration,
+	logger *zap.Logger,
+	opts ...lazy.Option,
+) *AsyncOrderEventProducer {
+	p := &AsyncOrderEventProducer{
+		orderEventEncoder: newOrderEventEncoder(topic, serializer, registerSchema, logger, opts...),
+		logger:            logger,
+		stop:              make(chan struct{}),
+		stopped:           make(chan struct{}),
+	}
+	p.producer = lazy.Connect("kafka producer", logger, func(ctx context.Context) (*asyncProducer, error) {
+		producer, err := newProducer(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return p.dispatch(producer), nil
+	}, opts...)
+	go p.retryFailed(retryInterval)
+	return p
+}
+
+// SendOrderEvent buffers event and returns. It fails only if the event
+// cannot be encoded or the producer is not connected; send failures are
+// logged and retried.
+func (p *AsyncOrderEventProducer) SendOrderEvent(ctx context.Context, event domain.OrderEvent) error {
+	return p.SendOrderEventAsync(ctx, event, nil)
+}
+
+// SendOrderEventAsync is SendOrderEvent with a callback for the result. A
+// failed event is retried and callback is called again for every attempt.
+func (p *AsyncOrderEventProducer) SendOrderEventAsync(ctx context.Context, event domain.OrderEvent, callback func(Delivery)) error {
+	producer, err := p.producer.Get()
+	if err != nil {
+		return err
+	}
+	return p.send(ctx, producer, &pendingEvent{ctx: context.WithoutCancel(ctx), event: event, callback: callback})
+}
+
+func (p *AsyncOrderEventProducer) send(ctx context.Context, producer *asyncProducer, pending *pendingEvent) error {
+	msg, err := p.message(pending.ctx, pending.event)
+	if err != nil {
+		return err
+	}
+	_, pending.span = kafkatrace.StartProducerSpan(pending.ctx, msg)
+	pending.start = time.Now()
+	msg.Metadata = pending
+
+	select {
+	case producer.Input() <- msg:
+		return nil
+	case <-ctx.Done():
+		pending.span.End()
+		return ctx.Err()
+	}
+}
+
+// asyncProducer is a producer whose results are being dispatched.
+type asyncProducer struct {
+	sarama.AsyncProducer
+	done chan struct{}
+}
+
+// Close sends the buffered events and waits for their results.
+func (a *asyncProducer) Close() error {
+	a.AsyncClose()
+	<-a.done
+	return nil
+}
+
+func (p *AsyncOrderEventProducer) dispatch(producer sarama.AsyncProducer) *asyncProducer {
+	a := &asyncProducer{AsyncProducer: producer, done: make(chan struct{})}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for msg := range producer.Successes() {
+			p.delivered(msg, nil)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for perr := range producer.Errors() {
+			p.delivered(perr.Msg, perr.Err)
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(a.done)
+	}()
+	return a
+}
+
+func (p *AsyncOrderEventProducer) delivered(msg *sarama.ProducerMessage, err error) {
+	pending := msg.Metadata.(*pendingEvent)
+	kafkametrics.ObserveProduce(p.topic, pending.start, err)
+	kafkatrace.EndProducerSpan(pending.span, msg.Partition, msg.Offset, err)
+
+	logger := logging.FromContext(pending.ctx, p.logger)
+	if err != nil {
+		logger.Error("failed to send order event", zap.String("eventID", pending.event.EventID), zap.Error(err))
+		p.recordFailure(pending)
+	} else {
+		logger.Debug("Order event sent",
+			zap.String("topic", p.topic),
+			zap.Int32("partition", msg.Partition),
+			zap.Int64("offset", msg.Offset),
+			zap.Int("size", msg.Value.Length()))
+	}
+
+	if pending.callback != nil {
+		pending.callback(Delivery{Event: pending.event, Partition: msg.Partition, Offset: msg.Offset, Err: err})
+	}
+}
+
+func (p *AsyncOrderEventProducer) recordFailure(pending *pendingEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failed = append(p.failed, pending)
+	p.dropExcessFailed()
+}
+
+// requeueFailed puts the events a retry did not buffer back ahead of those
+// that failed since.
+func (p *AsyncOrderEventProducer) requeueFailed(unsent []*pendingEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failed = slices.Concat(unsent, p.failed)
+	p.dropExcessFailed()
+}
+
+// dropExcessFailed drops the oldest failed events beyond maxFailed. p.mu
+// must be held.
+func (p *AsyncOrderEventProducer) dropExcessFailed() {
+	for len(p.failed) > maxFailed {
+		p.logger.Warn("Dropping failed order event, too many awaiting a retry", zap.String("eventID", p.failed[0].event.EventID))
+		p.failed = p.failed[1:]
+	}
+}
+
+// Failed returns the events whose send failed and that await a retry.
+func (p *AsyncOrderEventProducer) Failed() []domain.OrderEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	events := make([]domain.OrderEvent, len(p.failed))
+	for i, pending := range p.failed {
+		events[i] = pending.event
+	}
+	return events
+}
+
+// RetryFailed sends the failed events again and returns how many it
+// buffered. Those it cannot buffer stay failed.
+func (p *AsyncOrderEventProducer) RetryFailed(ctx context.Context) (int, error) {
+	producer, err := p.producer.Get()
+	if err != nil {
+		return 0, err
+	}
+	p.mu.Lock()
+	failed := p.failed
+	p.failed = nil
+	p.mu.Unlock()
+
+	for i, pending := range failed {
+		if err := p.send(ctx, producer, pending); err != nil {
+			p.requeueFailed(failed[i:])
+			return i, err
+		}
+	}
+	return len(failed), nil
+}
+
+func (p *AsyncOrderEventProducer) retryFailed(interval time.Duration) {
+	defer close(p.stopped)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			if len(p.Failed()) == 0 {
+				continue
+			}
+			n, err := p.RetryFailed(context.Background())
+			if err != nil {
+				p.logger.Warn("Retrying failed order events", zap.Int("sent", n), zap.Error(err))
+				continue
+			}
+			p.logger.Info("Retried failed order events", zap.Int("sent", n))
+		}
+	}
+}
+
+// Check fails until the schema is registered and the producer connected.
+func (p *AsyncOrderEventProducer) Check(ctx context.Context) error {
+	return errors.Join(p.schemaID.Check(ctx), p.producer.Check(ctx))
+}
+
+// Close sends the buffered events and waits for their results. Events that
+// still fail are logged and lost.
+func (p *AsyncOrderEventProducer) Close() error {
+	close(p.stop)
+	<-p.stopped
+	err := errors.Join(p.schemaID.Close(), p.producer.Close())
+	if failed := p.Failed(); len(failed) > 0 {
+		p.logger.Error("Order events not sent", zap.Int("count", len(failed)))
+	}
+	return err
+}
@@ -0,0 +1,139 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"order-service/internal/domain"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/jakkapat-chongsuwat/go-microservice/events/serde"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lazy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewProducerConfig(t *testing.T) {
+	config := newProducerConfig(ProducerOptions{Compression: sarama.CompressionZSTD})
+
+	require.NoError(t, config.Validate())
+	assert.True(t, config.Producer.Idempotent)
+	assert.Equal(t, sarama.WaitForAll, config.Producer.RequiredAcks)
+	assert.Equal(t, 1, config.Net.MaxOpenRequests)
+	assert.Equal(t, sarama.CompressionZSTD, config.Producer.Compression)
+}
+
+func newTestAsyncProducer(t *testing.T, mockProducer *mocks.AsyncProducer) *AsyncOrderEventProducer {
+	t.Helper()
+	p := newAsyncOrderEventProducer("order-events", NewOrderEventSerializer(serde.Avro, orderEventSchema(t)),
+		func(context.Context) (int, error) { return 7, nil },
+		func(context.Context) (sarama.AsyncProducer, error) { return mockProducer, nil },
+		time.Hour, zap.NewNop())
+	require.Eventually(t, func() bool { return p.Check(context.Background()) == nil }, time.Second, time.Millisecond)
+	return p
+}
+
+func orderEvent(orderID string) domain.OrderEvent {
+	order := domain.NewOrder("user-1")
+	order.ID = orderID
+	return domain.NewOrderEvent(domain.EventTypeOrderCreated, order, "")
+}
+
+func receive(t *testing.T, deliveries <-chan Delivery) Delivery {
+	t.Helper()
+	select {
+	case d := <-deliveries:
+		return d
+	case <-time.After(time.Second):
+		t.Fatal("no delivery")
+		return Delivery{}
+	}
+}
+
+func TestAsyncOrderEventProducer_RetriesFailedEvents(t *testing.T) {
+	mockProducer := mocks.NewAsyncProducer(t, newProducerConfig(ProducerOptions{}))
+	p := newTestAsyncProducer(t, mockProducer)
+	defer p.Close()
+	deliveries := make(chan Delivery, 3)
+	callback := func(d Delivery) { deliveries <- d }
+
+	mockProducer.ExpectInputAndSucceed()
+	require.NoError(t, p.SendOrderEventAsync(context.Background(), orderEvent("order-1"), callback))
+	d := receive(t, deliveries)
+	require.NoError(t, d.Err)
+	assert.Equal(t, "order-1", d.Event.Order.OrderID)
+	assert.Equal(t, int64(1), d.Offset)
+
+	errBroker := errors.New("not enough in-sync replicas")
+	mockProducer.ExpectInputAndFail(errBroker)
+	require.NoError(t, p.SendOrderEventAsync(context.Background(), orderEvent("order-2"), callback))
+	d = receive(t, deliveries)
+	assert.ErrorIs(t, d.Err, errBroker)
+	require.Len(t, p.Failed(), 1)
+	assert.Equal(t, "order-2", p.Failed()[0].Order.OrderID)
+
+	mockProducer.ExpectInputAndSucceed()
+	sent, err := p.RetryFailed(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	d = receive(t, deliveries)
+	require.NoError(t, d.Err)
+	assert.Equal(t, "order-2", d.Event.Order.OrderID)
+	assert.Empty(t, p.Failed())
+}
+
+func TestAsyncOrderEventProducer_BoundsRequeuedEvents(t *testing.T) {
+	p := newTestAsyncProducer(t, mocks.NewAsyncProducer(t, newProducerConfig(ProducerOptions{})))
+	defer p.Close()
+	pending := func(orderID string) *pendingEvent {
+		return &pendingEvent{ctx: context.Background(), event: orderEvent(orderID)}
+	}
+
+	// A retry took order-1 and order-2 but could not buffer them, while
+	// maxFailed other events failed.
+	for i := 0; i < maxFailed; i++ {
+		p.recordFailure(pending("order-new"))
+	}
+	p.requeueFailed([]*pendingEvent{pending("order-1"), pending("order-2")})
+
+	failed := p.Failed()
+	assert.Len(t, failed, maxFailed)
+	for _, event := range failed {
+		assert.Equal(t, "order-new", event.Order.OrderID, "the oldest events are dropped")
+	}
+
+	p.requeueFailed([]*pendingEvent{pending("order-3")})
+	assert.Equal(t, "order-new", p.Failed()[0].Order.OrderID)
+	assert.Len(t, p.Failed(), maxFailed)
+}
+
+func TestAsyncOrderEventProducer_CloseWaitsForResults(t *testing.T) {
+	mockProducer := mocks.NewAsyncProducer(t, newProducerConfig(ProducerOptions{}))
+	p := newTestAsyncProducer(t, mockProducer)
+	deliveries := make(chan Delivery, 2)
+
+	mockProducer.ExpectInputAndSucceed()
+	mockProducer.ExpectInputAndSucceed()
+	for _, id := range []string{"order-1", "order-2"} {
+		require.NoError(t, p.SendOrderEventAsync(context.Background(), orderEvent(id), func(d Delivery) { deliveries <- d }))
+	}
+	require.NoError(t, p.Close())
+
+	assert.Len(t, deliveries, 2)
+}
+
+func TestAsyncOrderEventProducer_NotReady(t *testing.T) {
+	p := newAsyncOrderEventProducer("order-events", NewOrderEventSerializer(serde.Avro, orderEventSchema(t)),
+		func(context.Context) (int, error) { return 0, errors.New("connection refused") },
+		func(context.Context) (sarama.AsyncProducer, error) { return nil, errors.New("connection refused") },
+		time.Hour, zap.NewNop(), lazy.WithBackoff(lazy.Backoff{Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 1}))
+	defer p.Close()
+
+	assert.ErrorIs(t, p.SendOrderEvent(context.Background(), orderEvent("order-1")), lazy.ErrNotReady)
+	_, err := p.RetryFailed(context.Background())
+	assert.ErrorIs(t, err, lazy.ErrNotReady)
+}
@@ -0,0 +1,266 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"order-service/internal/adapters/mappers"
+	"order-service/internal/adapters/schemas"
+	"order-service/internal/domain"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const v1SchemaPath = "testdata/order_event_v1.avsc"
+
+func orderEventSchema(t *testing.T) *schemas.Schema {
+	t.Helper()
+	registry, err := schemas.Load("")
+	require.NoError(t, err)
+	schema, err := registry.Schema(schemas.OrderEvent)
+	require.NoError(t, err)
+	return schema
+}
+
+func readSchema(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func pricedOrderEvent() domain.OrderEvent {
+	order := domain.NewOrder("user-1")
+	order.ID = "order-1"
+	order.CreatedAt = time.UnixMilli(1_700_000_000_000).UTC()
+	order.Items = []*domain.OrderItem{domain.NewOrderItem("product-1", 2), domain.NewOrderItem("product-2", 1)}
+	event := domain.NewOrderEvent(domain.EventTypeOrderCreated, order, "req-1")
+	event.Order.Items[0].UnitPrice = &domain.Money{AmountMinor: 1999, CurrencyCode: "USD"}
+	event.Order.Items[1].UnitPrice = &domain.Money{AmountMinor: 500, CurrencyCode: "USD"}
+	event.Order.Total = &domain.Money{AmountMinor: 4498, CurrencyCode: "USD"}
+	return event
+}
+
+// The registry checks the same rules when the schema is registered; see
+// TestProducerIntegration. This catches an incompatible change without one.
+func TestOrderEventSchema_IsBackwardCompatible(t *testing.T) {
+	current := orderEventSchema(t).Definition
+	v1 := readSchema(t, v1SchemaPath)
+
+	assert.NoError(t, checkBackward(current, v1), "the current schema must read version 1 events")
+	assert.NoError(t, checkBackward(current, current))
+}
+
+func TestOrderEventSchema_RejectsIncompatibleChange(t *testing.T) {
+	v1 := readSchema(t, v1SchemaPath)
+	withoutDefault := `{"type": "record", "name": "OrderEvent", "namespace": "com.example.order", "fields": [
+		{"name": "order_id", "type": "string"},
+		{"name": "user_id", "type": "string"}
+	]}`
+	retyped := `{"type": "record", "name": "OrderEvent", "namespace": "com.example.order", "fields": [
+		{"name": "order_id", "type": "long"}
+	]}`
+
+	assert.ErrorContains(t, checkBackward(withoutDefault, v1), "user_id")
+	assert.ErrorContains(t, checkBackward(retyped, v1), "order_id")
+}
+
+// Consumers decode with the writer's schema from the registry and read the
+// version 1 fields by name. Whatever they get back from a version 2 event
+// must still be a valid version 1 event.
+func TestOrderEventSchema_VersionOneConsumersReadNewEvents(t *testing.T) {
+	current := orderEventSchema(t).Codec
+	v1, err := goavro.NewCodec(readSchema(t, v1SchemaPath))
+	require.NoError(t, err)
+	event := pricedOrderEvent()
+
+	binary, err := mappers.DomainOrderEventToAvro(event).EncodeAvro(current)
+	require.NoError(t, err)
+	native, _, err := current.NativeFromBinary(binary)
+	require.NoError(t, err)
+
+	asV1, err := v1.BinaryFromNative(nil, native)
+	require.NoError(t, err, "the version 1 fields of a new event must encode as version 1")
+	old, _, err := v1.NativeFromBinary(asV1)
+	require.NoError(t, err)
+	fields := old.(map[string]interface{})
+
+	assert.Equal(t, "order-1", fields["order_id"])
+	assert.Equal(t, domain.EventTypeOrderCreated, fields["event_type"])
+	assert.Equal(t, "Order created for items: product-1, product-2", fields["message"])
+	assert.Equal(t, event.Timestamp.UnixMilli(), fields["timestamp"].(time.Time).UnixMilli())
+}
+
+// checkBackward reports whether data written with writer can be read with
+// reader, following the Avro schema resolution rules for the types the order
+// events use. Logical types are compared by their underlying type.
+func checkBackward(reader, writer string) error {
+	var r, w interface{}
+	if err := json.Unmarshal([]byte(reader), &r); err != nil {
+		return fmt.Errorf("reader schema: %w", err)
+	}
+	if err := json.Unmarshal([]byte(writer), &w); err != nil {
+		return fmt.Errorf("writer schema: %w", err)
+	}
+	c := compatChecker{readerNames: map[string]interface{}{}, writerNames: map[string]interface{}{}}
+	collectNames(r, "", c.readerNames)
+	collectNames(w, "", c.writerNames)
+	return c.canRead(r, w, "")
+}
+
+type compatChecker struct {
+	readerNames, writerNames map[string]interface{}
+}
+
+var promotions = map[string][]string{
+	"int":    {"long", "float", "double"},
+	"long":   {"float", "double"},
+	"float":  {"double"},
+	"string": {"bytes"},
+	"bytes":  {"string"},
+}
+
+func (c compatChecker) canRead(reader, writer interface{}, path string) error {
+	reader = resolveName(reader, c.readerNames)
+	writer = resolveName(writer, c.writerNames)
+
+	if branches, ok := writer.([]interface{}); ok {
+		for _, branch := range branches {
+			if err := c.canRead(reader, branch, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if branches, ok := reader.([]interface{}); ok {
+		for _, branch := range branches {
+			if c.canRead(branch, writer, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: no branch of reader union %v reads %v", path, reader, typeName(writer))
+	}
+
+	rt, wt := typeName(reader), typeName(writer)
+	if rt != wt {
+		for _, promoted := range promotions[wt] {
+			if promoted == rt {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: reader type %s cannot read writer type %s", path, rt, wt)
+	}
+
+	switch rt {
+	case "record":
+		rm, wm := reader.(map[string]interface{}), writer.(map[string]interface{})
+		writerFields := map[string]interface{}{}
+		for _, f := range wm["fields"].([]interface{}) {
+			field := f.(map[string]interface{})
+			writerFields[field["name"].(string)] = field["type"]
+		}
+		for _, f := range rm["fields"].([]interface{}) {
+			field := f.(map[string]interface{})
+			name := field["name"].(string)
+			wType, ok := writerFields[name]
+			if !ok {
+				if _, hasDefault := field["default"]; !hasDefault {
+					return fmt.Errorf("%s.%s: field added without a default", path, name)
+				}
+				continue
+			}
+			if err := c.canRead(field["type"], wType, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		return c.canRead(reader.(map[string]interface{})["items"], writer.(map[string]interface{})["items"], path+"[]")
+	case "map":
+		return c.canRead(reader.(map[string]interface{})["values"], writer.(map[string]interface{})["values"], path+"{}")
+	case "enum":
+		rm := reader.(map[string]interface{})
+		if _, hasDefault := rm["default"]; hasDefault {
+			return nil
+		}
+		symbols := map[interface{}]bool{}
+		for _, s := range rm["symbols"].([]interface{}) {
+			symbols[s] = true
+		}
+		for _, s := range writer.(map[string]interface{})["symbols"].([]interface{}) {
+			if !symbols[s] {
+				return fmt.Errorf("%s: reader enum lacks symbol %v", path, s)
+			}
+		}
+	}
+	return nil
+}
+
+// typeName is the Avro type of a resolved schema: a primitive name or the
+// complex type.
+func typeName(schema interface{}) string {
+	switch s := schema.(type) {
+	case string:
+		return s
+	case map[string]interface{}:
+		return typeName(s["type"])
+	case []interface{}:
+		return "union"
+	}
+	return fmt.Sprintf("%v", schema)
+}
+
+// resolveName replaces a reference to a named type by its definition.
+func resolveName(schema interface{}, names map[string]interface{}) interface{} {
+	if name, ok := schema.(string); ok {
+		if def, ok := names[name]; ok {
+			return def
+		}
+	}
+	if m, ok := schema.(map[string]interface{}); ok {
+		if name, ok := m["type"].(string); ok {
+			if def, ok := names[name]; ok {
+				return def
+			}
+		}
+	}
+	return schema
+}
+
+// collectNames records the named types defined in schema under their full
+// and short names.
+func collectNames(schema interface{}, namespace string, names map[string]interface{}) {
+	switch s := schema.(type) {
+	case []interface{}:
+		for _, branch := range s {
+			collectNames(branch, namespace, names)
+		}
+	case map[string]interface{}:
+		switch s["type"] {
+		case "record", "enum", "fixed":
+			if ns, ok := s["namespace"].(string); ok {
+				namespace = ns
+			}
+			name := s["name"].(string)
+			names[name] = s
+			if namespace != "" {
+				names[namespace+"."+name] = s
+			}
+			if fields, ok := s["fields"].([]interface{}); ok {
+				for _, f := range fields {
+					collectNames(f.(map[string]interface{})["type"], namespace, names)
+				}
+			}
+		case "array":
+			collectNames(s["items"], namespace, names)
+		case "map":
+			collectNames(s["values"], namespace, names)
+		default:
+			collectNames(s["type"], namespace, names)
+		}
+	}
+}
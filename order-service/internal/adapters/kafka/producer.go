@@ -1,96 +1,201 @@
 package kafka
 
 import (
-	"bytes"
-	"encoding/binary"
+	"context"
+	"errors"
 	"fmt"
+	"order-service/internal/adapters/mappers"
+	"order-service/internal/adapters/schemas"
 	"order-service/internal/domain"
+	"time"
 
 	"github.com/IBM/sarama"
-	"github.com/linkedin/goavro/v2"
+	"github.com/jakkapat-chongsuwat/go-microservice/events/serde"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lazy"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging/kafkalog"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics/kafkametrics"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry/kafkatrace"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/events"
 	"github.com/riferrei/srclient"
+	"go.uber.org/zap"
 )
 
-type OrderEventProducer struct {
-	producer sarama.SyncProducer
-	topic    string
-	srClient *srclient.SchemaRegistryClient
-	codec    *goavro.Codec
-	schemaID int
+// Producer sends order events. OrderEventProducer waits for each event to
+// be written; AsyncOrderEventProducer batches them.
+type Producer interface {
+	SendOrderEvent(ctx context.Context, event domain.OrderEvent) error
+	Check(ctx context.Context) error
+	Close() error
 }
 
-func NewOrderEventProducer(brokers []string, topic, schemaRegistryURL, subject, schemaStr string) (*OrderEventProducer, error) {
-	srClient := srclient.CreateSchemaRegistryClient(schemaRegistryURL)
-
-	registeredSchema, err := srClient.CreateSchema(subject, schemaStr, srclient.Avro)
-	if err != nil {
-		return nil, fmt.Errorf("failed to register schema: %w", err)
-	}
-	schemaID := registeredSchema.ID()
-
-	codec, err := goavro.NewCodec(schemaStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create avro codec: %w", err)
-	}
+// ProducerOptions tune the Kafka producer.
+type ProducerOptions struct {
+	Compression sarama.CompressionCodec
+	// FlushFrequency and FlushMessages batch the sends of the async
+	// producer: a batch is sent when either is reached.
+	FlushFrequency time.Duration
+	FlushMessages  int
+	// RetryFailedInterval is how often the async producer sends the events
+	// that failed again.
+	RetryFailedInterval time.Duration
+}
 
+// newProducerConfig configures an idempotent producer: the broker drops the
+// duplicates of retried sends, and with one request in flight per broker
+// the events of an order stay in order as sarama sends them. Every event is
+// acknowledged by all in-sync replicas. The async producer sends failed
+// events again after the events that followed them, so in async mode the
+// events of an order can arrive out of order.
+func newProducerConfig(opts ProducerOptions) *sarama.Config {
 	config := sarama.NewConfig()
-	config.Producer.Return.Successes = true
 	config.Version = sarama.V4_0_0_0
+	config.Producer.Idempotent = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Net.MaxOpenRequests = 1
+	config.Producer.Compression = opts.Compression
+	config.Producer.Return.Successes = true
+	config.Producer.Return.Errors = true
+	return config
+}
 
-	prod, err := sarama.NewSyncProducer(brokers, config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create kafka producer: %w", err)
+type OrderEventProducer struct {
+	orderEventEncoder
+	producer *lazy.Value[sarama.SyncProducer]
+	logger   *zap.Logger
+}
+
+// NewOrderEventSerializer serializes order events in format: Avro events
+// with schema, protobuf events as the OrderEvent message of proto/events.
+func NewOrderEventSerializer(format serde.Format, schema *schemas.Schema) serde.Serializer {
+	if format == serde.Protobuf {
+		return serde.NewProtobufSerializer(events.OrderEventsProto, (&events.OrderEvent{}).ProtoReflect().Descriptor())
 	}
+	return serde.NewAvroSerializer(schema.Definition, schema.Codec)
+}
 
+// NewOrderEventProducer returns without waiting for Kafka or the schema
+// registry: the schema is registered and the producer connected in the
+// background, and SendOrderEvent fails with lazy.ErrNotReady until both are
+// done.
+func NewOrderEventProducer(brokers []string, topic, schemaRegistryURL, subject string, serializer serde.Serializer, opts ProducerOptions, logger *zap.Logger) *OrderEventProducer {
+	newProducer := func(ctx context.Context) (sarama.SyncProducer, error) {
+		prod, err := sarama.NewSyncProducer(brokers, newProducerConfig(opts))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kafka producer: %w", err)
+		}
+		return prod, nil
+	}
+
+	return newOrderEventProducer(topic, serializer, schemaRegistration(schemaRegistryURL, subject, serializer), newProducer, logger)
+}
+
+func newOrderEventProducer(
+	topic string,
+	serializer serde.Serializer,
+	registerSchema func(context.Context) (int, error),
+	newProducer func(context.Context) (sarama.SyncProducer, error),
+	logger *zap.Logger,
+	opts ...lazy.Option,
+) *OrderEventProducer {
 	return &OrderEventProducer{
-		producer: prod,
-		topic:    topic,
-		srClient: srClient,
-		codec:    codec,
-		schemaID: schemaID,
-	}, nil
+		orderEventEncoder: newOrderEventEncoder(topic, serializer, registerSchema, logger, opts...),
+		producer:          lazy.Connect("kafka producer", logger, newProducer, opts...),
+		logger:            logger,
+	}
 }
 
-// [magic byte (0)] + [4-byte schema ID] + [Avro payload]
-func (p *OrderEventProducer) SendOrderEvent(event domain.OrderEvent) error {
-	native := map[string]interface{}{
-		"order_id":   event.OrderID,
-		"event_type": event.EventType,
-		"message":    event.Message,
-		"timestamp":  event.Timestamp.UnixMilli(),
+// SendOrderEvent writes event in Confluent's wire format, in the format of
+// the producer's serializer, and waits until all in-sync replicas have it.
+//
+// The trace context and request ID from ctx are written to the message
+// headers.
+func (p *OrderEventProducer) SendOrderEvent(ctx context.Context, event domain.OrderEvent) error {
+	producer, err := p.producer.Get()
+	if err != nil {
+		return err
+	}
+	msg, err := p.message(ctx, event)
+	if err != nil {
+		return err
 	}
 
-	avroPayload, err := p.codec.BinaryFromNative(nil, native)
+	_, span := kafkatrace.StartProducerSpan(ctx, msg)
+	start := time.Now()
+	partition, offset, err := producer.SendMessage(msg)
+	kafkametrics.ObserveProduce(p.topic, start, err)
+	kafkatrace.EndProducerSpan(span, partition, offset, err)
 	if err != nil {
-		return fmt.Errorf("failed to encode avro message: %w", err)
+		return fmt.Errorf("failed to send message: %w", err)
 	}
+	logging.FromContext(ctx, p.logger).Debug("Order event sent",
+		zap.String("topic", p.topic),
+		zap.Int32("partition", partition),
+		zap.Int64("offset", offset),
+		zap.Int("size", msg.Value.Length()))
+	return nil
+}
+
+// Check fails until the schema is registered and the producer connected.
+func (p *OrderEventProducer) Check(ctx context.Context) error {
+	return errors.Join(p.schemaID.Check(ctx), p.producer.Check(ctx))
+}
 
-	fmt.Printf("Avro payload size: %d bytes\n", len(avroPayload))
+func (p *OrderEventProducer) Close() error {
+	return errors.Join(p.schemaID.Close(), p.producer.Close())
+}
 
-	var buf bytes.Buffer
-	buf.WriteByte(0)
-	if err := binary.Write(&buf, binary.BigEndian, uint32(p.schemaID)); err != nil {
-		return fmt.Errorf("failed to write schema id: %w", err)
+// schemaRegistration registers the serializer's schema under subject.
+func schemaRegistration(schemaRegistryURL, subject string, serializer serde.Serializer) func(context.Context) (int, error) {
+	srClient := srclient.CreateSchemaRegistryClient(schemaRegistryURL)
+	return func(ctx context.Context) (int, error) {
+		registeredSchema, err := srClient.CreateSchema(subject, serializer.Schema(), serializer.SchemaType())
+		if err != nil {
+			return 0, fmt.Errorf("failed to register schema: %w", err)
+		}
+		return registeredSchema.ID(), nil
 	}
-	buf.Write(avroPayload)
+}
 
-	totalMessage := buf.Bytes()
-	fmt.Printf("Total message size: %d bytes\n", len(totalMessage))
+// orderEventEncoder turns order events into messages of a topic.
+type orderEventEncoder struct {
+	schemaID   *lazy.Value[int]
+	topic      string
+	serializer serde.Serializer
+}
 
-	msg := &sarama.ProducerMessage{
-		Topic: p.topic,
-		Key:   sarama.StringEncoder(event.OrderID),
-		Value: sarama.ByteEncoder(totalMessage),
+func newOrderEventEncoder(topic string, serializer serde.Serializer, registerSchema func(context.Context) (int, error), logger *zap.Logger, opts ...lazy.Option) orderEventEncoder {
+	return orderEventEncoder{
+		schemaID:   lazy.Connect("order event schema", logger, registerSchema, opts...),
+		topic:      topic,
+		serializer: serializer,
 	}
+}
 
-	partition, offset, err := p.producer.SendMessage(msg)
+// message encodes event, keyed by order so the events of an order share a
+// partition, with the request ID from ctx in the headers.
+func (e *orderEventEncoder) message(ctx context.Context, event domain.OrderEvent) (*sarama.ProducerMessage, error) {
+	schemaID, err := e.schemaID.Get()
 	if err != nil {
-		return fmt.Errorf("failed to send message: %w", err)
+		return nil, err
 	}
-	fmt.Printf("Message sent to partition %d, offset %d\n", partition, offset)
-	return nil
+	value, err := e.serializer.Serialize(schemaID, orderEventValue(e.serializer.Format(), event))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s message: %w", e.serializer.Format(), err)
+	}
+
+	msg := &sarama.ProducerMessage{
+		Topic: e.topic,
+		Key:   sarama.StringEncoder(event.Order.OrderID),
+		Value: sarama.ByteEncoder(value),
+	}
+	kafkalog.Inject(ctx, msg)
+	return msg, nil
 }
 
-func (p *OrderEventProducer) Close() error {
-	return p.producer.Close()
+func orderEventValue(format serde.Format, event domain.OrderEvent) any {
+	if format == serde.Protobuf {
+		return mappers.DomainOrderEventToProto(event)
+	}
+	return mappers.DomainOrderEventToAvro(event)
 }
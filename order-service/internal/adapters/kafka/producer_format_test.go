@@ -0,0 +1,85 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"order-service/internal/domain"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/jakkapat-chongsuwat/go-microservice/events/orderevents"
+	"github.com/jakkapat-chongsuwat/go-microservice/events/serde"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/events"
+	"github.com/riferrei/srclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// registeredAs serves the schema of serializer under id, as the registry
+// does once the producer has registered it.
+type registeredAs struct {
+	id         int
+	serializer serde.Serializer
+}
+
+func (r registeredAs) GetSchema(int) (*srclient.Schema, error) {
+	return srclient.NewSchema(r.id, r.serializer.Schema(), r.serializer.SchemaType(), 1, nil, nil, nil)
+}
+
+// sendOrderEvent sends event with serializer and returns the message value.
+func sendOrderEvent(t *testing.T, serializer serde.Serializer, event domain.OrderEvent) []byte {
+	t.Helper()
+	mockProducer := mocks.NewSyncProducer(t, nil)
+	p := newOrderEventProducer("order-events", serializer,
+		func(context.Context) (int, error) { return 7, nil },
+		func(context.Context) (sarama.SyncProducer, error) { return mockProducer, nil },
+		zap.NewNop())
+	defer p.Close()
+	require.Eventually(t, func() bool { return p.Check(context.Background()) == nil }, time.Second, time.Millisecond)
+
+	var value []byte
+	mockProducer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		var err error
+		value, err = msg.Value.Encode()
+		return err
+	})
+	require.NoError(t, p.SendOrderEvent(context.Background(), event))
+	return value
+}
+
+func TestOrderEventProducer_Formats(t *testing.T) {
+	event := pricedOrderEvent()
+
+	t.Run("avro", func(t *testing.T) {
+		serializer := NewOrderEventSerializer(serde.Avro, orderEventSchema(t))
+		d := serde.NewDeserializer(registeredAs{7, serializer})
+
+		msg, err := d.Parse(sendOrderEvent(t, serializer, event))
+		require.NoError(t, err)
+		assert.Equal(t, serde.Avro, msg.Format)
+		var got orderevents.OrderEvent
+		require.NoError(t, d.DecodeAvro(msg, &got))
+		assert.Equal(t, "order-1", got.OrderID)
+		assert.Equal(t, int64(4498), got.Order.Total.AmountMinor)
+	})
+
+	t.Run("protobuf", func(t *testing.T) {
+		serializer := NewOrderEventSerializer(serde.Protobuf, orderEventSchema(t))
+		assert.Equal(t, events.OrderEventsProto, serializer.Schema())
+		d := serde.NewDeserializer(registeredAs{7, serializer})
+
+		value := sendOrderEvent(t, serializer, event)
+		assert.Equal(t, []byte{0, 0, 0, 0, 7, 0}, value[:6], "header and message index of the first message")
+		msg, err := d.Parse(value)
+		require.NoError(t, err)
+		assert.Equal(t, serde.Protobuf, msg.Format)
+		var got events.OrderEvent
+		require.NoError(t, serde.DecodeProtobuf(msg, &got))
+		assert.Equal(t, "order-1", got.OrderId)
+		assert.Equal(t, "req-1", got.CorrelationId)
+		assert.Equal(t, int64(4498), got.Order.Total.AmountMinor)
+	})
+}
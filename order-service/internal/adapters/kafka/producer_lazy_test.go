@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"order-service/internal/domain"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/jakkapat-chongsuwat/go-microservice/events/serde"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lazy"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestOrderEventProducer_RegistersSchemaInBackground(t *testing.T) {
+	var attempts atomic.Int32
+	registryUp := make(chan struct{})
+	registerSchema := func(ctx context.Context) (int, error) {
+		attempts.Add(1)
+		select {
+		case <-registryUp:
+			return 7, nil
+		default:
+			return 0, errors.New("connection refused")
+		}
+	}
+	mockProducer := mocks.NewSyncProducer(t, nil)
+	newProducer := func(ctx context.Context) (sarama.SyncProducer, error) { return mockProducer, nil }
+
+	p := newOrderEventProducer("order-events", NewOrderEventSerializer(serde.Avro, orderEventSchema(t)), registerSchema, newProducer, zap.NewNop(),
+		lazy.WithBackoff(lazy.Backoff{Initial: time.Millisecond, Max: 2 * time.Millisecond, Multiplier: 2}))
+	defer p.Close()
+
+	order := domain.NewOrder("user-1")
+	order.ID = "order-1"
+	event := domain.NewOrderEvent(domain.EventTypeOrderCreated, order, "")
+	require.Eventually(t, func() bool { return attempts.Load() > 1 }, time.Second, time.Millisecond)
+	assert.ErrorIs(t, p.SendOrderEvent(context.Background(), event), lazy.ErrNotReady)
+	assert.ErrorIs(t, p.Check(context.Background()), lazy.ErrNotReady)
+
+	close(registryUp)
+	require.Eventually(t, func() bool { return p.Check(context.Background()) == nil }, time.Second, time.Millisecond)
+
+	mockProducer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		value, err := msg.Value.Encode()
+		if err != nil {
+			return err
+		}
+		if id := binary.BigEndian.Uint32(value[1:5]); id != 7 {
+			return errors.New("unexpected schema id")
+		}
+		for _, h := range msg.Headers {
+			if string(h.Key) == logging.MetadataRequestID && string(h.Value) == "req-1" {
+				return nil
+			}
+		}
+		return errors.New("request ID header missing")
+	})
+	assert.NoError(t, p.SendOrderEvent(logging.WithRequestID(context.Background(), "req-1"), event))
+}
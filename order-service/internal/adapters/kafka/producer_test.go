@@ -7,13 +7,16 @@ import (
 	"fmt"
 	"io"
 	"order-service/internal/adapters/kafka"
-	"order-service/internal/adapters/models"
+	"order-service/internal/adapters/schemas"
 	"order-service/internal/domain"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/IBM/sarama"
+	"github.com/jakkapat-chongsuwat/go-microservice/events/serde"
 	"github.com/linkedin/goavro/v2"
+	"github.com/riferrei/srclient"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
 	tcKafka "github.com/testcontainers/testcontainers-go/modules/kafka"
@@ -96,22 +99,37 @@ func TestProducerIntegration(t *testing.T) {
 	require.NoError(t, err)
 	schemaRegistryURL := fmt.Sprintf("http://%s:%s", srHost, srPort.Port())
 
-	models.LoadSchema("../../../configs/order_event_schema.json")
-	schemaStr := models.OrderEventSchema
+	registry, err := schemas.Load("")
+	require.NoError(t, err)
+	schema, err := registry.Schema(schemas.OrderEvent)
+	require.NoError(t, err)
+	schemaStr := schema.Definition
 
 	subject := "order-events-value"
 	topic := "order-events"
 
-	producer, err := kafka.NewOrderEventProducer(brokers, topic, schemaRegistryURL, subject, schemaStr)
+	// The subject starts at version 1, as in existing deployments, and must
+	// accept version 2 under BACKWARD compatibility.
+	v1Schema, err := os.ReadFile("testdata/order_event_v1.avsc")
+	require.NoError(t, err)
+	srClient := srclient.CreateSchemaRegistryClient(schemaRegistryURL)
+	_, err = srClient.ChangeSubjectCompatibilityLevel(subject, srclient.Backward)
+	require.NoError(t, err)
+	_, err = srClient.CreateSchema(subject, string(v1Schema), srclient.Avro)
 	require.NoError(t, err)
+	compatible, err := srClient.IsSchemaCompatible(subject, schemaStr, "latest", srclient.Avro)
+	require.NoError(t, err)
+	require.True(t, compatible, "order event schema is not BACKWARD compatible with version 1")
+
+	producer := kafka.NewOrderEventProducer(brokers, topic, schemaRegistryURL, subject, kafka.NewOrderEventSerializer(serde.Avro, schema), kafka.ProducerOptions{}, logger)
 	defer producer.Close()
+	require.Eventually(t, func() bool { return producer.Check(ctx) == nil }, 30*time.Second, 100*time.Millisecond)
 
-	event := domain.OrderEvent{
-		OrderID:   "test-order-1",
-		EventType: "CREATED",
-		Timestamp: time.Now(),
-	}
-	err = producer.SendOrderEvent(event)
+	order := domain.NewOrder("test-user-1")
+	order.ID = "test-order-1"
+	order.Items = []*domain.OrderItem{domain.NewOrderItem("product-1", 2)}
+	event := domain.NewOrderEvent(domain.EventTypeOrderCreated, order, "req-1")
+	err = producer.SendOrderEvent(ctx, event)
 	require.NoError(t, err)
 
 	consumer, err := sarama.NewConsumer(brokers, nil)
@@ -149,7 +167,8 @@ func TestProducerIntegration(t *testing.T) {
 			require.Fail(t, fmt.Sprintf("timestamp is not numeric, got %T", nativeMap["timestamp"]))
 		}
 
-		require.Equal(t, event.OrderID, nativeMap["order_id"])
+		require.Equal(t, event.Order.OrderID, nativeMap["order_id"])
+		require.Equal(t, event.EventID, nativeMap["event_id"])
 		require.Equal(t, event.EventType, nativeMap["event_type"])
 		require.InDelta(t, event.Timestamp.UnixMilli(), ts, 1000, "timestamp mismatch")
 	case <-time.After(180 * time.Second):
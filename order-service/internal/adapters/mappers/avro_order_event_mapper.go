@@ -0,0 +1,50 @@
+package mappers
+
+import (
+	"order-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/events/orderevents"
+)
+
+func DomainOrderEventToAvro(event domain.OrderEvent) *orderevents.OrderEvent {
+	items := make([]orderevents.OrderItem, 0, len(event.Order.Items))
+	for _, item := range event.Order.Items {
+		items = append(items, orderevents.OrderItem{
+			ProductID: item.ProductID,
+			Quantity:  int32(item.Quantity),
+			UnitPrice: domainMoneyToAvro(item.UnitPrice),
+		})
+	}
+
+	var correlationID *string
+	if event.CorrelationID != "" {
+		correlationID = &event.CorrelationID
+	}
+
+	return &orderevents.OrderEvent{
+		OrderID:       event.Order.OrderID,
+		EventType:     event.EventType,
+		Message:       event.Message,
+		Timestamp:     event.Timestamp,
+		EventID:       event.EventID,
+		SchemaVersion: int32(event.SchemaVersion),
+		CorrelationID: correlationID,
+		Source:        event.Source,
+		Order: &orderevents.Order{
+			OrderID:       event.Order.OrderID,
+			UserID:        event.Order.UserID,
+			Status:        string(event.Order.Status),
+			Items:         items,
+			TotalQuantity: int32(event.Order.TotalQuantity),
+			Total:         domainMoneyToAvro(event.Order.Total),
+			CreatedAt:     event.Order.CreatedAt,
+		},
+	}
+}
+
+func domainMoneyToAvro(m *domain.Money) *orderevents.Money {
+	if m == nil {
+		return nil
+	}
+	return &orderevents.Money{AmountMinor: m.AmountMinor, CurrencyCode: m.CurrencyCode}
+}
@@ -0,0 +1,52 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"order-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/events/orderevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainOrderEventToAvro(t *testing.T) {
+	order := domain.NewOrder("user-1")
+	order.ID = "order-1"
+	order.CreatedAt = time.UnixMilli(1_700_000_000_000).UTC()
+	order.Items = []*domain.OrderItem{domain.NewOrderItem("product-1", 2), domain.NewOrderItem("product-2", 1)}
+	event := domain.NewOrderEvent(domain.EventTypeOrderCreated, order, "req-1")
+	event.Timestamp = time.UnixMilli(1_700_000_001_000).UTC()
+	event.Order.Items[0].UnitPrice = &domain.Money{AmountMinor: 1999, CurrencyCode: "USD"}
+
+	record := DomainOrderEventToAvro(event)
+
+	require.NotNil(t, record.CorrelationID)
+	assert.Equal(t, "req-1", *record.CorrelationID)
+	assert.Equal(t, &orderevents.Order{
+		OrderID: "order-1",
+		UserID:  "user-1",
+		Status:  "CREATED",
+		Items: []orderevents.OrderItem{
+			{ProductID: "product-1", Quantity: 2, UnitPrice: &orderevents.Money{AmountMinor: 1999, CurrencyCode: "USD"}},
+			{ProductID: "product-2", Quantity: 1},
+		},
+		TotalQuantity: 3,
+		CreatedAt:     order.CreatedAt,
+	}, record.Order)
+
+	data, err := record.MarshalAvro()
+	require.NoError(t, err)
+	var decoded orderevents.OrderEvent
+	require.NoError(t, decoded.UnmarshalAvro(data))
+	assert.Equal(t, *record, decoded)
+}
+
+func TestDomainOrderEventToAvro_WithoutCorrelationID(t *testing.T) {
+	record := DomainOrderEventToAvro(domain.NewOrderEvent(domain.EventTypeOrderCreated, domain.NewOrder("user-1"), ""))
+
+	assert.Nil(t, record.CorrelationID)
+	_, err := record.MarshalAvro()
+	assert.NoError(t, err)
+}
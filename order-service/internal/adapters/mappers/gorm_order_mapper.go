@@ -30,8 +30,10 @@ type Converter interface {
 	// goverter:ignore CreatedAt
 	// goverter:ignore UpdatedAt
 	// goverter:ignore DeletedAt
+	// goverter:ignore UnitPrice
 	DomainToGormOrderItem(source domain.OrderItem) models.GormDBOrderItem
 
+	// goverter:ignore UnitPrice
 	GormToDomainOrderItem(source models.GormDBOrderItem) domain.OrderItem
 }
 
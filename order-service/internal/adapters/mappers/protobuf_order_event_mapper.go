@@ -0,0 +1,46 @@
+package mappers
+
+import (
+	"order-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/events"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func DomainOrderEventToProto(event domain.OrderEvent) *events.OrderEvent {
+	items := make([]*events.OrderItem, 0, len(event.Order.Items))
+	for _, item := range event.Order.Items {
+		items = append(items, &events.OrderItem{
+			ProductId: item.ProductID,
+			Quantity:  int32(item.Quantity),
+			UnitPrice: domainMoneyToProto(item.UnitPrice),
+		})
+	}
+
+	return &events.OrderEvent{
+		OrderId:       event.Order.OrderID,
+		EventType:     event.EventType,
+		Message:       event.Message,
+		Timestamp:     timestamppb.New(event.Timestamp),
+		EventId:       event.EventID,
+		SchemaVersion: int32(event.SchemaVersion),
+		CorrelationId: event.CorrelationID,
+		Source:        event.Source,
+		Order: &events.Order{
+			OrderId:       event.Order.OrderID,
+			UserId:        event.Order.UserID,
+			Status:        string(event.Order.Status),
+			Items:         items,
+			TotalQuantity: int32(event.Order.TotalQuantity),
+			Total:         domainMoneyToProto(event.Order.Total),
+			CreatedAt:     timestamppb.New(event.Order.CreatedAt),
+		},
+	}
+}
+
+func domainMoneyToProto(m *domain.Money) *events.Money {
+	if m == nil {
+		return nil
+	}
+	return &events.Money{AmountMinor: m.AmountMinor, CurrencyCode: m.CurrencyCode}
+}
@@ -0,0 +1,47 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"order-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDomainOrderEventToProto(t *testing.T) {
+	order := domain.NewOrder("user-1")
+	order.ID = "order-1"
+	order.CreatedAt = time.UnixMilli(1_700_000_000_000).UTC()
+	order.Items = []*domain.OrderItem{domain.NewOrderItem("product-1", 2), domain.NewOrderItem("product-2", 1)}
+	event := domain.NewOrderEvent(domain.EventTypeOrderCreated, order, "req-1")
+	event.Order.Items[0].UnitPrice = &domain.Money{AmountMinor: 1999, CurrencyCode: "USD"}
+
+	message := DomainOrderEventToProto(event)
+
+	assert.Equal(t, "order-1", message.OrderId)
+	assert.Equal(t, "req-1", message.CorrelationId)
+	assert.Equal(t, int32(domain.OrderEventSchemaVersion), message.SchemaVersion)
+	assert.Equal(t, event.Timestamp.UnixMilli(), message.Timestamp.AsTime().UnixMilli())
+	assert.True(t, proto.Equal(&events.Order{
+		OrderId: "order-1",
+		UserId:  "user-1",
+		Status:  "CREATED",
+		Items: []*events.OrderItem{
+			{ProductId: "product-1", Quantity: 2, UnitPrice: &events.Money{AmountMinor: 1999, CurrencyCode: "USD"}},
+			{ProductId: "product-2", Quantity: 1},
+		},
+		TotalQuantity: 3,
+		CreatedAt:     timestamppb.New(order.CreatedAt),
+	}, message.Order), "got %v", message.Order)
+
+	data, err := proto.Marshal(message)
+	require.NoError(t, err)
+	var decoded events.OrderEvent
+	require.NoError(t, proto.Unmarshal(data, &decoded))
+	assert.True(t, proto.Equal(message, &decoded))
+}
@@ -10,6 +10,7 @@ import (
 	"order-service/internal/domain/interfaces"
 	"time"
 
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -30,6 +31,11 @@ func NewGormOrderRepo(db *gorm.DB, logger *zap.Logger) *GormOrderRepository {
 	}
 }
 
+// log returns the request-scoped logger of ctx.
+func (r *GormOrderRepository) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, r.logger)
+}
+
 func (r *GormOrderRepository) CreateOrderWithItems(ctx context.Context, order *domain.Order, items []*domain.OrderItem) (*domain.Order, error) {
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
@@ -38,7 +44,7 @@ func (r *GormOrderRepository) CreateOrderWithItems(ctx context.Context, order *d
 		dbOrder := r.mapper.DomainToGorm(*order)
 
 		if err := tx.Omit("Items").Create(&dbOrder).Error; err != nil {
-			r.logger.Error("failed to create order in transaction", zap.Error(err))
+			r.log(ctx).Error("failed to create order in transaction", zap.Error(err))
 			return fmt.Errorf("failed to insert order: %w", err)
 		}
 
@@ -46,18 +52,18 @@ func (r *GormOrderRepository) CreateOrderWithItems(ctx context.Context, order *d
 			dbItem := r.mapper.DomainToGormOrderItem(*it)
 			dbItem.OrderID = dbOrder.ID
 			if err := tx.Create(&dbItem).Error; err != nil {
-				r.logger.Error("failed to insert order item", zap.Error(err), zap.Any("orderItem", dbItem))
+				r.log(ctx).Error("failed to insert order item", zap.Error(err), zap.Any("orderItem", dbItem))
 				return fmt.Errorf("failed to insert order item: %w", err)
 			}
 		}
 		return nil
 	})
 	if err != nil {
-		r.logger.Error("Transaction failed", zap.Error(err))
+		r.log(ctx).Error("Transaction failed", zap.Error(err))
 		return nil, fmt.Errorf("transaction failed: %w", err)
 	}
 
-	r.logger.Info("Transaction successful", zap.String("orderID", order.ID))
+	r.log(ctx).Info("Transaction successful", zap.String("orderID", order.ID))
 	return order, nil
 }
 
@@ -70,10 +76,10 @@ func (r *GormOrderRepository) GetOrder(ctx context.Context, orderID string) (*do
 		Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			r.logger.Error("order not found", zap.String("orderID", orderID))
+			r.log(ctx).Error("order not found", zap.String("orderID", orderID))
 			return nil, err
 		}
-		r.logger.Error("failed to get order", zap.String("orderID", orderID), zap.Error(err))
+		r.log(ctx).Error("failed to get order", zap.String("orderID", orderID), zap.Error(err))
 		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
 
@@ -89,7 +95,7 @@ func (r *GormOrderRepository) GetOrdersByUserID(ctx context.Context, userID stri
 		Where(columns.ColumnUserID+" = ?", userID).
 		Find(&dbOrders).Error
 	if err != nil {
-		r.logger.Error("failed to get orders by userID", zap.String("userID", userID), zap.Error(err))
+		r.log(ctx).Error("failed to get orders by userID", zap.String("userID", userID), zap.Error(err))
 		return nil, fmt.Errorf("failed to get orders: %w", err)
 	}
 
@@ -0,0 +1,76 @@
+// Package schemas holds the Avro schemas of the events order-service
+// publishes. The schemas come from the events module and are compiled into
+// the binary, so the service does not depend on the directory it is started
+// from; during development a directory of schema files can stand in for them.
+package schemas
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/events/orderevents"
+	"github.com/linkedin/goavro/v2"
+)
+
+// EventType identifies the kind of event a schema describes.
+type EventType string
+
+const OrderEvent EventType = "OrderEvent"
+
+// files names the schema file of each event type.
+var files = map[EventType]string{
+	OrderEvent: "order_event.avsc",
+}
+
+// Schema is the schema of an event type and the codec compiled from it.
+type Schema struct {
+	EventType  EventType
+	Definition string
+	Codec      *goavro.Codec
+}
+
+// Registry holds one schema per event type.
+type Registry struct {
+	schemas map[EventType]*Schema
+}
+
+// Load returns the compiled-in schemas, or those read from dir when it is
+// not empty. dir must hold a file for every event type, under the names the
+// embedded files use, e.g. order_event.avsc.
+func Load(dir string) (*Registry, error) {
+	if dir == "" {
+		return New(orderevents.Schemas)
+	}
+	r, err := New(os.DirFS(dir))
+	if err != nil {
+		return nil, fmt.Errorf("schema directory %s: %w", dir, err)
+	}
+	return r, nil
+}
+
+// New reads and compiles the schema of every event type from fsys.
+func New(fsys fs.FS) (*Registry, error) {
+	r := &Registry{schemas: make(map[EventType]*Schema, len(files))}
+	for eventType, name := range files {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("read %s schema: %w", eventType, err)
+		}
+		codec, err := goavro.NewCodec(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("compile %s schema: %w", eventType, err)
+		}
+		r.schemas[eventType] = &Schema{EventType: eventType, Definition: string(data), Codec: codec}
+	}
+	return r, nil
+}
+
+// Schema returns the schema of eventType.
+func (r *Registry) Schema(eventType EventType) (*Schema, error) {
+	s, ok := r.schemas[eventType]
+	if !ok {
+		return nil, fmt.Errorf("no schema for event type %q", eventType)
+	}
+	return s, nil
+}
@@ -0,0 +1,47 @@
+package schemas
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Embedded(t *testing.T) {
+	r, err := Load("")
+	require.NoError(t, err)
+
+	for eventType := range files {
+		s, err := r.Schema(eventType)
+		require.NoError(t, err)
+		assert.Equal(t, eventType, s.EventType)
+		assert.NotEmpty(t, s.Definition)
+		assert.NotNil(t, s.Codec)
+	}
+	_, err = r.Schema("UnknownEvent")
+	assert.ErrorContains(t, err, "UnknownEvent")
+}
+
+func TestLoad_Directory(t *testing.T) {
+	dir := t.TempDir()
+	definition := `{"type": "record", "name": "OrderEvent", "fields": [{"name": "order_id", "type": "string"}]}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "order_event.avsc"), []byte(definition), 0o600))
+
+	r, err := Load(dir)
+	require.NoError(t, err)
+	s, err := r.Schema(OrderEvent)
+	require.NoError(t, err)
+	assert.Equal(t, definition, s.Definition)
+}
+
+func TestLoad_DirectoryErrors(t *testing.T) {
+	_, err := Load(t.TempDir())
+	assert.ErrorContains(t, err, "read OrderEvent schema")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "order_event.avsc"), []byte(`{"type": "record"}`), 0o600))
+	_, err = Load(dir)
+	assert.ErrorContains(t, err, "compile OrderEvent schema")
+}
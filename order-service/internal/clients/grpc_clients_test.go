@@ -0,0 +1,158 @@
+package clients
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"order-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/breaker"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/grpcclient"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/inventory_service"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/user_service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// faults makes a fake server fail its first failures calls with Unavailable
+// and wait delay before answering.
+type faults struct {
+	failures int32
+	delay    time.Duration
+	calls    atomic.Int32
+}
+
+func (f *faults) inject(ctx context.Context) error {
+	n := f.calls.Add(1)
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if n <= f.failures {
+		return status.Error(codes.Unavailable, "injected failure")
+	}
+	return nil
+}
+
+type fakeUserService struct {
+	user_service.UnimplementedUserServiceServer
+	*faults
+}
+
+func (s fakeUserService) GetUserByID(ctx context.Context, req *user_service.GetUserRequest) (*user_service.GetUserResponse, error) {
+	if err := s.inject(ctx); err != nil {
+		return nil, err
+	}
+	return &user_service.GetUserResponse{Id: req.Id}, nil
+}
+
+type fakeInventoryService struct {
+	inventory_service.UnimplementedInventoryServiceServer
+	*faults
+}
+
+func (s fakeInventoryService) GetProduct(ctx context.Context, req *inventory_service.GetProductRequest) (*inventory_service.GetProductResponse, error) {
+	if err := s.inject(ctx); err != nil {
+		return nil, err
+	}
+	return &inventory_service.GetProductResponse{Product: &inventory_service.Product{
+		Id:       req.Id,
+		Quantity: 10,
+		Price:    &inventory_service.Money{AmountMinor: 1999, CurrencyCode: "USD"},
+	}}, nil
+}
+
+func serve(t *testing.T, register func(*grpc.Server), policy grpcclient.Policy, b *breaker.Breaker) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	register(server)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	opts := append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, grpcclient.DialOptions(policy, b)...)
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+var testRetry = grpcclient.Retry{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond, Multiplier: 2}
+
+func TestVerifyUser_RetriesUnavailable(t *testing.T) {
+	f := &faults{failures: 2}
+	conn := serve(t, func(s *grpc.Server) {
+		user_service.RegisterUserServiceServer(s, fakeUserService{faults: f})
+	}, grpcclient.Policy{Service: user_service.UserService_ServiceDesc.ServiceName, Timeout: time.Second, Retry: testRetry}, nil)
+
+	err := NewGRPCUserServiceClient(conn).VerifyUser(context.Background(), "user-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), f.calls.Load())
+}
+
+func TestVerifyInventory_ReturnsPrice(t *testing.T) {
+	conn := serve(t, func(s *grpc.Server) {
+		inventory_service.RegisterInventoryServiceServer(s, fakeInventoryService{faults: &faults{}})
+	}, grpcclient.Policy{Service: inventory_service.InventoryService_ServiceDesc.ServiceName, Timeout: time.Second}, nil)
+	client := NewGRPCInventoryServiceClient(conn)
+
+	price, err := client.VerifyInventory(context.Background(), "product-1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.Money{AmountMinor: 1999, CurrencyCode: "USD"}, price)
+
+	_, err = client.VerifyInventory(context.Background(), "product-1", 11)
+	assert.ErrorContains(t, err, "insufficient stock")
+}
+
+func TestVerifyInventory_TimesOut(t *testing.T) {
+	f := &faults{delay: time.Second}
+	service := inventory_service.InventoryService_ServiceDesc.ServiceName
+	conn := serve(t, func(s *grpc.Server) {
+		inventory_service.RegisterInventoryServiceServer(s, fakeInventoryService{faults: f})
+	}, grpcclient.Policy{
+		Service:        service,
+		Timeout:        5 * time.Second,
+		MethodTimeouts: map[string]time.Duration{service + "/GetProduct": 50 * time.Millisecond},
+	}, nil)
+
+	start := time.Now()
+	_, err := NewGRPCInventoryServiceClient(conn).VerifyInventory(context.Background(), "product-1", 1)
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Less(t, time.Since(start), time.Second, "the call does not wait for the slow server")
+}
+
+func TestVerifyInventory_BreakerOpens(t *testing.T) {
+	f := &faults{failures: 100}
+	b := breaker.New("inventory-service-test", breaker.Settings{FailureThreshold: 3, OpenTimeout: time.Minute})
+	conn := serve(t, func(s *grpc.Server) {
+		inventory_service.RegisterInventoryServiceServer(s, fakeInventoryService{faults: f})
+	}, grpcclient.Policy{Service: inventory_service.InventoryService_ServiceDesc.ServiceName, Timeout: time.Second}, b)
+	client := NewGRPCInventoryServiceClient(conn)
+
+	for i := 0; i < 3; i++ {
+		_, err := client.VerifyInventory(context.Background(), "product-1", 1)
+		assert.Error(t, err)
+	}
+	_, err := client.VerifyInventory(context.Background(), "product-1", 1)
+
+	assert.ErrorContains(t, err, breaker.ErrOpen.Error())
+	assert.Equal(t, int32(3), f.calls.Load())
+	assert.ErrorIs(t, b.Check(context.Background()), breaker.ErrOpen, "readiness reports the open breaker")
+}
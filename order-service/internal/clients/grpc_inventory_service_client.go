@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	"order-service/internal/domain"
+
 	"github.com/jakkapat-chongsuwat/go-microservice/proto/inventory_service"
 	"google.golang.org/grpc"
 )
@@ -18,17 +20,23 @@ func NewGRPCInventoryServiceClient(conn *grpc.ClientConn) *GRPCInventoryServiceC
 	}
 }
 
-func (c *GRPCInventoryServiceClient) VerifyInventory(ctx context.Context, productID string, requiredQuantity int) error {
+// VerifyInventory checks that requiredQuantity of the product is in stock and
+// returns its unit price, nil if the product has none.
+func (c *GRPCInventoryServiceClient) VerifyInventory(ctx context.Context, productID string, requiredQuantity int) (*domain.Money, error) {
 	req := &inventory_service.GetProductRequest{Id: productID}
 	resp, err := c.client.GetProduct(ctx, req)
 	if err != nil {
-		return fmt.Errorf("failed to verify inventory: %w", err)
+		return nil, fmt.Errorf("failed to verify inventory: %w", err)
 	}
 	if resp == nil || resp.Product == nil || resp.Product.Id == "" {
-		return fmt.Errorf("product not found")
+		return nil, fmt.Errorf("product not found")
 	}
 	if int(resp.Product.Quantity) < requiredQuantity {
-		return fmt.Errorf("insufficient stock: available %d, required %d", resp.Product.Quantity, requiredQuantity)
+		return nil, fmt.Errorf("insufficient stock: available %d, required %d", resp.Product.Quantity, requiredQuantity)
+	}
+	price := resp.Product.GetPrice()
+	if price == nil {
+		return nil, nil
 	}
-	return nil
+	return &domain.Money{AmountMinor: price.AmountMinor, CurrencyCode: price.CurrencyCode}, nil
 }
@@ -0,0 +1,176 @@
+// Package config is the typed configuration of order-service.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/events/serde"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/breaker"
+	platformconfig "github.com/jakkapat-chongsuwat/go-microservice/platform/config"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/grpcclient"
+)
+
+type Config struct {
+	GRPCPort        int           `env:"GRPC_PORT" default:"60051" yaml:"grpc_port"`
+	HTTPPort        int           `env:"HTTP_PORT" default:"60052" yaml:"http_port"`
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" default:"25s" yaml:"shutdown_timeout"`
+	RepoType        string        `env:"REPO_TYPE" default:"gorm" yaml:"repo_type" validate:"oneof=gorm"`
+
+	UserServiceAddress      string `env:"USER_SERVICE_ADDRESS" default:"localhost:50051" yaml:"user_service_address" validate:"required"`
+	InventoryServiceAddress string `env:"INVENTORY_SERVICE_ADDRESS" default:"localhost:30051" yaml:"inventory_service_address" validate:"required"`
+
+	Clients   Clients                  `yaml:"clients"`
+	Kafka     Kafka                    `yaml:"kafka"`
+	Database  platformconfig.Database  `yaml:"database"`
+	RateLimit platformconfig.RateLimit `yaml:"rate_limit"`
+}
+
+// Clients configures the gRPC clients of user-service and inventory-service.
+type Clients struct {
+	UserServiceTimeout      time.Duration `env:"USER_SERVICE_TIMEOUT" default:"2s" yaml:"user_service_timeout"`
+	InventoryServiceTimeout time.Duration `env:"INVENTORY_SERVICE_TIMEOUT" default:"2s" yaml:"inventory_service_timeout"`
+	// MethodTimeouts override the service timeouts for single methods, as
+	// "package.Service/Method=duration" entries.
+	MethodTimeouts []string `env:"GRPC_METHOD_TIMEOUTS" yaml:"method_timeouts"`
+
+	RetryMaxAttempts    int           `env:"GRPC_RETRY_MAX_ATTEMPTS" default:"3" yaml:"retry_max_attempts"`
+	RetryInitialBackoff time.Duration `env:"GRPC_RETRY_INITIAL_BACKOFF" default:"100ms" yaml:"retry_initial_backoff"`
+	RetryMaxBackoff     time.Duration `env:"GRPC_RETRY_MAX_BACKOFF" default:"1s" yaml:"retry_max_backoff"`
+
+	BreakerFailureThreshold int           `env:"BREAKER_FAILURE_THRESHOLD" default:"5" yaml:"breaker_failure_threshold"`
+	BreakerOpenTimeout      time.Duration `env:"BREAKER_OPEN_TIMEOUT" default:"30s" yaml:"breaker_open_timeout"`
+}
+
+func (c *Clients) Validate() error {
+	var errs []error
+	for _, d := range []struct {
+		env   string
+		value time.Duration
+	}{
+		{"USER_SERVICE_TIMEOUT", c.UserServiceTimeout},
+		{"INVENTORY_SERVICE_TIMEOUT", c.InventoryServiceTimeout},
+		{"GRPC_RETRY_INITIAL_BACKOFF", c.RetryInitialBackoff},
+		{"GRPC_RETRY_MAX_BACKOFF", c.RetryMaxBackoff},
+		{"BREAKER_OPEN_TIMEOUT", c.BreakerOpenTimeout},
+	} {
+		if d.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", d.env, d.value))
+		}
+	}
+	if c.RetryMaxAttempts < 1 || c.RetryMaxAttempts > 5 {
+		errs = append(errs, fmt.Errorf("GRPC_RETRY_MAX_ATTEMPTS must be between 1 and 5, got %d", c.RetryMaxAttempts))
+	}
+	if c.BreakerFailureThreshold < 1 {
+		errs = append(errs, fmt.Errorf("BREAKER_FAILURE_THRESHOLD must be positive, got %d", c.BreakerFailureThreshold))
+	}
+	if _, err := grpcclient.ParseMethodTimeouts(c.MethodTimeouts); err != nil {
+		errs = append(errs, fmt.Errorf("GRPC_METHOD_TIMEOUTS: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// Policy is the call policy for service with the given default timeout.
+func (c Clients) Policy(service string, timeout time.Duration) grpcclient.Policy {
+	methodTimeouts, _ := grpcclient.ParseMethodTimeouts(c.MethodTimeouts)
+	return grpcclient.Policy{
+		Service:        service,
+		Timeout:        timeout,
+		MethodTimeouts: methodTimeouts,
+		Retry: grpcclient.Retry{
+			MaxAttempts:    c.RetryMaxAttempts,
+			InitialBackoff: c.RetryInitialBackoff,
+			MaxBackoff:     c.RetryMaxBackoff,
+			Multiplier:     grpcclient.DefaultRetry.Multiplier,
+		},
+	}
+}
+
+func (c Clients) BreakerSettings() breaker.Settings {
+	return breaker.Settings{FailureThreshold: c.BreakerFailureThreshold, OpenTimeout: c.BreakerOpenTimeout}
+}
+
+type Kafka struct {
+	Brokers           []string `env:"KAFKA_BROKERS" default:"localhost:9092" yaml:"brokers" validate:"required"`
+	OrderTopic        string   `env:"KAFKA_ORDER_TOPIC" default:"order-events" yaml:"order_topic" validate:"required"`
+	SchemaRegistryURL string   `env:"SCHEMA_REGISTRY_URL" default:"http://localhost:8081" yaml:"schema_registry_url" validate:"required"`
+	// SchemaDir replaces the compiled-in event schemas with the files of a
+	// directory, to try schema changes without a rebuild.
+	SchemaDir string `env:"KAFKA_SCHEMA_DIR" yaml:"schema_dir"`
+	// TopicFormats choose the serialization of topics, as "topic=format"
+	// entries with format avro or protobuf. Topics not listed use avro.
+	TopicFormats []string `env:"KAFKA_TOPIC_FORMATS" yaml:"topic_formats"`
+
+	Compression string `env:"KAFKA_COMPRESSION" default:"snappy" yaml:"compression" validate:"oneof=none gzip snappy lz4 zstd"`
+	// ProducerMode async returns from a send once the event is buffered; the
+	// events are sent in batches and failed sends are retried every
+	// RetryFailedInterval, after the later events of the same order, so
+	// async does not keep the events of an order in order. sync waits for
+	// each event to be written.
+	ProducerMode        string        `env:"KAFKA_PRODUCER_MODE" default:"sync" yaml:"producer_mode" validate:"oneof=sync async"`
+	FlushFrequency      time.Duration `env:"KAFKA_FLUSH_FREQUENCY" default:"10ms" yaml:"flush_frequency"`
+	FlushMessages       int           `env:"KAFKA_FLUSH_MESSAGES" default:"100" yaml:"flush_messages"`
+	RetryFailedInterval time.Duration `env:"KAFKA_RETRY_FAILED_INTERVAL" default:"30s" yaml:"retry_failed_interval"`
+}
+
+func (k *Kafka) Validate() error {
+	var errs []error
+	if _, err := serde.ParseTopicFormats(k.TopicFormats); err != nil {
+		errs = append(errs, fmt.Errorf("KAFKA_TOPIC_FORMATS: %w", err))
+	}
+	if k.FlushFrequency < 0 {
+		errs = append(errs, fmt.Errorf("KAFKA_FLUSH_FREQUENCY must not be negative, got %s", k.FlushFrequency))
+	}
+	if k.FlushMessages < 0 {
+		errs = append(errs, fmt.Errorf("KAFKA_FLUSH_MESSAGES must not be negative, got %d", k.FlushMessages))
+	}
+	if k.RetryFailedInterval <= 0 {
+		errs = append(errs, fmt.Errorf("KAFKA_RETRY_FAILED_INTERVAL must be positive, got %s", k.RetryFailedInterval))
+	}
+	return errors.Join(errs...)
+}
+
+// TopicFormat is the serialization format of topic.
+func (k Kafka) TopicFormat(topic string) serde.Format {
+	formats, _ := serde.ParseTopicFormats(k.TopicFormats)
+	if format, ok := formats[topic]; ok {
+		return format
+	}
+	return serde.Avro
+}
+
+// OrderSubject is the schema registry subject of the order topic's values.
+func (k Kafka) OrderSubject() string {
+	return k.OrderTopic + "-value"
+}
+
+// Load reads the configuration from the environment, .env and the optional
+// YAML file.
+func Load(yamlFile string) (*Config, error) {
+	cfg := &Config{
+		Database: platformconfig.Database{Name: "order_service"},
+		RateLimit: platformconfig.RateLimit{Rules: []string{
+			"POST /api/orders=60/m:10",
+			"/order_service.OrderService/CreateOrder=60/m:10",
+		}},
+	}
+	if err := platformconfig.Load(cfg, platformconfig.WithYAML(yamlFile)); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+func (c *Config) Validate() error {
+	errs := []error{
+		platformconfig.ValidatePort("GRPC_PORT", c.GRPCPort),
+		platformconfig.ValidatePort("HTTP_PORT", c.HTTPPort),
+	}
+	if c.GRPCPort == c.HTTPPort {
+		errs = append(errs, fmt.Errorf("GRPC_PORT and HTTP_PORT must differ, both are %d", c.GRPCPort))
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("SHUTDOWN_TIMEOUT must be positive, got %s", c.ShutdownTimeout))
+	}
+	return errors.Join(errs...)
+}
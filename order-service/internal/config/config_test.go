@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/events/serde"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load("")
+	require.NoError(t, err)
+
+	assert.Equal(t, 60051, cfg.GRPCPort)
+	assert.Equal(t, 60052, cfg.HTTPPort)
+	assert.Equal(t, "localhost:50051", cfg.UserServiceAddress)
+	assert.Equal(t, "localhost:30051", cfg.InventoryServiceAddress)
+	assert.Equal(t, []string{"localhost:9092"}, cfg.Kafka.Brokers)
+	assert.Equal(t, "order-events-value", cfg.Kafka.OrderSubject())
+	assert.Empty(t, cfg.Kafka.SchemaDir, "the compiled-in schemas are used by default")
+	assert.Equal(t, serde.Avro, cfg.Kafka.TopicFormat(cfg.Kafka.OrderTopic))
+	assert.Equal(t, "snappy", cfg.Kafka.Compression)
+	assert.Equal(t, "sync", cfg.Kafka.ProducerMode)
+	assert.Equal(t, "order_service", cfg.Database.Name)
+	assert.Equal(t, 2*time.Second, cfg.Clients.UserServiceTimeout)
+	assert.Equal(t, 3, cfg.Clients.RetryMaxAttempts)
+	assert.Equal(t, 5, cfg.Clients.BreakerSettings().FailureThreshold)
+}
+
+func TestClients_Policy(t *testing.T) {
+	t.Setenv("GRPC_METHOD_TIMEOUTS", "inventory_service.InventoryService/GetProduct=500ms")
+	t.Setenv("GRPC_RETRY_MAX_ATTEMPTS", "4")
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+
+	p := cfg.Clients.Policy("inventory_service.InventoryService", cfg.Clients.InventoryServiceTimeout)
+	assert.Equal(t, 2*time.Second, p.Timeout)
+	assert.Equal(t, 500*time.Millisecond, p.MethodTimeouts["inventory_service.InventoryService/GetProduct"])
+	assert.Equal(t, 4, p.Retry.MaxAttempts)
+	assert.Equal(t, 100*time.Millisecond, p.Retry.InitialBackoff)
+}
+
+func TestLoad_YAMLAndEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "order-service.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+http_port: 8080
+kafka:
+  brokers: [kafka-1:9092, kafka-2:9092]
+  order_topic: orders
+  topic_formats: [orders=protobuf]
+database:
+  host: db.internal
+  password: from-yaml
+`), 0o600))
+	t.Setenv("DB_PASS", "from-env")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 8080, cfg.HTTPPort)
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.Kafka.Brokers)
+	assert.Equal(t, "orders-value", cfg.Kafka.OrderSubject())
+	assert.Equal(t, serde.Protobuf, cfg.Kafka.TopicFormat("orders"))
+	assert.Equal(t, "db.internal", cfg.Database.Host)
+	assert.Equal(t, "from-env", cfg.Database.Password)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Setenv("KAFKA_BROKERS", ",")
+	t.Setenv("GRPC_PORT", "60052")
+	t.Setenv("GRPC_METHOD_TIMEOUTS", "GetProduct=1s")
+	t.Setenv("GRPC_RETRY_MAX_ATTEMPTS", "9")
+	t.Setenv("KAFKA_TOPIC_FORMATS", "order-events=json")
+	t.Setenv("KAFKA_COMPRESSION", "brotli")
+
+	_, err := Load("")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "KAFKA_BROKERS is required")
+	assert.Contains(t, err.Error(), "GRPC_PORT and HTTP_PORT must differ, both are 60052")
+	assert.Contains(t, err.Error(), `GRPC_METHOD_TIMEOUTS: method timeout "GetProduct=1s" is not package.Service/Method=duration`)
+	assert.Contains(t, err.Error(), "GRPC_RETRY_MAX_ATTEMPTS must be between 1 and 5, got 9")
+	assert.Contains(t, err.Error(), `KAFKA_TOPIC_FORMATS: topic order-events: unknown format "json"`)
+	assert.Contains(t, err.Error(), `KAFKA_COMPRESSION must be one of none, gzip, snappy, lz4, zstd, got "brotli"`)
+}
+
+func TestLoad_RateLimits(t *testing.T) {
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.True(t, cfg.RateLimit.Enabled)
+	assert.Contains(t, cfg.RateLimit.Rules, "POST /api/orders=60/m:10")
+
+	t.Setenv("RATE_LIMITS", "POST /api/orders=ten/m")
+	_, err = Load("")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "RATE_LIMITS")
+}
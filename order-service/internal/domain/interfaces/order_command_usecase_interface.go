@@ -21,5 +21,5 @@ type IOrderRepository interface {
 }
 
 type IOrderEventProducer interface {
-	SendOrderEvent(event domain.OrderEvent) error
+	SendOrderEvent(ctx context.Context, event domain.OrderEvent) error
 }
@@ -1,10 +1,121 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderEventSchemaVersion is the version of the order event schema written by
+// this service. Version 1 carried only the order ID, type, message and time.
+const OrderEventSchemaVersion = 2
+
+// EventSource names this service in the events it publishes.
+const EventSource = "order-service"
+
+const EventTypeOrderCreated = "CREATED"
+
+// EventEnvelope is the metadata every event carries, whatever its payload.
+type EventEnvelope struct {
+	EventID       string
+	EventType     string
+	SchemaVersion int
+	// CorrelationID is the ID of the request that caused the event.
+	CorrelationID string
+	Source        string
+	Timestamp     time.Time
+}
+
+// Money is an amount in the minor unit of an ISO 4217 currency.
+type Money struct {
+	AmountMinor  int64
+	CurrencyCode string
+}
+
+type OrderEventItem struct {
+	ProductID string
+	Quantity  int
+	// UnitPrice is nil when the product has no price.
+	UnitPrice *Money
+}
+
+// OrderSnapshot is the state of an order when the event was raised.
+type OrderSnapshot struct {
+	OrderID       string
+	UserID        string
+	Status        OrderStatus
+	Items         []OrderEventItem
+	TotalQuantity int
+	// Total is nil unless every item is priced in the same currency.
+	Total     *Money
+	CreatedAt time.Time
+}
 
 type OrderEvent struct {
-	OrderID   string
-	EventType string
-	Message   string
-	Timestamp time.Time
+	EventEnvelope
+	// Message is a human-readable summary, kept for version 1 consumers.
+	Message string
+	Order   OrderSnapshot
+}
+
+// NewOrderEvent describes order for an event of eventType caused by the
+// request correlationID.
+func NewOrderEvent(eventType string, order *Order, correlationID string) OrderEvent {
+	snapshot := OrderSnapshot{
+		OrderID:   order.ID,
+		UserID:    order.UserID,
+		Status:    order.Status,
+		Items:     make([]OrderEventItem, 0, len(order.Items)),
+		CreatedAt: order.CreatedAt,
+	}
+	productIDs := make([]string, 0, len(order.Items))
+	for _, item := range order.Items {
+		snapshot.Items = append(snapshot.Items, OrderEventItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+		})
+		snapshot.TotalQuantity += item.Quantity
+		productIDs = append(productIDs, item.ProductID)
+	}
+	snapshot.Total = totalOf(snapshot.Items)
+
+	message := "Order created"
+	if len(productIDs) > 0 {
+		message = fmt.Sprintf("Order created for items: %s", strings.Join(productIDs, ", "))
+	}
+
+	return OrderEvent{
+		EventEnvelope: EventEnvelope{
+			EventID:       uuid.NewString(),
+			EventType:     eventType,
+			SchemaVersion: OrderEventSchemaVersion,
+			CorrelationID: correlationID,
+			Source:        EventSource,
+			Timestamp:     Clock.Now(),
+		},
+		Message: message,
+		Order:   snapshot,
+	}
+}
+
+func totalOf(items []OrderEventItem) *Money {
+	if len(items) == 0 {
+		return nil
+	}
+	var total Money
+	for _, item := range items {
+		if item.UnitPrice == nil {
+			return nil
+		}
+		if total.CurrencyCode == "" {
+			total.CurrencyCode = item.UnitPrice.CurrencyCode
+		} else if total.CurrencyCode != item.UnitPrice.CurrencyCode {
+			return nil
+		}
+		total.AmountMinor += item.UnitPrice.AmountMinor * int64(item.Quantity)
+	}
+	return &total
 }
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTotalOf(t *testing.T) {
+	usd := func(amount int64) *Money { return &Money{AmountMinor: amount, CurrencyCode: "USD"} }
+
+	assert.Equal(t, usd(4498), totalOf([]OrderEventItem{
+		{ProductID: "a", Quantity: 2, UnitPrice: usd(1999)},
+		{ProductID: "b", Quantity: 1, UnitPrice: usd(500)},
+	}))
+	assert.Nil(t, totalOf(nil))
+	assert.Nil(t, totalOf([]OrderEventItem{{ProductID: "a", Quantity: 1}}), "unpriced item")
+	assert.Nil(t, totalOf([]OrderEventItem{
+		{ProductID: "a", Quantity: 1, UnitPrice: usd(100)},
+		{ProductID: "b", Quantity: 1, UnitPrice: &Money{AmountMinor: 100, CurrencyCode: "EUR"}},
+	}), "mixed currencies")
+}
+
+func TestNewOrderEvent(t *testing.T) {
+	order := NewOrder("user-1")
+	order.Items = []*OrderItem{NewOrderItem("a", 2)}
+
+	event := NewOrderEvent(EventTypeOrderCreated, order, "req-1")
+
+	assert.NotEmpty(t, event.EventID)
+	assert.Equal(t, EventSource, event.Source)
+	assert.Equal(t, OrderEventSchemaVersion, event.SchemaVersion)
+	assert.Equal(t, "req-1", event.CorrelationID)
+	assert.Equal(t, order.ID, event.Order.OrderID)
+	assert.Equal(t, 2, event.Order.TotalQuantity)
+	assert.Equal(t, "Order created for items: a", event.Message)
+	assert.Nil(t, event.Order.Total, "unpriced items have no total")
+	assert.Equal(t, "Order created", NewOrderEvent(EventTypeOrderCreated, NewOrder("user-1"), "").Message)
+}
+
+func TestNewOrderEvent_Priced(t *testing.T) {
+	usd := func(amount int64) *Money { return &Money{AmountMinor: amount, CurrencyCode: "USD"} }
+	order := NewOrder("user-1")
+	a, b := NewOrderItem("a", 2), NewOrderItem("b", 1)
+	a.UnitPrice, b.UnitPrice = usd(1999), usd(500)
+	order.Items = []*OrderItem{a, b}
+
+	event := NewOrderEvent(EventTypeOrderCreated, order, "req-1")
+
+	require.Len(t, event.Order.Items, 2)
+	assert.Equal(t, usd(1999), event.Order.Items[0].UnitPrice)
+	assert.Equal(t, usd(500), event.Order.Items[1].UnitPrice)
+	assert.Equal(t, usd(4498), event.Order.Total)
+}
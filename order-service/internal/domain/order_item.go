@@ -11,6 +11,10 @@ type OrderItem struct {
 	OrderID   string
 	ProductID string
 	Quantity  int
+	// UnitPrice is the product's price when the order was placed, nil if
+	// the product has none. It is carried into the order's events and not
+	// stored.
+	UnitPrice *Money
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -1,7 +1,6 @@
 package usecases
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"order-service/internal/adapters/models"
@@ -9,6 +8,7 @@ import (
 	"order-service/internal/domain/interfaces"
 	"sync"
 
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"go.uber.org/zap"
 )
 
@@ -17,7 +17,9 @@ type UserServiceClient interface {
 }
 
 type InventoryServiceClient interface {
-	VerifyInventory(ctx context.Context, productID string, requiredQuantity int) error
+	// VerifyInventory checks that requiredQuantity of the product is in
+	// stock and returns its unit price, nil if it has none.
+	VerifyInventory(ctx context.Context, productID string, requiredQuantity int) (*domain.Money, error)
 }
 
 type OrderUseCaseImpl struct {
@@ -46,6 +48,11 @@ func NewOrderUsecase(
 	}
 }
 
+// log returns the request-scoped logger of ctx.
+func (o *OrderUseCaseImpl) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, o.logger)
+}
+
 func (o *OrderUseCaseImpl) CreateOrder(ctx context.Context, userID string, itemsReq []models.OrderItemRequest) (*domain.Order, error) {
 	order := domain.NewOrder(userID)
 	var items []*domain.OrderItem
@@ -64,7 +71,7 @@ func (o *OrderUseCaseImpl) CreateOrder(ctx context.Context, userID string, items
 
 // test uncle bob style
 func (o *OrderUseCaseImpl) CreateOrderWithItems(ctx context.Context, order *domain.Order, items []*domain.OrderItem) (*domain.Order, error) {
-	o.logger.Info("CreateOrderWithItems called", zap.String("userID", order.UserID))
+	o.log(ctx).Debug("CreateOrderWithItems called", zap.String("userID", order.UserID))
 
 	now := domain.Clock.Now()
 	if order.CreatedAt.IsZero() {
@@ -85,7 +92,7 @@ func (o *OrderUseCaseImpl) CreateOrderWithItems(ctx context.Context, order *doma
 		return nil, fmt.Errorf("failed to create order with items: %w", err)
 	}
 
-	o.publishOrderEvent(createdOrder)
+	o.publishOrderEvent(ctx, createdOrder)
 
 	return createdOrder, nil
 }
@@ -96,10 +103,12 @@ func (o *OrderUseCaseImpl) verifyUser(ctx context.Context, userID string) error
 
 func (o *OrderUseCaseImpl) verifyInventory(ctx context.Context, items []*domain.OrderItem) error {
 	for _, item := range items {
-		if err := o.inventorySvc.VerifyInventory(ctx, item.ProductID, item.Quantity); err != nil {
-			o.logger.Error("Inventory check failed", zap.String("productID", item.ProductID), zap.Error(err))
+		price, err := o.inventorySvc.VerifyInventory(ctx, item.ProductID, item.Quantity)
+		if err != nil {
+			o.log(ctx).Error("Inventory check failed", zap.String("productID", item.ProductID), zap.Error(err))
 			return err
 		}
+		item.UnitPrice = price
 	}
 	return nil
 }
@@ -108,63 +117,38 @@ func (o *OrderUseCaseImpl) persistOrder(ctx context.Context, order *domain.Order
 	return o.orderRepo.CreateOrderWithItems(ctx, order, items)
 }
 
-func (o *OrderUseCaseImpl) publishOrderEvent(order *domain.Order) {
-	var itemSummaries []string
-	for _, item := range order.Items {
-		itemSummaries = append(itemSummaries, item.ProductID)
-	}
-	summary := ""
-	if len(itemSummaries) > 0 {
-		summary = fmt.Sprintf(" for items: %s", joinStrings(itemSummaries, ", "))
-	}
-
-	event := domain.OrderEvent{
-		OrderID:   order.ID,
-		EventType: "CREATED",
-		Message:   fmt.Sprintf("Order created%s", summary),
-		Timestamp: domain.Clock.Now(),
-	}
-	if err := o.orderEventProducer.SendOrderEvent(event); err != nil {
-		o.logger.Error("failed to send order event", zap.Error(err))
-	}
-}
-
-func joinStrings(strs []string, sep string) string {
-	var buf bytes.Buffer
-	for i, s := range strs {
-		if i > 0 {
-			buf.WriteString(sep)
-		}
-		buf.WriteString(s)
+func (o *OrderUseCaseImpl) publishOrderEvent(ctx context.Context, order *domain.Order) {
+	event := domain.NewOrderEvent(domain.EventTypeOrderCreated, order, logging.RequestID(ctx))
+	if err := o.orderEventProducer.SendOrderEvent(ctx, event); err != nil {
+		o.log(ctx).Error("failed to send order event", zap.String("eventID", event.EventID), zap.Error(err))
 	}
-	return buf.String()
 }
 
 func (o *OrderUseCaseImpl) GetOrder(ctx context.Context, orderID string) (*domain.Order, error) {
-	o.logger.Info("GetOrder called", zap.String("orderID", orderID))
+	o.log(ctx).Debug("GetOrder called", zap.String("orderID", orderID))
 	order, err := o.orderRepo.GetOrder(ctx, orderID)
 	if err != nil {
-		o.logger.Error("failed to get order", zap.String("orderID", orderID), zap.Error(err))
+		o.log(ctx).Error("failed to get order", zap.String("orderID", orderID), zap.Error(err))
 		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
-	o.logger.Info("order found", zap.String("orderID", orderID))
+	o.log(ctx).Info("order found", zap.String("orderID", orderID))
 	return order, nil
 }
 
 func (o *OrderUseCaseImpl) GetOrdersByUserID(ctx context.Context, userID string) ([]*domain.Order, error) {
-	o.logger.Info("GetOrdersByUserID called", zap.String("userID", userID))
+	o.log(ctx).Debug("GetOrdersByUserID called", zap.String("userID", userID))
 	orders, err := o.orderRepo.GetOrdersByUserID(ctx, userID)
 	if err != nil {
-		o.logger.Error("failed to get orders", zap.String("userID", userID), zap.Error(err))
+		o.log(ctx).Error("failed to get orders", zap.String("userID", userID), zap.Error(err))
 		return nil, fmt.Errorf("failed to get orders: %w", err)
 	}
-	o.logger.Info("orders found", zap.String("userID", userID))
+	o.log(ctx).Info("orders found", zap.String("userID", userID))
 	return orders, nil
 }
 
 // test get parallel orders, batch can be more efficient
 func (o *OrderUseCaseImpl) GetOrdersInParallel(ctx context.Context, orderIDs []string) ([]*domain.Order, error) {
-	o.logger.Info("GetOrdersInParallel called", zap.Int("count", len(orderIDs)))
+	o.log(ctx).Debug("GetOrdersInParallel called", zap.Int("count", len(orderIDs)))
 	var (
 		wg       sync.WaitGroup
 		mu       sync.Mutex
@@ -6,6 +6,7 @@ import (
 	"order-service/internal/domain"
 	"testing"
 
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -60,17 +61,20 @@ type MockProductServiceClient struct {
 	mock.Mock
 }
 
-func (m *MockProductServiceClient) VerifyInventory(ctx context.Context, productID string, requiredQuantity int) error {
+func (m *MockProductServiceClient) VerifyInventory(ctx context.Context, productID string, requiredQuantity int) (*domain.Money, error) {
 	args := m.Called(ctx, productID, requiredQuantity)
-	return args.Error(0)
+	if price, ok := args.Get(0).(*domain.Money); ok {
+		return price, args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
 type MockOrderEventProducer struct {
 	mock.Mock
 }
 
-func (m *MockOrderEventProducer) SendOrderEvent(event domain.OrderEvent) error {
-	args := m.Called(event)
+func (m *MockOrderEventProducer) SendOrderEvent(ctx context.Context, event domain.OrderEvent) error {
+	args := m.Called(ctx, event)
 	return args.Error(0)
 }
 
@@ -85,7 +89,7 @@ func TestOrderUseCase(t *testing.T) {
 			mockEventProducer := new(MockOrderEventProducer)
 			logger, _ := zap.NewDevelopment()
 
-			mockEventProducer.On("SendOrderEvent", mock.Anything).Return(nil).Maybe()
+			mockEventProducer.On("SendOrderEvent", mock.Anything, mock.Anything).Return(nil).Maybe()
 
 			inputOrder := domain.NewOrder("user123")
 			inputItem := domain.NewOrderItem("prodABC", 2)
@@ -106,7 +110,7 @@ func TestOrderUseCase(t *testing.T) {
 			}
 
 			mockUserSvc.On("VerifyUser", mock.Anything, "user123").Return(nil).Once()
-			mockInvenSvc.On("VerifyInventory", mock.Anything, "prodABC", 2).Return(nil).Once()
+			mockInvenSvc.On("VerifyInventory", mock.Anything, "prodABC", 2).Return(nil, nil).Once()
 			mockRepo.On("CreateOrderWithItems", mock.Anything,
 				mock.MatchedBy(func(o *domain.Order) bool {
 					if o.UserID != "user123" || o.Status != domain.OrderStatusCreated || o.ID == "" {
@@ -139,6 +143,49 @@ func TestOrderUseCase(t *testing.T) {
 			mockRepo.AssertExpectations(t)
 		})
 
+		t.Run("PublishesOrderEvent", func(t *testing.T) {
+			mockRepo := new(MockOrderRepository)
+			mockUserSvc := new(MockUserServiceClient)
+			mockInvenSvc := new(MockProductServiceClient)
+			mockEventProducer := new(MockOrderEventProducer)
+
+			order := domain.NewOrder("user123")
+			order.Items = []*domain.OrderItem{domain.NewOrderItem("prodA", 2), domain.NewOrderItem("prodB", 1)}
+			created := *order
+			created.ID = "order_123"
+
+			mockUserSvc.On("VerifyUser", mock.Anything, "user123").Return(nil)
+			priceA := &domain.Money{AmountMinor: 1999, CurrencyCode: "USD"}
+			priceB := &domain.Money{AmountMinor: 500, CurrencyCode: "USD"}
+			mockInvenSvc.On("VerifyInventory", mock.Anything, "prodA", 2).Return(priceA, nil)
+			mockInvenSvc.On("VerifyInventory", mock.Anything, "prodB", 1).Return(priceB, nil)
+			mockRepo.On("CreateOrderWithItems", mock.Anything, mock.Anything, mock.Anything).Return(&created, nil)
+			var event domain.OrderEvent
+			mockEventProducer.On("SendOrderEvent", mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) { event = args.Get(1).(domain.OrderEvent) }).
+				Return(nil).Once()
+
+			ctx := logging.WithRequestID(context.Background(), "req-1")
+			_, err := NewOrderUsecase(mockRepo, mockUserSvc, mockInvenSvc, mockEventProducer, zap.NewNop()).CreateOrderWithItems(ctx, order, order.Items)
+			assert.NoError(t, err)
+			mockEventProducer.AssertExpectations(t)
+
+			assert.NotEmpty(t, event.EventID)
+			assert.Equal(t, domain.EventTypeOrderCreated, event.EventType)
+			assert.Equal(t, domain.OrderEventSchemaVersion, event.SchemaVersion)
+			assert.Equal(t, "req-1", event.CorrelationID)
+			assert.Equal(t, "order_123", event.Order.OrderID)
+			assert.Equal(t, "user123", event.Order.UserID)
+			assert.Equal(t, domain.OrderStatusCreated, event.Order.Status)
+			assert.Equal(t, []domain.OrderEventItem{
+				{ProductID: "prodA", Quantity: 2, UnitPrice: priceA},
+				{ProductID: "prodB", Quantity: 1, UnitPrice: priceB},
+			}, event.Order.Items)
+			assert.Equal(t, 3, event.Order.TotalQuantity)
+			assert.Equal(t, &domain.Money{AmountMinor: 4498, CurrencyCode: "USD"}, event.Order.Total)
+			assert.Equal(t, "Order created for items: prodA, prodB", event.Message)
+		})
+
 		t.Run("VerifyUserFail", func(t *testing.T) {
 			mockRepo := new(MockOrderRepository)
 			mockUserSvc := new(MockUserServiceClient)
@@ -175,7 +222,7 @@ func TestOrderUseCase(t *testing.T) {
 			inputOrder.Items = []*domain.OrderItem{domain.NewOrderItem("prodY", 1)}
 
 			mockUserSvc.On("VerifyUser", mock.Anything, "userX").Return(nil).Once()
-			mockInvenSvc.On("VerifyInventory", mock.Anything, "prodY", 1).Return(nil).Once()
+			mockInvenSvc.On("VerifyInventory", mock.Anything, "prodY", 1).Return(nil, nil).Once()
 			mockRepo.On("CreateOrderWithItems", mock.Anything, mock.Anything, mock.Anything).
 				Return(nil, errors.New("db error")).Once()
 
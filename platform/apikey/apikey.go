@@ -0,0 +1,65 @@
+// Package apikey verifies the API keys that partners and services present in
+// the X-API-Key header. Keys are issued and stored by user-service; a service
+// verifies them through a Verifier, normally user-service's VerifyAPIKey RPC
+// behind a Cache, and fiberapikey checks them on incoming requests.
+package apikey
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// Header carries the key on HTTP requests.
+const Header = "X-API-Key"
+
+// Scopes granted to keys by user-service.
+const (
+	ScopeInventoryRead  = "inventory:read"
+	ScopeInventoryWrite = "inventory:write"
+	ScopeOrdersRead     = "orders:read"
+	ScopeOrdersWrite    = "orders:write"
+	ScopeUsersRead      = "users:read"
+)
+
+// ErrInvalidKey is returned for unknown, revoked and expired keys. Any other
+// Verify error means the key could not be checked.
+var ErrInvalidKey = errors.New("invalid api key")
+
+// Principal is the caller a valid key authenticates.
+type Principal struct {
+	KeyID   string
+	OwnerID string
+	Scopes  []string
+	// ExpiresAt is zero for keys that do not expire.
+	ExpiresAt time.Time
+}
+
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+type Verifier interface {
+	Verify(ctx context.Context, key string) (Principal, error)
+}
+
+// VerifierFunc adapts a function to Verifier.
+type VerifierFunc func(ctx context.Context, key string) (Principal, error)
+
+func (f VerifierFunc) Verify(ctx context.Context, key string) (Principal, error) {
+	return f(ctx, key)
+}
+
+type principalKey struct{}
+
+// NewContext stores the principal of a request in ctx.
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored by NewContext, if any.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
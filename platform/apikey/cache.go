@@ -0,0 +1,161 @@
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultCacheSize bounds the number of valid keys a Cache remembers.
+const DefaultCacheSize = 10000
+
+// Rejected keys are remembered apart from valid ones, fewer and for less
+// time, so a flood of made-up keys cannot push the valid ones out.
+const (
+	DefaultRejectedSize = 1000
+	DefaultRejectedTTL  = 5 * time.Second
+)
+
+type cacheEntry struct {
+	principal Principal
+	expires   time.Time
+}
+
+type CacheOption func(*Cache)
+
+// WithClock replaces time.Now; tests pass a fake clock.
+func WithClock(now func() time.Time) CacheOption {
+	return func(c *Cache) { c.now = now }
+}
+
+// WithSize bounds the number of cached valid keys.
+func WithSize(size int) CacheOption {
+	return func(c *Cache) { c.size = size }
+}
+
+// WithRejected bounds the number of cached rejected keys and how long they
+// are remembered, at most the ttl of the Cache.
+func WithRejected(size int, ttl time.Duration) CacheOption {
+	return func(c *Cache) { c.rejectedSize, c.rejectedTTL = size, ttl }
+}
+
+// Cache remembers the outcome of verifying a key for ttl, so a busy client
+// does not cost a round trip per request. Valid keys are cached no longer
+// than they live; rejected keys are cached apart, see DefaultRejectedSize.
+// Errors that leave a key unchecked are not cached. A key revoked upstream
+// keeps working here for up to ttl. Entries are keyed by a hash of the key.
+type Cache struct {
+	verifier     Verifier
+	ttl          time.Duration
+	size         int
+	rejectedSize int
+	rejectedTTL  time.Duration
+	now          func() time.Time
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]cacheEntry
+	// rejected holds when each rejected key is to be verified again.
+	rejected map[[sha256.Size]byte]time.Time
+}
+
+func NewCache(verifier Verifier, ttl time.Duration, opts ...CacheOption) *Cache {
+	c := &Cache{
+		verifier:     verifier,
+		ttl:          ttl,
+		size:         DefaultCacheSize,
+		rejectedSize: DefaultRejectedSize,
+		rejectedTTL:  DefaultRejectedTTL,
+		now:          time.Now,
+		entries:      make(map[[sha256.Size]byte]cacheEntry),
+		rejected:     make(map[[sha256.Size]byte]time.Time),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.rejectedTTL = min(c.rejectedTTL, ttl)
+	return c
+}
+
+func (c *Cache) Verify(ctx context.Context, key string) (Principal, error) {
+	id := sha256.Sum256([]byte(key))
+	now := c.now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[id]
+	retry, rejected := c.rejected[id]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.principal, nil
+	}
+	if rejected && now.Before(retry) {
+		return Principal{}, ErrInvalidKey
+	}
+
+	p, err := c.verifier.Verify(ctx, key)
+	if errors.Is(err, ErrInvalidKey) {
+		c.reject(id, now)
+		return Principal{}, err
+	}
+	if err != nil {
+		return Principal{}, err
+	}
+	expires := now.Add(c.ttl)
+	if !p.ExpiresAt.IsZero() && p.ExpiresAt.Before(expires) {
+		expires = p.ExpiresAt
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.rejected, id)
+	if len(c.entries) >= c.size {
+		c.evict(now)
+	}
+	if len(c.entries) < c.size {
+		c.entries[id] = cacheEntry{principal: p, expires: expires}
+	}
+	return p, nil
+}
+
+// Len is the number of cached valid keys.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// evict drops expired entries. If none have expired the cache stays full and
+// new keys are verified without being cached until some do.
+func (c *Cache) evict(now time.Time) {
+	for id, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, id)
+		}
+	}
+}
+
+// reject remembers a rejected key. When the rejected keys are at their
+// bound the expired ones are dropped, or else an arbitrary one: forgetting
+// a rejection only costs another verification.
+func (c *Cache) reject(id [sha256.Size]byte, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, id)
+	if len(c.rejected) >= c.rejectedSize {
+		for other, retry := range c.rejected {
+			if !now.Before(retry) {
+				delete(c.rejected, other)
+			}
+		}
+	}
+	for other := range c.rejected {
+		if len(c.rejected) < c.rejectedSize {
+			break
+		}
+		delete(c.rejected, other)
+	}
+	if c.rejectedSize > 0 {
+		c.rejected[id] = now.Add(c.rejectedTTL)
+	}
+}
@@ -0,0 +1,134 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// countingVerifier accepts "good", other keys starting with "good-", and
+// "short-lived", and counts its calls.
+type countingVerifier struct {
+	calls     int
+	err       error
+	expiresAt time.Time
+}
+
+func (v *countingVerifier) Verify(_ context.Context, key string) (Principal, error) {
+	v.calls++
+	if v.err != nil {
+		return Principal{}, v.err
+	}
+	switch {
+	case key == "good":
+		return Principal{KeyID: "k1", OwnerID: "u1", Scopes: []string{ScopeInventoryRead}}, nil
+	case strings.HasPrefix(key, "good-"):
+		return Principal{KeyID: key, OwnerID: "u1"}, nil
+	case key == "short-lived":
+		return Principal{KeyID: "k2", OwnerID: "u1", ExpiresAt: v.expiresAt}, nil
+	default:
+		return Principal{}, ErrInvalidKey
+	}
+}
+
+func TestCache_CachesOutcomesForTTL(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	verifier := &countingVerifier{}
+	cache := NewCache(verifier, 30*time.Second, WithClock(clock.Now))
+
+	for i := 0; i < 3; i++ {
+		p, err := cache.Verify(context.Background(), "good")
+		require.NoError(t, err)
+		assert.Equal(t, "k1", p.KeyID)
+		assert.True(t, p.HasScope(ScopeInventoryRead))
+
+		_, err = cache.Verify(context.Background(), "bad")
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	}
+	assert.Equal(t, 2, verifier.calls, "rejections are cached too")
+
+	clock.Advance(DefaultRejectedTTL)
+	_, _ = cache.Verify(context.Background(), "bad")
+	assert.Equal(t, 3, verifier.calls, "rejections expire sooner")
+
+	clock.Advance(30*time.Second - DefaultRejectedTTL)
+	_, _ = cache.Verify(context.Background(), "good")
+	assert.Equal(t, 4, verifier.calls, "entries expire after the ttl")
+}
+
+func TestCache_DoesNotOutliveTheKey(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	verifier := &countingVerifier{expiresAt: clock.now.Add(5 * time.Second)}
+	cache := NewCache(verifier, time.Minute, WithClock(clock.Now))
+
+	_, _ = cache.Verify(context.Background(), "short-lived")
+	clock.Advance(5 * time.Second)
+	verifier.err = ErrInvalidKey
+	_, err := cache.Verify(context.Background(), "short-lived")
+
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	assert.Equal(t, 2, verifier.calls)
+}
+
+func TestCache_DoesNotCacheFailures(t *testing.T) {
+	verifier := &countingVerifier{err: errors.New("user-service unavailable")}
+	cache := NewCache(verifier, time.Minute)
+
+	_, err := cache.Verify(context.Background(), "good")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalidKey)
+
+	verifier.err = nil
+	_, err = cache.Verify(context.Background(), "good")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, verifier.calls)
+}
+
+func TestCache_IsBounded(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cache := NewCache(&countingVerifier{}, time.Minute, WithClock(clock.Now), WithSize(2))
+
+	for _, key := range []string{"good-a", "good-b", "good-c"} {
+		_, _ = cache.Verify(context.Background(), key)
+	}
+	assert.Equal(t, 2, cache.Len())
+
+	clock.Advance(time.Minute)
+	_, _ = cache.Verify(context.Background(), "good-d")
+	assert.Equal(t, 1, cache.Len(), "expired entries make room")
+}
+
+func TestCache_RejectedKeysDoNotCrowdOutValidOnes(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	verifier := &countingVerifier{}
+	cache := NewCache(verifier, time.Minute, WithClock(clock.Now), WithSize(2), WithRejected(3, time.Second))
+
+	_, err := cache.Verify(context.Background(), "good-a")
+	require.NoError(t, err)
+	for i := range 100 {
+		_, err := cache.Verify(context.Background(), fmt.Sprintf("made-up-%d", i))
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	}
+	_, err = cache.Verify(context.Background(), "good-b")
+	require.NoError(t, err)
+	assert.Equal(t, 2, cache.Len())
+	assert.Len(t, cache.rejected, 3)
+
+	calls := verifier.calls
+	for _, key := range []string{"good-a", "good-b"} {
+		_, err := cache.Verify(context.Background(), key)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, calls, verifier.calls, "valid keys stay cached")
+}
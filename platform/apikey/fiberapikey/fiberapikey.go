@@ -0,0 +1,76 @@
+// Package fiberapikey authenticates Fiber requests by their X-API-Key header.
+package fiberapikey
+
+import (
+	"errors"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/apikey"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/ratelimit"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type Options struct {
+	// Required rejects requests without a key. Otherwise they pass as
+	// anonymous, and only requests that present a key are checked.
+	Required bool
+	// Scope returns the scope a request needs. Nil, or an empty scope,
+	// accepts any valid key.
+	Scope func(c *fiber.Ctx) string
+}
+
+// MethodScopes requires read for GET and HEAD requests and write for the
+// rest.
+func MethodScopes(read, write string) func(c *fiber.Ctx) string {
+	return func(c *fiber.Ctx) string {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead:
+			return read
+		default:
+			return write
+		}
+	}
+}
+
+// Middleware verifies the request's key and stores its principal with
+// apikey.NewContext, and as the rate limit subject, in c.UserContext().
+// Missing or invalid keys get 401, keys without the scope 403, and requests
+// whose key cannot be checked 503.
+func Middleware(verifier apikey.Verifier, logger *zap.Logger, opts Options) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(apikey.Header)
+		if key == "" {
+			if opts.Required {
+				return reject(c, fiber.StatusUnauthorized, "an API key is required")
+			}
+			return c.Next()
+		}
+
+		ctx := c.UserContext()
+		principal, err := verifier.Verify(ctx, key)
+		switch {
+		case errors.Is(err, apikey.ErrInvalidKey):
+			return reject(c, fiber.StatusUnauthorized, "invalid API key")
+		case err != nil:
+			logging.FromContext(ctx, logger).Error("failed to verify api key", zap.Error(err))
+			return reject(c, fiber.StatusServiceUnavailable, "API keys cannot be verified, try again later")
+		}
+
+		if opts.Scope != nil {
+			if scope := opts.Scope(c); scope != "" && !principal.HasScope(scope) {
+				return reject(c, fiber.StatusForbidden, "API key lacks scope "+scope)
+			}
+		}
+
+		ctx = apikey.NewContext(ctx, principal)
+		ctx = ratelimit.WithSubject(ctx, principal.OwnerID)
+		c.SetUserContext(ctx)
+		return c.Next()
+	}
+}
+
+func reject(c *fiber.Ctx, status int, message string) error {
+	return c.Status(status).JSON(fiber.Map{"error": message})
+}
@@ -0,0 +1,87 @@
+package fiberapikey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/apikey"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/ratelimit"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var verifier = apikey.VerifierFunc(func(_ context.Context, key string) (apikey.Principal, error) {
+	switch key {
+	case "reader":
+		return apikey.Principal{KeyID: "k1", OwnerID: "u1", Scopes: []string{apikey.ScopeInventoryRead}}, nil
+	case "down":
+		return apikey.Principal{}, errors.New("connection refused")
+	default:
+		return apikey.Principal{}, apikey.ErrInvalidKey
+	}
+})
+
+func newApp(opts Options) *fiber.App {
+	app := fiber.New()
+	app.Use(Middleware(verifier, zap.NewNop(), opts))
+	handler := func(c *fiber.Ctx) error {
+		p, ok := apikey.FromContext(c.UserContext())
+		if !ok {
+			return c.SendString("anonymous")
+		}
+		return c.SendString(p.KeyID + " " + ratelimit.Subject(c.UserContext()))
+	}
+	app.Get("/products", handler)
+	app.Post("/products", handler)
+	return app
+}
+
+func do(t *testing.T, app *fiber.App, method, key string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest(method, "/products", nil)
+	if key != "" {
+		req.Header.Set(apikey.Header, key)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body := make([]byte, 64)
+	n, _ := resp.Body.Read(body)
+	return resp.StatusCode, string(body[:n])
+}
+
+func TestMiddleware_Optional(t *testing.T) {
+	app := newApp(Options{Scope: MethodScopes(apikey.ScopeInventoryRead, apikey.ScopeInventoryWrite)})
+
+	status, body := do(t, app, http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "anonymous", body)
+
+	status, body = do(t, app, http.MethodGet, "reader")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "k1 u1", body, "the principal and rate limit subject are set")
+
+	status, _ = do(t, app, http.MethodPost, "reader")
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, _ = do(t, app, http.MethodGet, "revoked")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, _ = do(t, app, http.MethodGet, "down")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+}
+
+func TestMiddleware_Required(t *testing.T) {
+	app := newApp(Options{Required: true})
+
+	status, _ := do(t, app, http.MethodGet, "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, _ = do(t, app, http.MethodPost, "reader")
+	assert.Equal(t, http.StatusOK, status, "without a scope any valid key passes")
+}
@@ -0,0 +1,189 @@
+// Package breaker is a consecutive-failure circuit breaker for calls to a
+// downstream service. After FailureThreshold failures in a row the breaker
+// opens and rejects calls without making them. Once OpenTimeout has passed it
+// lets a single probe through (half-open): success closes it again, failure
+// reopens it.
+//
+// The state of every breaker is exported as circuit_breaker_state and can be
+// added to the readiness checks with Check.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// State is the position of a breaker. Its value is the circuit_breaker_state
+// gauge.
+type State int
+
+const (
+	StateClosed State = iota
+	StateHalfOpen
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half-open"
+	case StateOpen:
+		return "open"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// ErrOpen is returned by Allow while the breaker rejects calls.
+var ErrOpen = errors.New("circuit breaker is open")
+
+const (
+	DefaultFailureThreshold = 5
+	DefaultOpenTimeout      = 30 * time.Second
+)
+
+var (
+	stateGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "circuit_breaker_state",
+		Help: "State of the circuit breaker: 0 closed, 1 half-open, 2 open.",
+	}, []string{metrics.LabelBreaker})
+
+	rejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "circuit_breaker_rejected_total",
+		Help: "Number of calls rejected because the circuit breaker was open.",
+	}, []string{metrics.LabelBreaker})
+)
+
+// Settings configure a Breaker. Zero values take the defaults.
+type Settings struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+	// Now is the clock; tests replace it.
+	Now func() time.Time
+}
+
+type Breaker struct {
+	name     string
+	settings Settings
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func New(name string, settings Settings) *Breaker {
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = DefaultFailureThreshold
+	}
+	if settings.OpenTimeout <= 0 {
+		settings.OpenTimeout = DefaultOpenTimeout
+	}
+	if settings.Now == nil {
+		settings.Now = time.Now
+	}
+	b := &Breaker{name: name, settings: settings}
+	stateGauge.WithLabelValues(name).Set(float64(StateClosed))
+	return b
+}
+
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State reports the current state, moving an open breaker to half-open once
+// its timeout has passed.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.expireLocked()
+	return b.state
+}
+
+// Allow reports whether a call may be made. Every allowed call must be
+// followed by Record with its outcome.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.expireLocked()
+
+	switch b.state {
+	case StateOpen:
+	case StateHalfOpen:
+		if !b.probing {
+			b.probing = true
+			return nil
+		}
+	default:
+		return nil
+	}
+	rejectedTotal.WithLabelValues(b.name).Inc()
+	return fmt.Errorf("%s: %w", b.name, ErrOpen)
+}
+
+// Record reports the outcome of an allowed call. Failures should only be
+// errors that say something about the downstream's health, not rejected
+// input.
+func (b *Breaker) Record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateHalfOpen:
+		b.probing = false
+		if failed {
+			b.openLocked()
+		} else {
+			b.setLocked(StateClosed)
+			b.failures = 0
+		}
+	case StateClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.settings.FailureThreshold {
+			b.openLocked()
+		}
+	}
+	// Results that arrive while open come from calls allowed before the
+	// breaker tripped; they do not change anything.
+}
+
+// Check fails while the breaker is open, for use as a readiness check.
+func (b *Breaker) Check(ctx context.Context) error {
+	if state := b.State(); state == StateOpen {
+		return fmt.Errorf("%s: %w", b.name, ErrOpen)
+	}
+	return nil
+}
+
+func (b *Breaker) expireLocked() {
+	if b.state == StateOpen && !b.settings.Now().Before(b.openedAt.Add(b.settings.OpenTimeout)) {
+		b.setLocked(StateHalfOpen)
+		b.probing = false
+	}
+}
+
+func (b *Breaker) openLocked() {
+	b.openedAt = b.settings.Now()
+	b.failures = 0
+	b.setLocked(StateOpen)
+}
+
+func (b *Breaker) setLocked(state State) {
+	b.state = state
+	stateGauge.WithLabelValues(b.name).Set(float64(state))
+}
@@ -0,0 +1,100 @@
+package breaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestBreaker(name string) (*Breaker, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	return New(name, Settings{FailureThreshold: 3, OpenTimeout: 10 * time.Second, Now: clock.Now}), clock
+}
+
+func fail(t *testing.T, b *Breaker, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		require.NoError(t, b.Allow())
+		b.Record(true)
+	}
+}
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker("opens")
+	rejected := rejectedTotal.WithLabelValues("opens")
+	rejectedBefore := testutil.ToFloat64(rejected)
+
+	fail(t, b, 2)
+	require.NoError(t, b.Allow())
+	b.Record(false)
+	fail(t, b, 2)
+	assert.Equal(t, StateClosed, b.State(), "a success resets the count")
+
+	fail(t, b, 1)
+	assert.Equal(t, StateOpen, b.State())
+	assert.ErrorIs(t, b.Allow(), ErrOpen)
+	assert.ErrorIs(t, b.Check(context.Background()), ErrOpen)
+	assert.Equal(t, float64(StateOpen), testutil.ToFloat64(stateGauge.WithLabelValues("opens")))
+	assert.Equal(t, rejectedBefore+1, testutil.ToFloat64(rejected))
+}
+
+func TestBreaker_HalfOpenProbe(t *testing.T) {
+	b, clock := newTestBreaker("probe")
+	fail(t, b, 3)
+
+	clock.Advance(9 * time.Second)
+	assert.ErrorIs(t, b.Allow(), ErrOpen)
+
+	clock.Advance(time.Second)
+	assert.Equal(t, StateHalfOpen, b.State())
+	assert.NoError(t, b.Check(context.Background()), "half-open is ready to try again")
+	require.NoError(t, b.Allow())
+	assert.ErrorIs(t, b.Allow(), ErrOpen, "only one probe at a time")
+
+	b.Record(false)
+	assert.Equal(t, StateClosed, b.State())
+	assert.NoError(t, b.Allow())
+	assert.Equal(t, float64(StateClosed), testutil.ToFloat64(stateGauge.WithLabelValues("probe")))
+}
+
+func TestBreaker_FailedProbeReopens(t *testing.T) {
+	b, clock := newTestBreaker("reopen")
+	fail(t, b, 3)
+	clock.Advance(10 * time.Second)
+
+	require.NoError(t, b.Allow())
+	b.Record(true)
+
+	assert.Equal(t, StateOpen, b.State())
+	clock.Advance(5 * time.Second)
+	assert.ErrorIs(t, b.Allow(), ErrOpen, "the timeout restarts")
+}
+
+func TestBreaker_LateResultsWhileOpenAreIgnored(t *testing.T) {
+	b, clock := newTestBreaker("late")
+	require.NoError(t, b.Allow())
+	fail(t, b, 3)
+
+	b.Record(false)
+	assert.Equal(t, StateOpen, b.State())
+
+	clock.Advance(10 * time.Second)
+	assert.Equal(t, StateHalfOpen, b.State())
+}
+
+func TestNew_Defaults(t *testing.T) {
+	b := New("defaults", Settings{})
+	assert.Equal(t, DefaultFailureThreshold, b.settings.FailureThreshold)
+	assert.Equal(t, DefaultOpenTimeout, b.settings.OpenTimeout)
+	assert.Equal(t, "defaults", b.Name())
+	assert.Equal(t, "closed", b.State().String())
+}
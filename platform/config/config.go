@@ -0,0 +1,341 @@
+// Package config loads a service's typed configuration. Each field of the
+// config struct names its environment variable and default in struct tags:
+//
+//	type Config struct {
+//		HTTPPort int           `env:"HTTP_PORT" default:"8080" yaml:"http_port" validate:"required"`
+//		Password string        `env:"DB_PASS" yaml:"password" secret:"true"`
+//		Timeout  time.Duration `env:"SHUTDOWN_TIMEOUT" default:"25s" yaml:"shutdown_timeout"`
+//	}
+//
+// Values are applied in increasing order of precedence: the default tag, the
+// optional YAML file, the .env file, and the process environment. A default
+// only fills a field that is still zero, so a service can pre-set its own
+// defaults for a shared group. Nested structs are walked, so groups such as
+// Database can be embedded in several services.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted replaces the value of secret fields in printed configuration.
+const Redacted = "******"
+
+// Validator is implemented by config structs with checks that tags cannot
+// express, such as rules spanning several fields.
+type Validator interface {
+	Validate() error
+}
+
+type options struct {
+	yamlFile string
+	dotEnv   string
+	lookup   func(string) (string, bool)
+}
+
+type Option func(*options)
+
+// WithYAML reads path before the environment. An empty path is ignored, so
+// services can pass their --config flag through unconditionally.
+func WithYAML(path string) Option {
+	return func(o *options) { o.yamlFile = path }
+}
+
+// WithDotEnv reads variables from path instead of ".env". A missing file is
+// not an error.
+func WithDotEnv(path string) Option {
+	return func(o *options) { o.dotEnv = path }
+}
+
+// Load fills cfg, which must be a pointer to a struct, and validates it. The
+// returned error lists every invalid field by its environment variable.
+func Load(cfg any, opts ...Option) error {
+	o := options{dotEnv: ".env", lookup: os.LookupEnv}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: Load needs a pointer to a struct, got %T", cfg)
+	}
+
+	if err := walk(v.Elem(), func(f field) error {
+		if f.def == "" || !f.value.IsZero() {
+			return nil
+		}
+		return f.set(f.def)
+	}); err != nil {
+		return err
+	}
+
+	if o.yamlFile != "" {
+		data, err := os.ReadFile(o.yamlFile)
+		if err != nil {
+			return fmt.Errorf("config: read %s: %w", o.yamlFile, err)
+		}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return fmt.Errorf("config: parse %s: %w", o.yamlFile, err)
+		}
+	}
+
+	dotEnv := map[string]string{}
+	if o.dotEnv != "" {
+		vars, err := godotenv.Read(o.dotEnv)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("config: read %s: %w", o.dotEnv, err)
+		}
+		if vars != nil {
+			dotEnv = vars
+		}
+	}
+
+	var errs []error
+	_ = walk(v.Elem(), func(f field) error {
+		if f.env == "" {
+			return nil
+		}
+		// An empty variable counts as unset, so compose files can list
+		// optional variables without overriding the defaults.
+		raw, _ := o.lookup(f.env)
+		if raw == "" {
+			raw = dotEnv[f.env]
+		}
+		if raw == "" {
+			return nil
+		}
+		if err := f.set(raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+		}
+		return nil
+	})
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return Validate(cfg)
+}
+
+// Validate checks the validate tags of cfg, a pointer to a struct, and then
+// the Validate methods of cfg and its nested structs. Supported rules are
+// "required" and "oneof=a b c", separated by commas.
+func Validate(cfg any) error {
+	var errs []error
+	v := reflect.ValueOf(cfg).Elem()
+	_ = walk(v, func(f field) error {
+		for _, rule := range strings.Split(f.rules, ",") {
+			switch {
+			case rule == "":
+			case rule == "required":
+				if f.value.IsZero() {
+					errs = append(errs, fmt.Errorf("%s is required", f.name()))
+				}
+			case strings.HasPrefix(rule, "oneof="):
+				allowed := strings.Fields(strings.TrimPrefix(rule, "oneof="))
+				if got := f.String(); !contains(allowed, got) {
+					errs = append(errs, fmt.Errorf("%s must be one of %s, got %q", f.name(), strings.Join(allowed, ", "), got))
+				}
+			}
+		}
+		return nil
+	})
+	for _, validator := range validators(v) {
+		if err := validator.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validators returns the nested structs of v that implement Validator,
+// innermost first, followed by v itself.
+func validators(v reflect.Value) []Validator {
+	var out []Validator
+	for i := 0; i < v.NumField(); i++ {
+		fv := v.Field(i)
+		if v.Type().Field(i).IsExported() && fv.Kind() == reflect.Struct && fv.Type() != durationType {
+			out = append(out, validators(fv)...)
+		}
+	}
+	if validator, ok := v.Addr().Interface().(Validator); ok {
+		out = append(out, validator)
+	}
+	return out
+}
+
+// Values returns the effective configuration keyed by environment variable,
+// with secrets redacted. It is meant for startup logs.
+func Values(cfg any) map[string]string {
+	values := make(map[string]string)
+	for _, f := range fields(cfg) {
+		values[f.env] = f.display()
+	}
+	return values
+}
+
+// Print writes the effective configuration as KEY=value lines in field order,
+// with secrets redacted. It backs the --print-config flag of the services.
+func Print(w io.Writer, cfg any) error {
+	for _, f := range fields(cfg) {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", f.env, f.display()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fields(cfg any) []field {
+	var out []field
+	v := reflect.ValueOf(cfg)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	_ = walk(v, func(f field) error {
+		if f.env != "" {
+			out = append(out, f)
+		}
+		return nil
+	})
+	return out
+}
+
+type field struct {
+	value  reflect.Value
+	path   string
+	env    string
+	def    string
+	rules  string
+	secret bool
+}
+
+func (f field) name() string {
+	if f.env != "" {
+		return f.env
+	}
+	return f.path
+}
+
+func (f field) display() string {
+	s := f.String()
+	if f.secret && s != "" {
+		return Redacted
+	}
+	return s
+}
+
+func (f field) String() string {
+	switch v := f.value.Interface().(type) {
+	case []string:
+		return strings.Join(v, ",")
+	case time.Duration:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func (f field) set(raw string) error {
+	v := f.value
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		v.SetFloat(n)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+func walk(v reflect.Value, fn func(field) error) error {
+	return walkPath(v, "", fn)
+}
+
+func walkPath(v reflect.Value, prefix string, fn func(field) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		path := prefix + sf.Name
+		if fv.Kind() == reflect.Struct && fv.Type() != durationType {
+			if err := walkPath(fv, path+".", fn); err != nil {
+				return err
+			}
+			continue
+		}
+		secret, _ := strconv.ParseBool(sf.Tag.Get("secret"))
+		if err := fn(field{
+			value:  fv,
+			path:   path,
+			env:    sf.Tag.Get("env"),
+			def:    sf.Tag.Get("default"),
+			rules:  sf.Tag.Get("validate"),
+			secret: secret,
+		}); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidatePort reports a port outside 1-65535 under the variable name env.
+func ValidatePort(env string, port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("%s must be between 1 and 65535, got %d", env, port)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	HTTPPort int           `env:"TEST_HTTP_PORT" default:"8080" yaml:"http_port" validate:"required"`
+	Brokers  []string      `env:"TEST_BROKERS" default:"localhost:9092" yaml:"brokers"`
+	Timeout  time.Duration `env:"TEST_TIMEOUT" default:"25s" yaml:"timeout"`
+	Debug    bool          `env:"TEST_DEBUG" yaml:"debug"`
+	Database Database      `yaml:"database"`
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg := testConfig{Database: Database{Name: "test_service"}}
+
+	require.NoError(t, Load(&cfg, WithDotEnv("")))
+
+	assert.Equal(t, 8080, cfg.HTTPPort)
+	assert.Equal(t, []string{"localhost:9092"}, cfg.Brokers)
+	assert.Equal(t, 25*time.Second, cfg.Timeout)
+	assert.False(t, cfg.Debug)
+	assert.Equal(t, "postgres", cfg.Database.Driver)
+	assert.Equal(t, 5432, cfg.Database.PortOrDefault())
+	assert.Equal(t, "test_service", cfg.Database.Name, "pre-set values win over defaults")
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	yamlFile := writeFile(t, "config.yaml", `
+http_port: 9000
+timeout: 5s
+debug: true
+database:
+  host: yaml-host
+  name: yaml_db
+  port: 5555
+`)
+	dotEnv := writeFile(t, ".env", "TEST_HTTP_PORT=9100\nDB_HOST=dotenv-host\n")
+	t.Setenv("TEST_HTTP_PORT", "9200")
+	t.Setenv("TEST_BROKERS", "kafka-1:9092, kafka-2:9092")
+
+	var cfg testConfig
+	require.NoError(t, Load(&cfg, WithYAML(yamlFile), WithDotEnv(dotEnv)))
+
+	assert.Equal(t, 9200, cfg.HTTPPort, "environment beats .env and YAML")
+	assert.Equal(t, "dotenv-host", cfg.Database.Host, ".env beats YAML")
+	assert.Equal(t, 5*time.Second, cfg.Timeout, "YAML beats defaults")
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, 5555, cfg.Database.Port)
+	assert.Equal(t, "yaml_db", cfg.Database.Name)
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.Brokers)
+}
+
+func TestLoad_ValidationErrors(t *testing.T) {
+	t.Setenv("TEST_HTTP_PORT", "0")
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_PORT", "70000")
+
+	var cfg testConfig
+	err := Load(&cfg, WithDotEnv(""))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TEST_HTTP_PORT is required")
+	assert.Contains(t, err.Error(), `DB_DRIVER must be one of postgres, mysql, got "sqlite"`)
+	assert.Contains(t, err.Error(), "DB_NAME is required")
+	assert.Contains(t, err.Error(), "DB_PORT must be between 1 and 65535")
+}
+
+func TestLoad_EmptyVariableIsUnset(t *testing.T) {
+	t.Setenv("TEST_HTTP_PORT", "")
+	cfg := testConfig{Database: Database{Name: "db"}}
+
+	require.NoError(t, Load(&cfg, WithDotEnv("")))
+	assert.Equal(t, 8080, cfg.HTTPPort)
+}
+
+func TestLoad_ParseErrors(t *testing.T) {
+	t.Setenv("TEST_HTTP_PORT", "eighty")
+	t.Setenv("TEST_TIMEOUT", "soon")
+
+	var cfg testConfig
+	err := Load(&cfg, WithDotEnv(""))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `TEST_HTTP_PORT: invalid integer "eighty"`)
+	assert.Contains(t, err.Error(), `TEST_TIMEOUT: invalid duration "soon"`)
+}
+
+func TestLoad_MissingFiles(t *testing.T) {
+	cfg := testConfig{Database: Database{Name: "db"}}
+	assert.NoError(t, Load(&cfg, WithDotEnv(filepath.Join(t.TempDir(), ".env"))), "a missing .env is fine")
+	assert.Error(t, Load(&cfg, WithYAML(filepath.Join(t.TempDir(), "config.yaml"))), "a missing YAML file is not")
+	assert.Error(t, Load(cfg), "needs a pointer")
+}
+
+func TestPrint_RedactsSecrets(t *testing.T) {
+	cfg := testConfig{Database: Database{Name: "db", Password: "hunter2"}}
+	require.NoError(t, Load(&cfg, WithDotEnv("")))
+
+	var buf bytes.Buffer
+	require.NoError(t, Print(&buf, &cfg))
+
+	out := buf.String()
+	assert.Contains(t, out, "TEST_HTTP_PORT=8080\n")
+	assert.Contains(t, out, "TEST_BROKERS=localhost:9092\n")
+	assert.Contains(t, out, "DB_PASS="+Redacted+"\n")
+	assert.NotContains(t, out, "hunter2")
+	assert.Equal(t, Redacted, Values(&cfg)["DB_PASS"])
+	assert.Equal(t, "25s", Values(&cfg)["TEST_TIMEOUT"])
+}
+
+func TestDatabase(t *testing.T) {
+	db := Database{Driver: "postgres", Host: "db", User: "u", Password: "secret", Name: "orders", Schema: "public", SSLMode: "disable"}
+	assert.Equal(t, "host=db port=5432 user=u password=secret dbname=orders sslmode=disable search_path=public", db.DSN())
+	assert.Equal(t, "postgres://u@db:5432/orders", db.String())
+	assert.NotContains(t, db.String(), "secret")
+
+	db.Driver, db.Port = "mysql", 3307
+	assert.Equal(t, "u:secret@tcp(db:3307)/orders?parseTime=true", db.DSN())
+}
+
+func TestValidatePort(t *testing.T) {
+	assert.NoError(t, ValidatePort("HTTP_PORT", 8080))
+	assert.EqualError(t, ValidatePort("HTTP_PORT", 0), "HTTP_PORT must be between 1 and 65535, got 0")
+	assert.Error(t, ValidatePort("HTTP_PORT", 65536))
+}
+
+func TestRateLimit(t *testing.T) {
+	var cfg struct {
+		RateLimit RateLimit `yaml:"rate_limit"`
+	}
+	cfg.RateLimit.Rules = []string{"POST /api/orders=10/s"}
+	require.NoError(t, Load(&cfg, WithDotEnv("")))
+	assert.True(t, cfg.RateLimit.Enabled)
+	assert.NotNil(t, cfg.RateLimit.Limiter())
+
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	require.NoError(t, Load(&cfg, WithDotEnv("")))
+	assert.Nil(t, cfg.RateLimit.Limiter())
+
+	t.Setenv("RATE_LIMITS", "POST /api/orders=10/week")
+	err := Load(&cfg, WithDotEnv(""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "RATE_LIMITS:")
+}
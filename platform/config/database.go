@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// Database holds the connection settings shared by the GORM-backed services.
+// Services pre-set Name before Load, since each has its own database.
+type Database struct {
+	Driver   string `env:"DB_DRIVER" default:"postgres" yaml:"driver" validate:"oneof=postgres mysql"`
+	Host     string `env:"DB_HOST" default:"localhost" yaml:"host" validate:"required"`
+	Port     int    `env:"DB_PORT" yaml:"port"`
+	User     string `env:"DB_USER" default:"devuser" yaml:"user" validate:"required"`
+	Password string `env:"DB_PASS" default:"devpass" yaml:"password" secret:"true"`
+	Name     string `env:"DB_NAME" yaml:"name" validate:"required"`
+	Schema   string `env:"DB_SCHEMA" default:"public" yaml:"schema"`
+	SSLMode  string `env:"DB_SSLMODE" default:"disable" yaml:"sslmode"`
+}
+
+// Validate checks the port range; an unset port means the driver's default.
+func (d *Database) Validate() error {
+	if d.Port == 0 {
+		return nil
+	}
+	return ValidatePort("DB_PORT", d.Port)
+}
+
+// PortOrDefault returns Port, or 3306 for MySQL and 5432 for Postgres when
+// it is unset.
+func (d Database) PortOrDefault() int {
+	if d.Port != 0 {
+		return d.Port
+	}
+	if d.Driver == "mysql" {
+		return 3306
+	}
+	return 5432
+}
+
+// DSN is the driver-specific connection string. It contains the password and
+// must not be logged; use String for that.
+func (d Database) DSN() string {
+	port := strconv.Itoa(d.PortOrDefault())
+	if d.Driver == "mysql" {
+		return fmt.Sprintf("%s:%s@tcp(%s)/%s?parseTime=true", d.User, d.Password, net.JoinHostPort(d.Host, port), d.Name)
+	}
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s search_path=%s",
+		d.Host, port, d.User, d.Password, d.Name, d.SSLMode, d.Schema)
+}
+
+// String describes the connection without the password.
+func (d Database) String() string {
+	return fmt.Sprintf("%s://%s@%s/%s", d.Driver, d.User, net.JoinHostPort(d.Host, strconv.Itoa(d.PortOrDefault())), d.Name)
+}
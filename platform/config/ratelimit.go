@@ -0,0 +1,33 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/ratelimit"
+)
+
+// RateLimit configures the per-client limits of a service's public routes.
+// Services pre-set Rules before Load with the routes they expose.
+type RateLimit struct {
+	Enabled bool `env:"RATE_LIMIT_ENABLED" default:"true" yaml:"enabled"`
+	// Rules are "route=count/unit[:burst]" entries, where a route is
+	// "METHOD /path" or a gRPC full method and may end in "*".
+	Rules []string `env:"RATE_LIMITS" yaml:"rules"`
+}
+
+func (r *RateLimit) Validate() error {
+	if _, err := ratelimit.ParseRules(r.Rules); err != nil {
+		return fmt.Errorf("RATE_LIMITS: %w", err)
+	}
+	return nil
+}
+
+// Limiter builds an in-memory limiter for the rules. It is nil, and limits
+// nothing, when rate limiting is disabled.
+func (r RateLimit) Limiter(opts ...ratelimit.Option) *ratelimit.Limiter {
+	if !r.Enabled {
+		return nil
+	}
+	rules, _ := ratelimit.ParseRules(r.Rules)
+	return ratelimit.New(ratelimit.NewMemoryStore(), rules, opts...)
+}
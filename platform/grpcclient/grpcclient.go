@@ -0,0 +1,191 @@
+// Package grpcclient makes outgoing gRPC connections resilient. Deadlines and
+// retries are expressed as a gRPC service config, so grpc-go applies them per
+// attempt with its own jittered exponential backoff; a circuit breaker
+// interceptor sits outside the retries and sees only the final outcome of
+// each call.
+package grpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/breaker"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Retry is the retry policy of a service. grpc-go waits a random time between
+// zero and the current backoff before each retry, and caps attempts at 5.
+type Retry struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+}
+
+// DefaultRetry retries Unavailable twice, 100ms and then up to 200ms apart.
+var DefaultRetry = Retry{MaxAttempts: 3, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+
+// Policy describes calls to one downstream gRPC service.
+type Policy struct {
+	// Service is the fully qualified service name, e.g. "user_service.UserService".
+	Service string
+	// Timeout bounds every call to the service, including retries.
+	Timeout time.Duration
+	// MethodTimeouts override Timeout, keyed by "package.Service/Method".
+	// Entries for other services are ignored, so one map can serve every
+	// Policy of a client.
+	MethodTimeouts map[string]time.Duration
+	Retry          Retry
+}
+
+// RetryableCodes are retried by the service config. Only Unavailable is
+// safe: the call never reached the server's handler.
+var RetryableCodes = []codes.Code{codes.Unavailable}
+
+type methodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method,omitempty"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+type methodConfig struct {
+	Name        []methodName `json:"name"`
+	Timeout     string       `json:"timeout,omitempty"`
+	RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+type serviceConfig struct {
+	MethodConfig []methodConfig `json:"methodConfig"`
+}
+
+// ServiceConfig renders the policy as a gRPC service config in JSON.
+func (p Policy) ServiceConfig() string {
+	var retry *retryPolicy
+	if p.Retry.MaxAttempts > 1 {
+		retry = &retryPolicy{
+			MaxAttempts:       p.Retry.MaxAttempts,
+			InitialBackoff:    duration(p.Retry.InitialBackoff),
+			MaxBackoff:        duration(p.Retry.MaxBackoff),
+			BackoffMultiplier: p.Retry.Multiplier,
+		}
+		for _, code := range RetryableCodes {
+			retry.RetryableStatusCodes = append(retry.RetryableStatusCodes, statusName(code))
+		}
+	}
+
+	cfg := serviceConfig{MethodConfig: []methodConfig{{
+		Name:        []methodName{{Service: p.Service}},
+		Timeout:     duration(p.Timeout),
+		RetryPolicy: retry,
+	}}}
+
+	methods := make([]string, 0, len(p.MethodTimeouts))
+	for fullMethod := range p.MethodTimeouts {
+		methods = append(methods, fullMethod)
+	}
+	sort.Strings(methods)
+	for _, fullMethod := range methods {
+		service, method, ok := strings.Cut(fullMethod, "/")
+		if !ok || service != p.Service {
+			continue
+		}
+		cfg.MethodConfig = append(cfg.MethodConfig, methodConfig{
+			Name:        []methodName{{Service: service, Method: method}},
+			Timeout:     duration(p.MethodTimeouts[fullMethod]),
+			RetryPolicy: retry,
+		})
+	}
+
+	out, _ := json.Marshal(cfg)
+	return string(out)
+}
+
+// DialOptions applies the policy and, when b is not nil, the circuit breaker.
+func DialOptions(p Policy, b *breaker.Breaker) []grpc.DialOption {
+	opts := []grpc.DialOption{grpc.WithDefaultServiceConfig(p.ServiceConfig())}
+	if b != nil {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(b)))
+	}
+	return opts
+}
+
+// UnaryClientInterceptor rejects calls with Unavailable while b is open and
+// records the outcome of the calls it lets through.
+func UnaryClientInterceptor(b *breaker.Breaker) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := b.Allow(); err != nil {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		b.Record(IsFailure(ctx, err))
+		return err
+	}
+}
+
+// IsFailure reports whether err says the downstream is unhealthy. Errors
+// about the request itself, such as NotFound or InvalidArgument, and calls
+// the caller cancelled do not count.
+func IsFailure(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() == context.Canceled {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseMethodTimeouts parses "package.Service/Method=duration" entries.
+func ParseMethodTimeouts(entries []string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(entries))
+	for _, entry := range entries {
+		method, raw, ok := strings.Cut(entry, "=")
+		if !ok || !strings.Contains(method, "/") {
+			return nil, fmt.Errorf("method timeout %q is not package.Service/Method=duration", entry)
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("method timeout %q has an invalid duration", entry)
+		}
+		timeouts[method] = d
+	}
+	return timeouts, nil
+}
+
+// duration formats d the way protobuf JSON expects, e.g. "0.1s".
+func duration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// statusName spells code the way the service config does, e.g.
+// "DEADLINE_EXCEEDED" for codes.DeadlineExceeded.
+func statusName(code codes.Code) string {
+	var b strings.Builder
+	for i, r := range code.String() {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String())
+}
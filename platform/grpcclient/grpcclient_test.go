@@ -0,0 +1,167 @@
+package grpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/breaker"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const healthService = "grpc.health.v1.Health"
+
+// flakyHealth fails the first failures calls with Unavailable and sleeps for
+// delay on every call.
+type flakyHealth struct {
+	healthpb.UnimplementedHealthServer
+	failures int32
+	delay    time.Duration
+	calls    atomic.Int32
+}
+
+func (s *flakyHealth) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	n := s.calls.Add(1)
+	if s.delay > 0 {
+		select {
+		case <-time.After(s.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if n <= s.failures {
+		return nil, status.Error(codes.Unavailable, "injected failure")
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func dial(t *testing.T, srv *flakyHealth, p Policy, b *breaker.Breaker) healthpb.HealthClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, srv)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	opts := append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, DialOptions(p, b)...)
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+var fastRetry = Retry{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond, Multiplier: 2}
+
+func TestRetry_RecoversFromUnavailable(t *testing.T) {
+	srv := &flakyHealth{failures: 2}
+	client := dial(t, srv, Policy{Service: healthService, Timeout: time.Second, Retry: fastRetry}, nil)
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), srv.calls.Load())
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	srv := &flakyHealth{failures: 10}
+	client := dial(t, srv, Policy{Service: healthService, Timeout: time.Second, Retry: fastRetry}, nil)
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, int32(3), srv.calls.Load())
+}
+
+func TestMethodTimeout(t *testing.T) {
+	srv := &flakyHealth{delay: time.Second}
+	client := dial(t, srv, Policy{
+		Service:        healthService,
+		Timeout:        5 * time.Second,
+		MethodTimeouts: map[string]time.Duration{healthService + "/Check": 50 * time.Millisecond},
+	}, nil)
+
+	start := time.Now()
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestBreaker_OpensAndRejectsWithoutCalling(t *testing.T) {
+	srv := &flakyHealth{failures: 100}
+	b := breaker.New("grpcclient-test", breaker.Settings{FailureThreshold: 2, OpenTimeout: time.Minute})
+	client := dial(t, srv, Policy{Service: healthService, Timeout: time.Second}, b)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	}
+	require.Equal(t, breaker.StateOpen, b.State())
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.ErrorContains(t, err, breaker.ErrOpen.Error())
+	assert.Equal(t, int32(2), srv.calls.Load(), "an open breaker does not reach the server")
+}
+
+func TestIsFailure(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, IsFailure(ctx, nil))
+	assert.True(t, IsFailure(ctx, status.Error(codes.Unavailable, "")))
+	assert.True(t, IsFailure(ctx, status.Error(codes.DeadlineExceeded, "")))
+	assert.False(t, IsFailure(ctx, status.Error(codes.NotFound, "")))
+	assert.False(t, IsFailure(ctx, status.Error(codes.InvalidArgument, "")))
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.False(t, IsFailure(cancelled, status.Error(codes.Canceled, "")))
+}
+
+func TestServiceConfig(t *testing.T) {
+	p := Policy{
+		Service: "user_service.UserService",
+		Timeout: 2 * time.Second,
+		MethodTimeouts: map[string]time.Duration{
+			"user_service.UserService/GetUser":           500 * time.Millisecond,
+			"inventory_service.InventoryService/GetItem": time.Second,
+		},
+		Retry: DefaultRetry,
+	}
+
+	var cfg serviceConfig
+	require.NoError(t, json.Unmarshal([]byte(p.ServiceConfig()), &cfg))
+
+	require.Len(t, cfg.MethodConfig, 2, "other services' methods are skipped")
+	assert.Equal(t, "2s", cfg.MethodConfig[0].Timeout)
+	assert.Equal(t, "0.5s", cfg.MethodConfig[1].Timeout)
+	assert.Equal(t, "GetUser", cfg.MethodConfig[1].Name[0].Method)
+	require.NotNil(t, cfg.MethodConfig[1].RetryPolicy)
+	assert.Equal(t, []string{"UNAVAILABLE"}, cfg.MethodConfig[1].RetryPolicy.RetryableStatusCodes)
+	assert.Equal(t, "0.1s", cfg.MethodConfig[0].RetryPolicy.InitialBackoff)
+	assert.Equal(t, "DEADLINE_EXCEEDED", statusName(codes.DeadlineExceeded))
+}
+
+func TestParseMethodTimeouts(t *testing.T) {
+	timeouts, err := ParseMethodTimeouts([]string{"user_service.UserService/GetUser=750ms"})
+	require.NoError(t, err)
+	assert.Equal(t, 750*time.Millisecond, timeouts["user_service.UserService/GetUser"])
+
+	_, err = ParseMethodTimeouts([]string{"GetUser=1s"})
+	assert.Error(t, err)
+	_, err = ParseMethodTimeouts([]string{"user_service.UserService/GetUser=soon"})
+	assert.Error(t, err)
+}
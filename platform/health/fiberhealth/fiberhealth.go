@@ -0,0 +1,20 @@
+// Package fiberhealth mounts the liveness and readiness probes on a Fiber
+// router.
+package fiberhealth
+
+import (
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Register adds GET /livez and GET /readyz.
+func Register(router fiber.Router, checker *health.Checker) {
+	router.Get("/livez", func(c *fiber.Ctx) error {
+		return c.JSON(health.Report{Status: health.StatusUp})
+	})
+	router.Get("/readyz", func(c *fiber.Ctx) error {
+		report := checker.Check(c.UserContext())
+		return c.Status(report.StatusCode()).JSON(report)
+	})
+}
@@ -0,0 +1,44 @@
+package fiberhealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	var dbErr error
+	checker := health.NewChecker(time.Second)
+	checker.Register("database", func(ctx context.Context) error { return dbErr })
+
+	app := fiber.New()
+	Register(app, checker)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/livez", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	dbErr = errors.New("connection refused")
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	var report health.Report
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	assert.Equal(t, health.StatusDown, report.Components["database"].Status)
+	assert.Equal(t, "connection refused", report.Components["database"].Error)
+}
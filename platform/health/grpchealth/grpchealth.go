@@ -0,0 +1,75 @@
+// Package grpchealth checks outgoing gRPC connections and keeps the standard
+// grpc.health.v1 Health service in step with a health.Checker.
+package grpchealth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// DefaultInterval is how often Sync re-runs the checks.
+const DefaultInterval = 10 * time.Second
+
+// Conn checks the connectivity state of conn. An idle connection is asked
+// to reconnect and counts as healthy, since gRPC only leaves the idle state
+// when a call is made.
+func Conn(conn *grpc.ClientConn) health.CheckFunc {
+	return func(ctx context.Context) error {
+		switch state := conn.GetState(); state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Idle:
+			conn.Connect()
+			return nil
+		default:
+			return fmt.Errorf("connection to %s is %s", conn.Target(), state)
+		}
+	}
+}
+
+// NewServer returns the grpc-go Health service implementation. Register it
+// with healthpb.RegisterHealthServer and keep it current with Sync.
+func NewServer() *grpchealth.Server {
+	return grpchealth.NewServer()
+}
+
+// Register adds the Health service to s.
+func Register(s grpc.ServiceRegistrar, srv healthpb.HealthServer) {
+	healthpb.RegisterHealthServer(s, srv)
+}
+
+// Sync runs checker every interval and reports the result as the serving
+// status of the overall server ("") and of each named service, until ctx is
+// cancelled. The first check runs immediately.
+func Sync(ctx context.Context, srv *grpchealth.Server, checker *health.Checker, interval time.Duration, services ...string) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status := healthpb.HealthCheckResponse_NOT_SERVING
+		if checker.Check(ctx).Healthy() {
+			status = healthpb.HealthCheckResponse_SERVING
+		}
+		srv.SetServingStatus("", status)
+		for _, service := range services {
+			srv.SetServingStatus(service, status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package grpchealth
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestSyncAndConn(t *testing.T) {
+	var depErr atomic.Pointer[error]
+	checker := health.NewChecker(time.Second)
+	checker.Register("dependency", func(ctx context.Context) error {
+		if err := depErr.Load(); err != nil {
+			return *err
+		}
+		return nil
+	})
+
+	srv := NewServer()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	Register(s, srv)
+	go s.Serve(lis)
+	defer s.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	status := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		return resp.Status
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	synced := make(chan struct{})
+	go func() {
+		defer close(synced)
+		Sync(ctx, srv, checker, 10*time.Millisecond, "test.Service")
+	}()
+
+	assert.Eventually(t, func() bool { return status("test.Service") == healthpb.HealthCheckResponse_SERVING }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status(""))
+
+	down := errors.New("down")
+	depErr.Store(&down)
+	assert.Eventually(t, func() bool { return status("") == healthpb.HealthCheckResponse_NOT_SERVING }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-synced
+
+	// The client connection has been used, so it is ready.
+	assert.NoError(t, Conn(conn)(context.Background()))
+	conn.Close()
+	assert.ErrorContains(t, Conn(conn)(context.Background()), "SHUTDOWN")
+}
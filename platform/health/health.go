@@ -0,0 +1,185 @@
+// Package health runs dependency checks for the readiness probes. Liveness
+// only says the process is up and able to serve HTTP; it deliberately does
+// not look at dependencies, so an outage of a database or broker makes pods
+// unready instead of restarting them.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// DefaultTimeout bounds each check when the Checker is created with zero.
+const DefaultTimeout = 2 * time.Second
+
+// CheckFunc reports a dependency as healthy by returning nil.
+type CheckFunc func(ctx context.Context) error
+
+// Component is the result of one named check.
+type Component struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the JSON body of the readiness endpoints.
+type Report struct {
+	Status     string               `json:"status"`
+	Components map[string]Component `json:"components,omitempty"`
+}
+
+// Healthy reports whether every component is up.
+func (r Report) Healthy() bool {
+	return r.Status == StatusUp
+}
+
+// StatusCode is the HTTP status the probes answer with.
+func (r Report) StatusCode() int {
+	if r.Healthy() {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
+
+// Checker holds the named dependency checks of a service.
+type Checker struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks map[string]CheckFunc
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Checker{
+		timeout: timeout,
+		checks:  make(map[string]CheckFunc),
+	}
+}
+
+// Register adds or replaces the check reported under name.
+func (c *Checker) Register(name string, check CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// Check runs every check concurrently. A check that outlives the timeout is
+// reported as down even if it ignores its context.
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make(map[string]CheckFunc, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.RUnlock()
+
+	report := Report{Status: StatusUp, Components: make(map[string]Component, len(checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check CheckFunc) {
+			defer wg.Done()
+			component := c.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[name] = component
+			if component.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(name, check)
+	}
+	wg.Wait()
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check CheckFunc) Component {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out after %s", c.timeout)
+	}
+
+	component := Component{Status: StatusUp, Duration: time.Since(start).Round(time.Microsecond).String()}
+	if err != nil {
+		component.Status = StatusDown
+		component.Error = err.Error()
+	}
+	return component
+}
+
+// LiveHandler always answers 200 {"status":"up"}.
+func LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, Report{Status: StatusUp})
+	})
+}
+
+// ReadyHandler answers 200 when every check passes and 503 otherwise, with
+// the per-component report as the body.
+func ReadyHandler(c *Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, c.Check(r.Context()))
+	})
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(report.StatusCode())
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// Pinger is satisfied by *sql.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Ping checks a database connection pool.
+func Ping(db Pinger) CheckFunc {
+	return db.PingContext
+}
+
+// HTTPGet checks that url answers with a 2xx status.
+func HTTPGet(client *http.Client, url string) CheckFunc {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("GET %s returned %s", url, resp.Status)
+		}
+		return nil
+	}
+}
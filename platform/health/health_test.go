@@ -0,0 +1,94 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker(t *testing.T) {
+	t.Run("AllUp", func(t *testing.T) {
+		checker := NewChecker(time.Second)
+		checker.Register("database", func(ctx context.Context) error { return nil })
+		checker.Register("kafka", func(ctx context.Context) error { return nil })
+
+		report := checker.Check(context.Background())
+		assert.True(t, report.Healthy())
+		assert.Equal(t, http.StatusOK, report.StatusCode())
+		assert.Len(t, report.Components, 2)
+		assert.Equal(t, StatusUp, report.Components["kafka"].Status)
+	})
+
+	t.Run("OneDown", func(t *testing.T) {
+		checker := NewChecker(time.Second)
+		checker.Register("database", func(ctx context.Context) error { return nil })
+		checker.Register("kafka", func(ctx context.Context) error { return errors.New("no brokers") })
+
+		report := checker.Check(context.Background())
+		assert.False(t, report.Healthy())
+		assert.Equal(t, http.StatusServiceUnavailable, report.StatusCode())
+		assert.Equal(t, StatusUp, report.Components["database"].Status)
+		assert.Equal(t, Component{Status: StatusDown, Error: "no brokers", Duration: report.Components["kafka"].Duration}, report.Components["kafka"])
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		checker := NewChecker(20 * time.Millisecond)
+		block := make(chan struct{})
+		defer close(block)
+		checker.Register("stuck", func(ctx context.Context) error {
+			<-block
+			return nil
+		})
+
+		start := time.Now()
+		report := checker.Check(context.Background())
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, StatusDown, report.Components["stuck"].Status)
+		assert.Contains(t, report.Components["stuck"].Error, "timed out")
+	})
+
+	t.Run("NoChecks", func(t *testing.T) {
+		assert.True(t, NewChecker(0).Check(context.Background()).Healthy())
+	})
+}
+
+func TestHandlers(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Register("database", func(ctx context.Context) error { return errors.New("connection refused") })
+
+	rec := httptest.NewRecorder()
+	LiveHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"up"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	ReadyHandler(checker).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var report Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, "connection refused", report.Components["database"].Error)
+}
+
+func TestHTTPGet(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	check := HTTPGet(server.Client(), server.URL)
+	assert.NoError(t, check(context.Background()))
+
+	status = http.StatusInternalServerError
+	assert.ErrorContains(t, check(context.Background()), "500")
+}
@@ -0,0 +1,32 @@
+// Package kafkahealth checks Kafka brokers and the schema registry.
+package kafkahealth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health"
+
+	"github.com/IBM/sarama"
+)
+
+// Brokers refreshes the cluster metadata through client, which fails when
+// no broker answers.
+func Brokers(client sarama.Client) health.CheckFunc {
+	return func(ctx context.Context) error {
+		if err := client.RefreshMetadata(); err != nil {
+			return err
+		}
+		if len(client.Brokers()) == 0 {
+			return errors.New("no brokers in cluster metadata")
+		}
+		return nil
+	}
+}
+
+// SchemaRegistry checks that the registry at url lists its subjects.
+func SchemaRegistry(client *http.Client, url string) health.CheckFunc {
+	return health.HTTPGet(client, strings.TrimRight(url, "/")+"/subjects")
+}
@@ -0,0 +1,44 @@
+package kafkahealth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrokers(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).SetBroker(broker.Addr(), broker.BrokerID()),
+	})
+
+	config := sarama.NewConfig()
+	config.Metadata.Retry.Max = 0
+	client, err := sarama.NewClient([]string{broker.Addr()}, config)
+	require.NoError(t, err)
+	defer client.Close()
+
+	check := Brokers(client)
+	assert.NoError(t, check(context.Background()))
+
+	broker.Close()
+	assert.Error(t, check(context.Background()))
+}
+
+func TestSchemaRegistry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/subjects" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`["order-events-value"]`))
+	}))
+	defer server.Close()
+
+	assert.NoError(t, SchemaRegistry(server.Client(), server.URL+"/")(context.Background()))
+}
@@ -0,0 +1,160 @@
+// Package lazy connects to a service dependency in the background, so a
+// service can start serving before its dependencies are up. Connect keeps
+// retrying with jittered exponential backoff; until it succeeds Get fails with
+// ErrNotReady and Check fails the readiness probe, and only the calls that need
+// the dependency are affected.
+package lazy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrNotReady is returned while a dependency is still connecting.
+var ErrNotReady = errors.New("dependency is not ready")
+
+// Backoff is the wait between connection attempts. Each wait is drawn
+// uniformly from zero to the current backoff ("full jitter"), which grows by
+// Multiplier up to Max.
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+var DefaultBackoff = Backoff{Initial: 500 * time.Millisecond, Max: 30 * time.Second, Multiplier: 2}
+
+// Delay returns the wait after the given number of failed attempts.
+func (b Backoff) Delay(failures int) time.Duration {
+	ceiling := float64(b.Initial)
+	for i := 1; i < failures && ceiling < float64(b.Max); i++ {
+		ceiling *= b.Multiplier
+	}
+	if ceiling > float64(b.Max) {
+		ceiling = float64(b.Max)
+	}
+	if ceiling < 1 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(ceiling)) + 1)
+}
+
+type Option func(*settings)
+
+type settings struct {
+	backoff Backoff
+}
+
+// WithBackoff replaces DefaultBackoff.
+func WithBackoff(b Backoff) Option {
+	return func(s *settings) { s.backoff = b }
+}
+
+// Value is a dependency of type T that connects in the background.
+type Value[T any] struct {
+	name   string
+	cancel context.CancelFunc
+	done   chan struct{}
+	ready  chan struct{}
+
+	mu      sync.RWMutex
+	value   T
+	ok      bool
+	lastErr error
+}
+
+// Connect calls connect in a new goroutine until it succeeds or the Value is
+// closed. Failed attempts are logged as warnings.
+func Connect[T any](name string, logger *zap.Logger, connect func(context.Context) (T, error), opts ...Option) *Value[T] {
+	s := settings{backoff: DefaultBackoff}
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	v := &Value[T]{
+		name:    name,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		ready:   make(chan struct{}),
+		lastErr: errors.New("connecting"),
+	}
+	go v.run(ctx, logger, connect, s.backoff)
+	return v
+}
+
+func (v *Value[T]) run(ctx context.Context, logger *zap.Logger, connect func(context.Context) (T, error), backoff Backoff) {
+	defer close(v.done)
+	for failures := 1; ; failures++ {
+		value, err := connect(ctx)
+		if err == nil {
+			v.mu.Lock()
+			v.value, v.ok, v.lastErr = value, true, nil
+			v.mu.Unlock()
+			close(v.ready)
+			logger.Info("Dependency connected", zap.String("dependency", v.name), zap.Int("attempts", failures))
+			return
+		}
+
+		v.mu.Lock()
+		v.lastErr = err
+		v.mu.Unlock()
+
+		delay := backoff.Delay(failures)
+		logger.Warn("Dependency not available, retrying",
+			zap.String("dependency", v.name), zap.Int("attempt", failures), zap.Duration("retry_in", delay), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// Get returns the connected value, or an error wrapping ErrNotReady and the
+// last connection error.
+func (v *Value[T]) Get() (T, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if !v.ok {
+		var zero T
+		return zero, fmt.Errorf("%s: %w: %v", v.name, ErrNotReady, v.lastErr)
+	}
+	return v.value, nil
+}
+
+// Ready is closed once the dependency has connected.
+func (v *Value[T]) Ready() <-chan struct{} {
+	return v.ready
+}
+
+// Check fails until the dependency has connected, for use as a readiness
+// check.
+func (v *Value[T]) Check(ctx context.Context) error {
+	_, err := v.Get()
+	return err
+}
+
+// Close stops connecting and closes the value if it is an io.Closer.
+func (v *Value[T]) Close() error {
+	v.cancel()
+	<-v.done
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if !v.ok {
+		return nil
+	}
+	v.ok, v.lastErr = false, errors.New("closed")
+	if closer, isCloser := any(v.value).(io.Closer); isCloser {
+		return closer.Close()
+	}
+	return nil
+}
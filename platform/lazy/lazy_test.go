@@ -0,0 +1,76 @@
+package lazy
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var fast = WithBackoff(Backoff{Initial: time.Millisecond, Max: 2 * time.Millisecond, Multiplier: 2})
+
+type closer struct{ closed atomic.Bool }
+
+func (c *closer) Close() error {
+	c.closed.Store(true)
+	return nil
+}
+
+func TestConnect_RetriesUntilConnected(t *testing.T) {
+	var attempts atomic.Int32
+	unblock := make(chan struct{})
+	v := Connect("flaky", zap.NewNop(), func(ctx context.Context) (*closer, error) {
+		if attempts.Add(1) < 3 {
+			return nil, errors.New("connection refused")
+		}
+		<-unblock
+		return &closer{}, nil
+	}, fast)
+
+	require.Eventually(t, func() bool { return attempts.Load() == 3 }, time.Second, time.Millisecond)
+	_, err := v.Get()
+	assert.ErrorIs(t, err, ErrNotReady)
+	assert.ErrorContains(t, err, "connection refused")
+	assert.ErrorIs(t, v.Check(context.Background()), ErrNotReady)
+
+	close(unblock)
+	<-v.Ready()
+	c, err := v.Get()
+	require.NoError(t, err)
+	assert.NoError(t, v.Check(context.Background()))
+
+	require.NoError(t, v.Close())
+	assert.True(t, c.closed.Load())
+	_, err = v.Get()
+	assert.ErrorIs(t, err, ErrNotReady)
+}
+
+func TestClose_StopsRetrying(t *testing.T) {
+	var attempts atomic.Int32
+	v := Connect("down", zap.NewNop(), func(ctx context.Context) (int, error) {
+		attempts.Add(1)
+		return 0, errors.New("down")
+	}, fast)
+	require.Eventually(t, func() bool { return attempts.Load() > 1 }, time.Second, time.Millisecond)
+
+	require.NoError(t, v.Close())
+	n := attempts.Load()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, n, attempts.Load())
+}
+
+func TestBackoff_Delay(t *testing.T) {
+	b := Backoff{Initial: 100 * time.Millisecond, Max: time.Second, Multiplier: 2}
+	for i := 0; i < 100; i++ {
+		assert.LessOrEqual(t, b.Delay(1), 100*time.Millisecond)
+		assert.LessOrEqual(t, b.Delay(3), 400*time.Millisecond)
+		d := b.Delay(20)
+		assert.Positive(t, d)
+		assert.LessOrEqual(t, d, time.Second)
+	}
+}
@@ -0,0 +1,238 @@
+// Package lifecycle runs the servers and background workers of a service and
+// shuts them down gracefully. Components are stopped in the reverse order in
+// which they were added, so a service adds its closers (producers, database
+// pools, tracing) first and its servers last: the servers stop taking new
+// work and drain in-flight requests before anything they depend on is
+// closed. The whole shutdown is bounded by a drain timeout.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultDrainTimeout bounds the shutdown when the Runner is created with
+// zero. It stays below the 30s termination grace period of Kubernetes.
+const DefaultDrainTimeout = 25 * time.Second
+
+// Component is a long-running part of a service. Run blocks until the
+// component stops; Stop asks it to stop and waits for it to drain, giving up
+// when ctx is done.
+type Component interface {
+	Run() error
+	Stop(ctx context.Context) error
+}
+
+type unit struct {
+	name string
+	// run is nil for units that only have something to close.
+	run  func() error
+	stop func(ctx context.Context) error
+}
+
+// Runner starts components and stops them when the context passed to Run is
+// cancelled or when any component exits on its own.
+type Runner struct {
+	logger       *zap.Logger
+	drainTimeout time.Duration
+	units        []unit
+}
+
+func NewRunner(logger *zap.Logger, drainTimeout time.Duration) *Runner {
+	if drainTimeout <= 0 {
+		drainTimeout = DefaultDrainTimeout
+	}
+	return &Runner{logger: logger, drainTimeout: drainTimeout}
+}
+
+// Add registers a component under name.
+func (r *Runner) Add(name string, c Component) {
+	r.units = append(r.units, unit{name: name, run: c.Run, stop: c.Stop})
+}
+
+// Go registers a background worker. fn runs until its context is cancelled
+// at shutdown; stopping waits for fn to return.
+func (r *Runner) Go(name string, fn func(ctx context.Context) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	r.units = append(r.units, unit{
+		name: name,
+		run: func() error {
+			defer close(done)
+			return fn(ctx)
+		},
+		stop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+}
+
+// OnStop registers fn to run during shutdown, in the same reverse order as
+// the components.
+func (r *Runner) OnStop(name string, fn func(ctx context.Context) error) {
+	r.units = append(r.units, unit{name: name, stop: fn})
+}
+
+// Run starts every component and blocks until ctx is cancelled or one of them
+// exits, then stops everything. It returns the error of the component that
+// exited, if any, joined with the errors of the shutdown.
+func (r *Runner) Run(ctx context.Context) error {
+	exited := make(chan error, len(r.units))
+	for _, u := range r.units {
+		if u.run == nil {
+			continue
+		}
+		r.logger.Info("Starting component", zap.String("component", u.name))
+		go func(u unit) {
+			err := u.run()
+			if err == nil {
+				err = fmt.Errorf("%s exited", u.name)
+			} else {
+				err = fmt.Errorf("%s: %w", u.name, err)
+			}
+			exited <- err
+		}(u)
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		r.logger.Info("Shutting down", zap.Duration("drain_timeout", r.drainTimeout))
+	case runErr = <-exited:
+		r.logger.Error("Component stopped unexpectedly, shutting down", zap.Error(runErr))
+	}
+
+	return errors.Join(runErr, r.shutdown())
+}
+
+func (r *Runner) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.drainTimeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(r.units) - 1; i >= 0; i-- {
+		u := r.units[i]
+		start := time.Now()
+		if err := u.stop(ctx); err != nil {
+			r.logger.Error("Failed to stop component", zap.String("component", u.name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("stop %s: %w", u.name, err))
+			continue
+		}
+		r.logger.Info("Stopped component", zap.String("component", u.name), zap.Duration("duration", time.Since(start)))
+	}
+	return errors.Join(errs...)
+}
+
+// Close adapts an io.Closer, such as a Kafka producer or a gRPC client
+// connection, for OnStop. It stops waiting for Close when ctx is done, and
+// leaves it running.
+func Close(c io.Closer) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		closed := make(chan error, 1)
+		go func() { closed <- c.Close() }()
+		select {
+		case err := <-closed:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// GRPCServer is satisfied by *grpc.Server.
+type GRPCServer interface {
+	Serve(lis net.Listener) error
+	GracefulStop()
+	Stop()
+}
+
+type grpcComponent struct {
+	srv  GRPCServer
+	addr string
+}
+
+// GRPC serves srv on addr. Stopping waits for in-flight RPCs with
+// GracefulStop and cancels whatever is left when the drain timeout runs out.
+func GRPC(srv GRPCServer, addr string) Component {
+	return &grpcComponent{srv: srv, addr: addr}
+}
+
+func (g *grpcComponent) Run() error {
+	lis, err := net.Listen("tcp", g.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", g.addr, err)
+	}
+	return g.srv.Serve(lis)
+}
+
+func (g *grpcComponent) Stop(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		g.srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		g.srv.Stop()
+		return ctx.Err()
+	}
+}
+
+// FiberApp is satisfied by *fiber.App.
+type FiberApp interface {
+	Listen(addr string) error
+	ShutdownWithContext(ctx context.Context) error
+}
+
+type fiberComponent struct {
+	app  FiberApp
+	addr string
+}
+
+// Fiber serves app on addr and shuts it down with ShutdownWithContext.
+func Fiber(app FiberApp, addr string) Component {
+	return &fiberComponent{app: app, addr: addr}
+}
+
+func (f *fiberComponent) Run() error {
+	return f.app.Listen(f.addr)
+}
+
+func (f *fiberComponent) Stop(ctx context.Context) error {
+	return f.app.ShutdownWithContext(ctx)
+}
+
+type httpComponent struct {
+	srv *http.Server
+}
+
+// HTTP serves srv on its Addr and shuts it down with Shutdown.
+func HTTP(srv *http.Server) Component {
+	return &httpComponent{srv: srv}
+}
+
+func (h *httpComponent) Run() error {
+	if err := h.srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (h *httpComponent) Stop(ctx context.Context) error {
+	return h.srv.Shutdown(ctx)
+}
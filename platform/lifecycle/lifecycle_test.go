@@ -0,0 +1,253 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// fakeComponent runs until stopped and records the stop in a shared log.
+type fakeComponent struct {
+	name    string
+	log     *[]string
+	mu      *sync.Mutex
+	stopped chan struct{}
+	runErr  error
+	block   bool
+}
+
+func newFake(name string, log *[]string, mu *sync.Mutex) *fakeComponent {
+	return &fakeComponent{name: name, log: log, mu: mu, stopped: make(chan struct{})}
+}
+
+func (f *fakeComponent) Run() error {
+	if f.runErr != nil {
+		return f.runErr
+	}
+	<-f.stopped
+	return nil
+}
+
+func (f *fakeComponent) Stop(ctx context.Context) error {
+	f.mu.Lock()
+	*f.log = append(*f.log, f.name)
+	f.mu.Unlock()
+	if f.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	close(f.stopped)
+	return nil
+}
+
+func TestRunner_StopsInReverseOrder(t *testing.T) {
+	var (
+		log []string
+		mu  sync.Mutex
+	)
+	r := NewRunner(zap.NewNop(), time.Second)
+	r.OnStop("producer", func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		log = append(log, "producer")
+		return nil
+	})
+	r.Go("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		mu.Lock()
+		defer mu.Unlock()
+		log = append(log, "worker")
+		return nil
+	})
+	r.Add("grpc", newFake("grpc", &log, &mu))
+	r.Add("http", newFake("http", &log, &mu))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("runner did not stop")
+	}
+	assert.Equal(t, []string{"http", "grpc", "worker", "producer"}, log)
+}
+
+func TestRunner_ComponentFailureStopsTheRest(t *testing.T) {
+	var (
+		log []string
+		mu  sync.Mutex
+	)
+	r := NewRunner(zap.NewNop(), time.Second)
+	r.Add("grpc", newFake("grpc", &log, &mu))
+	failing := newFake("http", &log, &mu)
+	failing.runErr = errors.New("address already in use")
+	r.Add("http", failing)
+
+	err := r.Run(context.Background())
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, failing.runErr)
+	assert.Contains(t, err.Error(), "http: address already in use")
+	assert.Equal(t, []string{"http", "grpc"}, log)
+}
+
+func TestRunner_DrainTimeout(t *testing.T) {
+	var (
+		log []string
+		mu  sync.Mutex
+	)
+	r := NewRunner(zap.NewNop(), 50*time.Millisecond)
+	closed := false
+	r.OnStop("producer", func(ctx context.Context) error {
+		closed = true
+		return nil
+	})
+	stuck := newFake("grpc", &log, &mu)
+	stuck.block = true
+	r.Add("grpc", stuck)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	err := r.Run(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.True(t, closed, "later stops still run after a timeout")
+}
+
+// closerFunc is an io.Closer.
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+func TestClose(t *testing.T) {
+	errClose := errors.New("close failed")
+	assert.ErrorIs(t, Close(closerFunc(func() error { return errClose }))(context.Background()), errClose)
+
+	release := make(chan struct{})
+	defer close(release)
+	stuck := closerFunc(func() error {
+		<-release
+		return nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+
+	assert.ErrorIs(t, Close(stuck)(ctx), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second, "a closer that ignores cancellation does not hold up the shutdown")
+}
+
+func TestNewRunner_DefaultDrainTimeout(t *testing.T) {
+	assert.Equal(t, DefaultDrainTimeout, NewRunner(zap.NewNop(), 0).drainTimeout)
+}
+
+func TestGRPC_GracefulStop(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+
+	r := NewRunner(zap.NewNop(), time.Second)
+	r.Add("grpc", GRPC(srv, addr))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+	assert.Eventually(t, func() bool {
+		_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.Error(t, err)
+}
+
+func TestFiber_DrainsInFlightRequest(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+
+	started := make(chan struct{})
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/slow", func(c *fiber.Ctx) error {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		return c.SendString("done")
+	})
+
+	r := NewRunner(zap.NewNop(), time.Second)
+	r.Add("http", Fiber(app, addr))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	type result struct {
+		status int
+		err    error
+	}
+	res := make(chan result)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/slow")
+		if err != nil {
+			res <- result{err: err}
+			return
+		}
+		resp.Body.Close()
+		res <- result{status: resp.StatusCode}
+	}()
+
+	<-started
+	cancel()
+
+	got := <-res
+	require.NoError(t, got.err)
+	assert.Equal(t, http.StatusOK, got.status)
+	require.NoError(t, <-done)
+}
+
+func TestHTTP_Shutdown(t *testing.T) {
+	srv := &http.Server{Addr: "127.0.0.1:0"}
+	r := NewRunner(zap.NewNop(), time.Second)
+	r.Add("http", HTTP(srv))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NoError(t, r.Run(ctx))
+}
@@ -0,0 +1,65 @@
+// Package fiberlog assigns request IDs to Fiber requests and logs each
+// request once it completes.
+package fiberlog
+
+import (
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// quietPaths are polled by probes and Prometheus; their requests are logged at
+// debug level.
+var quietPaths = map[string]bool{"/livez": true, "/readyz": true, "/metrics": true}
+
+// Middleware adopts the caller's X-Request-ID, or assigns a new one, and
+// echoes it in the response. It stores the ID and a request-scoped logger in
+// c.UserContext(), so it must run after fibertrace for the logger to carry
+// the trace ID.
+func Middleware(logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		id := c.Get(logging.HeaderRequestID)
+		if !logging.ValidRequestID(id) {
+			id = logging.NewRequestID()
+		}
+		c.Set(logging.HeaderRequestID, id)
+		ctx := logging.NewContext(c.UserContext(), logger, id)
+		c.SetUserContext(ctx)
+
+		// Like fibertrace, handle the error here so that the logged status is
+		// the one the app's error handler picks.
+		err := c.Next()
+		if err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		level := zapcore.InfoLevel
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			level = zapcore.ErrorLevel
+		case quietPaths[c.Path()]:
+			level = zapcore.DebugLevel
+		}
+		fields := []zap.Field{
+			zap.String("method", c.Method()),
+			zap.String("route", c.Route().Path),
+			zap.String("path", c.Path()),
+			zap.Int("status", status),
+			zap.Duration("duration", time.Since(start)),
+			zap.String("client_ip", c.IP()),
+		}
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		}
+		logging.FromContext(ctx, logger).Log(level, "HTTP request", fields...)
+		return nil
+	}
+}
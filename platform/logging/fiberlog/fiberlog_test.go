@@ -0,0 +1,71 @@
+package fiberlog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMiddleware(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	var seenID string
+
+	app := fiber.New()
+	app.Use(Middleware(zap.New(core)))
+	app.Get("/orders/:id", func(c *fiber.Ctx) error {
+		seenID = logging.RequestID(c.UserContext())
+		logging.FromContext(c.UserContext(), zap.NewNop()).Info("handler")
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "down")
+	})
+	app.Get("/livez", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+	req.Header.Set(logging.HeaderRequestID, "caller-id-1")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "caller-id-1", resp.Header.Get(logging.HeaderRequestID))
+	assert.Equal(t, "caller-id-1", seenID)
+	require.Equal(t, 2, logs.Len())
+	for _, entry := range logs.TakeAll() {
+		assert.Equal(t, "caller-id-1", entry.ContextMap()[logging.FieldRequestID])
+	}
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/fail", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.True(t, logging.ValidRequestID(resp.Header.Get(logging.HeaderRequestID)), "a new ID is assigned")
+	entry := logs.TakeAll()[0]
+	assert.Equal(t, zapcore.ErrorLevel, entry.Level)
+	assert.Equal(t, "/fail", entry.ContextMap()["route"])
+	assert.EqualValues(t, 503, entry.ContextMap()["status"])
+
+	_, err = app.Test(httptest.NewRequest(http.MethodGet, "/livez", nil))
+	require.NoError(t, err)
+	assert.Equal(t, zapcore.DebugLevel, logs.TakeAll()[0].Level)
+}
+
+func TestMiddleware_ReplacesUnsafeID(t *testing.T) {
+	app := fiber.New()
+	app.Use(Middleware(zap.NewNop()))
+	app.Get("/", func(c *fiber.Ctx) error { return nil })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(logging.HeaderRequestID, "bad id with spaces")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, "bad id with spaces", resp.Header.Get(logging.HeaderRequestID))
+}
@@ -0,0 +1,134 @@
+// Package grpclog assigns request IDs to incoming RPCs, logs each RPC once it
+// completes, and passes the request ID on to outgoing RPCs in metadata.
+package grpclog
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// quietPrefix marks the health service, polled by probes, whose RPCs are
+// logged at debug level.
+const quietPrefix = "/grpc.health.v1.Health/"
+
+// ServerOptions installs the unary and stream server interceptors.
+func ServerOptions(logger *zap.Logger) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor(logger)),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor(logger)),
+	}
+}
+
+// DialOption installs the unary client interceptor.
+func DialOption() grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(UnaryClientInterceptor())
+}
+
+// UnaryServerInterceptor adopts the caller's x-request-id, or assigns a new
+// one, returns it in the response header and stores it with a request-scoped
+// logger in the handler's context.
+func UnaryServerInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx = newContext(ctx, logger)
+		resp, err := handler(ctx, req)
+		logCall(ctx, logger, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func StreamServerInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := newContext(ss.Context(), logger)
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, logger, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor sends the request ID of ctx, if any, as x-request-id.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+func newContext(ctx context.Context, logger *zap.Logger) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(logging.MetadataRequestID); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	if !logging.ValidRequestID(id) {
+		id = logging.NewRequestID()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(logging.MetadataRequestID, id))
+	return logging.NewContext(ctx, logger, id)
+}
+
+func outgoingContext(ctx context.Context) context.Context {
+	id := logging.RequestID(ctx)
+	if id == "" {
+		return ctx
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(logging.MetadataRequestID)) > 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, logging.MetadataRequestID, id)
+}
+
+func logCall(ctx context.Context, logger *zap.Logger, fullMethod string, start time.Time, err error) {
+	code := status.Code(err)
+	level := zapcore.InfoLevel
+	switch {
+	case serverFault(code):
+		level = zapcore.ErrorLevel
+	case strings.HasPrefix(fullMethod, quietPrefix):
+		level = zapcore.DebugLevel
+	}
+	fields := []zap.Field{
+		zap.String("grpc_method", fullMethod),
+		zap.String("grpc_code", code.String()),
+		zap.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	logging.FromContext(ctx, logger).Log(level, "gRPC request", fields...)
+}
+
+func serverFault(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.Unimplemented, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,70 @@
+package grpclog
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type recordingHealth struct {
+	healthpb.UnimplementedHealthServer
+	requestID string
+}
+
+func (s *recordingHealth) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	s.requestID = logging.RequestID(ctx)
+	logging.FromContext(ctx, zap.NewNop()).Info("handler")
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	srv := &recordingHealth{}
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(ServerOptions(zap.New(core))...)
+	healthpb.RegisterHealthServer(server, srv)
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		DialOption(),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	ctx := logging.WithRequestID(context.Background(), "upstream-id")
+	var header metadata.MD
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+
+	assert.Equal(t, "upstream-id", srv.requestID, "the client sends the ID of its context")
+	assert.Equal(t, []string{"upstream-id"}, header.Get(logging.MetadataRequestID))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "upstream-id", entries[0].ContextMap()[logging.FieldRequestID])
+	assert.Equal(t, "gRPC request", entries[1].Message)
+	assert.Equal(t, zapcore.DebugLevel, entries[1].Level, "health checks are quiet")
+	assert.Equal(t, "OK", entries[1].ContextMap()["grpc_code"])
+
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.True(t, logging.ValidRequestID(srv.requestID))
+	assert.NotEqual(t, "upstream-id", srv.requestID, "calls without an ID get a new one")
+}
@@ -0,0 +1,36 @@
+// Package kafkalog carries the request ID across Kafka in the x-request-id
+// record header, so that the logs of a consumer can be joined with those of
+// the request that produced the message.
+package kafkalog
+
+import (
+	"context"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry/kafkatrace"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+)
+
+// Inject writes the request ID of ctx, if any, to the headers of msg.
+func Inject(ctx context.Context, msg *sarama.ProducerMessage) {
+	if id := logging.RequestID(ctx); id != "" {
+		kafkatrace.ProducerMessageCarrier{Msg: msg}.Set(logging.MetadataRequestID, id)
+	}
+}
+
+// NewContext stores the request ID from the headers of msg, or a new one for
+// messages without it, and a logger derived from base that also carries the
+// message's coordinates.
+func NewContext(ctx context.Context, base *zap.Logger, msg *sarama.ConsumerMessage) context.Context {
+	id := kafkatrace.ConsumerMessageCarrier{Msg: msg}.Get(logging.MetadataRequestID)
+	if !logging.ValidRequestID(id) {
+		id = logging.NewRequestID()
+	}
+	return logging.NewContext(ctx, base, id,
+		zap.String("topic", msg.Topic),
+		zap.Int32("partition", msg.Partition),
+		zap.Int64("offset", msg.Offset),
+	)
+}
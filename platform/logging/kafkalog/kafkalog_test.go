@@ -0,0 +1,44 @@
+package kafkalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRoundTrip(t *testing.T) {
+	produced := &sarama.ProducerMessage{Topic: "order-events"}
+	Inject(logging.WithRequestID(context.Background(), "req-42"), produced)
+	Inject(logging.WithRequestID(context.Background(), "req-42"), produced)
+	assert.Len(t, produced.Headers, 1, "a retried message keeps one header")
+
+	consumed := &sarama.ConsumerMessage{Topic: "order-events", Partition: 2, Offset: 7}
+	for _, h := range produced.Headers {
+		consumed.Headers = append(consumed.Headers, &sarama.RecordHeader{Key: h.Key, Value: h.Value})
+	}
+
+	core, logs := observer.New(zap.DebugLevel)
+	ctx := NewContext(context.Background(), zap.New(core), consumed)
+	logging.FromContext(ctx, zap.NewNop()).Info("processed")
+
+	assert.Equal(t, "req-42", logging.RequestID(ctx))
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "req-42", fields[logging.FieldRequestID])
+	assert.Equal(t, "order-events", fields["topic"])
+	assert.EqualValues(t, 7, fields["offset"])
+}
+
+func TestNewContext_AssignsIDWithoutHeader(t *testing.T) {
+	ctx := NewContext(context.Background(), zap.NewNop(), &sarama.ConsumerMessage{})
+	assert.True(t, logging.ValidRequestID(logging.RequestID(ctx)))
+
+	produced := &sarama.ProducerMessage{}
+	Inject(context.Background(), produced)
+	assert.Empty(t, produced.Headers)
+}
@@ -0,0 +1,103 @@
+// Package logging carries a request ID and a request-scoped zap logger in the
+// context. The middleware in fiberlog and grpclog assign or adopt the
+// X-Request-ID of every incoming request, grpclog and kafkalog pass it on to
+// outgoing calls and messages, and use cases log through FromContext so that
+// every line of a request carries its request_id (and trace_id when the
+// request is traced).
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// HeaderRequestID is the HTTP header of the request ID.
+	HeaderRequestID = "X-Request-ID"
+	// MetadataRequestID is the gRPC metadata key and Kafka header of the
+	// request ID. gRPC requires lower-case keys.
+	MetadataRequestID = "x-request-id"
+
+	FieldRequestID = "request_id"
+	FieldTraceID   = "trace_id"
+)
+
+// maxRequestIDLength bounds request IDs taken from callers.
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+type loggerKey struct{}
+
+// NewRequestID returns a random 128-bit ID in hex.
+func NewRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// ValidRequestID reports whether id, received from a caller, is safe to adopt:
+// non-empty, at most 128 characters, and made of letters, digits and "-_.:".
+// Anything else is replaced so that callers cannot inject into log lines.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.:", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// WithRequestID stores id in ctx.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored in ctx, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithLogger stores logger in ctx.
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger stored in ctx, or fallback
+// when there is none, for code running outside a request.
+func FromContext(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return logger
+	}
+	return fallback
+}
+
+// NewContext stores id and a logger derived from base in ctx. The logger
+// carries the request ID and the trace ID of the span in ctx, if any.
+func NewContext(ctx context.Context, base *zap.Logger, id string, fields ...zap.Field) context.Context {
+	fields = append([]zap.Field{zap.String(FieldRequestID, id)}, fields...)
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		fields = append(fields, zap.String(FieldTraceID, sc.TraceID().String()))
+	}
+	return WithLogger(WithRequestID(ctx, id), base.With(fields...))
+}
+
+// Sampled returns a logger that writes the first `first` entries with the
+// same message and level each second and then every `thereafter`-th one. It
+// is meant for per-message payload logs that would flood the output.
+func Sampled(logger *zap.Logger, first, thereafter int) *zap.Logger {
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewSamplerWithOptions(core, time.Second, first, thereafter)
+	}))
+}
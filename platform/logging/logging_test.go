@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNewContext(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	base := zap.New(core)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	ctx = NewContext(ctx, base, "req-1")
+	FromContext(ctx, zap.NewNop()).Info("hello")
+
+	assert.Equal(t, "req-1", RequestID(ctx))
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "req-1", fields[FieldRequestID])
+	assert.Equal(t, traceID.String(), fields[FieldTraceID])
+}
+
+func TestFromContext_Fallback(t *testing.T) {
+	fallback := zap.NewNop()
+	assert.Same(t, fallback, FromContext(context.Background(), fallback))
+	assert.Empty(t, RequestID(context.Background()))
+}
+
+func TestValidRequestID(t *testing.T) {
+	assert.True(t, ValidRequestID(NewRequestID()))
+	assert.True(t, ValidRequestID("3f2c-abc_1.2:x"))
+	assert.False(t, ValidRequestID(""))
+	assert.False(t, ValidRequestID("id\nfake log line"))
+	assert.False(t, ValidRequestID(strings.Repeat("a", 129)))
+	assert.NotEqual(t, NewRequestID(), NewRequestID())
+}
+
+func TestSampled(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	logger := Sampled(zap.New(core), 2, 10)
+
+	for i := 0; i < 25; i++ {
+		logger.Debug("payload")
+	}
+
+	assert.Equal(t, 4, logs.Len(), "the first 2, then the 10th and 20th of the rest")
+}
+
+func TestRedact(t *testing.T) {
+	payload := map[string]any{
+		"order_id": "o-1",
+		"Email":    "jane@example.com",
+		"user": map[string]any{
+			"api_key": "k",
+			"name":    "jane",
+		},
+		"items": []any{map[string]any{"access-token": "t"}},
+	}
+
+	redacted := Redact(payload)
+
+	assert.Equal(t, "o-1", redacted["order_id"])
+	assert.Equal(t, Redacted, redacted["Email"])
+	assert.Equal(t, Redacted, redacted["user"].(map[string]any)["api_key"])
+	assert.Equal(t, "jane", redacted["user"].(map[string]any)["name"])
+	assert.Equal(t, Redacted, redacted["items"].([]any)[0].(map[string]any)["access-token"])
+	assert.Equal(t, "jane@example.com", payload["Email"], "the input is not modified")
+
+	assert.Equal(t, Redacted, Redact(map[string]any{"message": "hi"}, "message")["message"])
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Redacted replaces sensitive values in logged payloads.
+const Redacted = "******"
+
+// SensitiveKeys are redacted from payloads by default. Keys match without
+// regard to case, "-" or "_", so "apiKey" and "API-KEY" are both caught.
+var SensitiveKeys = []string{"password", "token", "secret", "apikey", "authorization", "email", "phone"}
+
+// Redact returns a copy of payload with the values of sensitive keys, at any
+// depth, replaced by Redacted.
+func Redact(payload map[string]any, keys ...string) map[string]any {
+	if len(keys) == 0 {
+		keys = SensitiveKeys
+	}
+	return redactMap(payload, keys)
+}
+
+// Payload is a zap field with the redacted payload.
+func Payload(key string, payload map[string]any) zap.Field {
+	return zap.Any(key, Redact(payload))
+}
+
+func redactMap(m map[string]any, keys []string) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		if sensitive(k, keys) {
+			out[k] = Redacted
+			continue
+		}
+		out[k] = redactValue(v, keys)
+	}
+	return out
+}
+
+func redactValue(v any, keys []string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		return redactMap(v, keys)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = redactValue(item, keys)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func sensitive(key string, keys []string) bool {
+	normalized := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(key))
+	for _, k := range keys {
+		if strings.Contains(normalized, k) {
+			return true
+		}
+	}
+	return false
+}
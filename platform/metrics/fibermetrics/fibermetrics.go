@@ -0,0 +1,65 @@
+// Package fibermetrics records RED metrics for Fiber routes and serves the
+// /metrics endpoint.
+package fibermetrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_server_requests_total",
+		Help: "Number of HTTP requests handled, by route and status code.",
+	}, []string{metrics.LabelMethod, metrics.LabelRoute, metrics.LabelStatus})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_server_request_duration_seconds",
+		Help:    "Time spent handling HTTP requests, by route and status code.",
+		Buckets: metrics.LatencyBuckets,
+	}, []string{metrics.LabelMethod, metrics.LabelRoute, metrics.LabelStatus})
+
+	requestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "http_server_requests_in_flight",
+		Help: "Number of HTTP requests currently being handled.",
+	})
+)
+
+// Middleware records the request count, duration and in-flight gauge. Routes
+// are labelled with their template (e.g. /products/:id), not the raw path.
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		requestsInFlight.Inc()
+		defer requestsInFlight.Dec()
+
+		// Like fibertrace, handle the error here so the recorded status is
+		// the one the app's error handler sends.
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		labels := prometheus.Labels{
+			metrics.LabelMethod: c.Method(),
+			metrics.LabelRoute:  c.Route().Path,
+			metrics.LabelStatus: strconv.Itoa(c.Response().StatusCode()),
+		}
+		requestsTotal.With(labels).Inc()
+		requestDuration.With(labels).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
+
+// Handler serves the default registry.
+func Handler() fiber.Handler {
+	return adaptor.HTTPHandler(metrics.Handler())
+}
@@ -0,0 +1,49 @@
+package fibermetrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	app := fiber.New()
+	app.Use(Middleware())
+	app.Get("/metrics", Handler())
+	app.Get("/items/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "down")
+	})
+
+	ok := requestsTotal.WithLabelValues(http.MethodGet, "/items/:id", "204")
+	failed := requestsTotal.WithLabelValues(http.MethodGet, "/fail", "503")
+	okBefore, failedBefore := testutil.ToFloat64(ok), testutil.ToFloat64(failed)
+
+	for _, path := range []string{"/items/1", "/items/2"} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	}
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/fail", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	assert.Equal(t, okBefore+2, testutil.ToFloat64(ok), "requests are labelled with the route template")
+	assert.Equal(t, failedBefore+1, testutil.ToFloat64(failed), "errors are labelled with the handled status")
+	assert.Zero(t, testutil.ToFloat64(requestsInFlight))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `http_server_requests_total{method="GET",route="/items/:id",status="204"}`)
+	assert.Contains(t, string(body), "http_server_request_duration_seconds_bucket")
+}
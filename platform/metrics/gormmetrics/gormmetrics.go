@@ -0,0 +1,93 @@
+// Package gormmetrics is a GORM plugin that records query latency and errors.
+package gormmetrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+)
+
+var (
+	queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Time spent executing database statements, by operation and table.",
+		Buckets: metrics.LatencyBuckets,
+	}, []string{metrics.LabelOperation, metrics.LabelTable})
+
+	queryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Number of failed database statements, by operation and table.",
+	}, []string{metrics.LabelOperation, metrics.LabelTable})
+)
+
+const startKey = "gormmetrics:start"
+
+// Plugin times every statement GORM runs. Install it with db.Use(Plugin{}).
+type Plugin struct{}
+
+var _ gorm.Plugin = Plugin{}
+
+func (Plugin) Name() string {
+	return "gormmetrics"
+}
+
+// registerer is satisfied by the callbacks returned from GORM's
+// processor Before and After methods.
+type registerer interface {
+	Register(name string, fn func(*gorm.DB)) error
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	hooks := []struct {
+		operation     string
+		before, after registerer
+	}{
+		{"create", cb.Create().Before("gorm:create"), cb.Create().After("gorm:create")},
+		{"query", cb.Query().Before("gorm:query"), cb.Query().After("gorm:query")},
+		{"update", cb.Update().Before("gorm:update"), cb.Update().After("gorm:update")},
+		{"delete", cb.Delete().Before("gorm:delete"), cb.Delete().After("gorm:delete")},
+		{"row", cb.Row().Before("gorm:row"), cb.Row().After("gorm:row")},
+		{"raw", cb.Raw().Before("gorm:raw"), cb.Raw().After("gorm:raw")},
+	}
+	for _, h := range hooks {
+		if err := h.before.Register("gormmetrics:before_"+h.operation, before); err != nil {
+			return err
+		}
+		if err := h.after.Register("gormmetrics:after_"+h.operation, after(h.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func before(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+func after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		queryDuration.WithLabelValues(operation, table).Observe(time.Since(start).Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			queryErrors.WithLabelValues(operation, table).Inc()
+		}
+	}
+}
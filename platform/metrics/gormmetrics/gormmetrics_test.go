@@ -0,0 +1,39 @@
+package gormmetrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+func TestPlugin(t *testing.T) {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(Plugin{}))
+
+	before := sampleCount(t, "create", "widgets")
+	require.NoError(t, db.Create(&widget{Name: "a"}).Error)
+	assert.Equal(t, before+1, sampleCount(t, "create", "widgets"))
+
+	before = sampleCount(t, "query", "widgets")
+	var found []widget
+	require.NoError(t, db.Find(&found).Error)
+	assert.Equal(t, before+1, sampleCount(t, "query", "widgets"))
+}
+
+func sampleCount(t *testing.T, operation, table string) uint64 {
+	t.Helper()
+	metric := &dto.Metric{}
+	require.NoError(t, queryDuration.WithLabelValues(operation, table).(prometheus.Histogram).Write(metric))
+	return metric.GetHistogram().GetSampleCount()
+}
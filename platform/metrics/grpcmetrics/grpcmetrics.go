@@ -0,0 +1,103 @@
+// Package grpcmetrics records RED metrics for gRPC servers and clients.
+package grpcmetrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var labels = []string{metrics.LabelGRPCService, metrics.LabelGRPCMethod, metrics.LabelGRPCCode}
+
+var (
+	serverRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_requests_total",
+		Help: "Number of gRPC calls handled, by method and status code.",
+	}, labels)
+
+	serverRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_request_duration_seconds",
+		Help:    "Time spent handling gRPC calls, by method and status code.",
+		Buckets: metrics.LatencyBuckets,
+	}, labels)
+
+	clientRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_requests_total",
+		Help: "Number of gRPC calls made, by method and status code.",
+	}, labels)
+
+	clientRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_client_request_duration_seconds",
+		Help:    "Time spent waiting for gRPC calls, by method and status code.",
+		Buckets: metrics.LatencyBuckets,
+	}, labels)
+)
+
+// ServerOptions installs the unary and stream server interceptors.
+func ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor()),
+	}
+}
+
+// DialOption installs the unary client interceptor.
+func DialOption() grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(UnaryClientInterceptor())
+}
+
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observe(serverRequestsTotal, serverRequestDuration, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor observes a stream once it finishes, so the
+// duration covers the whole stream.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observe(serverRequestsTotal, serverRequestDuration, info.FullMethod, start, err)
+		return err
+	}
+}
+
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		observe(clientRequestsTotal, clientRequestDuration, method, start, err)
+		return err
+	}
+}
+
+func observe(total *prometheus.CounterVec, duration *prometheus.HistogramVec, fullMethod string, start time.Time, err error) {
+	service, method := splitMethod(fullMethod)
+	l := prometheus.Labels{
+		metrics.LabelGRPCService: service,
+		metrics.LabelGRPCMethod:  method,
+		metrics.LabelGRPCCode:    status.Code(err).String(),
+	}
+	total.With(l).Inc()
+	duration.With(l).Observe(time.Since(start).Seconds())
+}
+
+// splitMethod splits "/package.Service/Method" into its service and method.
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
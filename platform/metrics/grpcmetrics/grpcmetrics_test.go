@@ -0,0 +1,58 @@
+package grpcmetrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSplitMethod(t *testing.T) {
+	service, method := splitMethod("/inventory.InventoryService/GetProduct")
+	assert.Equal(t, "inventory.InventoryService", service)
+	assert.Equal(t, "GetProduct", method)
+
+	service, method = splitMethod("Bare")
+	assert.Equal(t, "unknown", service)
+	assert.Equal(t, "Bare", method)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}
+
+	okCounter := serverRequestsTotal.WithLabelValues("test.Service", "Get", codes.OK.String())
+	notFoundCounter := serverRequestsTotal.WithLabelValues("test.Service", "Get", codes.NotFound.String())
+	okBefore, notFoundBefore := testutil.ToFloat64(okCounter), testutil.ToFloat64(notFoundCounter)
+
+	resp, err := interceptor(context.Background(), "req", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "resp", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "resp", resp)
+
+	_, err = interceptor(context.Background(), "req", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	assert.Equal(t, okBefore+1, testutil.ToFloat64(okCounter))
+	assert.Equal(t, notFoundBefore+1, testutil.ToFloat64(notFoundCounter))
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	interceptor := UnaryClientInterceptor()
+	counter := clientRequestsTotal.WithLabelValues("test.Service", "Put", codes.Unavailable.String())
+	before := testutil.ToFloat64(counter)
+
+	err := interceptor(context.Background(), "/test.Service/Put", "req", nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return status.Error(codes.Unavailable, "down")
+		})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
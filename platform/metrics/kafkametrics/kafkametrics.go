@@ -0,0 +1,74 @@
+// Package kafkametrics counts produced and consumed Kafka messages and
+// tracks consumer lag.
+package kafkametrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics"
+
+	"github.com/IBM/sarama"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	producedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_producer_messages_total",
+		Help: "Number of messages successfully produced, by topic.",
+	}, []string{metrics.LabelTopic})
+
+	produceErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_producer_errors_total",
+		Help: "Number of messages that failed to produce, by topic.",
+	}, []string{metrics.LabelTopic})
+
+	produceDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kafka_producer_duration_seconds",
+		Help:    "Time spent waiting for the broker to acknowledge a message, by topic.",
+		Buckets: metrics.LatencyBuckets,
+	}, []string{metrics.LabelTopic})
+
+	consumedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_consumer_messages_total",
+		Help: "Number of messages consumed, by topic and consumer group.",
+	}, []string{metrics.LabelTopic, metrics.LabelGroup})
+
+	consumeErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_consumer_errors_total",
+		Help: "Number of consumed messages that failed processing, by topic and consumer group.",
+	}, []string{metrics.LabelTopic, metrics.LabelGroup})
+
+	consumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kafka_consumer_lag",
+		Help: "Messages between the last consumed offset and the partition high watermark.",
+	}, []string{metrics.LabelTopic, metrics.LabelPartition, metrics.LabelGroup})
+)
+
+// ObserveProduce records the outcome of sending a message to topic.
+func ObserveProduce(topic string, start time.Time, err error) {
+	produceDuration.WithLabelValues(topic).Observe(time.Since(start).Seconds())
+	if err != nil {
+		produceErrors.WithLabelValues(topic).Inc()
+		return
+	}
+	producedTotal.WithLabelValues(topic).Inc()
+}
+
+// ObserveConsume records that msg was consumed by groupID and updates the
+// partition's lag from the claim's high watermark. A non-nil err counts the
+// message as failed.
+func ObserveConsume(msg *sarama.ConsumerMessage, groupID string, highWaterMark int64, err error) {
+	consumedTotal.WithLabelValues(msg.Topic, groupID).Inc()
+	if err != nil {
+		consumeErrors.WithLabelValues(msg.Topic, groupID).Inc()
+	}
+
+	// The high watermark is the offset of the next message to be written.
+	lag := highWaterMark - msg.Offset - 1
+	if lag < 0 {
+		lag = 0
+	}
+	consumerLag.WithLabelValues(msg.Topic, strconv.Itoa(int(msg.Partition)), groupID).Set(float64(lag))
+}
@@ -0,0 +1,40 @@
+package kafkametrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveProduce(t *testing.T) {
+	sent, failed := producedTotal.WithLabelValues("produce-test"), produceErrors.WithLabelValues("produce-test")
+	sentBefore, failedBefore := testutil.ToFloat64(sent), testutil.ToFloat64(failed)
+
+	ObserveProduce("produce-test", time.Now(), nil)
+	ObserveProduce("produce-test", time.Now(), nil)
+	ObserveProduce("produce-test", time.Now(), errors.New("broker down"))
+
+	assert.Equal(t, sentBefore+2, testutil.ToFloat64(sent))
+	assert.Equal(t, failedBefore+1, testutil.ToFloat64(failed))
+}
+
+func TestObserveConsume(t *testing.T) {
+	msg := &sarama.ConsumerMessage{Topic: "consume-test", Partition: 2, Offset: 10}
+	consumed, failed := consumedTotal.WithLabelValues("consume-test", "group"), consumeErrors.WithLabelValues("consume-test", "group")
+	lag := consumerLag.WithLabelValues("consume-test", "2", "group")
+	consumedBefore, failedBefore := testutil.ToFloat64(consumed), testutil.ToFloat64(failed)
+
+	ObserveConsume(msg, "group", 15, nil)
+	assert.Equal(t, consumedBefore+1, testutil.ToFloat64(consumed))
+	assert.Equal(t, 4.0, testutil.ToFloat64(lag))
+
+	msg.Offset = 14
+	ObserveConsume(msg, "group", 15, errors.New("bad payload"))
+	assert.Equal(t, consumedBefore+2, testutil.ToFloat64(consumed))
+	assert.Equal(t, failedBefore+1, testutil.ToFloat64(failed))
+	assert.Zero(t, testutil.ToFloat64(lag), "caught up")
+}
@@ -0,0 +1,57 @@
+// Package metrics exposes the services' Prometheus metrics. The collectors
+// live in the fibermetrics, grpcmetrics, gormmetrics and kafkametrics
+// packages and in the breaker and ratelimit packages, and register with the
+// default registry, so every service reports the same metric names and labels:
+//
+//	http_server_requests_total{method,route,status}
+//	http_server_request_duration_seconds{method,route,status}
+//	http_server_requests_in_flight
+//	grpc_server_requests_total{grpc_service,grpc_method,grpc_code}
+//	grpc_server_request_duration_seconds{grpc_service,grpc_method,grpc_code}
+//	grpc_client_requests_total{grpc_service,grpc_method,grpc_code}
+//	grpc_client_request_duration_seconds{grpc_service,grpc_method,grpc_code}
+//	db_query_duration_seconds{operation,table}
+//	db_query_errors_total{operation,table}
+//	kafka_producer_messages_total{topic}
+//	kafka_producer_errors_total{topic}
+//	kafka_producer_duration_seconds{topic}
+//	kafka_consumer_messages_total{topic,group}
+//	kafka_consumer_errors_total{topic,group}
+//	kafka_consumer_lag{topic,partition,group}
+//	circuit_breaker_state{breaker}
+//	circuit_breaker_rejected_total{breaker}
+//	rate_limit_rejected_total{route}
+//
+// Service-specific metrics follow the same <subsystem>_<name>_<unit> scheme.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Label names shared by the collectors.
+const (
+	LabelMethod      = "method"
+	LabelRoute       = "route"
+	LabelStatus      = "status"
+	LabelGRPCService = "grpc_service"
+	LabelGRPCMethod  = "grpc_method"
+	LabelGRPCCode    = "grpc_code"
+	LabelOperation   = "operation"
+	LabelTable       = "table"
+	LabelTopic       = "topic"
+	LabelPartition   = "partition"
+	LabelGroup       = "group"
+	LabelBreaker     = "breaker"
+)
+
+// LatencyBuckets are the histogram buckets, in seconds, used for request and
+// query durations.
+var LatencyBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Handler serves the default registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
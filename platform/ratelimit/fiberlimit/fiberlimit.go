@@ -0,0 +1,64 @@
+// Package fiberlimit rate limits Fiber routes with a ratelimit.Limiter.
+package fiberlimit
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/ratelimit"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Middleware limits requests by "METHOD /path" and client, and sets the
+// RateLimit-* headers on limited routes. The path is normalised the way Fiber
+// routes it (see route), so rules name paths in lower case and without a
+// trailing slash. Rejected requests get 429 with Retry-After. If the store fails the request is let through and the error
+// logged. It must run after fiberlog, and after any authentication that
+// records a subject.
+func Middleware(limiter *ratelimit.Limiter, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		key := ratelimit.ClientKey(ctx, c.IP())
+		res, limited, err := limiter.Allow(ctx, route(c), key)
+		if err != nil {
+			logging.FromContext(ctx, logger).Warn("rate limiter unavailable, request not limited", zap.Error(err))
+			return c.Next()
+		}
+		if !limited {
+			return c.Next()
+		}
+
+		c.Set(ratelimit.HeaderLimit, strconv.Itoa(res.Limit))
+		c.Set(ratelimit.HeaderRemaining, strconv.Itoa(res.Remaining))
+		c.Set(ratelimit.HeaderReset, ratelimit.Seconds(res.Reset))
+		if !res.Allowed {
+			c.Set(ratelimit.HeaderRetryAfter, ratelimit.Seconds(res.RetryAfter))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": "rate limit exceeded, try again later",
+			})
+		}
+		return c.Next()
+	}
+}
+
+// route returns "METHOD /path" of the request. Unless the app routes case
+// sensitively the path is lower-cased, and unless it routes strictly its
+// trailing slashes are dropped, so "POST /API/orders/" is limited like the
+// "POST /api/orders" it is served by.
+func route(c *fiber.Ctx) string {
+	path := c.Path()
+	config := c.App().Config()
+	if !config.CaseSensitive {
+		path = strings.ToLower(path)
+	}
+	if !config.StrictRouting && len(path) > 1 {
+		path = strings.TrimRight(path, "/")
+		if path == "" {
+			path = "/"
+		}
+	}
+	return c.Method() + " " + path
+}
@@ -0,0 +1,110 @@
+package fiberlimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/ratelimit"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestMiddleware(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rules, err := ratelimit.ParseRules([]string{"POST /api/orders=60/m:2"})
+	require.NoError(t, err)
+	limiter := ratelimit.New(ratelimit.NewMemoryStore(), rules, ratelimit.WithClock(clock.Now))
+
+	app := fiber.New()
+	// Stands in for authentication that verified the caller.
+	app.Use(func(c *fiber.Ctx) error {
+		if subject := c.Get("X-Test-Subject"); subject != "" {
+			c.SetUserContext(ratelimit.WithSubject(c.UserContext(), subject))
+		}
+		return c.Next()
+	})
+	app.Use(Middleware(limiter, zap.NewNop()))
+	app.Post("/api/orders", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+	app.Get("/api/orders", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	post := func(header, value string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/api/orders", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := post("", "")
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get(ratelimit.HeaderLimit))
+	assert.Equal(t, "1", resp.Header.Get(ratelimit.HeaderRemaining))
+	assert.Equal(t, "1", resp.Header.Get(ratelimit.HeaderReset))
+
+	post("", "")
+	resp = post("", "")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "0", resp.Header.Get(ratelimit.HeaderRemaining))
+	assert.Equal(t, "1", resp.Header.Get(ratelimit.HeaderRetryAfter))
+
+	assert.Equal(t, http.StatusTooManyRequests, post("X-API-Key", "key-1").StatusCode,
+		"unverified API keys are limited by IP")
+	assert.Equal(t, http.StatusCreated, post("X-Test-Subject", "u-1").StatusCode,
+		"authenticated callers are limited apart from the IP")
+
+	clock.now = clock.now.Add(time.Second)
+	assert.Equal(t, http.StatusCreated, post("", "").StatusCode, "a token refills every second")
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/api/orders", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(ratelimit.HeaderLimit), "routes without a rule are not limited")
+}
+
+func TestMiddleware_PathVariants(t *testing.T) {
+	rules, err := ratelimit.ParseRules([]string{"POST /api/orders=60/m:1"})
+	require.NoError(t, err)
+	limiter := ratelimit.New(ratelimit.NewMemoryStore(), rules,
+		ratelimit.WithClock(func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }))
+
+	app := fiber.New()
+	app.Use(Middleware(limiter, zap.NewNop()))
+	app.Post("/api/orders", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+
+	post := func(path string) int {
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, path, nil))
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	require.Equal(t, http.StatusCreated, post("/api/orders"))
+	for _, path := range []string{"/api/orders/", "/API/orders", "/Api/Orders//"} {
+		assert.Equal(t, http.StatusTooManyRequests, post(path), "%s shares the bucket of /api/orders", path)
+	}
+}
+
+func TestMiddleware_StrictCaseSensitiveRouting(t *testing.T) {
+	rules, err := ratelimit.ParseRules([]string{"POST /api/orders=60/m:1"})
+	require.NoError(t, err)
+	limiter := ratelimit.New(ratelimit.NewMemoryStore(), rules,
+		ratelimit.WithClock(func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }))
+
+	app := fiber.New(fiber.Config{CaseSensitive: true, StrictRouting: true})
+	app.Use(Middleware(limiter, zap.NewNop()))
+	app.Post("/API/orders/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/API/orders/", nil))
+	require.NoError(t, err)
+	assert.Empty(t, resp.Header.Get(ratelimit.HeaderLimit), "a distinct route of a strict, case-sensitive app")
+}
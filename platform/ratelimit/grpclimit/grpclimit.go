@@ -0,0 +1,87 @@
+// Package grpclimit rate limits gRPC methods with a ratelimit.Limiter.
+package grpclimit
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/ratelimit"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// ServerOptions installs the unary and stream server interceptors. They must
+// come after grpclog's so that errors are logged with the request ID.
+func ServerOptions(limiter *ratelimit.Limiter, logger *zap.Logger) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor(limiter, logger)),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor(limiter, logger)),
+	}
+}
+
+// UnaryServerInterceptor limits calls by full method and client. The
+// ratelimit-* fields are returned in the response header, and rejected calls
+// fail with ResourceExhausted.
+func UnaryServerInterceptor(limiter *ratelimit.Limiter, logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := allow(ctx, limiter, logger, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor limits the opening of streams, not their messages.
+func StreamServerInterceptor(limiter *ratelimit.Limiter, logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := allow(ss.Context(), limiter, logger, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func allow(ctx context.Context, limiter *ratelimit.Limiter, logger *zap.Logger, fullMethod string) error {
+	res, limited, err := limiter.Allow(ctx, fullMethod, clientKey(ctx))
+	if err != nil {
+		logging.FromContext(ctx, logger).Warn("rate limiter unavailable, call not limited", zap.Error(err))
+		return nil
+	}
+	if !limited {
+		return nil
+	}
+
+	md := metadata.Pairs(
+		strings.ToLower(ratelimit.HeaderLimit), strconv.Itoa(res.Limit),
+		strings.ToLower(ratelimit.HeaderRemaining), strconv.Itoa(res.Remaining),
+		strings.ToLower(ratelimit.HeaderReset), ratelimit.Seconds(res.Reset),
+	)
+	if !res.Allowed {
+		md.Set(strings.ToLower(ratelimit.HeaderRetryAfter), ratelimit.Seconds(res.RetryAfter))
+	}
+	_ = grpc.SetHeader(ctx, md)
+	if !res.Allowed {
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry after %ss", ratelimit.Seconds(res.RetryAfter))
+	}
+	return nil
+}
+
+// clientKey identifies the caller by subject or peer IP.
+func clientKey(ctx context.Context) string {
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+	return ratelimit.ClientKey(ctx, ip)
+}
@@ -0,0 +1,69 @@
+package grpclimit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rules, err := ratelimit.ParseRules([]string{"/grpc.health.v1.Health/Check=1/s:1"})
+	require.NoError(t, err)
+	limiter := ratelimit.New(ratelimit.NewMemoryStore(), rules, ratelimit.WithClock(clock.Now))
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(ServerOptions(limiter, zap.NewNop())...)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	client := healthpb.NewHealthClient(conn)
+
+	check := func(ctx context.Context) (metadata.MD, error) {
+		var header metadata.MD
+		_, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Header(&header))
+		return header, err
+	}
+
+	header, err := check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, header.Get("ratelimit-limit"))
+	assert.Equal(t, []string{"0"}, header.Get("ratelimit-remaining"))
+
+	header, err = check(context.Background())
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"1"}, header.Get("retry-after"))
+
+	withKey := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "key-1")
+	_, err = check(withKey)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "unverified API keys are limited by peer")
+
+	clock.now = clock.now.Add(time.Second)
+	_, err = check(context.Background())
+	assert.NoError(t, err)
+}
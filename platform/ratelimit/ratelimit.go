@@ -0,0 +1,226 @@
+// Package ratelimit throttles clients of the public APIs with token buckets.
+// Every client gets one bucket per rule: the bucket holds up to Burst tokens,
+// refills at Count tokens per Per, and each request takes one token. A request
+// that finds the bucket empty is rejected until the next token arrives.
+//
+// Rules are matched against a route, "METHOD /path" for HTTP and the full
+// method "/package.Service/Method" for gRPC, and are usually parsed from
+// configuration:
+//
+//	POST /api/orders=60/m:10
+//	/order_service.OrderService/CreateOrder=60/m:10
+//
+// Buckets live in a Store. MemoryStore keeps them in the process, which is
+// enough for a single instance; replicas that must share their quota plug in a
+// shared Store instead. The fiberlimit and grpclimit packages adapt a Limiter
+// to Fiber and gRPC.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Header names of the RateLimit fields, following the IETF draft, sent on
+// every limited response.
+const (
+	HeaderLimit      = "RateLimit-Limit"
+	HeaderRemaining  = "RateLimit-Remaining"
+	HeaderReset      = "RateLimit-Reset"
+	HeaderRetryAfter = "Retry-After"
+)
+
+var rejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "rate_limit_rejected_total",
+	Help: "Number of requests rejected by the rate limiter, by route.",
+}, []string{metrics.LabelRoute})
+
+// Limit allows Count requests per Per, with bursts of up to Burst requests.
+type Limit struct {
+	Count int
+	Per   time.Duration
+	Burst int
+}
+
+// Rate is the refill rate in tokens per second.
+func (l Limit) Rate() float64 {
+	return float64(l.Count) / l.Per.Seconds()
+}
+
+func (l Limit) String() string {
+	s := fmt.Sprintf("%d/%s", l.Count, unitNames[l.Per])
+	if l.Burst != l.Count {
+		s += ":" + strconv.Itoa(l.Burst)
+	}
+	return s
+}
+
+var units = map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour}
+
+var unitNames = map[time.Duration]string{time.Second: "s", time.Minute: "m", time.Hour: "h"}
+
+// ParseLimit parses "count/unit[:burst]", where unit is s, m or h. Without a
+// burst the bucket holds one unit's worth of requests.
+func ParseLimit(s string) (Limit, error) {
+	rate, burst, hasBurst := strings.Cut(strings.TrimSpace(s), ":")
+	count, unit, ok := strings.Cut(rate, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("limit %q: want count/unit[:burst]", s)
+	}
+	per, ok := units[unit]
+	if !ok {
+		return Limit{}, fmt.Errorf("limit %q: unit must be s, m or h", s)
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("limit %q: count must be a positive integer", s)
+	}
+	l := Limit{Count: n, Per: per, Burst: n}
+	if hasBurst {
+		if l.Burst, err = strconv.Atoi(burst); err != nil || l.Burst <= 0 {
+			return Limit{}, fmt.Errorf("limit %q: burst must be a positive integer", s)
+		}
+	}
+	return l, nil
+}
+
+// Rule applies Limit to the requests of Route. A Route ending in "*" matches
+// every route with that prefix.
+type Rule struct {
+	Route string
+	Limit Limit
+}
+
+func (r Rule) matches(route string) bool {
+	if prefix, ok := strings.CutSuffix(r.Route, "*"); ok {
+		return strings.HasPrefix(route, prefix)
+	}
+	return r.Route == route
+}
+
+// ParseRules parses "route=limit" entries, such as "POST /api/orders=60/m:10".
+func ParseRules(entries []string) ([]Rule, error) {
+	rules := make([]Rule, 0, len(entries))
+	var errs []error
+	for _, entry := range entries {
+		route, limit, ok := strings.Cut(entry, "=")
+		route = strings.TrimSpace(route)
+		if !ok || route == "" {
+			errs = append(errs, fmt.Errorf("rule %q: want route=limit", entry))
+			continue
+		}
+		l, err := ParseLimit(limit)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %q: %w", entry, err))
+			continue
+		}
+		rules = append(rules, Rule{Route: route, Limit: l})
+	}
+	return rules, errors.Join(errs...)
+}
+
+// Result is the state of a bucket after a request.
+type Result struct {
+	Allowed bool
+	// Limit is the bucket size and Remaining the tokens left in it.
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again, and RetryAfter the
+	// time until a rejected request would be allowed.
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets. Take takes one token from the bucket of key,
+// refilled up to now, and reports the outcome. Implementations must be safe
+// for concurrent use.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+type Option func(*Limiter)
+
+// WithClock replaces time.Now; tests pass a fake clock.
+func WithClock(now func() time.Time) Option {
+	return func(l *Limiter) { l.now = now }
+}
+
+// Limiter applies rules to the requests of a service.
+type Limiter struct {
+	store Store
+	rules []Rule
+	now   func() time.Time
+}
+
+func New(store Store, rules []Rule, opts ...Option) *Limiter {
+	l := &Limiter{store: store, rules: rules, now: time.Now}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Allow takes a token for the client key from the first rule matching route.
+// ok is false when no rule matches and the request is not limited. Errors
+// come from the store; callers let such requests through rather than fail
+// them. A nil Limiter limits nothing.
+func (l *Limiter) Allow(ctx context.Context, route, key string) (res Result, ok bool, err error) {
+	if l == nil {
+		return Result{}, false, nil
+	}
+	for _, rule := range l.rules {
+		if !rule.matches(route) {
+			continue
+		}
+		res, err = l.store.Take(ctx, rule.Route+"|"+key, rule.Limit, l.now())
+		if err != nil {
+			return Result{}, true, fmt.Errorf("rate limit %s: %w", rule.Route, err)
+		}
+		if !res.Allowed {
+			rejectedTotal.WithLabelValues(rule.Route).Inc()
+		}
+		return res, true, nil
+	}
+	return Result{}, false, nil
+}
+
+type subjectKey struct{}
+
+// WithSubject records the authenticated user or client of a request, so its
+// limits follow the caller rather than the address it calls from.
+func WithSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectKey{}, subject)
+}
+
+// Subject is the subject recorded by WithSubject, or "".
+func Subject(ctx context.Context) string {
+	subject, _ := ctx.Value(subjectKey{}).(string)
+	return subject
+}
+
+// ClientKey identifies the client of a request: the subject recorded by
+// authentication if there is one, otherwise the client IP. Credentials that
+// have not been verified, such as a raw API key header, are never used: a
+// client could send a new one with every request to get a fresh limit.
+func ClientKey(ctx context.Context, ip string) string {
+	if subject := Subject(ctx); subject != "" {
+		return "user:" + subject
+	}
+	return "ip:" + ip
+}
+
+// Seconds rounds d up to whole seconds for the RateLimit-Reset and
+// Retry-After fields.
+func Seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
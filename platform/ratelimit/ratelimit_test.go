@@ -0,0 +1,175 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestParseLimit(t *testing.T) {
+	l, err := ParseLimit("60/m:10")
+	require.NoError(t, err)
+	assert.Equal(t, Limit{Count: 60, Per: time.Minute, Burst: 10}, l)
+	assert.Equal(t, 1.0, l.Rate())
+	assert.Equal(t, "60/m:10", l.String())
+
+	l, err = ParseLimit("5/s")
+	require.NoError(t, err)
+	assert.Equal(t, Limit{Count: 5, Per: time.Second, Burst: 5}, l, "the burst defaults to the count")
+	assert.Equal(t, "5/s", l.String())
+
+	for _, bad := range []string{"", "10", "10/d", "0/s", "x/s", "10/s:0", "10/s:x"} {
+		_, err := ParseLimit(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]string{"POST /api/orders=60/m:10", "/order_service.OrderService/*=100/s"})
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Route: "POST /api/orders", Limit: Limit{Count: 60, Per: time.Minute, Burst: 10}},
+		{Route: "/order_service.OrderService/*", Limit: Limit{Count: 100, Per: time.Second, Burst: 100}},
+	}, rules)
+
+	_, err = ParseRules([]string{"POST /api/orders", "=1/s", "GET /x=1/w"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `rule "POST /api/orders"`)
+	assert.Contains(t, err.Error(), `rule "=1/s"`)
+	assert.Contains(t, err.Error(), `rule "GET /x=1/w"`)
+}
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	clock := newFakeClock()
+	store := NewMemoryStore()
+	limit := Limit{Count: 1, Per: time.Second, Burst: 3}
+	take := func() Result {
+		res, err := store.Take(context.Background(), "k", limit, clock.Now())
+		require.NoError(t, err)
+		return res
+	}
+
+	for i := 2; i >= 0; i-- {
+		res := take()
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 3, res.Limit)
+		assert.Equal(t, i, res.Remaining)
+	}
+	assert.Equal(t, 3*time.Second, take().Reset, "an empty bucket refills in three seconds")
+
+	res := take()
+	assert.False(t, res.Allowed, "the burst is spent")
+	assert.Equal(t, time.Second, res.RetryAfter)
+
+	clock.Advance(500 * time.Millisecond)
+	res = take()
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+	clock.Advance(500 * time.Millisecond)
+	assert.True(t, take().Allowed, "one token refilled")
+	assert.False(t, take().Allowed)
+
+	clock.Advance(time.Hour)
+	res = take()
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Remaining, "the bucket never holds more than the burst")
+}
+
+func TestMemoryStore_KeysAreIndependent(t *testing.T) {
+	clock := newFakeClock()
+	store := NewMemoryStore()
+	limit := Limit{Count: 1, Per: time.Minute, Burst: 1}
+
+	a, _ := store.Take(context.Background(), "a", limit, clock.Now())
+	b, _ := store.Take(context.Background(), "b", limit, clock.Now())
+	again, _ := store.Take(context.Background(), "a", limit, clock.Now())
+
+	assert.True(t, a.Allowed)
+	assert.True(t, b.Allowed)
+	assert.False(t, again.Allowed)
+}
+
+func TestMemoryStore_DropsRefilledBuckets(t *testing.T) {
+	clock := newFakeClock()
+	store := NewMemoryStore()
+	limit := Limit{Count: 1, Per: time.Second, Burst: 1}
+
+	for i := 0; i < sweepEvery-1; i++ {
+		_, _ = store.Take(context.Background(), fmt.Sprint(i), limit, clock.Now())
+	}
+	assert.Equal(t, sweepEvery-1, store.Len())
+
+	clock.Advance(time.Second)
+	_, _ = store.Take(context.Background(), "last", limit, clock.Now())
+	assert.Equal(t, 1, store.Len())
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit, time.Time) (Result, error) {
+	return Result{}, errors.New("connection refused")
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	clock := newFakeClock()
+	rules, err := ParseRules([]string{"POST /api/orders=1/m", "GET /api/*=2/m"})
+	require.NoError(t, err)
+	limiter := New(NewMemoryStore(), rules, WithClock(clock.Now))
+	rejected := rejectedTotal.WithLabelValues("POST /api/orders")
+	before := testutil.ToFloat64(rejected)
+
+	res, limited, err := limiter.Allow(context.Background(), "POST /api/orders", "ip:1")
+	require.NoError(t, err)
+	assert.True(t, limited)
+	assert.True(t, res.Allowed)
+
+	res, _, _ = limiter.Allow(context.Background(), "POST /api/orders", "ip:1")
+	assert.False(t, res.Allowed)
+	assert.Equal(t, before+1, testutil.ToFloat64(rejected))
+
+	res, _, _ = limiter.Allow(context.Background(), "POST /api/orders", "ip:2")
+	assert.True(t, res.Allowed, "clients have their own buckets")
+
+	res, limited, _ = limiter.Allow(context.Background(), "GET /api/orders/1", "ip:1")
+	assert.True(t, limited, "prefix rules match")
+	assert.Equal(t, 2, res.Limit)
+
+	_, limited, _ = limiter.Allow(context.Background(), "GET /health", "ip:1")
+	assert.False(t, limited)
+
+	var disabled *Limiter
+	_, limited, _ = disabled.Allow(context.Background(), "POST /api/orders", "ip:1")
+	assert.False(t, limited, "a nil limiter limits nothing")
+
+	_, limited, err = New(failingStore{}, rules).Allow(context.Background(), "POST /api/orders", "ip:1")
+	assert.True(t, limited)
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+func TestClientKey(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "ip:10.0.0.1", ClientKey(ctx, "10.0.0.1"))
+	assert.Equal(t, "user:u-1", ClientKey(WithSubject(ctx, "u-1"), "10.0.0.1"))
+}
+
+func TestSeconds(t *testing.T) {
+	assert.Equal(t, "0", Seconds(0))
+	assert.Equal(t, "1", Seconds(100*time.Millisecond))
+	assert.Equal(t, "2", Seconds(2*time.Second))
+}
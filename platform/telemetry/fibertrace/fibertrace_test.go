@@ -0,0 +1,56 @@
+package fibertrace
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry/telemetrytest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMiddleware(t *testing.T) {
+	exporter := telemetrytest.Install(t)
+
+	var handlerSpan trace.SpanContext
+	app := fiber.New()
+	app.Use(Middleware())
+	app.Get("/items/:id", func(c *fiber.Ctx) error {
+		handlerSpan = trace.SpanContextFromContext(c.UserContext())
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "down")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET /items/:id", span.Name)
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent.SpanID().String())
+	assert.Equal(t, span.SpanContext.SpanID(), handlerSpan.SpanID(), "handlers see the server span")
+
+	exporter.Reset()
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/fail", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	spans = exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.False(t, spans[0].Parent.IsValid())
+	assert.Len(t, spans[0].Events, 1, "the handler error is recorded")
+}
@@ -0,0 +1,18 @@
+// Package grpctrace wires the otelgrpc stats handlers, which create spans for
+// every RPC and carry the trace context in gRPC metadata.
+package grpctrace
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+)
+
+// ServerOption traces incoming RPCs.
+func ServerOption() grpc.ServerOption {
+	return grpc.StatsHandler(otelgrpc.NewServerHandler())
+}
+
+// DialOption traces outgoing RPCs.
+func DialOption() grpc.DialOption {
+	return grpc.WithStatsHandler(otelgrpc.NewClientHandler())
+}
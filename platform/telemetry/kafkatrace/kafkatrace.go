@@ -0,0 +1,138 @@
+// Package kafkatrace carries W3C trace context across Kafka in record
+// headers and creates producer and consumer spans for sarama messages.
+package kafkatrace
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartProducerSpan starts a span for publishing msg and injects its context
+// into the message headers. Call EndProducerSpan once the send completes.
+func StartProducerSpan(ctx context.Context, msg *sarama.ProducerMessage) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystemKafka,
+		semconv.MessagingOperationTypePublish,
+		semconv.MessagingDestinationName(msg.Topic),
+	}
+	if msg.Key != nil {
+		if key, err := msg.Key.Encode(); err == nil {
+			attrs = append(attrs, semconv.MessagingKafkaMessageKey(string(key)))
+		}
+	}
+	ctx, span := otel.Tracer(telemetry.InstrumentationName).Start(ctx, "publish "+msg.Topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attrs...),
+	)
+	otel.GetTextMapPropagator().Inject(ctx, ProducerMessageCarrier{msg})
+	return ctx, span
+}
+
+// EndProducerSpan records where the message landed, or the send error.
+func EndProducerSpan(span trace.Span, partition int32, offset int64, err error) {
+	if err != nil {
+		RecordError(span, err)
+	} else {
+		span.SetAttributes(
+			semconv.MessagingDestinationPartitionID(strconv.Itoa(int(partition))),
+			semconv.MessagingKafkaMessageOffset(int(offset)),
+		)
+	}
+	span.End()
+}
+
+// RecordError marks span as failed with err.
+func RecordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// StartConsumerSpan continues the trace found in the message headers with a
+// span for processing msg.
+func StartConsumerSpan(ctx context.Context, msg *sarama.ConsumerMessage, groupID string) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, ConsumerMessageCarrier{msg})
+	return otel.Tracer(telemetry.InstrumentationName).Start(ctx, "process "+msg.Topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeDeliver,
+			semconv.MessagingDestinationName(msg.Topic),
+			semconv.MessagingDestinationPartitionID(strconv.Itoa(int(msg.Partition))),
+			semconv.MessagingKafkaMessageOffset(int(msg.Offset)),
+			semconv.MessagingKafkaMessageKey(string(msg.Key)),
+			semconv.MessagingKafkaConsumerGroup(groupID),
+		),
+	)
+}
+
+// ProducerMessageCarrier adapts the headers of an outgoing message to
+// propagation.TextMapCarrier.
+type ProducerMessageCarrier struct {
+	Msg *sarama.ProducerMessage
+}
+
+func (c ProducerMessageCarrier) Get(key string) string {
+	for _, h := range c.Msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces an existing header so a retried message does not carry two
+// traceparent values.
+func (c ProducerMessageCarrier) Set(key, value string) {
+	for i, h := range c.Msg.Headers {
+		if string(h.Key) == key {
+			c.Msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.Msg.Headers = append(c.Msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+func (c ProducerMessageCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.Msg.Headers))
+	for _, h := range c.Msg.Headers {
+		keys = append(keys, string(h.Key))
+	}
+	return keys
+}
+
+// ConsumerMessageCarrier adapts the headers of a received message to
+// propagation.TextMapCarrier. Set is a no-op because received messages are
+// read-only.
+type ConsumerMessageCarrier struct {
+	Msg *sarama.ConsumerMessage
+}
+
+func (c ConsumerMessageCarrier) Get(key string) string {
+	for _, h := range c.Msg.Headers {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c ConsumerMessageCarrier) Set(string, string) {}
+
+func (c ConsumerMessageCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.Msg.Headers))
+	for _, h := range c.Msg.Headers {
+		if h != nil {
+			keys = append(keys, string(h.Key))
+		}
+	}
+	return keys
+}
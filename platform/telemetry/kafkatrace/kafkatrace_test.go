@@ -0,0 +1,68 @@
+package kafkatrace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry/telemetrytest"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestProducerToConsumerPropagation(t *testing.T) {
+	exporter := telemetrytest.Install(t)
+
+	msg := &sarama.ProducerMessage{Topic: "order-events", Key: sarama.StringEncoder("order-1")}
+	_, producerSpan := StartProducerSpan(context.Background(), msg)
+	EndProducerSpan(producerSpan, 2, 17, nil)
+
+	require.Len(t, msg.Headers, 1)
+	assert.Equal(t, "traceparent", string(msg.Headers[0].Key))
+
+	received := &sarama.ConsumerMessage{Topic: msg.Topic, Partition: 2, Offset: 17, Key: []byte("order-1")}
+	for _, h := range msg.Headers {
+		h := h
+		received.Headers = append(received.Headers, &h)
+	}
+	ctx, consumerSpan := StartConsumerSpan(context.Background(), received, "notifications")
+	consumerSpan.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	producer, consumer := spans[0], spans[1]
+	assert.Equal(t, "publish order-events", producer.Name)
+	assert.Equal(t, trace.SpanKindProducer, producer.SpanKind)
+	assert.Equal(t, "process order-events", consumer.Name)
+	assert.Equal(t, trace.SpanKindConsumer, consumer.SpanKind)
+	assert.Equal(t, producer.SpanContext.TraceID(), consumer.SpanContext.TraceID())
+	assert.Equal(t, producer.SpanContext.SpanID(), consumer.Parent.SpanID())
+	assert.True(t, consumer.Parent.IsRemote())
+	assert.Equal(t, consumer.SpanContext.SpanID(), trace.SpanContextFromContext(ctx).SpanID())
+}
+
+func TestProducerMessageCarrier_SetReplaces(t *testing.T) {
+	msg := &sarama.ProducerMessage{Topic: "t"}
+	carrier := ProducerMessageCarrier{msg}
+	carrier.Set("traceparent", "a")
+	carrier.Set("traceparent", "b")
+
+	assert.Equal(t, []string{"traceparent"}, carrier.Keys())
+	assert.Equal(t, "b", carrier.Get("traceparent"))
+}
+
+func TestEndProducerSpan_Error(t *testing.T) {
+	exporter := telemetrytest.Install(t)
+
+	_, span := StartProducerSpan(context.Background(), &sarama.ProducerMessage{Topic: "t"})
+	EndProducerSpan(span, 0, 0, errors.New("broker down"))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, "broker down", spans[0].Status.Description)
+}
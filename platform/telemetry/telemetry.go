@@ -0,0 +1,125 @@
+// Package telemetry configures OpenTelemetry tracing for the services. Setup
+// installs a global tracer provider and the W3C trace-context propagator, so
+// the instrumentation in the fibertrace, grpctrace and kafkatrace packages
+// only has to talk to the otel globals.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// InstrumentationName identifies the tracers created by this module.
+const InstrumentationName = "github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry"
+
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterNone   = "none"
+)
+
+type Config struct {
+	ServiceName string
+	// Exporter is one of ExporterOTLP, ExporterStdout or ExporterNone.
+	Exporter string
+	// Endpoint is the host:port of the OTLP gRPC receiver.
+	Endpoint string
+	Insecure bool
+	// SampleRatio is the fraction of new traces that are recorded. Spans
+	// with a sampled remote parent are always recorded.
+	SampleRatio float64
+}
+
+// ConfigFromEnv reads the standard OTEL_* variables. Without
+// OTEL_EXPORTER_OTLP_ENDPOINT nothing is exported unless
+// OTEL_TRACES_EXPORTER asks for stdout.
+func ConfigFromEnv(serviceName string) Config {
+	cfg := Config{
+		ServiceName: serviceName,
+		Exporter:    os.Getenv("OTEL_TRACES_EXPORTER"),
+		Endpoint:    os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		Insecure:    os.Getenv("OTEL_EXPORTER_OTLP_INSECURE") != "false",
+		SampleRatio: 1,
+	}
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		cfg.ServiceName = name
+	}
+	if cfg.Exporter == "" {
+		cfg.Exporter = ExporterNone
+		if cfg.Endpoint != "" {
+			cfg.Exporter = ExporterOTLP
+		}
+	}
+	if raw := os.Getenv("OTEL_TRACES_SAMPLER_ARG"); raw != "" {
+		if ratio, err := strconv.ParseFloat(raw, 64); err == nil {
+			cfg.SampleRatio = ratio
+		}
+	}
+	return cfg
+}
+
+// Setup installs the global tracer provider and propagator. The returned
+// function flushes pending spans and must be called before the process exits.
+func Setup(ctx context.Context, cfg Config) (shutdown func(context.Context) error, err error) {
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build telemetry resource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	provider := sdktrace.NewTracerProvider(opts...)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(Propagator())
+	return provider.Shutdown, nil
+}
+
+// Propagator carries W3C trace context and baggage.
+func Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err := otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		return exporter, nil
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterNone, "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+}
@@ -0,0 +1,40 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	assert.Equal(t, ExporterNone, ConfigFromEnv("order-service").Exporter)
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "otel-collector:4317")
+	t.Setenv("OTEL_TRACES_SAMPLER_ARG", "0.25")
+	cfg := ConfigFromEnv("order-service")
+	assert.Equal(t, ExporterOTLP, cfg.Exporter)
+	assert.Equal(t, "order-service", cfg.ServiceName)
+	assert.True(t, cfg.Insecure)
+	assert.Equal(t, 0.25, cfg.SampleRatio)
+}
+
+func TestSetup(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	shutdown, err := Setup(context.Background(), Config{ServiceName: "test", Exporter: ExporterNone, SampleRatio: 1})
+	require.NoError(t, err)
+	defer shutdown(context.Background())
+
+	_, span := otel.Tracer("test").Start(context.Background(), "op")
+	assert.True(t, span.SpanContext().IsSampled())
+	span.End()
+
+	_, err = Setup(context.Background(), Config{Exporter: "zipkin"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,36 @@
+// Package telemetrytest records spans in memory so tests can assert on them.
+package telemetrytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Install replaces the global tracer provider and propagator for the
+// duration of the test. Spans are exported synchronously when they end.
+func Install(t testing.TB) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+	)
+
+	prevProvider := otel.GetTracerProvider()
+	prevPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(telemetry.Propagator())
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return exporter
+}
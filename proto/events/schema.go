@@ -0,0 +1,9 @@
+package events
+
+import _ "embed"
+
+// OrderEventsProto is the source of order_events.proto, the schema
+// registered for topics that carry these messages.
+//
+//go:embed order_events.proto
+var OrderEventsProto string
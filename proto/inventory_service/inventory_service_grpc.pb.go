@@ -21,9 +21,14 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	InventoryService_CreateProduct_FullMethodName              = "/inventory_service.InventoryService/CreateProduct"
 	InventoryService_GetProduct_FullMethodName                 = "/inventory_service.InventoryService/GetProduct"
+	InventoryService_GetProductBySKU_FullMethodName            = "/inventory_service.InventoryService/GetProductBySKU"
 	InventoryService_UpdateProductMetadata_FullMethodName      = "/inventory_service.InventoryService/UpdateProductMetadata"
 	InventoryService_UpdateProductStockQuantity_FullMethodName = "/inventory_service.InventoryService/UpdateProductStockQuantity"
 	InventoryService_ListProducts_FullMethodName               = "/inventory_service.InventoryService/ListProducts"
+	InventoryService_SearchProducts_FullMethodName             = "/inventory_service.InventoryService/SearchProducts"
+	InventoryService_ImportProducts_FullMethodName             = "/inventory_service.InventoryService/ImportProducts"
+	InventoryService_ScheduleProductPrice_FullMethodName       = "/inventory_service.InventoryService/ScheduleProductPrice"
+	InventoryService_ListProductPrices_FullMethodName          = "/inventory_service.InventoryService/ListProductPrices"
 )
 
 // InventoryServiceClient is the client API for InventoryService service.
@@ -32,9 +37,14 @@ const (
 type InventoryServiceClient interface {
 	CreateProduct(ctx context.Context, in *CreateProductRequest, opts ...grpc.CallOption) (*CreateProductResponse, error)
 	GetProduct(ctx context.Context, in *GetProductRequest, opts ...grpc.CallOption) (*GetProductResponse, error)
+	GetProductBySKU(ctx context.Context, in *GetProductBySKURequest, opts ...grpc.CallOption) (*GetProductBySKUResponse, error)
 	UpdateProductMetadata(ctx context.Context, in *UpdateProductMetadataRequest, opts ...grpc.CallOption) (*UpdateProductMetadataResponse, error)
 	UpdateProductStockQuantity(ctx context.Context, in *UpdateProductStockQuantityRequest, opts ...grpc.CallOption) (*UpdateProductStockQuantityResponse, error)
 	ListProducts(ctx context.Context, in *ListProductsRequest, opts ...grpc.CallOption) (*ListProductsResponse, error)
+	SearchProducts(ctx context.Context, in *SearchProductsRequest, opts ...grpc.CallOption) (*SearchProductsResponse, error)
+	ImportProducts(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportProductsRequest, ImportProductsResponse], error)
+	ScheduleProductPrice(ctx context.Context, in *ScheduleProductPriceRequest, opts ...grpc.CallOption) (*ScheduleProductPriceResponse, error)
+	ListProductPrices(ctx context.Context, in *ListProductPricesRequest, opts ...grpc.CallOption) (*ListProductPricesResponse, error)
 }
 
 type inventoryServiceClient struct {
@@ -65,6 +75,16 @@ func (c *inventoryServiceClient) GetProduct(ctx context.Context, in *GetProductR
 	return out, nil
 }
 
+func (c *inventoryServiceClient) GetProductBySKU(ctx context.Context, in *GetProductBySKURequest, opts ...grpc.CallOption) (*GetProductBySKUResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProductBySKUResponse)
+	err := c.cc.Invoke(ctx, InventoryService_GetProductBySKU_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *inventoryServiceClient) UpdateProductMetadata(ctx context.Context, in *UpdateProductMetadataRequest, opts ...grpc.CallOption) (*UpdateProductMetadataResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UpdateProductMetadataResponse)
@@ -95,15 +115,63 @@ func (c *inventoryServiceClient) ListProducts(ctx context.Context, in *ListProdu
 	return out, nil
 }
 
+func (c *inventoryServiceClient) SearchProducts(ctx context.Context, in *SearchProductsRequest, opts ...grpc.CallOption) (*SearchProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchProductsResponse)
+	err := c.cc.Invoke(ctx, InventoryService_SearchProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) ImportProducts(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportProductsRequest, ImportProductsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &InventoryService_ServiceDesc.Streams[0], InventoryService_ImportProducts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportProductsRequest, ImportProductsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type InventoryService_ImportProductsClient = grpc.ClientStreamingClient[ImportProductsRequest, ImportProductsResponse]
+
+func (c *inventoryServiceClient) ScheduleProductPrice(ctx context.Context, in *ScheduleProductPriceRequest, opts ...grpc.CallOption) (*ScheduleProductPriceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScheduleProductPriceResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ScheduleProductPrice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) ListProductPrices(ctx context.Context, in *ListProductPricesRequest, opts ...grpc.CallOption) (*ListProductPricesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProductPricesResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ListProductPrices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InventoryServiceServer is the server API for InventoryService service.
 // All implementations must embed UnimplementedInventoryServiceServer
 // for forward compatibility.
 type InventoryServiceServer interface {
 	CreateProduct(context.Context, *CreateProductRequest) (*CreateProductResponse, error)
 	GetProduct(context.Context, *GetProductRequest) (*GetProductResponse, error)
+	GetProductBySKU(context.Context, *GetProductBySKURequest) (*GetProductBySKUResponse, error)
 	UpdateProductMetadata(context.Context, *UpdateProductMetadataRequest) (*UpdateProductMetadataResponse, error)
 	UpdateProductStockQuantity(context.Context, *UpdateProductStockQuantityRequest) (*UpdateProductStockQuantityResponse, error)
 	ListProducts(context.Context, *ListProductsRequest) (*ListProductsResponse, error)
+	SearchProducts(context.Context, *SearchProductsRequest) (*SearchProductsResponse, error)
+	ImportProducts(grpc.ClientStreamingServer[ImportProductsRequest, ImportProductsResponse]) error
+	ScheduleProductPrice(context.Context, *ScheduleProductPriceRequest) (*ScheduleProductPriceResponse, error)
+	ListProductPrices(context.Context, *ListProductPricesRequest) (*ListProductPricesResponse, error)
 	mustEmbedUnimplementedInventoryServiceServer()
 }
 
@@ -120,6 +188,9 @@ func (UnimplementedInventoryServiceServer) CreateProduct(context.Context, *Creat
 func (UnimplementedInventoryServiceServer) GetProduct(context.Context, *GetProductRequest) (*GetProductResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetProduct not implemented")
 }
+func (UnimplementedInventoryServiceServer) GetProductBySKU(context.Context, *GetProductBySKURequest) (*GetProductBySKUResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductBySKU not implemented")
+}
 func (UnimplementedInventoryServiceServer) UpdateProductMetadata(context.Context, *UpdateProductMetadataRequest) (*UpdateProductMetadataResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateProductMetadata not implemented")
 }
@@ -129,6 +200,18 @@ func (UnimplementedInventoryServiceServer) UpdateProductStockQuantity(context.Co
 func (UnimplementedInventoryServiceServer) ListProducts(context.Context, *ListProductsRequest) (*ListProductsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListProducts not implemented")
 }
+func (UnimplementedInventoryServiceServer) SearchProducts(context.Context, *SearchProductsRequest) (*SearchProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchProducts not implemented")
+}
+func (UnimplementedInventoryServiceServer) ImportProducts(grpc.ClientStreamingServer[ImportProductsRequest, ImportProductsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportProducts not implemented")
+}
+func (UnimplementedInventoryServiceServer) ScheduleProductPrice(context.Context, *ScheduleProductPriceRequest) (*ScheduleProductPriceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleProductPrice not implemented")
+}
+func (UnimplementedInventoryServiceServer) ListProductPrices(context.Context, *ListProductPricesRequest) (*ListProductPricesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProductPrices not implemented")
+}
 func (UnimplementedInventoryServiceServer) mustEmbedUnimplementedInventoryServiceServer() {}
 func (UnimplementedInventoryServiceServer) testEmbeddedByValue()                          {}
 
@@ -186,6 +269,24 @@ func _InventoryService_GetProduct_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _InventoryService_GetProductBySKU_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductBySKURequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).GetProductBySKU(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_GetProductBySKU_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).GetProductBySKU(ctx, req.(*GetProductBySKURequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _InventoryService_UpdateProductMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateProductMetadataRequest)
 	if err := dec(in); err != nil {
@@ -240,6 +341,67 @@ func _InventoryService_ListProducts_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _InventoryService_SearchProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).SearchProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_SearchProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).SearchProducts(ctx, req.(*SearchProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_ImportProducts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(InventoryServiceServer).ImportProducts(&grpc.GenericServerStream[ImportProductsRequest, ImportProductsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type InventoryService_ImportProductsServer = grpc.ClientStreamingServer[ImportProductsRequest, ImportProductsResponse]
+
+func _InventoryService_ScheduleProductPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleProductPriceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ScheduleProductPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ScheduleProductPrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ScheduleProductPrice(ctx, req.(*ScheduleProductPriceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_ListProductPrices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProductPricesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ListProductPrices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ListProductPrices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ListProductPrices(ctx, req.(*ListProductPricesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // InventoryService_ServiceDesc is the grpc.ServiceDesc for InventoryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -255,6 +417,10 @@ var InventoryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetProduct",
 			Handler:    _InventoryService_GetProduct_Handler,
 		},
+		{
+			MethodName: "GetProductBySKU",
+			Handler:    _InventoryService_GetProductBySKU_Handler,
+		},
 		{
 			MethodName: "UpdateProductMetadata",
 			Handler:    _InventoryService_UpdateProductMetadata_Handler,
@@ -267,7 +433,25 @@ var InventoryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListProducts",
 			Handler:    _InventoryService_ListProducts_Handler,
 		},
+		{
+			MethodName: "SearchProducts",
+			Handler:    _InventoryService_SearchProducts_Handler,
+		},
+		{
+			MethodName: "ScheduleProductPrice",
+			Handler:    _InventoryService_ScheduleProductPrice_Handler,
+		},
+		{
+			MethodName: "ListProductPrices",
+			Handler:    _InventoryService_ListProductPrices_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ImportProducts",
+			Handler:       _InventoryService_ImportProducts_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "inventory_service/inventory_service.proto",
 }
@@ -1,41 +1,98 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 	fiber_http "user-service/internal/adapters/fiber"
 	"user-service/internal/adapters/grpc"
 	"user-service/internal/adapters/repository"
+	"user-service/internal/config"
 	"user-service/internal/usecases"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/joho/godotenv"
+	platformconfig "github.com/jakkapat-chongsuwat/go-microservice/platform/config"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health/fiberhealth"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/health/grpchealth"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/lifecycle"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging/fiberlog"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging/grpclog"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics/fibermetrics"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics/gormmetrics"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/metrics/grpcmetrics"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/ratelimit"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/ratelimit/fiberlimit"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/ratelimit/grpclimit"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry/fibertrace"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/telemetry/grpctrace"
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/user_service"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 func main() {
-	loadEnv()
+	configFile := flag.String("config", "", "path to a YAML config file")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration, secrets redacted, and exit")
+	flag.Parse()
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *printConfig {
+		if err := platformconfig.Print(os.Stdout, cfg); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
 	logger := createLogger()
 	defer logger.Sync()
+	logger.Info("Configuration loaded", zap.Any("config", platformconfig.Values(cfg)))
 
-	repo := buildRepository(logger)
-	userUsecase := usecases.NewUserUseCase(repo, logger)
+	runner := lifecycle.NewRunner(logger, cfg.ShutdownTimeout)
+	runner.OnStop("tracing", setupTracing(logger))
 
-	go startGRPC(logger, userUsecase)
+	checker := health.NewChecker(0)
+	repo, apiKeyRepo := buildRepositories(cfg, logger, checker, runner)
+	userUsecase := usecases.NewUserUseCase(repo, logger)
+	apiKeyUsecase := usecases.NewAPIKeyUseCase(apiKeyRepo, repo, logger)
 
-	startHTTP(logger, userUsecase)
+	healthServer := grpchealth.NewServer()
+	runner.Go("health sync", func(ctx context.Context) error {
+		grpchealth.Sync(ctx, healthServer, checker, 0, user_service.UserService_ServiceDesc.ServiceName)
+		return nil
+	})
+	limiter := cfg.RateLimit.Limiter()
+	runner.Add("grpc", newGRPCServer(cfg.GRPCPort, logger, userUsecase, apiKeyUsecase, healthServer, limiter))
+	runner.Add("http", newHTTPServer(cfg.HTTPPort, logger, userUsecase, apiKeyUsecase, cfg.APIKeysAdminToken, checker, limiter))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := runner.Run(ctx); err != nil {
+		logger.Error("User service stopped with errors", zap.Error(err))
+		logger.Sync()
+		os.Exit(1)
+	}
+	logger.Info("User service stopped")
 }
 
-func loadEnv() {
-	if err := godotenv.Load(".env"); err != nil {
-		log.Println("Warning: no .env file found, proceeding...")
+func setupTracing(logger *zap.Logger) func(context.Context) error {
+	shutdown, err := telemetry.Setup(context.Background(), telemetry.ConfigFromEnv("user-service"))
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", zap.Error(err))
 	}
+	return shutdown
 }
 
 func createLogger() *zap.Logger {
@@ -46,47 +103,45 @@ func createLogger() *zap.Logger {
 	return logger
 }
 
-func buildRepository(logger *zap.Logger) usecases.UserRepository {
-	repoType := getEnv("REPO_TYPE", "memory")
-	switch repoType {
+func buildRepositories(cfg *config.Config, logger *zap.Logger, checker *health.Checker, runner *lifecycle.Runner) (usecases.UserRepository, usecases.APIKeyRepository) {
+	switch cfg.RepoType {
 	case "gorm":
-		return buildGormRepo(logger)
+		return buildGormRepos(cfg.Database, logger, checker, runner)
 	default:
 		logger.Info("Using In-Memory Repository (default)")
-		return repository.NewInMemoryUserRepo(logger)
+		return repository.NewInMemoryUserRepo(logger), repository.NewInMemoryAPIKeyRepo(logger)
 	}
 }
 
-func buildGormRepo(logger *zap.Logger) usecases.UserRepository {
-	dbDriver := getEnv("DB_DRIVER", "postgres")
-	db, err := connectGorm(dbDriver, logger)
+func buildGormRepos(dbConfig platformconfig.Database, logger *zap.Logger, checker *health.Checker, runner *lifecycle.Runner) (usecases.UserRepository, usecases.APIKeyRepository) {
+	db, err := connectGorm(dbConfig, logger)
 	if err != nil {
 		logger.Fatal("Failed to connect GORM DB", zap.Error(err))
 	}
-	return repository.NewGormUserRepo(db, logger)
+	if err := db.Use(gormmetrics.Plugin{}); err != nil {
+		logger.Fatal("Failed to install GORM metrics", zap.Error(err))
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		logger.Fatal("Failed to get database handle", zap.Error(err))
+	}
+	checker.Register("database", health.Ping(sqlDB))
+	runner.OnStop("database", lifecycle.Close(sqlDB))
+	return repository.NewGormUserRepo(db, logger), repository.NewGormAPIKeyRepo(db, logger)
 }
 
-func connectGorm(driver string, logger *zap.Logger) (*gorm.DB, error) {
-	switch driver {
+func connectGorm(dbConfig platformconfig.Database, logger *zap.Logger) (*gorm.DB, error) {
+	switch dbConfig.Driver {
 	case "mysql":
-		return connectMySQL(logger)
+		return connectMySQL(dbConfig, logger)
 	default:
-		return connectPostgres(logger)
+		return connectPostgres(dbConfig, logger)
 	}
 }
 
-func connectPostgres(logger *zap.Logger) (*gorm.DB, error) {
-	host := getEnv("DB_HOST", "localhost")
-	port := getEnv("DB_PORT", "5555")
-	user := getEnv("DB_USER", "devuser")
-	pass := getEnv("DB_PASS", "devpass")
-	dbname := getEnv("DB_NAME", "user_service")
-	dbschema := getEnv("DB_SCHEMA", "public")
-	sslmode := getEnv("DB_SSLMODE", "disable")
-
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s search_path=%s",
-		host, port, user, pass, dbname, sslmode, dbschema)
-	logger.Info("Connecting to Postgres", zap.String("dsn", dsn))
+func connectPostgres(dbConfig platformconfig.Database, logger *zap.Logger) (*gorm.DB, error) {
+	dsn := dbConfig.DSN()
+	logger.Info("Connecting to Postgres", zap.Stringer("database", dbConfig))
 
 	var db *gorm.DB
 	var err error
@@ -104,47 +159,36 @@ func connectPostgres(logger *zap.Logger) (*gorm.DB, error) {
 	return nil, fmt.Errorf("failed to connect to Postgres after %d attempts: %w", maxRetries, err)
 }
 
-func connectMySQL(logger *zap.Logger) (*gorm.DB, error) {
-	host := getEnv("DB_HOST", "localhost")
-	port := getEnv("DB_PORT", "3306")
-	user := getEnv("DB_USER", "root")
-	pass := getEnv("DB_PASS", "")
-	dbname := getEnv("DB_NAME", "user_service")
-
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", user, pass, host, port, dbname)
-	logger.Info("Connecting to MySQL", zap.String("dsn", dsn))
+func connectMySQL(dbConfig platformconfig.Database, logger *zap.Logger) (*gorm.DB, error) {
+	dsn := dbConfig.DSN()
+	logger.Info("Connecting to MySQL", zap.Stringer("database", dbConfig))
 
 	return gorm.Open(mysql.Open(dsn), &gorm.Config{})
 }
 
-func startGRPC(logger *zap.Logger, u usecases.UserUseCase) {
-	port := getEnv("GRPC_PORT", "50051")
-	if err := grpc.StartGRPCServer(port, u, logger); err != nil {
-		logger.Fatal("failed to start gRPC server", zap.Error(err))
-	}
+func newGRPCServer(port int, logger *zap.Logger, u usecases.UserUseCase, keys usecases.APIKeyUseCase, healthServer grpc_health_v1.HealthServer, limiter *ratelimit.Limiter) lifecycle.Component {
+	opts := append(grpcmetrics.ServerOptions(), grpctrace.ServerOption())
+	opts = append(opts, grpclog.ServerOptions(logger)...)
+	opts = append(opts, grpclimit.ServerOptions(limiter, logger)...)
+	srv := grpc.NewGRPCServer(u, keys, logger, healthServer, opts...)
+	logger.Info("Starting gRPC server", zap.Int("port", port))
+	return lifecycle.GRPC(srv, fmt.Sprintf(":%d", port))
 }
 
-func startHTTP(logger *zap.Logger, u usecases.UserUseCase) {
+func newHTTPServer(port int, logger *zap.Logger, u usecases.UserUseCase, keys usecases.APIKeyUseCase, adminToken string, checker *health.Checker, limiter *ratelimit.Limiter) lifecycle.Component {
 	app := fiber.New()
+	app.Use(fibertrace.Middleware(), fiberlog.Middleware(logger), fibermetrics.Middleware(), fiberlimit.Middleware(limiter, logger))
+	app.Get("/metrics", fibermetrics.Handler())
+	fiberhealth.Register(app, checker)
 
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.SendString("User Service is running")
 	})
 
 	fiber_http.RegisterUserRoutes(app, fiber_http.NewUserHttpHandler(u, logger))
+	fiber_http.RegisterAPIKeyRoutes(app, fiber_http.NewAPIKeyHTTPHandler(keys, adminToken, logger))
 
-	port := getEnv("HTTP_PORT", "8080")
-	logger.Info("Starting HTTP server on port", zap.String("port", port))
+	logger.Info("Starting HTTP server on port", zap.Int("port", port))
 
-	if err := app.Listen(":" + port); err != nil {
-		logger.Fatal("Failed to start HTTP server", zap.Error(err))
-	}
-}
-
-func getEnv(key, fallback string) string {
-	val := os.Getenv(key)
-	if val == "" {
-		return fallback
-	}
-	return val
+	return lifecycle.Fiber(app, fmt.Sprintf(":%d", port))
 }
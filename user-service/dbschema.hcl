@@ -40,6 +40,81 @@ table "users" {
   }
 }
 
+table "api_keys" {
+  schema = schema.user_service
+
+  column "id" {
+    type = varchar(255)
+    null = false
+  }
+
+  column "owner_id" {
+    type = varchar(255)
+    null = false
+  }
+
+  column "name" {
+    type = varchar(255)
+    null = false
+  }
+
+  column "prefix" {
+    type = varchar(16)
+    null = false
+  }
+
+  column "key_hash" {
+    type = char(64)
+    null = false
+  }
+
+  column "scopes" {
+    type = text
+    null = false
+  }
+
+  column "created_at" {
+    type    = timestamp
+    null    = false
+    default = sql("CURRENT_TIMESTAMP")
+  }
+
+  column "expires_at" {
+    type = timestamp
+    null = true
+  }
+
+  column "last_used_at" {
+    type = timestamp
+    null = true
+  }
+
+  column "revoked_at" {
+    type = timestamp
+    null = true
+  }
+
+  primary_key {
+    columns = [column.id]
+  }
+
+  foreign_key "api_keys_owner_id_fkey" {
+    columns     = [column.owner_id]
+    ref_columns = [table.users.column.id]
+    on_update   = NO_ACTION
+    on_delete   = CASCADE
+  }
+
+  index "api_keys_key_hash_key" {
+    unique  = true
+    columns = [column.key_hash]
+  }
+
+  index "api_keys_owner_id_idx" {
+    columns = [column.owner_id]
+  }
+}
+
 function "set_updated_at" {
   schema = schema.user_service
   lang   = PLpgSQL
@@ -6,4 +6,11 @@ const (
 	ColumnID       = "id"
 	ColumnUsername = "username"
 	ColumnEmail    = "email"
+
+	ColumnOwnerID    = "owner_id"
+	ColumnKeyHash    = "key_hash"
+	ColumnCreatedAt  = "created_at"
+	ColumnExpiresAt  = "expires_at"
+	ColumnLastUsedAt = "last_used_at"
+	ColumnRevokedAt  = "revoked_at"
 )
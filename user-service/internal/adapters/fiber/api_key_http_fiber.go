@@ -0,0 +1,201 @@
+package fiber_http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"user-service/internal/adapters/models"
+	"user-service/internal/domain"
+	"user-service/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/apikey"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"go.uber.org/zap"
+)
+
+type APIKeyHTTPHandler struct {
+	apiKeyUseCase usecases.APIKeyUseCase
+	adminToken    string
+	logger        *zap.Logger
+}
+
+// NewAPIKeyHTTPHandler serves the key management routes. Callers present
+// either adminToken as a bearer token, to manage the keys of any user, or
+// one of their own keys in the X-API-Key header. Without an adminToken only
+// the owners of existing keys can manage keys.
+func NewAPIKeyHTTPHandler(uc usecases.APIKeyUseCase, adminToken string, logger *zap.Logger) *APIKeyHTTPHandler {
+	return &APIKeyHTTPHandler{
+		apiKeyUseCase: uc,
+		adminToken:    adminToken,
+		logger:        logger,
+	}
+}
+
+// callerKey is the Locals key of the domain.Caller set by Authenticate.
+const callerKey = "apiKeyCaller"
+
+// Authenticate identifies the caller of the routes after it, and rejects
+// requests without valid credentials with 401.
+func (h *APIKeyHTTPHandler) Authenticate(c *fiber.Ctx) error {
+	if token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "); ok {
+		if h.adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid credentials"})
+		}
+		c.Locals(callerKey, domain.Caller{Admin: true})
+		return c.Next()
+	}
+
+	secret := c.Get(apikey.Header)
+	if secret == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "credentials required"})
+	}
+	key, err := h.apiKeyUseCase.VerifyAPIKey(c.UserContext(), secret, "")
+	switch {
+	case errors.Is(err, domain.ErrInvalidAPIKey), errors.Is(err, domain.ErrAPIKeyRevoked), errors.Is(err, domain.ErrAPIKeyExpired):
+		h.log(c).Info("api key rejected", zap.Error(err))
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid credentials"})
+	case err != nil:
+		h.log(c).Error("failed to verify api key", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to verify api key"})
+	}
+	c.Locals(callerKey, domain.Caller{UserID: key.OwnerID, Scopes: key.Scopes})
+	return c.Next()
+}
+
+// caller returns the caller set by Authenticate; without it the caller may
+// manage nothing.
+func caller(c *fiber.Ctx) domain.Caller {
+	caller, _ := c.Locals(callerKey).(domain.Caller)
+	return caller
+}
+
+// log returns the request-scoped logger of c.
+func (h *APIKeyHTTPHandler) log(c *fiber.Ctx) *zap.Logger {
+	return logging.FromContext(c.UserContext(), h.logger)
+}
+
+func (h *APIKeyHTTPHandler) IssueAPIKey(c *fiber.Ctx) error {
+	var req models.IssueAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request payload"})
+	}
+	if req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name is required"})
+	}
+	ttl, err := parseOptionalDuration(req.TTL)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ttl: " + err.Error()})
+	}
+
+	// Fiber reuses the memory of params after the request; the owner is kept.
+	ownerID := utils.CopyString(c.Params("id"))
+	key, secret, err := h.apiKeyUseCase.IssueAPIKey(c.UserContext(), caller(c), ownerID, req.Name, req.Scopes, ttl)
+	if err != nil {
+		return h.fail(c, "failed to issue api key", err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(models.IssuedAPIKeyResponse{
+		APIKeyResponse: toAPIKeyResponse(key),
+		Key:            secret,
+	})
+}
+
+func (h *APIKeyHTTPHandler) ListAPIKeys(c *fiber.Ctx) error {
+	keys, err := h.apiKeyUseCase.ListAPIKeys(c.UserContext(), caller(c), c.Params("id"))
+	if err != nil {
+		return h.fail(c, "failed to list api keys", err)
+	}
+	responses := make([]models.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		responses = append(responses, toAPIKeyResponse(key))
+	}
+	return c.JSON(models.NewResponse(responses, &models.Meta{Total: len(responses)}))
+}
+
+func (h *APIKeyHTTPHandler) RotateAPIKey(c *fiber.Ctx) error {
+	var req models.RotateAPIKeyRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request payload"})
+		}
+	}
+	grace, err := parseOptionalDuration(req.GracePeriod)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "gracePeriod: " + err.Error()})
+	}
+
+	key, secret, err := h.apiKeyUseCase.RotateAPIKey(c.UserContext(), caller(c), c.Params("keyId"), grace)
+	if err != nil {
+		return h.fail(c, "failed to rotate api key", err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(models.IssuedAPIKeyResponse{
+		APIKeyResponse: toAPIKeyResponse(key),
+		Key:            secret,
+	})
+}
+
+func (h *APIKeyHTTPHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	if err := h.apiKeyUseCase.RevokeAPIKey(c.UserContext(), caller(c), c.Params("keyId")); err != nil {
+		return h.fail(c, "failed to revoke api key", err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *APIKeyHTTPHandler) fail(c *fiber.Ctx, message string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrAPIKeyNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidScope):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrAPIKeyRevoked), errors.Is(err, domain.ErrAPIKeyExpired):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	}
+	h.log(c).Error(message, zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": message})
+}
+
+func parseOptionalDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, errors.New("must not be negative")
+	}
+	return d, nil
+}
+
+func toAPIKeyResponse(key *domain.APIKey) models.APIKeyResponse {
+	scopes := make([]string, len(key.Scopes))
+	for i, s := range key.Scopes {
+		scopes[i] = string(s)
+	}
+	return models.APIKeyResponse{
+		ID:         key.ID,
+		OwnerID:    key.OwnerID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		Status:     key.Status(domain.Clock.Now()),
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
+
+func RegisterAPIKeyRoutes(app *fiber.App, handler *APIKeyHTTPHandler) {
+	api := app.Group("/api")
+	api.Post("/users/:id/api-keys", handler.Authenticate, handler.IssueAPIKey)
+	api.Get("/users/:id/api-keys", handler.Authenticate, handler.ListAPIKeys)
+	api.Post("/api-keys/:keyId/rotate", handler.Authenticate, handler.RotateAPIKey)
+	api.Delete("/api-keys/:keyId", handler.Authenticate, handler.RevokeAPIKey)
+}
@@ -0,0 +1,153 @@
+package fiber_http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"user-service/internal/adapters/models"
+	"user-service/internal/adapters/repository"
+	"user-service/internal/domain"
+	"user-service/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const adminToken = "admin-token-0123456789abcdef012345"
+
+func newAPIKeyApp(t *testing.T) (*fiber.App, usecases.APIKeyUseCase) {
+	t.Helper()
+	users := repository.NewInMemoryUserRepo(zap.NewNop())
+	for _, id := range []string{"u1", "u2"} {
+		require.NoError(t, users.Save(context.Background(), &domain.User{ID: id, Username: "partner-" + id, Email: id + "@example.com"}))
+	}
+	uc := usecases.NewAPIKeyUseCase(repository.NewInMemoryAPIKeyRepo(zap.NewNop()), users, zap.NewNop())
+
+	app := fiber.New()
+	RegisterAPIKeyRoutes(app, NewAPIKeyHTTPHandler(uc, adminToken, zap.NewNop()))
+	return app, uc
+}
+
+// send calls the API as an administrator.
+func send(t *testing.T, app *fiber.App, method, path, body string, out any) int {
+	t.Helper()
+	return sendAs(t, app, "Authorization", "Bearer "+adminToken, method, path, body, out)
+}
+
+// sendAs calls the API with the credentials in header, if any.
+func sendAs(t *testing.T, app *fiber.App, header, value, method, path, body string, out any) int {
+	t.Helper()
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+func TestAPIKeyRoutes(t *testing.T) {
+	app, uc := newAPIKeyApp(t)
+
+	var issued models.IssuedAPIKeyResponse
+	status := send(t, app, http.MethodPost, "/api/users/u1/api-keys",
+		`{"name":"nightly sync","scopes":["inventory:read"],"ttl":"720h"}`, &issued)
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, issued.Key[:12], issued.Prefix)
+	assert.Equal(t, []string{"inventory:read"}, issued.Scopes)
+	assert.Equal(t, "active", issued.Status)
+	assert.NotNil(t, issued.ExpiresAt)
+
+	_, err := uc.VerifyAPIKey(context.Background(), issued.Key, domain.ScopeInventoryRead)
+	require.NoError(t, err)
+
+	var listed models.Response[[]map[string]any]
+	require.Equal(t, http.StatusOK, send(t, app, http.MethodGet, "/api/users/u1/api-keys", "", &listed))
+	require.Len(t, listed.Data, 1)
+	assert.NotContains(t, listed.Data[0], "key", "listings never include the secret")
+	assert.NotContains(t, listed.Data[0], "hash")
+	assert.NotNil(t, listed.Data[0]["lastUsedAt"])
+
+	var rotated models.IssuedAPIKeyResponse
+	require.Equal(t, http.StatusCreated, send(t, app, http.MethodPost, "/api/api-keys/"+issued.ID+"/rotate", "", &rotated))
+	assert.NotEqual(t, issued.Key, rotated.Key)
+	_, err = uc.VerifyAPIKey(context.Background(), issued.Key, "")
+	assert.ErrorIs(t, err, domain.ErrAPIKeyRevoked)
+
+	require.Equal(t, http.StatusNoContent, send(t, app, http.MethodDelete, "/api/api-keys/"+rotated.ID, "", nil))
+	_, err = uc.VerifyAPIKey(context.Background(), rotated.Key, "")
+	assert.ErrorIs(t, err, domain.ErrAPIKeyRevoked)
+}
+
+func TestAPIKeyRoutes_Errors(t *testing.T) {
+	app, _ := newAPIKeyApp(t)
+
+	for _, tc := range []struct {
+		name, method, path, body string
+		status                   int
+	}{
+		{"unknown scope", http.MethodPost, "/api/users/u1/api-keys", `{"name":"k","scopes":["root"]}`, http.StatusBadRequest},
+		{"no scopes", http.MethodPost, "/api/users/u1/api-keys", `{"name":"k"}`, http.StatusBadRequest},
+		{"no name", http.MethodPost, "/api/users/u1/api-keys", `{"scopes":["inventory:read"]}`, http.StatusBadRequest},
+		{"bad ttl", http.MethodPost, "/api/users/u1/api-keys", `{"name":"k","scopes":["inventory:read"],"ttl":"forever"}`, http.StatusBadRequest},
+		{"unknown user", http.MethodPost, "/api/users/ghost/api-keys", `{"name":"k","scopes":["inventory:read"]}`, http.StatusNotFound},
+		{"unknown key", http.MethodPost, "/api/api-keys/missing/rotate", `{"gracePeriod":"1h"}`, http.StatusNotFound},
+		{"revoke unknown key", http.MethodDelete, "/api/api-keys/missing", "", http.StatusNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.status, send(t, app, tc.method, tc.path, tc.body, nil))
+		})
+	}
+}
+
+func TestAPIKeyRoutes_Authorization(t *testing.T) {
+	app, _ := newAPIKeyApp(t)
+
+	var reader models.IssuedAPIKeyResponse
+	require.Equal(t, http.StatusCreated, send(t, app, http.MethodPost, "/api/users/u1/api-keys",
+		`{"name":"reader","scopes":["inventory:read","orders:read"]}`, &reader))
+	var other models.IssuedAPIKeyResponse
+	require.Equal(t, http.StatusCreated, send(t, app, http.MethodPost, "/api/users/u2/api-keys",
+		`{"name":"other","scopes":["inventory:read"]}`, &other))
+
+	asOwner := func(method, path, body string) int {
+		return sendAs(t, app, "X-API-Key", reader.Key, method, path, body, nil)
+	}
+	for _, tc := range []struct {
+		name, header, value string
+	}{
+		{"no credentials", "", ""},
+		{"wrong admin token", "Authorization", "Bearer not-the-token"},
+		{"unknown key", "X-API-Key", "gmk_made_up"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			status := sendAs(t, app, tc.header, tc.value, http.MethodPost, "/api/users/u1/api-keys",
+				`{"name":"k","scopes":["inventory:read"]}`, nil)
+			assert.Equal(t, http.StatusUnauthorized, status)
+			assert.Equal(t, http.StatusUnauthorized, sendAs(t, app, tc.header, tc.value, http.MethodDelete, "/api/api-keys/"+reader.ID, "", nil))
+		})
+	}
+
+	assert.Equal(t, http.StatusCreated, asOwner(http.MethodPost, "/api/users/u1/api-keys", `{"name":"k","scopes":["orders:read"]}`),
+		"owners grant scopes of their key")
+	assert.Equal(t, http.StatusForbidden, asOwner(http.MethodPost, "/api/users/u1/api-keys", `{"name":"k","scopes":["inventory:write"]}`),
+		"owners cannot grant more than their key has")
+	assert.Equal(t, http.StatusForbidden, asOwner(http.MethodPost, "/api/users/u2/api-keys", `{"name":"k","scopes":["inventory:read"]}`))
+	assert.Equal(t, http.StatusForbidden, asOwner(http.MethodGet, "/api/users/u2/api-keys", ""))
+	assert.Equal(t, http.StatusNotFound, asOwner(http.MethodPost, "/api/api-keys/"+other.ID+"/rotate", ""))
+	assert.Equal(t, http.StatusNotFound, asOwner(http.MethodDelete, "/api/api-keys/"+other.ID, ""))
+
+	assert.Equal(t, http.StatusOK, asOwner(http.MethodGet, "/api/users/u1/api-keys", ""))
+	assert.Equal(t, http.StatusNoContent, asOwner(http.MethodDelete, "/api/api-keys/"+reader.ID, ""))
+	assert.Equal(t, http.StatusUnauthorized, asOwner(http.MethodGet, "/api/users/u1/api-keys", ""), "a revoked key no longer authenticates")
+}
@@ -5,6 +5,7 @@ import (
 	"user-service/internal/usecases"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"go.uber.org/zap"
 )
 
@@ -20,12 +21,15 @@ func NewUserHttpHandler(userUC usecases.UserUseCase, logger *zap.Logger) *UserHT
 	}
 }
 
-func (u *UserHTTPHandler) CreateUser(c *fiber.Ctx) error {
-	u.logger.Info("CreateUser endpoint called")
+// log returns the request-scoped logger of c.
+func (u *UserHTTPHandler) log(c *fiber.Ctx) *zap.Logger {
+	return logging.FromContext(c.UserContext(), u.logger)
+}
 
+func (u *UserHTTPHandler) CreateUser(c *fiber.Ctx) error {
 	var req models.CreateUserRequest
 	if err := c.BodyParser(&req); err != nil {
-		u.logger.Error("failed to parse request body", zap.Error(err))
+		u.log(c).Error("failed to parse request body", zap.Error(err))
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "invalid request payload",
 		})
@@ -41,7 +45,7 @@ func (u *UserHTTPHandler) CreateUser(c *fiber.Ctx) error {
 
 	user, err := u.userUseCase.CreateUser(ctx, req.Username, req.Email)
 	if err != nil {
-		u.logger.Error("failed to create user", zap.Error(err))
+		u.log(c).Error("failed to create user", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "failed to create user",
 		})
@@ -57,12 +61,10 @@ func (u *UserHTTPHandler) CreateUser(c *fiber.Ctx) error {
 }
 
 func (u *UserHTTPHandler) GetUsers(c *fiber.Ctx) error {
-	u.logger.Info("GetUsers endpoint called")
-
 	ctx := c.UserContext()
 	users, err := u.userUseCase.GetAllUsers(ctx)
 	if err != nil {
-		u.logger.Error("failed to get users", zap.Error(err))
+		u.log(c).Error("failed to get users", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "failed to get users",
 		})
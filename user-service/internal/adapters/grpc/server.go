@@ -3,28 +3,25 @@
 package grpc
 
 import (
-	"fmt"
-	"net"
 	"user-service/internal/usecases"
 
 	"github.com/jakkapat-chongsuwat/go-microservice/proto/user_service"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
-func StartGRPCServer(port string, userUseCase usecases.UserUseCase, logger *zap.Logger) error {
-	lis, err := net.Listen("tcp", ":"+port)
-	if err != nil {
-		return fmt.Errorf("failed to listen: %w", err)
-	}
+// NewGRPCServer registers the UserService and the health service on a new
+// gRPC server. The caller serves it and stops it.
+func NewGRPCServer(userUseCase usecases.UserUseCase, apiKeyUseCase usecases.APIKeyUseCase, logger *zap.Logger, healthServer grpc_health_v1.HealthServer, opts ...grpc.ServerOption) *grpc.Server {
+	grpcServer := grpc.NewServer(opts...)
 
-	grpcServer := grpc.NewServer()
+	user_service.RegisterUserServiceServer(grpcServer, NewUserGRPCServer(userUseCase, apiKeyUseCase, logger))
 
-	user_service.RegisterUserServiceServer(grpcServer, NewUserGRPCServer(userUseCase, logger))
+	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
 
 	reflection.Register(grpcServer)
 
-	logger.Info("Starting UserService gRPC server", zap.String("port", port))
-	return grpcServer.Serve(lis)
+	return grpcServer
 }
@@ -0,0 +1,44 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"user-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/user_service"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *UserGRPcServer) VerifyAPIKey(ctx context.Context, req *user_service.VerifyAPIKeyRequest) (*user_service.VerifyAPIKeyResponse, error) {
+	if req.GetKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+	key, err := s.apiKeyUseCase.VerifyAPIKey(ctx, req.GetKey(), domain.Scope(req.GetRequiredScope()))
+	switch {
+	case errors.Is(err, domain.ErrInvalidAPIKey), errors.Is(err, domain.ErrAPIKeyRevoked), errors.Is(err, domain.ErrAPIKeyExpired):
+		// The reason is logged but not returned, so callers cannot probe keys.
+		s.log(ctx).Info("api key rejected", zap.Error(err))
+		return nil, status.Error(codes.Unauthenticated, domain.ErrInvalidAPIKey.Error())
+	case errors.Is(err, domain.ErrScopeNotGranted):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case err != nil:
+		s.log(ctx).Error("Failed to verify api key", zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to verify api key")
+	}
+
+	resp := &user_service.VerifyAPIKeyResponse{
+		KeyId:   key.ID,
+		OwnerId: key.OwnerID,
+	}
+	for _, scope := range key.Scopes {
+		resp.Scopes = append(resp.Scopes, string(scope))
+	}
+	if key.ExpiresAt != nil {
+		resp.ExpiresAt = timestamppb.New(*key.ExpiresAt)
+	}
+	return resp, nil
+}
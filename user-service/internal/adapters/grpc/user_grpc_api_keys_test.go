@@ -0,0 +1,77 @@
+package grpc_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"user-service/internal/adapters/grpc"
+	"user-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/proto/user_service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type FakeAPIKeyUseCase struct {
+	mock.Mock
+}
+
+func (f *FakeAPIKeyUseCase) IssueAPIKey(ctx context.Context, caller domain.Caller, ownerID, name string, scopes []string, ttl time.Duration) (*domain.APIKey, string, error) {
+	return nil, "", nil
+}
+
+func (f *FakeAPIKeyUseCase) ListAPIKeys(ctx context.Context, caller domain.Caller, ownerID string) ([]*domain.APIKey, error) {
+	return nil, nil
+}
+
+func (f *FakeAPIKeyUseCase) RotateAPIKey(ctx context.Context, caller domain.Caller, id string, grace time.Duration) (*domain.APIKey, string, error) {
+	return nil, "", nil
+}
+
+func (f *FakeAPIKeyUseCase) RevokeAPIKey(ctx context.Context, caller domain.Caller, id string) error {
+	return nil
+}
+
+func (f *FakeAPIKeyUseCase) VerifyAPIKey(ctx context.Context, secret string, scope domain.Scope) (*domain.APIKey, error) {
+	args := f.Called(ctx, secret, scope)
+	if k, ok := args.Get(0).(*domain.APIKey); ok {
+		return k, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestUserGRPCServer_VerifyAPIKey(t *testing.T) {
+	fakeKeys := new(FakeAPIKeyUseCase)
+	server := grpc.NewUserGRPCServer(new(FakeUserUseCase), fakeKeys, zap.NewNop())
+	expires := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	fakeKeys.On("VerifyAPIKey", mock.Anything, "good", domain.ScopeInventoryRead).Return(&domain.APIKey{
+		ID: "k1", OwnerID: "u1", Scopes: []domain.Scope{domain.ScopeInventoryRead}, ExpiresAt: &expires,
+	}, nil)
+	fakeKeys.On("VerifyAPIKey", mock.Anything, "revoked", domain.Scope("")).Return(nil, domain.ErrAPIKeyRevoked)
+	fakeKeys.On("VerifyAPIKey", mock.Anything, "good", domain.ScopeOrdersWrite).
+		Return(nil, fmt.Errorf("%w: %s", domain.ErrScopeNotGranted, domain.ScopeOrdersWrite))
+
+	resp, err := server.VerifyAPIKey(context.Background(), &user_service.VerifyAPIKeyRequest{Key: "good", RequiredScope: "inventory:read"})
+	require.NoError(t, err)
+	assert.Equal(t, "k1", resp.KeyId)
+	assert.Equal(t, "u1", resp.OwnerId)
+	assert.Equal(t, []string{"inventory:read"}, resp.Scopes)
+	assert.Equal(t, expires, resp.ExpiresAt.AsTime())
+
+	_, err = server.VerifyAPIKey(context.Background(), &user_service.VerifyAPIKeyRequest{Key: "revoked"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.NotContains(t, status.Convert(err).Message(), "revoked", "the reason is not disclosed")
+
+	_, err = server.VerifyAPIKey(context.Background(), &user_service.VerifyAPIKeyRequest{Key: "good", RequiredScope: "orders:write"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = server.VerifyAPIKey(context.Background(), &user_service.VerifyAPIKeyRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
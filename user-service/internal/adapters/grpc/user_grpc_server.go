@@ -4,29 +4,35 @@ import (
 	"context"
 	"user-service/internal/usecases"
 
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"github.com/jakkapat-chongsuwat/go-microservice/proto/user_service"
 	"go.uber.org/zap"
 )
 
 type UserGRPcServer struct {
 	user_service.UnimplementedUserServiceServer
-	userUseCase usecases.UserUseCase
-	logger      *zap.Logger
+	userUseCase   usecases.UserUseCase
+	apiKeyUseCase usecases.APIKeyUseCase
+	logger        *zap.Logger
 }
 
-func NewUserGRPCServer(u usecases.UserUseCase, logger *zap.Logger) *UserGRPcServer {
+func NewUserGRPCServer(u usecases.UserUseCase, keys usecases.APIKeyUseCase, logger *zap.Logger) *UserGRPcServer {
 	return &UserGRPcServer{
-		userUseCase: u,
-		logger:      logger,
+		userUseCase:   u,
+		apiKeyUseCase: keys,
+		logger:        logger,
 	}
 }
 
-func (s *UserGRPcServer) GetUserByID(ctx context.Context, req *user_service.GetUserRequest) (*user_service.GetUserResponse, error) {
-	s.logger.Info("Received GetUserById request", zap.String("id", req.Id))
+// log returns the request-scoped logger of ctx.
+func (s *UserGRPcServer) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, s.logger)
+}
 
+func (s *UserGRPcServer) GetUserByID(ctx context.Context, req *user_service.GetUserRequest) (*user_service.GetUserResponse, error) {
 	user, err := s.userUseCase.GetUserInParallel(ctx, []string{req.Id})
 	if err != nil {
-		s.logger.Error("Failed to get user", zap.String("id", req.Id), zap.Error(err))
+		s.log(ctx).Error("Failed to get user", zap.String("id", req.Id), zap.Error(err))
 		return nil, err
 	}
 
@@ -38,10 +44,9 @@ func (s *UserGRPcServer) GetUserByID(ctx context.Context, req *user_service.GetU
 }
 
 func (s *UserGRPcServer) CreateUser(ctx context.Context, req *user_service.CreateUserRequest) (*user_service.CreateUserResponse, error) {
-	s.logger.Info("Received CreateUser request", zap.String("username", req.Username), zap.String("email", req.Email))
 	user, err := s.userUseCase.CreateUser(ctx, req.Username, req.Email)
 	if err != nil {
-		s.logger.Error("Failed to create user", zap.Error(err))
+		s.log(ctx).Error("Failed to create user", zap.Error(err))
 		return nil, err
 	}
 
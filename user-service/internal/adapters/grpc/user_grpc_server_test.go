@@ -47,7 +47,7 @@ func (f *FakeUserUseCase) GetAllUsers(ctx context.Context) ([]*domain.User, erro
 func TestUserGRPCServer_CreateUser(t *testing.T) {
 	fakeUC := new(FakeUserUseCase)
 	logger, _ := zap.NewDevelopment()
-	server := grpc.NewUserGRPCServer(fakeUC, logger)
+	server := grpc.NewUserGRPCServer(fakeUC, nil, logger)
 
 	expectedUser := &domain.User{
 		ID:       "generated-id",
@@ -73,7 +73,7 @@ func TestUserGRPCServer_CreateUser(t *testing.T) {
 func TestUserGRPCServer_GetUserByID(t *testing.T) {
 	fakeUC := new(FakeUserUseCase)
 	logger, _ := zap.NewDevelopment()
-	server := grpc.NewUserGRPCServer(fakeUC, logger)
+	server := grpc.NewUserGRPCServer(fakeUC, nil, logger)
 
 	expectedUser := &domain.User{
 		ID:       "123",
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type IssueAPIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// TTL is a Go duration such as "720h"; empty keys never expire.
+	TTL string `json:"ttl"`
+}
+
+type RotateAPIKeyRequest struct {
+	// GracePeriod keeps the old key working for a Go duration such as "1h";
+	// empty revokes it at once.
+	GracePeriod string `json:"gracePeriod"`
+}
+
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	OwnerID    string     `json:"ownerId"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// IssuedAPIKeyResponse carries the secret, which is only returned here.
+type IssuedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
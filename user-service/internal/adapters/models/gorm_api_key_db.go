@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// GormDBAPIKey stores the scopes as a comma-separated list so the table is
+// the same on Postgres and MySQL.
+type GormDBAPIKey struct {
+	ID         string     `gorm:"column:id;primaryKey"`
+	OwnerID    string     `gorm:"column:owner_id"`
+	Name       string     `gorm:"column:name"`
+	Prefix     string     `gorm:"column:prefix"`
+	KeyHash    string     `gorm:"column:key_hash;uniqueIndex"`
+	Scopes     string     `gorm:"column:scopes"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+}
+
+func (GormDBAPIKey) TableName() string {
+	return "api_keys"
+}
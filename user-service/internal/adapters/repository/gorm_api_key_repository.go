@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"user-service/internal/adapters/columns"
+	"user-service/internal/adapters/models"
+	"user-service/internal/domain"
+	"user-service/internal/usecases"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormAPIKeyRepository struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+var _ usecases.APIKeyRepository = (*GormAPIKeyRepository)(nil)
+
+func NewGormAPIKeyRepo(db *gorm.DB, logger *zap.Logger) *GormAPIKeyRepository {
+	return &GormAPIKeyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// log returns the request-scoped logger of ctx.
+func (r *GormAPIKeyRepository) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, r.logger)
+}
+
+// Save inserts the key or updates its expiry and revocation. The other
+// fields of an issued key never change.
+func (r *GormAPIKeyRepository) Save(ctx context.Context, key *domain.APIKey) error {
+	dbKey := toGormAPIKey(key)
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: columns.ColumnID}},
+			DoUpdates: clause.AssignmentColumns([]string{columns.ColumnExpiresAt, columns.ColumnRevokedAt}),
+		}).
+		Create(&dbKey).Error
+	if err != nil {
+		r.log(ctx).Error("GORM failed to save api key", zap.String("keyId", key.ID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *GormAPIKeyRepository) FindByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	return r.first(ctx, columns.ColumnID, id)
+}
+
+func (r *GormAPIKeyRepository) FindByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	return r.first(ctx, columns.ColumnKeyHash, hash)
+}
+
+func (r *GormAPIKeyRepository) first(ctx context.Context, column, value string) (*domain.APIKey, error) {
+	var dbKey models.GormDBAPIKey
+	err := r.db.WithContext(ctx).First(&dbKey, column+" = ?", value).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		r.log(ctx).Error("GORM failed to find api key", zap.String("by", column), zap.Error(err))
+		return nil, err
+	}
+	return toDomainAPIKey(dbKey), nil
+}
+
+func (r *GormAPIKeyRepository) FindByOwner(ctx context.Context, ownerID string) ([]*domain.APIKey, error) {
+	var dbKeys []models.GormDBAPIKey
+	err := r.db.WithContext(ctx).
+		Where(columns.ColumnOwnerID+" = ?", ownerID).
+		Order(columns.ColumnCreatedAt).
+		Find(&dbKeys).Error
+	if err != nil {
+		r.log(ctx).Error("GORM failed to find api keys", zap.String("ownerId", ownerID), zap.Error(err))
+		return nil, err
+	}
+	keys := make([]*domain.APIKey, 0, len(dbKeys))
+	for _, dbKey := range dbKeys {
+		keys = append(keys, toDomainAPIKey(dbKey))
+	}
+	return keys, nil
+}
+
+func (r *GormAPIKeyRepository) UpdateLastUsed(ctx context.Context, id string, at time.Time) error {
+	res := r.db.WithContext(ctx).
+		Model(&models.GormDBAPIKey{}).
+		Where(columns.ColumnID+" = ?", id).
+		Update(columns.ColumnLastUsedAt, at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func toGormAPIKey(key *domain.APIKey) models.GormDBAPIKey {
+	scopes := make([]string, len(key.Scopes))
+	for i, s := range key.Scopes {
+		scopes[i] = string(s)
+	}
+	return models.GormDBAPIKey{
+		ID:         key.ID,
+		OwnerID:    key.OwnerID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		KeyHash:    key.Hash,
+		Scopes:     strings.Join(scopes, ","),
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
+
+func toDomainAPIKey(dbKey models.GormDBAPIKey) *domain.APIKey {
+	var scopes []domain.Scope
+	for _, s := range strings.Split(dbKey.Scopes, ",") {
+		if s != "" {
+			scopes = append(scopes, domain.Scope(s))
+		}
+	}
+	return &domain.APIKey{
+		ID:         dbKey.ID,
+		OwnerID:    dbKey.OwnerID,
+		Name:       dbKey.Name,
+		Prefix:     dbKey.Prefix,
+		Hash:       dbKey.KeyHash,
+		Scopes:     scopes,
+		CreatedAt:  dbKey.CreatedAt,
+		ExpiresAt:  dbKey.ExpiresAt,
+		LastUsedAt: dbKey.LastUsedAt,
+		RevokedAt:  dbKey.RevokedAt,
+	}
+}
@@ -4,11 +4,14 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"user-service/internal/adapters/columns"
 	"user-service/internal/adapters/models"
 	"user-service/internal/domain"
 	"user-service/internal/usecases"
 
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -28,6 +31,11 @@ func NewGormUserRepo(db *gorm.DB, logger *zap.Logger) *GormUserRepository {
 	}
 }
 
+// log returns the request-scoped logger of ctx.
+func (r *GormUserRepository) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, r.logger)
+}
+
 func (r *GormUserRepository) Save(ctx context.Context, user *domain.User) error {
 	dbUser := models.GormDBUser{
 		ID:       user.ID,
@@ -43,12 +51,12 @@ func (r *GormUserRepository) Save(ctx context.Context, user *domain.User) error
 		Create(&dbUser).Error
 
 	if err != nil {
-		r.logger.Error("GORM failed to save user",
+		r.log(ctx).Error("GORM failed to save user",
 			zap.String("id", user.ID), zap.Error(err))
 		return err
 	}
 
-	r.logger.Info("GORM saved user", zap.String("id", user.ID))
+	r.log(ctx).Info("GORM saved user", zap.String("id", user.ID))
 	return nil
 }
 
@@ -59,7 +67,10 @@ func (r *GormUserRepository) FindByID(ctx context.Context, id string) (*domain.U
 		First(&dbUser, columns.ColumnID+" = ?", id).
 		Error
 	if err != nil {
-		r.logger.Warn("GORM find by ID failed", zap.String("id", id), zap.Error(err))
+		r.log(ctx).Warn("GORM find by ID failed", zap.String("id", id), zap.Error(err))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %w", domain.ErrUserNotFound, err)
+		}
 		return nil, err
 	}
 
@@ -77,7 +88,7 @@ func (r *GormUserRepository) FindAll(ctx context.Context) ([]*domain.User, error
 		Find(&dbUsers).
 		Error
 	if err != nil {
-		r.logger.Warn("GORM find all failed", zap.Error(err))
+		r.log(ctx).Warn("GORM find all failed", zap.Error(err))
 		return nil, err
 	}
 
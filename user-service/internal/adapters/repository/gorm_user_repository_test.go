@@ -53,8 +53,8 @@ func TestGormUserRepository(t *testing.T) {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	require.NoError(t, err, "Failed to connect to postgres")
 
-	err = db.AutoMigrate(&models.GormDBUser{})
-	require.NoError(t, err, "Failed to migrate users table")
+	err = db.AutoMigrate(&models.GormDBUser{}, &models.GormDBAPIKey{})
+	require.NoError(t, err, "Failed to migrate users and api_keys tables")
 
 	logger, _ := zap.NewDevelopment()
 	repo := NewGormUserRepo(db, logger)
@@ -109,4 +109,29 @@ func TestGormUserRepository(t *testing.T) {
 		require.Equal(t, user2.Username, fetched.Username)
 		require.Equal(t, user2.Email, fetched.Email)
 	})
+	t.Run("APIKeys", func(t *testing.T) {
+		keys := NewGormAPIKeyRepo(db, logger)
+		key, _, err := domain.NewAPIKey("1", "partner", []domain.Scope{domain.ScopeInventoryRead, domain.ScopeOrdersRead}, nil)
+		require.NoError(t, err)
+		require.NoError(t, keys.Save(ctx, key))
+
+		fetched, err := keys.FindByHash(ctx, key.Hash)
+		require.NoError(t, err)
+		require.Equal(t, key.ID, fetched.ID)
+		require.Equal(t, key.Scopes, fetched.Scopes)
+
+		at := time.Now().UTC().Truncate(time.Second)
+		require.NoError(t, keys.UpdateLastUsed(ctx, key.ID, at))
+		key.RevokedAt = &at
+		require.NoError(t, keys.Save(ctx, key))
+
+		owned, err := keys.FindByOwner(ctx, "1")
+		require.NoError(t, err)
+		require.Len(t, owned, 1)
+		require.NotNil(t, owned[0].LastUsedAt)
+		require.NotNil(t, owned[0].RevokedAt)
+
+		_, err = keys.FindByID(ctx, "missing")
+		require.ErrorIs(t, err, domain.ErrAPIKeyNotFound)
+	})
 }
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"user-service/internal/domain"
+	"user-service/internal/usecases"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"go.uber.org/zap"
+)
+
+// InMemoryAPIKeyRepository keeps copies of the keys, so callers cannot change
+// stored keys without Save.
+type InMemoryAPIKeyRepository struct {
+	mu     sync.RWMutex
+	store  map[string]domain.APIKey
+	logger *zap.Logger
+}
+
+var _ usecases.APIKeyRepository = (*InMemoryAPIKeyRepository)(nil)
+
+func NewInMemoryAPIKeyRepo(logger *zap.Logger) *InMemoryAPIKeyRepository {
+	return &InMemoryAPIKeyRepository{
+		store:  make(map[string]domain.APIKey),
+		logger: logger,
+	}
+}
+
+// log returns the request-scoped logger of ctx.
+func (r *InMemoryAPIKeyRepository) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, r.logger)
+}
+
+func (r *InMemoryAPIKeyRepository) Save(ctx context.Context, key *domain.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store[key.ID] = *key
+	r.log(ctx).Debug("api key saved", zap.String("keyId", key.ID))
+	return nil
+}
+
+func (r *InMemoryAPIKeyRepository) FindByID(_ context.Context, id string) (*domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, ok := r.store[id]
+	if !ok {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return &key, nil
+}
+
+func (r *InMemoryAPIKeyRepository) FindByHash(_ context.Context, hash string) (*domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, key := range r.store {
+		if key.Hash == hash {
+			return &key, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (r *InMemoryAPIKeyRepository) FindByOwner(_ context.Context, ownerID string) ([]*domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keys := make([]*domain.APIKey, 0)
+	for _, key := range r.store {
+		if key.OwnerID == ownerID {
+			keys = append(keys, &key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b *domain.APIKey) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return keys, nil
+}
+
+func (r *InMemoryAPIKeyRepository) UpdateLastUsed(_ context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key, ok := r.store[id]
+	if !ok {
+		return domain.ErrAPIKeyNotFound
+	}
+	key.LastUsedAt = &at
+	r.store[id] = key
+	return nil
+}
@@ -2,10 +2,10 @@ package repository
 
 import (
 	"context"
-	"errors"
 	"user-service/internal/domain"
 	"user-service/internal/usecases"
 
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"go.uber.org/zap"
 )
 
@@ -21,21 +21,26 @@ func NewInMemoryUserRepo(logger *zap.Logger) *InMemoryUserRepository {
 	}
 }
 
+// log returns the request-scoped logger of ctx.
+func (r *InMemoryUserRepository) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, r.logger)
+}
+
 var _ usecases.UserRepository = (*InMemoryUserRepository)(nil)
 
 func (r *InMemoryUserRepository) Save(ctx context.Context, user *domain.User) error {
 	r.store[user.ID] = user
-	r.logger.Info("user saved", zap.String("id", user.ID), zap.String("username", user.Username), zap.String("email", user.Email))
+	r.log(ctx).Info("user saved", zap.String("id", user.ID), zap.String("username", user.Username))
 	return nil
 }
 
 func (r *InMemoryUserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
 	user, found := r.store[id]
 	if !found {
-		r.logger.Warn("user not found", zap.String("id", id))
-		return nil, errors.New("user not found")
+		r.log(ctx).Warn("user not found", zap.String("id", id))
+		return nil, domain.ErrUserNotFound
 	}
-	r.logger.Debug("user retrieved", zap.String("id", id))
+	r.log(ctx).Debug("user retrieved", zap.String("id", id))
 	return user, nil
 }
 
@@ -44,6 +49,6 @@ func (r *InMemoryUserRepository) FindAll(ctx context.Context) ([]*domain.User, e
 	for _, user := range r.store {
 		users = append(users, user)
 	}
-	r.logger.Debug("all users retrieved", zap.Int("count", len(users)))
+	r.log(ctx).Debug("all users retrieved", zap.Int("count", len(users)))
 	return users, nil
 }
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 	"user-service/internal/domain"
 
 	"github.com/stretchr/testify/assert"
@@ -61,3 +62,30 @@ func TestInMemoryUserRepository(t *testing.T) {
 		assert.Equal(t, user2, fetchedUser)
 	})
 }
+
+func TestInMemoryAPIKeyRepository(t *testing.T) {
+	repo := NewInMemoryAPIKeyRepo(zap.NewNop())
+	ctx := context.Background()
+	key, _, err := domain.NewAPIKey("u1", "partner", []domain.Scope{domain.ScopeInventoryRead}, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Save(ctx, key))
+
+	byHash, err := repo.FindByHash(ctx, key.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, key.ID, byHash.ID)
+
+	byHash.Name = "changed"
+	stored, _ := repo.FindByID(ctx, key.ID)
+	assert.Equal(t, "partner", stored.Name, "stored keys change only through Save")
+
+	at := key.CreatedAt.Add(time.Minute)
+	assert.NoError(t, repo.UpdateLastUsed(ctx, key.ID, at))
+	owned, err := repo.FindByOwner(ctx, "u1")
+	assert.NoError(t, err)
+	assert.Len(t, owned, 1)
+	assert.Equal(t, at, *owned[0].LastUsedAt)
+
+	_, err = repo.FindByHash(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrAPIKeyNotFound)
+	assert.ErrorIs(t, repo.UpdateLastUsed(ctx, "missing", at), domain.ErrAPIKeyNotFound)
+}
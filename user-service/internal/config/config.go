@@ -0,0 +1,58 @@
+// Package config is the typed configuration of user-service.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	platformconfig "github.com/jakkapat-chongsuwat/go-microservice/platform/config"
+)
+
+type Config struct {
+	GRPCPort        int           `env:"GRPC_PORT" default:"50051" yaml:"grpc_port"`
+	HTTPPort        int           `env:"HTTP_PORT" default:"50052" yaml:"http_port"`
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" default:"25s" yaml:"shutdown_timeout"`
+	RepoType        string        `env:"REPO_TYPE" default:"memory" yaml:"repo_type" validate:"oneof=memory gorm"`
+	// APIKeysAdminToken lets operators manage the API keys of any user, as a
+	// bearer token. Without it only the owners of existing keys can.
+	APIKeysAdminToken string `env:"API_KEYS_ADMIN_TOKEN" yaml:"api_keys_admin_token" secret:"true"`
+
+	Database  platformconfig.Database  `yaml:"database"`
+	RateLimit platformconfig.RateLimit `yaml:"rate_limit"`
+}
+
+const minAdminTokenLength = 32
+
+// Load reads the configuration from the environment, .env and the optional
+// YAML file.
+func Load(yamlFile string) (*Config, error) {
+	cfg := &Config{
+		Database: platformconfig.Database{Name: "user_service"},
+		RateLimit: platformconfig.RateLimit{Rules: []string{
+			"POST /api/users=20/m:5",
+			"/user_service.UserService/CreateUser=20/m:5",
+		}},
+	}
+	if err := platformconfig.Load(cfg, platformconfig.WithYAML(yamlFile)); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+func (c *Config) Validate() error {
+	errs := []error{
+		platformconfig.ValidatePort("GRPC_PORT", c.GRPCPort),
+		platformconfig.ValidatePort("HTTP_PORT", c.HTTPPort),
+	}
+	if c.GRPCPort == c.HTTPPort {
+		errs = append(errs, fmt.Errorf("GRPC_PORT and HTTP_PORT must differ, both are %d", c.GRPCPort))
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("SHUTDOWN_TIMEOUT must be positive, got %s", c.ShutdownTimeout))
+	}
+	if c.APIKeysAdminToken != "" && len(c.APIKeysAdminToken) < minAdminTokenLength {
+		errs = append(errs, fmt.Errorf("API_KEYS_ADMIN_TOKEN must have at least %d characters", minAdminTokenLength))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load("")
+	require.NoError(t, err)
+
+	assert.Equal(t, 50051, cfg.GRPCPort)
+	assert.Equal(t, 50052, cfg.HTTPPort)
+	assert.Equal(t, 25*time.Second, cfg.ShutdownTimeout)
+	assert.Equal(t, "memory", cfg.RepoType)
+	assert.Equal(t, "user_service", cfg.Database.Name)
+	assert.Equal(t, 5432, cfg.Database.PortOrDefault())
+	assert.Equal(t, []string{"POST /api/users=20/m:5", "/user_service.UserService/CreateUser=20/m:5"}, cfg.RateLimit.Rules)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Setenv("REPO_TYPE", "redis")
+	t.Setenv("HTTP_PORT", "50051")
+	t.Setenv("API_KEYS_ADMIN_TOKEN", "short")
+
+	_, err := Load("")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid configuration")
+	assert.Contains(t, err.Error(), `REPO_TYPE must be one of memory, gorm, got "redis"`)
+	assert.Contains(t, err.Error(), "GRPC_PORT and HTTP_PORT must differ")
+	assert.Contains(t, err.Error(), "API_KEYS_ADMIN_TOKEN must have at least 32 characters")
+	assert.NotContains(t, err.Error(), "short", "the token is a secret")
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScopes(t *testing.T) {
+	scopes, err := ParseScopes([]string{"inventory:read", "orders:write", "inventory:read"})
+	require.NoError(t, err)
+	assert.Equal(t, []Scope{ScopeInventoryRead, ScopeOrdersWrite}, scopes)
+
+	_, err = ParseScopes(nil)
+	assert.ErrorIs(t, err, ErrInvalidScope)
+	_, err = ParseScopes([]string{"inventory:delete"})
+	assert.ErrorIs(t, err, ErrInvalidScope)
+}
+
+func TestNewAPIKey(t *testing.T) {
+	key, secret, err := NewAPIKey("u1", "batch", []Scope{ScopeInventoryRead}, nil)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(secret, APIKeyPrefix))
+	assert.Len(t, secret, len(APIKeyPrefix)+43)
+	assert.Equal(t, secret[:12], key.Prefix)
+	assert.Equal(t, HashAPIKey(secret), key.Hash)
+	assert.NotContains(t, key.Hash, secret)
+
+	_, other, err := NewAPIKey("u1", "batch", []Scope{ScopeInventoryRead}, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+}
+
+func TestAPIKey_Check(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	expires := now.Add(time.Hour)
+	key := &APIKey{Scopes: []Scope{ScopeInventoryRead}, ExpiresAt: &expires}
+
+	assert.NoError(t, key.Check(now, ScopeInventoryRead))
+	assert.NoError(t, key.Check(now, ""))
+	assert.ErrorIs(t, key.Check(now, ScopeOrdersWrite), ErrScopeNotGranted)
+	assert.Equal(t, "active", key.Status(now))
+
+	assert.ErrorIs(t, key.Check(expires, ScopeInventoryRead), ErrAPIKeyExpired)
+	assert.Equal(t, "expired", key.Status(expires))
+
+	key.RevokedAt = &now
+	assert.ErrorIs(t, key.Check(now, ScopeInventoryRead), ErrAPIKeyRevoked)
+	assert.Equal(t, "revoked", key.Status(now))
+}
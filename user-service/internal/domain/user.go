@@ -14,6 +14,7 @@ type User struct {
 
 var (
 	ErrInvalidEmail = errors.New("invalid email")
+	ErrUserNotFound = errors.New("user not found")
 )
 
 func NewUser(username, email string) *User {
@@ -0,0 +1,202 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"user-service/internal/domain"
+
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
+	"go.uber.org/zap"
+)
+
+// lastUsedInterval limits how often verification writes a key's LastUsedAt,
+// so a busy key does not cost a write per request.
+const lastUsedInterval = time.Minute
+
+type APIKeyRepository interface {
+	Save(ctx context.Context, key *domain.APIKey) error
+	FindByID(ctx context.Context, id string) (*domain.APIKey, error)
+	FindByHash(ctx context.Context, hash string) (*domain.APIKey, error)
+	FindByOwner(ctx context.Context, ownerID string) ([]*domain.APIKey, error)
+	UpdateLastUsed(ctx context.Context, id string, at time.Time) error
+}
+
+// APIKeyUseCase manages keys on behalf of a caller, see domain.Caller.
+// Callers that may not manage an owner's keys get domain.ErrForbidden, and
+// keys of other owners are reported as domain.ErrAPIKeyNotFound, so their
+// IDs cannot be probed.
+type APIKeyUseCase interface {
+	// IssueAPIKey creates a key and returns it with its secret, which is not
+	// stored and cannot be shown again. A zero ttl never expires.
+	IssueAPIKey(ctx context.Context, caller domain.Caller, ownerID, name string, scopes []string, ttl time.Duration) (*domain.APIKey, string, error)
+	ListAPIKeys(ctx context.Context, caller domain.Caller, ownerID string) ([]*domain.APIKey, error)
+	// RotateAPIKey issues a key with the same owner, name, scopes and
+	// lifetime, and expires the old one after grace.
+	RotateAPIKey(ctx context.Context, caller domain.Caller, id string, grace time.Duration) (*domain.APIKey, string, error)
+	RevokeAPIKey(ctx context.Context, caller domain.Caller, id string) error
+	// VerifyAPIKey returns the key for secret if it is active and has scope.
+	VerifyAPIKey(ctx context.Context, secret string, scope domain.Scope) (*domain.APIKey, error)
+}
+
+type APIKeyUseCaseImpl struct {
+	keys   APIKeyRepository
+	users  UserRepository
+	logger *zap.Logger
+}
+
+func NewAPIKeyUseCase(keys APIKeyRepository, users UserRepository, logger *zap.Logger) APIKeyUseCase {
+	return &APIKeyUseCaseImpl{
+		keys:   keys,
+		users:  users,
+		logger: logger,
+	}
+}
+
+// log returns the request-scoped logger of ctx.
+func (u *APIKeyUseCaseImpl) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, u.logger)
+}
+
+func (u *APIKeyUseCaseImpl) IssueAPIKey(ctx context.Context, caller domain.Caller, ownerID, name string, scopes []string, ttl time.Duration) (*domain.APIKey, string, error) {
+	if !caller.CanManage(ownerID) {
+		return nil, "", domain.ErrForbidden
+	}
+	parsed, err := domain.ParseScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if !caller.CanGrant(parsed) {
+		return nil, "", fmt.Errorf("%w: scopes beyond those of the calling key", domain.ErrForbidden)
+	}
+	if ttl < 0 {
+		return nil, "", fmt.Errorf("ttl must not be negative, got %s", ttl)
+	}
+	if _, err := u.users.FindByID(ctx, ownerID); err != nil {
+		return nil, "", fmt.Errorf("find owner %s: %w", ownerID, err)
+	}
+
+	var expiresAt *time.Time
+	if ttl > 0 {
+		t := domain.Clock.Now().Add(ttl)
+		expiresAt = &t
+	}
+	return u.issue(ctx, ownerID, name, parsed, expiresAt)
+}
+
+func (u *APIKeyUseCaseImpl) issue(ctx context.Context, ownerID, name string, scopes []domain.Scope, expiresAt *time.Time) (*domain.APIKey, string, error) {
+	key, secret, err := domain.NewAPIKey(ownerID, name, scopes, expiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := u.keys.Save(ctx, key); err != nil {
+		u.log(ctx).Error("failed to save api key", zap.String("ownerId", ownerID), zap.Error(err))
+		return nil, "", fmt.Errorf("failed to save api key: %w", err)
+	}
+	u.log(ctx).Info("api key issued", zap.String("keyId", key.ID), zap.String("ownerId", ownerID), zap.String("prefix", key.Prefix))
+	return key, secret, nil
+}
+
+func (u *APIKeyUseCaseImpl) ListAPIKeys(ctx context.Context, caller domain.Caller, ownerID string) ([]*domain.APIKey, error) {
+	if !caller.CanManage(ownerID) {
+		return nil, domain.ErrForbidden
+	}
+	keys, err := u.keys.FindByOwner(ctx, ownerID)
+	if err != nil {
+		u.log(ctx).Error("failed to list api keys", zap.String("ownerId", ownerID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (u *APIKeyUseCaseImpl) RotateAPIKey(ctx context.Context, caller domain.Caller, id string, grace time.Duration) (*domain.APIKey, string, error) {
+	old, err := u.findKey(ctx, caller, id)
+	if err != nil {
+		return nil, "", err
+	}
+	now := domain.Clock.Now()
+	if err := old.Check(now, ""); err != nil {
+		return nil, "", err
+	}
+
+	var expiresAt *time.Time
+	if old.ExpiresAt != nil {
+		t := now.Add(old.ExpiresAt.Sub(old.CreatedAt))
+		expiresAt = &t
+	}
+	key, secret, err := u.issue(ctx, old.OwnerID, old.Name, old.Scopes, expiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if grace > 0 {
+		retire := now.Add(grace)
+		if old.ExpiresAt == nil || retire.Before(*old.ExpiresAt) {
+			old.ExpiresAt = &retire
+		}
+	} else {
+		old.RevokedAt = &now
+	}
+	if err := u.keys.Save(ctx, old); err != nil {
+		u.log(ctx).Error("failed to retire rotated api key", zap.String("keyId", old.ID), zap.Error(err))
+		return nil, "", fmt.Errorf("failed to retire api key: %w", err)
+	}
+	u.log(ctx).Info("api key rotated", zap.String("keyId", old.ID), zap.String("newKeyId", key.ID), zap.Duration("grace", grace))
+	return key, secret, nil
+}
+
+func (u *APIKeyUseCaseImpl) RevokeAPIKey(ctx context.Context, caller domain.Caller, id string) error {
+	key, err := u.findKey(ctx, caller, id)
+	if err != nil {
+		return err
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+	now := domain.Clock.Now()
+	key.RevokedAt = &now
+	if err := u.keys.Save(ctx, key); err != nil {
+		u.log(ctx).Error("failed to revoke api key", zap.String("keyId", id), zap.Error(err))
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	u.log(ctx).Info("api key revoked", zap.String("keyId", id))
+	return nil
+}
+
+// findKey returns key id if the caller may manage it.
+func (u *APIKeyUseCaseImpl) findKey(ctx context.Context, caller domain.Caller, id string) (*domain.APIKey, error) {
+	key, err := u.keys.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !caller.CanManage(key.OwnerID) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+func (u *APIKeyUseCaseImpl) VerifyAPIKey(ctx context.Context, secret string, scope domain.Scope) (*domain.APIKey, error) {
+	key, err := u.keys.FindByHash(ctx, domain.HashAPIKey(secret))
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := domain.Clock.Now()
+	if err := key.Check(now, scope); err != nil {
+		return nil, err
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedInterval {
+		// Tracking is best effort; a failed write does not reject the key.
+		if err := u.keys.UpdateLastUsed(ctx, key.ID, now); err != nil {
+			u.log(ctx).Warn("failed to record api key use", zap.String("keyId", key.ID), zap.Error(err))
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+	return key, nil
+}
@@ -0,0 +1,213 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"user-service/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func useFakeClock(t *testing.T) *fakeClock {
+	t.Helper()
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	old := domain.Clock
+	domain.Clock = clock
+	t.Cleanup(func() { domain.Clock = old })
+	return clock
+}
+
+// fakeAPIKeyRepo keeps copies of the keys, like the real repositories.
+type fakeAPIKeyRepo struct {
+	keys           map[string]domain.APIKey
+	lastUsedWrites int
+}
+
+func newFakeAPIKeyRepo() *fakeAPIKeyRepo {
+	return &fakeAPIKeyRepo{keys: make(map[string]domain.APIKey)}
+}
+
+func (r *fakeAPIKeyRepo) Save(_ context.Context, key *domain.APIKey) error {
+	r.keys[key.ID] = *key
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) FindByID(_ context.Context, id string) (*domain.APIKey, error) {
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return &key, nil
+}
+
+func (r *fakeAPIKeyRepo) FindByHash(_ context.Context, hash string) (*domain.APIKey, error) {
+	for _, key := range r.keys {
+		if key.Hash == hash {
+			return &key, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (r *fakeAPIKeyRepo) FindByOwner(_ context.Context, ownerID string) ([]*domain.APIKey, error) {
+	var keys []*domain.APIKey
+	for _, key := range r.keys {
+		if key.OwnerID == ownerID {
+			keys = append(keys, &key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *fakeAPIKeyRepo) UpdateLastUsed(_ context.Context, id string, at time.Time) error {
+	key := r.keys[id]
+	key.LastUsedAt = &at
+	r.keys[id] = key
+	r.lastUsedWrites++
+	return nil
+}
+
+var _ APIKeyRepository = (*fakeAPIKeyRepo)(nil)
+
+var admin = domain.Caller{Admin: true}
+
+func newAPIKeyUseCase(t *testing.T) (APIKeyUseCase, *fakeAPIKeyRepo, *fakeClock) {
+	t.Helper()
+	clock := useFakeClock(t)
+	users := new(MockUserRepository)
+	users.On("FindByID", mock.Anything, "u1").Return(&domain.User{ID: "u1"}, nil)
+	users.On("FindByID", mock.Anything, mock.Anything).Return(nil, domain.ErrUserNotFound)
+	keys := newFakeAPIKeyRepo()
+	return NewAPIKeyUseCase(keys, users, zap.NewNop()), keys, clock
+}
+
+func TestAPIKeyUseCase_IssueAndVerify(t *testing.T) {
+	uc, keys, clock := newAPIKeyUseCase(t)
+	ctx := context.Background()
+
+	key, secret, err := uc.IssueAPIKey(ctx, admin, "u1", "nightly sync", []string{"inventory:read"}, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, clock.now.Add(24*time.Hour), *key.ExpiresAt)
+	assert.NotContains(t, keys.keys[key.ID].Hash, secret, "only the hash is stored")
+
+	verified, err := uc.VerifyAPIKey(ctx, secret, domain.ScopeInventoryRead)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, verified.ID)
+	assert.Equal(t, clock.now, *keys.keys[key.ID].LastUsedAt)
+
+	clock.now = clock.now.Add(10 * time.Second)
+	_, err = uc.VerifyAPIKey(ctx, secret, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, keys.lastUsedWrites, "last use is recorded at most once a minute")
+
+	clock.now = clock.now.Add(time.Minute)
+	_, err = uc.VerifyAPIKey(ctx, secret, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, keys.lastUsedWrites)
+
+	_, err = uc.VerifyAPIKey(ctx, secret, domain.ScopeOrdersWrite)
+	assert.ErrorIs(t, err, domain.ErrScopeNotGranted)
+	_, err = uc.VerifyAPIKey(ctx, "gmk_unknown", "")
+	assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+
+	clock.now = clock.now.Add(24 * time.Hour)
+	_, err = uc.VerifyAPIKey(ctx, secret, "")
+	assert.ErrorIs(t, err, domain.ErrAPIKeyExpired)
+}
+
+func TestAPIKeyUseCase_IssueValidates(t *testing.T) {
+	uc, _, _ := newAPIKeyUseCase(t)
+	ctx := context.Background()
+
+	_, _, err := uc.IssueAPIKey(ctx, admin, "u1", "k", []string{"everything"}, 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidScope)
+
+	_, _, err = uc.IssueAPIKey(ctx, admin, "ghost", "k", []string{"inventory:read"}, 0)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
+
+func TestAPIKeyUseCase_Rotate(t *testing.T) {
+	uc, _, clock := newAPIKeyUseCase(t)
+	ctx := context.Background()
+	old, oldSecret, err := uc.IssueAPIKey(ctx, admin, "u1", "partner", []string{"inventory:read"}, 0)
+	require.NoError(t, err)
+
+	rotated, newSecret, err := uc.RotateAPIKey(ctx, admin, old.ID, time.Hour)
+	require.NoError(t, err)
+	assert.NotEqual(t, old.ID, rotated.ID)
+	assert.Equal(t, old.Scopes, rotated.Scopes)
+	assert.Nil(t, rotated.ExpiresAt)
+
+	_, err = uc.VerifyAPIKey(ctx, oldSecret, "")
+	assert.NoError(t, err, "the old key works during the grace period")
+	clock.now = clock.now.Add(time.Hour)
+	_, err = uc.VerifyAPIKey(ctx, oldSecret, "")
+	assert.ErrorIs(t, err, domain.ErrAPIKeyExpired)
+	_, err = uc.VerifyAPIKey(ctx, newSecret, "")
+	assert.NoError(t, err)
+
+	again, _, err := uc.RotateAPIKey(ctx, admin, rotated.ID, 0)
+	require.NoError(t, err)
+	_, err = uc.VerifyAPIKey(ctx, newSecret, "")
+	assert.ErrorIs(t, err, domain.ErrAPIKeyRevoked, "without grace the old key is revoked")
+
+	_, _, err = uc.RotateAPIKey(ctx, admin, rotated.ID, 0)
+	assert.ErrorIs(t, err, domain.ErrAPIKeyRevoked, "revoked keys cannot be rotated")
+
+	listed, err := uc.ListAPIKeys(ctx, admin, "u1")
+	require.NoError(t, err)
+	assert.Len(t, listed, 3)
+	assert.NotEmpty(t, again.ID)
+}
+
+func TestAPIKeyUseCase_Revoke(t *testing.T) {
+	uc, _, _ := newAPIKeyUseCase(t)
+	ctx := context.Background()
+	key, secret, err := uc.IssueAPIKey(ctx, admin, "u1", "partner", []string{"inventory:read"}, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, uc.RevokeAPIKey(ctx, admin, key.ID))
+	require.NoError(t, uc.RevokeAPIKey(ctx, admin, key.ID), "revoking twice is a no-op")
+
+	_, err = uc.VerifyAPIKey(ctx, secret, "")
+	assert.ErrorIs(t, err, domain.ErrAPIKeyRevoked)
+	assert.True(t, errors.Is(uc.RevokeAPIKey(ctx, admin, "missing"), domain.ErrAPIKeyNotFound))
+}
+
+func TestAPIKeyUseCase_Authorization(t *testing.T) {
+	uc, _, _ := newAPIKeyUseCase(t)
+	ctx := context.Background()
+	owner := domain.Caller{UserID: "u1", Scopes: []domain.Scope{domain.ScopeInventoryRead, domain.ScopeOrdersRead}}
+	stranger := domain.Caller{UserID: "u2", Scopes: domain.Scopes}
+
+	key, _, err := uc.IssueAPIKey(ctx, owner, "u1", "reader", []string{"inventory:read"}, 0)
+	require.NoError(t, err, "owners grant the scopes they have")
+
+	_, _, err = uc.IssueAPIKey(ctx, owner, "u1", "writer", []string{"inventory:read", "inventory:write"}, 0)
+	assert.ErrorIs(t, err, domain.ErrForbidden, "owners cannot grant scopes they lack")
+
+	_, _, err = uc.IssueAPIKey(ctx, stranger, "u1", "k", []string{"inventory:read"}, 0)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	_, err = uc.ListAPIKeys(ctx, stranger, "u1")
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	_, err = uc.ListAPIKeys(ctx, domain.Caller{}, "")
+	assert.ErrorIs(t, err, domain.ErrForbidden, "a caller without a user manages nothing")
+
+	_, _, err = uc.RotateAPIKey(ctx, stranger, key.ID, 0)
+	assert.ErrorIs(t, err, domain.ErrAPIKeyNotFound, "keys of others look missing")
+	assert.ErrorIs(t, uc.RevokeAPIKey(ctx, stranger, key.ID), domain.ErrAPIKeyNotFound)
+
+	_, _, err = uc.RotateAPIKey(ctx, owner, key.ID, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, uc.RevokeAPIKey(ctx, owner, key.ID))
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"user-service/internal/domain"
 
+	"github.com/jakkapat-chongsuwat/go-microservice/platform/logging"
 	"go.uber.org/zap"
 )
 
@@ -37,10 +38,15 @@ func NewUserUseCase(userRepo UserRepository, logger *zap.Logger) UserUseCase {
 	}
 }
 
+// log returns the request-scoped logger of ctx.
+func (u *UserUseCaseImpl) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, u.logger)
+}
+
 func (u *UserUseCaseImpl) CreateUser(ctx context.Context, username, email string) (*domain.User, error) {
-	u.logger.Info("CreateUser called", zap.String("username", username), zap.String("email", email))
+	u.log(ctx).Debug("CreateUser called", zap.String("username", username))
 	if email == "" {
-		u.logger.Error("invalid email", zap.String("username", username))
+		u.log(ctx).Error("invalid email", zap.String("username", username))
 		return nil, domain.ErrInvalidEmail
 	}
 
@@ -48,16 +54,16 @@ func (u *UserUseCaseImpl) CreateUser(ctx context.Context, username, email string
 
 	err := u.userRepo.Save(ctx, user)
 	if err != nil {
-		u.logger.Error("failed to save user", zap.String("id", user.ID), zap.Error(err))
+		u.log(ctx).Error("failed to save user", zap.String("id", user.ID), zap.Error(err))
 		return nil, fmt.Errorf("failed to save user: %w", err)
 	}
-	u.logger.Info("user created", zap.String("id", user.ID))
+	u.log(ctx).Info("user created", zap.String("id", user.ID))
 	return user, nil
 }
 
 // for testing, batch query can be more efficient
 func (u *UserUseCaseImpl) GetUserInParallel(ctx context.Context, userIDs []string) ([]*domain.User, error) {
-	u.logger.Info("GetUserInParallel called", zap.Int("count", len(userIDs)))
+	u.log(ctx).Debug("GetUserInParallel called", zap.Int("count", len(userIDs)))
 	var (
 		wg       sync.WaitGroup
 		mu       sync.Mutex
@@ -73,10 +79,10 @@ func (u *UserUseCaseImpl) GetUserInParallel(ctx context.Context, userIDs []strin
 			defer mu.Unlock()
 			if err != nil && firstErr == nil {
 				firstErr = err
-				u.logger.Error("error finding user in parallel", zap.String("id", uid), zap.Error(err))
+				u.log(ctx).Error("error finding user in parallel", zap.String("id", uid), zap.Error(err))
 			} else if err == nil {
 				results = append(results, user)
-				u.logger.Debug("user found in parallel", zap.String("id", uid))
+				u.log(ctx).Debug("user found in parallel", zap.String("id", uid))
 			}
 		}(id)
 	}
@@ -89,7 +95,7 @@ func (u *UserUseCaseImpl) GetUserInParallel(ctx context.Context, userIDs []strin
 
 // for testing, batch query can be more efficient
 func (u *UserUseCaseImpl) GetUsersWithConcurrencyLimit(ctx context.Context, userIDs []string, maxWorkers int) ([]*domain.User, error) {
-	u.logger.Info("GetUsersWithConcurrencyLimit called", zap.Int("count", len(userIDs)), zap.Int("maxWorkers", maxWorkers))
+	u.log(ctx).Debug("GetUsersWithConcurrencyLimit called", zap.Int("count", len(userIDs)), zap.Int("maxWorkers", maxWorkers))
 	if maxWorkers <= 0 {
 		maxWorkers = 5
 	}
@@ -119,12 +125,12 @@ func (u *UserUseCaseImpl) GetUsersWithConcurrencyLimit(ctx context.Context, user
 			defer mu.Unlock()
 			if err != nil && firstErr == nil {
 				firstErr = err
-				u.logger.Error("error finding user with concurrency limit", zap.String("id", uid), zap.Error(err))
+				u.log(ctx).Error("error finding user with concurrency limit", zap.String("id", uid), zap.Error(err))
 				return
 			}
 			if err == nil {
 				results[idx] = user
-				u.logger.Debug("user found with concurrency limit", zap.String("id", uid))
+				u.log(ctx).Debug("user found with concurrency limit", zap.String("id", uid))
 			}
 		}(i, id)
 	}
@@ -137,7 +143,7 @@ func (u *UserUseCaseImpl) GetUsersWithConcurrencyLimit(ctx context.Context, user
 
 // for testing, batch query can be more efficient
 func (u *UserUseCaseImpl) GetUsersFailFast(ctx context.Context, userIDs []string, maxWorkers int) ([]*domain.User, error) {
-	u.logger.Info("GetUsersFailFast called", zap.Int("count", len(userIDs)), zap.Int("maxWorkers", maxWorkers))
+	u.log(ctx).Debug("GetUsersFailFast called", zap.Int("count", len(userIDs)), zap.Int("maxWorkers", maxWorkers))
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	if maxWorkers <= 0 {
@@ -169,13 +175,13 @@ func (u *UserUseCaseImpl) GetUsersFailFast(ctx context.Context, userIDs []string
 			defer mu.Unlock()
 			if err != nil && firstErr == nil {
 				firstErr = err
-				u.logger.Error("error finding user (fail-fast)", zap.String("id", uid), zap.Error(err))
+				u.log(ctx).Error("error finding user (fail-fast)", zap.String("id", uid), zap.Error(err))
 				cancel()
 				return
 			}
 			if err == nil {
 				results[idx] = user
-				u.logger.Debug("user found (fail-fast)", zap.String("id", uid))
+				u.log(ctx).Debug("user found (fail-fast)", zap.String("id", uid))
 			}
 		}(i, id)
 	}
@@ -187,12 +193,12 @@ func (u *UserUseCaseImpl) GetUsersFailFast(ctx context.Context, userIDs []string
 }
 
 func (u *UserUseCaseImpl) GetAllUsers(ctx context.Context) ([]*domain.User, error) {
-	u.logger.Info("GetAllUsers called")
+	u.log(ctx).Debug("GetAllUsers called")
 	users, err := u.userRepo.FindAll(ctx)
 	if err != nil {
-		u.logger.Error("failed to get all users", zap.Error(err))
+		u.log(ctx).Error("failed to get all users", zap.Error(err))
 		return nil, fmt.Errorf("failed to get all users: %w", err)
 	}
-	u.logger.Info("all users retrieved", zap.Int("count", len(users)))
+	u.log(ctx).Info("all users retrieved", zap.Int("count", len(users)))
 	return users, nil
 }
@@ -0,0 +1,6 @@
+-- Create "api_keys" table
+CREATE TABLE "user_service"."api_keys" ("id" character varying(255) NOT NULL, "owner_id" character varying(255) NOT NULL, "name" character varying(255) NOT NULL, "prefix" character varying(16) NOT NULL, "key_hash" character(64) NOT NULL, "scopes" text NOT NULL, "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, "expires_at" timestamp NULL, "last_used_at" timestamp NULL, "revoked_at" timestamp NULL, PRIMARY KEY ("id"), CONSTRAINT "api_keys_owner_id_fkey" FOREIGN KEY ("owner_id") REFERENCES "user_service"."users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "api_keys_key_hash_key" to table: "api_keys"
+CREATE UNIQUE INDEX "api_keys_key_hash_key" ON "user_service"."api_keys" ("key_hash");
+-- Create index "api_keys_owner_id_idx" to table: "api_keys"
+CREATE INDEX "api_keys_owner_id_idx" ON "user_service"."api_keys" ("owner_id");